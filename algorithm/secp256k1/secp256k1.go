@@ -0,0 +1,120 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package secp256k1 registers the ECDSA signatures on the secp256k1 curve,
+// the curve of the Bitcoin and Ethereum keys, as the "secp256k1" signature
+// algorithm of the wallet client. It is enabled by importing the package:
+//
+//	import _ "github.com/arxanchain/wallet-sdk-go/algorithm/secp256k1"
+//
+// The private keys are 32 byte big endian scalars, the public keys are
+// encoded in uncompressed form (compressed ones are accepted), the
+// signatures are the ASN.1 DER encoded r and s of the SHA-256 digest of
+// the payload, with the low s value and the deterministic nonces of
+// RFC 6979. The arithmetic is the constant time one of
+// github.com/decred/dcrd/dcrec/secp256k1.
+package secp256k1
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/arxanchain/wallet-sdk-go/api"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// Name is the name of the algorithm in the keys and signatures.
+const Name = "secp256k1"
+
+func init() {
+	api.RegisterSignatureAlgorithm(Algorithm{})
+}
+
+// Algorithm is the secp256k1 api.SignatureAlgorithm.
+//
+type Algorithm struct{}
+
+// Name returns the name of the algorithm.
+//
+func (Algorithm) Name() string { return Name }
+
+// Sign signs the payload with the private key.
+//
+func (Algorithm) Sign(privateKey, payload []byte) ([]byte, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	defer key.Zero()
+	hash := sha256.Sum256(payload)
+	return ecdsa.Sign(key, hash[:]).Serialize(), nil
+}
+
+// Verify verifies the signature of the payload with the public key.
+//
+func (Algorithm) Verify(publicKey, payload, signature []byte) error {
+	key, err := secp256k1.ParsePubKey(publicKey)
+	if err != nil {
+		return fmt.Errorf("secp256k1 public key invalid: %v", err)
+	}
+	sig, err := ecdsa.ParseDERSignature(signature)
+	if err != nil {
+		return fmt.Errorf("secp256k1 signature invalid: %v", err)
+	}
+	hash := sha256.Sum256(payload)
+	if !sig.Verify(hash[:], key) {
+		return fmt.Errorf("secp256k1 signature mismatch")
+	}
+	return nil
+}
+
+// GenerateKey generates a key pair.
+//
+func GenerateKey() (privateKey, publicKey []byte, err error) {
+	key, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer key.Zero()
+	return key.Serialize(), key.PubKey().SerializeUncompressed(), nil
+}
+
+// PublicKey returns the public key of the private key.
+//
+func PublicKey(privateKey []byte) ([]byte, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	defer key.Zero()
+	return key.PubKey().SerializeUncompressed(), nil
+}
+
+// parsePrivateKey rejects the keys out of [1, n-1] instead of reducing them.
+func parsePrivateKey(privateKey []byte) (*secp256k1.PrivateKey, error) {
+	if len(privateKey) != 32 {
+		return nil, fmt.Errorf("secp256k1 private key invalid: %d bytes", len(privateKey))
+	}
+	var d secp256k1.ModNScalar
+	if overflow := d.SetByteSlice(privateKey); overflow || d.IsZero() {
+		d.Zero()
+		return nil, fmt.Errorf("secp256k1 private key out of range")
+	}
+	key := secp256k1.NewPrivateKey(&d)
+	d.Zero()
+	return key, nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secp256k1
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("decode hex fail: %v", err)
+	}
+	return b
+}
+
+func TestRegistered(t *testing.T) {
+	alg, err := api.LookupSignatureAlgorithm(Name)
+	if err != nil {
+		t.Fatalf("secp256k1 should be registered: %v", err)
+	}
+	if _, ok := alg.(Algorithm); !ok {
+		t.Fatalf("registered algorithm should be secp256k1, got %T", alg)
+	}
+}
+
+func TestPublicKey(t *testing.T) {
+	// 2·G
+	pub, err := PublicKey(mustHex(t, "0000000000000000000000000000000000000000000000000000000000000002"))
+	if err != nil {
+		t.Fatalf("public key fail: %v", err)
+	}
+	expected := "04" +
+		"c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5" +
+		"1ae168fea63dc339a3c58419466ceaeef7f632653266d0e1236431a950cfe52a"
+	if hex.EncodeToString(pub) != expected {
+		t.Fatalf("public key should be %s, got %x", expected, pub)
+	}
+
+	if _, err = PublicKey(make([]byte, 32)); err == nil {
+		t.Fatalf("zero private key should fail")
+	}
+	if _, err = PublicKey(mustHex(t, "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")); err == nil {
+		t.Fatalf("private key equal to the curve order should fail")
+	}
+	if _, err = PublicKey([]byte{2}); err == nil {
+		t.Fatalf("short private key should fail")
+	}
+}
+
+func TestSignRFC6979(t *testing.T) {
+	// deterministic signature of the private key 1, the vector published
+	// with the RFC 6979 implementations of secp256k1
+	priv := mustHex(t, "0000000000000000000000000000000000000000000000000000000000000001")
+	sig, err := Algorithm{}.Sign(priv, []byte("Satoshi Nakamoto"))
+	if err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+	expected := "3045" +
+		"022100934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8" +
+		"02202442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5"
+	if hex.EncodeToString(sig) != expected {
+		t.Fatalf("signature should be %s, got %x", expected, sig)
+	}
+}
+
+func TestVerifyOpenSSL(t *testing.T) {
+	// signature of "wallet payload" made with openssl dgst -sha256 -sign
+	pub := mustHex(t, "042ff14eb33fc41df6e54b5af9242c85ef2d738eaa338b755618e44e9a44dc2382"+
+		"5129ff50fea6df7a263e513c7eb449eeb9daa32f4f3eaf8c32e85a32724934d1")
+	sig := mustHex(t, "3045022100c6bb1564cb3744d7dcd1bb26944f214afff1ddb719360a45760e45f9ef10f121"+
+		"0220672d73643cac166628045e87a68363d5965552f1764178a41985c888f02cb3aa")
+	if err := (Algorithm{}).Verify(pub, []byte("wallet payload"), sig); err != nil {
+		t.Fatalf("verify openssl signature fail: %v", err)
+	}
+	if err := (Algorithm{}).Verify(pub, []byte("wallet payload!"), sig); err == nil {
+		t.Fatalf("verify should fail on another payload")
+	}
+
+	// compressed form of the same key, y is odd
+	compressed := append([]byte{3}, pub[1:33]...)
+	if err := (Algorithm{}).Verify(compressed, []byte("wallet payload"), sig); err != nil {
+		t.Fatalf("verify with compressed key fail: %v", err)
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	priv, pub, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	derived, err := PublicKey(priv)
+	if err != nil || hex.EncodeToString(derived) != hex.EncodeToString(pub) {
+		t.Fatalf("public key should be derived from the private key: %v", err)
+	}
+
+	payload := []byte(`{"owner":"did:axn:alice"}`)
+	sig, err := Algorithm{}.Sign(priv, payload)
+	if err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+	if err = (Algorithm{}).Verify(pub, payload, sig); err != nil {
+		t.Fatalf("verify fail: %v", err)
+	}
+
+	_, other, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	if err = (Algorithm{}).Verify(other, payload, sig); err == nil {
+		t.Fatalf("verify should fail with another key")
+	}
+	sig[len(sig)-1] ^= 1
+	if err = (Algorithm{}).Verify(pub, payload, sig); err == nil {
+		t.Fatalf("verify should fail on a tampered signature")
+	}
+}
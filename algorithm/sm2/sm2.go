@@ -0,0 +1,98 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sm2 registers the signatures of the Chinese SM2 public key
+// algorithm of GB/T 32918-2016, on the sm2p256v1 curve with the SM3 digest,
+// as the "sm2" signature algorithm of the wallet client. It is enabled by
+// importing the package:
+//
+//	import _ "github.com/arxanchain/wallet-sdk-go/algorithm/sm2"
+//
+// The private keys are 32 byte big endian scalars, the public keys are
+// encoded in uncompressed form, the signatures are the ASN.1 DER encoded r
+// and s, as produced by OpenSSL. The distinguishing identifier of the
+// signers is the default one, "1234567812345678". The arithmetic is the
+// constant time one of github.com/emmansun/gmsm.
+package sm2
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/arxanchain/wallet-sdk-go/api"
+	"github.com/emmansun/gmsm/sm2"
+)
+
+// Name is the name of the algorithm in the keys and signatures.
+const Name = "sm2"
+
+func init() {
+	api.RegisterSignatureAlgorithm(Algorithm{})
+}
+
+// Algorithm is the SM2 api.SignatureAlgorithm.
+//
+type Algorithm struct{}
+
+// Name returns the name of the algorithm.
+//
+func (Algorithm) Name() string { return Name }
+
+// Sign signs the payload with the private key.
+//
+func (Algorithm) Sign(privateKey, payload []byte) ([]byte, error) {
+	key, err := sm2.NewPrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("sm2 private key invalid: %v", err)
+	}
+	return key.Sign(rand.Reader, payload, sm2.DefaultSM2SignerOpts)
+}
+
+// Verify verifies the signature of the payload with the public key.
+//
+func (Algorithm) Verify(publicKey, payload, signature []byte) error {
+	key, err := sm2.NewPublicKey(publicKey)
+	if err != nil {
+		return fmt.Errorf("sm2 public key invalid: %v", err)
+	}
+	if !sm2.VerifyASN1WithSM2(key, nil, payload, signature) {
+		return fmt.Errorf("sm2 signature mismatch")
+	}
+	return nil
+}
+
+// GenerateKey generates a key pair.
+//
+func GenerateKey() (privateKey, publicKey []byte, err error) {
+	key, err := sm2.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey = make([]byte, 32)
+	key.D.FillBytes(privateKey)
+	return privateKey, elliptic.Marshal(key.Curve, key.X, key.Y), nil
+}
+
+// PublicKey returns the public key of the private key.
+//
+func PublicKey(privateKey []byte) ([]byte, error) {
+	key, err := sm2.NewPrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("sm2 private key invalid: %v", err)
+	}
+	return elliptic.Marshal(key.Curve, key.X, key.Y), nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sm2
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("decode hex fail: %v", err)
+	}
+	return b
+}
+
+func TestRegistered(t *testing.T) {
+	alg, err := api.LookupSignatureAlgorithm(Name)
+	if err != nil {
+		t.Fatalf("sm2 should be registered: %v", err)
+	}
+	if _, ok := alg.(Algorithm); !ok {
+		t.Fatalf("registered algorithm should be sm2, got %T", alg)
+	}
+}
+
+func TestVerifyStandard(t *testing.T) {
+	// example of GM/T 0003.5-2012 with the sm2p256v1 curve: signature of
+	// "message digest" with the default identifier and the SM3 digest
+	pub, err := PublicKey(mustHex(t, "3945208f7b2144b13f36e38ac6d39f95889393692860b51a42fb81ef4df7c5b8"))
+	if err != nil {
+		t.Fatalf("public key fail: %v", err)
+	}
+	expected := "04" +
+		"09f9df311e5421a150dd7d161e4bc5c672179fad1833fc076bb08ff356f35020" +
+		"ccea490ce26775a52dc6ea718cc1aa600aed05fbf35e084a6632f6072da9ad13"
+	if hex.EncodeToString(pub) != expected {
+		t.Fatalf("public key should be %s, got %x", expected, pub)
+	}
+	sig := mustHex(t, "3046"+
+		"022100f5a03b0648d2c4630eeac513e1bb81a15944da3827d5b74143ac7eaceee720b3"+
+		"022100b1b6aa29df212fd8763182bc0d421ca1bb9038fd1f7f42d4840b69c485bbc1aa")
+	if err = (Algorithm{}).Verify(pub, []byte("message digest"), sig); err != nil {
+		t.Fatalf("verify standard signature fail: %v", err)
+	}
+	if err = (Algorithm{}).Verify(pub, []byte("message digest!"), sig); err == nil {
+		t.Fatalf("verify should fail on another payload")
+	}
+}
+
+func TestPublicKeyOpenSSL(t *testing.T) {
+	// key generated with openssl genpkey -algorithm SM2
+	pub, err := PublicKey(mustHex(t, "90dfcdda549bb3d2695402481e7e6f060a8a480596e9ef9ba9a6d5e7249bd260"))
+	if err != nil {
+		t.Fatalf("public key fail: %v", err)
+	}
+	expected := "04cf6c5384085b2288583c6e592f63f940860cd28baf9167a1eb41ed7d24a6ebcb" +
+		"907cc6024fdf7bc7742109093ed6307ebc90fe40a00b6a4b786965f1989c0223"
+	if hex.EncodeToString(pub) != expected {
+		t.Fatalf("public key should be %s, got %x", expected, pub)
+	}
+
+	if _, err = PublicKey(make([]byte, 32)); err == nil {
+		t.Fatalf("zero private key should fail")
+	}
+}
+
+func TestVerifyOpenSSL(t *testing.T) {
+	// signature of "wallet payload" made with openssl dgst -sm3 -sign
+	pub := mustHex(t, "04cf6c5384085b2288583c6e592f63f940860cd28baf9167a1eb41ed7d24a6ebcb"+
+		"907cc6024fdf7bc7742109093ed6307ebc90fe40a00b6a4b786965f1989c0223")
+	sig := mustHex(t, "3046022100dcf96c9cac1aec2bae6598e8f895718d4489e513a6146ecd8bb29faf5fcb20e1"+
+		"022100a30b705c5fd9d7903d1d37a9f82582c140c629df1793b723850387abaf6e2f4b")
+	if err := (Algorithm{}).Verify(pub, []byte("wallet payload"), sig); err != nil {
+		t.Fatalf("verify openssl signature fail: %v", err)
+	}
+	if err := (Algorithm{}).Verify(pub, []byte("wallet payload!"), sig); err == nil {
+		t.Fatalf("verify should fail on another payload")
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	priv, pub, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	derived, err := PublicKey(priv)
+	if err != nil || hex.EncodeToString(derived) != hex.EncodeToString(pub) {
+		t.Fatalf("public key should be derived from the private key: %v", err)
+	}
+
+	payload := []byte(`{"owner":"did:axn:alice"}`)
+	sig, err := Algorithm{}.Sign(priv, payload)
+	if err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+	if err = (Algorithm{}).Verify(pub, payload, sig); err != nil {
+		t.Fatalf("verify fail: %v", err)
+	}
+
+	_, other, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	if err = (Algorithm{}).Verify(other, payload, sig); err == nil {
+		t.Fatalf("verify should fail with another key")
+	}
+	sig[len(sig)-1] ^= 1
+	if err = (Algorithm{}).Verify(pub, payload, sig); err == nil {
+		t.Fatalf("verify should fail on a tampered signature")
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/arxanchain/sdk-go-common/utils"
+	"golang.org/x/crypto/ed25519"
+)
+
+// AlgorithmEd25519 is the name of the ed25519 signature algorithm, the
+// default and only built-in algorithm.
+//
+const AlgorithmEd25519 = "ed25519"
+
+// algorithmSep separates the algorithm name from the key or the signature,
+// it is not a base64 character.
+const algorithmSep = ":"
+
+// SignatureAlgorithm signs payloads with the raw private keys of an
+// algorithm.
+//
+// The algorithm of a key is selected by the private key of the signature
+// params, prefixed with the algorithm name as returned by AlgorithmKey, and
+// advertised the same way in the signature values of the signature bodies:
+// "secp256k1:<base64 signature>". The ed25519 keys and signatures have no
+// prefix, as before the other algorithms were supported.
+//
+// Only ed25519 is built in. The other algorithms are registered with
+// RegisterSignatureAlgorithm, the secp256k1 and SM2 ones by importing the
+// algorithm/secp256k1 and algorithm/sm2 packages.
+//
+type SignatureAlgorithm interface {
+	// Name is the name of the algorithm in the keys and signatures
+	Name() string
+	// Sign returns the signature of the payload with the private key
+	Sign(privateKey, payload []byte) ([]byte, error)
+	// Verify verifies the signature of the payload with the public key
+	Verify(publicKey, payload, signature []byte) error
+}
+
+var algorithms = struct {
+	sync.RWMutex
+	m map[string]SignatureAlgorithm
+}{
+	m: map[string]SignatureAlgorithm{
+		AlgorithmEd25519: ed25519Algorithm{},
+	},
+}
+
+// RegisterSignatureAlgorithm makes the algorithm available to the keys
+// prefixed with its name, replacing the algorithm registered with the same
+// name if any.
+//
+func RegisterSignatureAlgorithm(alg SignatureAlgorithm) {
+	name := alg.Name()
+	if name == "" || strings.Contains(name, algorithmSep) {
+		panic(fmt.Sprintf("signature algorithm name invalid: %q", name))
+	}
+	algorithms.Lock()
+	defer algorithms.Unlock()
+	algorithms.m[name] = alg
+}
+
+// LookupSignatureAlgorithm returns the algorithm registered with the name.
+//
+func LookupSignatureAlgorithm(name string) (SignatureAlgorithm, error) {
+	algorithms.RLock()
+	defer algorithms.RUnlock()
+	alg, ok := algorithms.m[name]
+	if !ok {
+		return nil, fmt.Errorf("signature algorithm not supported: %s", name)
+	}
+	return alg, nil
+}
+
+// AlgorithmKey returns the private key of signature params signing with
+// the algorithm:
+//
+//	import _ "github.com/arxanchain/wallet-sdk-go/algorithm/secp256k1"
+//
+//	signParams := &pki.SignatureParam{
+//		Creator:    "did:axn:issuer",
+//		PrivateKey: api.AlgorithmKey("secp256k1", priv),
+//	}
+//
+func AlgorithmKey(name string, privateKey []byte) string {
+	if name == AlgorithmEd25519 {
+		return utils.EncodeBase64(privateKey)
+	}
+	return name + algorithmSep + utils.EncodeBase64(privateKey)
+}
+
+// SplitSignatureValue returns the algorithm name and the signature of a
+// signature value, the algorithm is AlgorithmEd25519 when the value has no
+// prefix.
+//
+func SplitSignatureValue(value string) (name, signature string) {
+	if i := strings.Index(value, algorithmSep); i > 0 {
+		return value[:i], value[i+1:]
+	}
+	return AlgorithmEd25519, value
+}
+
+// signatureValue prefixes the signature with the algorithm name, but for
+// ed25519.
+func signatureValue(name, signature string) string {
+	if name == "" || name == AlgorithmEd25519 {
+		return signature
+	}
+	return name + algorithmSep + signature
+}
+
+// keyAlgorithm returns the algorithm of the private key of signature
+// params and the base64 key, nil algorithm for the ed25519 keys signed the
+// default way.
+func keyAlgorithm(privateKey string) (alg SignatureAlgorithm, key string, err error) {
+	name, key := SplitSignatureValue(privateKey)
+	if name == AlgorithmEd25519 {
+		return nil, key, nil
+	}
+	alg, err = LookupSignatureAlgorithm(name)
+	return alg, key, err
+}
+
+type ed25519Algorithm struct{}
+
+func (ed25519Algorithm) Name() string { return AlgorithmEd25519 }
+
+func (ed25519Algorithm) Sign(privateKey, payload []byte) ([]byte, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("ed25519 private key invalid: %d bytes", len(privateKey))
+	}
+	return ed25519.Sign(ed25519.PrivateKey(privateKey), payload), nil
+}
+
+func (ed25519Algorithm) Verify(publicKey, payload, signature []byte) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("ed25519 public key invalid: %d bytes", len(publicKey))
+	}
+	if !ed25519.Verify(ed25519.PublicKey(publicKey), payload, signature) {
+		return fmt.Errorf("ed25519 signature mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/utils"
+	"golang.org/x/crypto/ed25519"
+)
+
+// p256Algorithm signs with ECDSA P-256 keys of the standard library, the
+// algorithm the tests register.
+type p256Algorithm struct{}
+
+func (p256Algorithm) Name() string { return "p256" }
+
+func (p256Algorithm) Sign(privateKey, payload []byte) ([]byte, error) {
+	curve := elliptic.P256()
+	key := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(privateKey)}
+	key.Curve = curve
+	key.X, key.Y = curve.ScalarBaseMult(privateKey)
+	hash := sha256.Sum256(payload)
+	r, s, err := ecdsa.Sign(rand.Reader, key, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
+
+func (p256Algorithm) Verify(publicKey, payload, signature []byte) error {
+	x, y := elliptic.Unmarshal(elliptic.P256(), publicKey)
+	if x == nil {
+		return fmt.Errorf("p256 public key invalid")
+	}
+	var sig struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(signature, &sig); err != nil {
+		return err
+	}
+	hash := sha256.Sum256(payload)
+	if !ecdsa.Verify(&ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, hash[:], sig.R, sig.S) {
+		return fmt.Errorf("p256 signature mismatch")
+	}
+	return nil
+}
+
+// registerP256 registers p256Algorithm and returns a key pair of it, the
+// algorithm is removed at the end of the test.
+func registerP256(t *testing.T) (priv, pub []byte, cleanup func()) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate p256 key fail: %v", err)
+	}
+	priv = make([]byte, 32)
+	d := key.D.Bytes()
+	copy(priv[32-len(d):], d)
+	RegisterSignatureAlgorithm(p256Algorithm{})
+	return priv, elliptic.Marshal(elliptic.P256(), key.X, key.Y), func() {
+		algorithms.Lock()
+		delete(algorithms.m, "p256")
+		algorithms.Unlock()
+	}
+}
+
+func TestBuildSignatureBodyAlgorithm(t *testing.T) {
+	priv, pub, cleanup := registerP256(t)
+	defer cleanup()
+
+	params := &pki.SignatureParam{
+		Creator:    "did:axn:alice",
+		Nonce:      "nonce",
+		PrivateKey: AlgorithmKey("p256", priv),
+	}
+	body, err := buildSignatureBody(params, []byte("payload"))
+	if err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+	if !strings.HasPrefix(body.SignatureValue, "p256:") {
+		t.Fatalf("signature should advertise the algorithm: %s", body.SignatureValue)
+	}
+	name, value := SplitSignatureValue(body.SignatureValue)
+	if name != "p256" {
+		t.Fatalf("algorithm should be p256, got %s", name)
+	}
+	sig, err := utils.DecodeBase64(value)
+	if err != nil {
+		t.Fatalf("signature not base64: %v", err)
+	}
+	if err = (p256Algorithm{}).Verify(pub, []byte("payload"), sig); err != nil {
+		t.Fatalf("verify fail: %v", err)
+	}
+
+	raw, err := buildSignatureBodyBase(params, []byte("payload"))
+	if err != nil {
+		t.Fatalf("raw sign fail: %v", err)
+	}
+	if !strings.HasPrefix(raw.SignatureValue, "p256:") {
+		t.Fatalf("raw signature should advertise the algorithm")
+	}
+}
+
+func TestBuiltinSignatureAlgorithms(t *testing.T) {
+	for _, name := range []string{"secp256k1", "sm2"} {
+		if _, err := LookupSignatureAlgorithm(name); err == nil {
+			t.Fatalf("%s should not be built in", name)
+		}
+	}
+}
+
+func TestBuildSignatureBodyEd25519Unprefixed(t *testing.T) {
+	body, err := buildSignatureBody(verifySignParams, []byte("payload"))
+	if err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+	if name, _ := SplitSignatureValue(body.SignatureValue); name != AlgorithmEd25519 || strings.Contains(body.SignatureValue, ":") {
+		t.Fatalf("ed25519 signature should have no prefix: %s", body.SignatureValue)
+	}
+
+	key, err := utils.DecodeBase64(verifySignParams.PrivateKey)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if AlgorithmKey(AlgorithmEd25519, key) != verifySignParams.PrivateKey {
+		t.Fatalf("ed25519 key should have no prefix")
+	}
+}
+
+func TestSignatureAlgorithmUnsupported(t *testing.T) {
+	params := &pki.SignatureParam{
+		Creator:    "did:axn:alice",
+		PrivateKey: "rsa:" + utils.EncodeBase64([]byte("key")),
+	}
+	if _, err := buildSignatureBody(params, []byte("payload")); err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Fatalf("unknown algorithm should fail, got %v", err)
+	}
+}
+
+type testAlgorithm struct{}
+
+func (testAlgorithm) Name() string { return "test" }
+
+func (testAlgorithm) Sign(privateKey, payload []byte) ([]byte, error) {
+	return append(append([]byte{}, privateKey...), payload...), nil
+}
+
+func (testAlgorithm) Verify(publicKey, payload, signature []byte) error { return nil }
+
+func TestRegisterSignatureAlgorithm(t *testing.T) {
+	RegisterSignatureAlgorithm(testAlgorithm{})
+	defer func() {
+		algorithms.Lock()
+		delete(algorithms.m, "test")
+		algorithms.Unlock()
+	}()
+
+	signer, err := NewKeySigner(&pki.SignatureParam{
+		Creator:    "did:axn:alice",
+		PrivateKey: AlgorithmKey("test", []byte("k-")),
+	})
+	if err != nil {
+		t.Fatalf("new key signer fail: %v", err)
+	}
+	body, err := signer.Sign([]byte("payload"))
+	if err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+	if body.SignatureValue != "test:"+utils.EncodeBase64([]byte("k-payload")) {
+		t.Fatalf("signature value mismatch: %s", body.SignatureValue)
+	}
+
+	// the raw signatures of the signers keep the algorithm
+	client := &WalletClient{}
+	client.SetSigner("did:axn:alice", signer)
+	raw, err := client.signRaw(&pki.SignatureParam{Creator: "did:axn:alice"}, []byte("payload"))
+	if err != nil {
+		t.Fatalf("raw sign fail: %v", err)
+	}
+	if raw.SignatureValue != "test:k-payload" {
+		t.Fatalf("raw signature value mismatch: %s", raw.SignatureValue)
+	}
+}
+
+func TestEd25519Algorithm(t *testing.T) {
+	alg, err := LookupSignatureAlgorithm(AlgorithmEd25519)
+	if err != nil {
+		t.Fatalf("lookup ed25519 fail: %v", err)
+	}
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ed25519 key fail: %v", err)
+	}
+	sig, err := alg.Sign(priv, []byte("payload"))
+	if err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+	if err = alg.Verify(pub, []byte("payload"), sig); err != nil {
+		t.Fatalf("verify fail: %v", err)
+	}
+	if err = alg.Verify(pub, []byte("payload!"), sig); err == nil {
+		t.Fatalf("verify should fail on another payload")
+	}
+	if _, err = alg.Sign(priv[:32], []byte("payload")); err == nil {
+		t.Fatalf("short ed25519 key should fail")
+	}
+}
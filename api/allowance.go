@@ -0,0 +1,183 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// ApproveBody is the request body of Approve.
+//
+// The Owner authorizes the Spender to transfer up to Amount of its TokenId
+// colored tokens with TransferFrom. A new approval replaces the previous one
+// of the same owner, spender and token, a zero Amount revokes it. When
+// Expires (unix seconds) is reached, the approval is void.
+//
+type ApproveBody struct {
+	Owner   string `json:"owner"`
+	Spender string `json:"spender"`
+	TokenId string `json:"token_id"`
+	Amount  int64  `json:"amount"`
+	Expires int64  `json:"expires,omitempty"`
+}
+
+// TransferFromBody is the request body of TransferFrom.
+//
+// The Spender transfers colored tokens of the From wallet, within the
+// allowance approved by its owner.
+//
+type TransferFromBody struct {
+	Spender string                `json:"spender"`
+	From    string                `json:"from"`
+	To      string                `json:"to"`
+	Tokens  []*wallet.TokenAmount `json:"tokens"`
+	Fee     *wallet.Fee           `json:"fee,omitempty"`
+}
+
+// Allowance is the amount of colored tokens of an owner a spender is still
+// allowed to transfer.
+//
+type Allowance struct {
+	Owner   string `json:"owner"`
+	Spender string `json:"spender"`
+	TokenId string `json:"token_id"`
+	Amount  int64  `json:"amount"`
+	Expires int64  `json:"expires,omitempty"`
+}
+
+// Approve is used to authorize a spender, e.g. an operator wallet, to
+// transfer a bounded amount of the owner colored tokens with TransferFrom.
+//
+// The request is signed by the owner.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) Approve(header http.Header, body *ApproveBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.Owner == "" || body.Spender == "" || body.TokenId == "" || body.Amount < 0 {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	if body.Owner == body.Spender {
+		err = fmt.Errorf("owner cannot approve itself")
+		return
+	}
+
+	// Build request body
+	reqBody, err := w.buildWalletRequest(header, body, signParams)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.doJSON(header, "POST", "/v2/transaction/tokens/approve", nil, reqBody, &result)
+
+	return
+}
+
+// TransferFrom is used by a spender to transfer colored tokens of another
+// wallet, within the allowance approved by its owner with Approve.
+//
+// The transactions are signed by the spender. The transfer goes through the
+// same counterparty checks as TransferCToken.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) TransferFrom(header http.Header, body *TransferFromBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.Spender == "" || body.From == "" || body.To == "" || len(body.Tokens) == 0 {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+
+	transfer := &wallet.TransferCTokenBody{From: body.From, To: body.To, Tokens: body.Tokens, Fee: body.Fee}
+	if err = w.checkCounterpartyKYC(header, body.To); err != nil {
+		return
+	}
+	screened := w.screenAsync(screeningTypeCToken, body.From, body.To, transfer)
+	submitHeader, err := w.travelRuleHeader(header, transfer)
+	if err != nil {
+		return
+	}
+
+	if w.s != nil {
+		signParams, err = w.queryPrivateKey(header, signParams)
+		if err != nil {
+			return
+		}
+	}
+
+	// 1 send transfer proposal to get wallet.Tx
+	var txs []*pw.TX
+	if err = w.doJSON(header, "POST", "/v2/transaction/tokens/transfer_from/prepare", nil, body, &txs); err != nil {
+		return nil, err
+	}
+
+	// 2 sign public key as signature
+	err = w.SignTxs(txs, signParams)
+	if err != nil {
+		err = fmt.Errorf("sign Txs error: %v", err)
+		return nil, err
+	}
+
+	// wait for the screening decision before submission
+	if err = screened(); err != nil {
+		return nil, err
+	}
+
+	// 3 call ProcessTx to transfer formally
+	return w.ProcessTx(submitHeader, txs)
+}
+
+// QueryAllowance is used to query the amount of the owner tokenId colored
+// tokens the spender is still allowed to transfer.
+//
+func (w *WalletClient) QueryAllowance(header http.Header, owner, spender did.Identifier, tokenId string) (result *Allowance, err error) {
+	if owner == "" || spender == "" || tokenId == "" {
+		err = fmt.Errorf("request id invalid")
+		return
+	}
+
+	params := url.Values{}
+	params.Set("owner", string(owner))
+	params.Set("spender", string(spender))
+	params.Set("token_id", tokenId)
+
+	err = w.doJSON(header, "GET", "/v2/transaction/tokens/allowance", params, nil, &result)
+
+	return
+}
@@ -0,0 +1,128 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestApproveSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/tokens/approve").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletResponse{Id: "approve-tx-001"}))
+
+	body := &ApproveBody{Owner: "did:axn:001", Spender: "did:axn:operator", TokenId: "ctoken-001", Amount: 100}
+	resp, err := client.Approve(http.Header{}, body, verifySignParams)
+	if err != nil {
+		t.Fatalf("approve fail: %v", err)
+	}
+	if resp == nil || resp.Id != "approve-tx-001" {
+		t.Fatalf("response id should be approve-tx-001")
+	}
+
+	body.Spender = body.Owner
+	if _, err = client.Approve(http.Header{}, body, verifySignParams); err == nil {
+		t.Fatalf("err should not be nil when the owner approves itself")
+	}
+}
+
+func TestTransferFromSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const spender = did.Identifier("did:axn:operator")
+	signer := &fakeSigner{creator: spender}
+	client.SetSigner(spender, signer)
+
+	script, err := json.Marshal(&pw.UTXOSignature{PublicKey: []byte("public-key")})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	txs := []map[string]interface{}{{
+		"founder": string(spender),
+		"txout":   []map[string]interface{}{{"script": script}},
+	}}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/tokens/transfer_from/prepare").
+		Reply(200).
+		JSON(payloadResponse(t, txs))
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/process").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletResponse{Id: "transfer-tx-001"}))
+
+	body := &TransferFromBody{
+		Spender: string(spender),
+		From:    "did:axn:001",
+		To:      "did:axn:002",
+		Tokens:  []*wallet.TokenAmount{{TokenId: "ctoken-001", Amount: 10}},
+	}
+	resp, err := client.TransferFrom(http.Header{}, body, &pki.SignatureParam{Creator: spender})
+	if err != nil {
+		t.Fatalf("transfer from fail: %v", err)
+	}
+	if resp == nil || resp.Id != "transfer-tx-001" {
+		t.Fatalf("response id should be transfer-tx-001")
+	}
+	if len(signer.signed) != 1 {
+		t.Fatalf("transfer should be signed by the spender")
+	}
+}
+
+func TestQueryAllowanceSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/transaction/tokens/allowance").
+		MatchParam("owner", "did:axn:001").
+		MatchParam("spender", "did:axn:operator").
+		MatchParam("token_id", "ctoken-001").
+		Reply(200).
+		JSON(payloadResponse(t, &Allowance{Owner: "did:axn:001", Spender: "did:axn:operator", TokenId: "ctoken-001", Amount: 90}))
+
+	allowance, err := client.QueryAllowance(http.Header{}, "did:axn:001", "did:axn:operator", "ctoken-001")
+	if err != nil {
+		t.Fatalf("query allowance fail: %v", err)
+	}
+	if allowance == nil || allowance.Amount != 90 {
+		t.Fatalf("allowance should be 90")
+	}
+	if _, err = client.QueryAllowance(http.Header{}, "did:axn:001", "", "ctoken-001"); err == nil {
+		t.Fatalf("err should not be nil when the spender is not set")
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+)
+
+// Asset provenance event types, in the Type field of AssetEvent.
+//
+const (
+	AssetEventIssue    = "issue"
+	AssetEventTransfer = "transfer"
+	AssetEventExchange = "exchange"
+)
+
+// AssetInfo describes a digital asset.
+//
+type AssetInfo struct {
+	Id       string         `json:"id"`
+	Name     string         `json:"name"`
+	Hash     string         `json:"hash"`
+	Metadata []byte         `json:"metadata"`
+	Issuer   did.Identifier `json:"issuer"`
+	// Owner is the wallet currently owning the asset
+	Owner  did.Identifier `json:"owner"`
+	Status pw.Status      `json:"status"`
+	// Created and Updated are the times of the issuance and of the last
+	// change of owner, in unix seconds
+	Created int64 `json:"created"`
+	Updated int64 `json:"updated"`
+}
+
+// AssetEvent is a change of owner of a digital asset.
+//
+type AssetEvent struct {
+	// Type is one of the AssetEvent* constants
+	Type string `json:"type"`
+	// From is the previous owner, empty for the issuance
+	From           did.Identifier `json:"from"`
+	To             did.Identifier `json:"to"`
+	TransactionIds []string       `json:"transaction_ids"`
+	// Timestamp is the time of the change, in unix seconds
+	Timestamp int64 `json:"timestamp"`
+}
+
+// QueryAsset is used to query the metadata and the current owner of a digital
+// asset.
+//
+func (w *WalletClient) QueryAsset(header http.Header, assetID string) (result *AssetInfo, err error) {
+	if assetID == "" {
+		err = fmt.Errorf("asset id invalid")
+		return
+	}
+
+	err = w.doJSON(header, "GET", "/v2/asset", url.Values{"id": {assetID}}, nil, &result)
+
+	return
+}
+
+// QueryAssetHistory is used to query the provenance of a digital asset, every
+// change of its owner from the issuance to the latest transfer.
+//
+// A buyer can check, before accepting a transfer, that the asset comes from
+// the expected issuer and that the seller is its last owner:
+//
+//	history, err := client.QueryAssetHistory(header, assetID)
+//	if err != nil {
+//		return err
+//	}
+//	if len(history) == 0 || history[0].To != issuer || history[len(history)-1].To != seller {
+//		return fmt.Errorf("asset %s provenance invalid", assetID)
+//	}
+//
+func (w *WalletClient) QueryAssetHistory(header http.Header, assetID string) (result []*AssetEvent, err error) {
+	if assetID == "" {
+		err = fmt.Errorf("asset id invalid")
+		return
+	}
+
+	err = w.doJSON(header, "GET", "/v2/asset/history", url.Values{"id": {assetID}}, nil, &result)
+
+	return
+}
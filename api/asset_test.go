@@ -0,0 +1,81 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestQueryAsset(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/asset").
+		MatchParam("id", "asset-001").
+		Reply(200).
+		JSON(payloadResponse(t, &AssetInfo{
+			Id:     "asset-001",
+			Issuer: "did:axn:issuer-001",
+			Owner:  "did:axn:owner-002",
+		}))
+
+	asset, err := client.QueryAsset(http.Header{}, "asset-001")
+	if err != nil {
+		t.Fatalf("query asset fail: %v", err)
+	}
+	if asset.Issuer != "did:axn:issuer-001" || asset.Owner != "did:axn:owner-002" {
+		t.Fatalf("asset should be returned, got %+v", asset)
+	}
+
+	if _, err = client.QueryAsset(http.Header{}, ""); err == nil {
+		t.Fatalf("err should not be nil without asset id")
+	}
+}
+
+func TestQueryAssetHistory(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/asset/history").
+		MatchParam("id", "asset-001").
+		Reply(200).
+		JSON(payloadResponse(t, []*AssetEvent{
+			{Type: AssetEventIssue, To: "did:axn:owner-001", Timestamp: 100},
+			{Type: AssetEventTransfer, From: "did:axn:owner-001", To: "did:axn:owner-002", Timestamp: 200},
+		}))
+
+	history, err := client.QueryAssetHistory(http.Header{}, "asset-001")
+	if err != nil {
+		t.Fatalf("query asset history fail: %v", err)
+	}
+	if len(history) != 2 || history[0].Type != AssetEventIssue || history[1].From != "did:axn:owner-001" {
+		t.Fatalf("asset history should be returned, got %+v", history)
+	}
+
+	if _, err = client.QueryAssetHistory(http.Header{}, ""); err == nil {
+		t.Fatalf("err should not be nil without asset id")
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+)
+
+// attachmentNameField is the upload form field of the attachment name.
+const attachmentNameField = "attachment_name"
+
+// POEAttachment is a named file attached to POE digital asset, besides its
+// main file uploaded with UploadPOEFile.
+//
+type POEAttachment struct {
+	PoeId did.Identifier `json:"poe_id"`
+	// Name identifies the attachment among the attachments of the POE
+	Name string `json:"name"`
+	// FileName is the name of the uploaded file
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// Hash is the sha256 hash of the file, hex encoded
+	Hash     string `json:"hash"`
+	ReadOnly bool   `json:"read_only"`
+	// Uploaded is the upload time, in unix seconds
+	Uploaded       int64    `json:"uploaded"`
+	TransactionIds []string `json:"transaction_ids"`
+}
+
+// AttachPOEFile is used to attach a named file to specified POE digital
+// asset, e.g. one of the supporting documents of a certificate. The file
+// contents are read from r, streamed like UploadPOEFileFromReader.
+//
+// name parameter identifies the attachment among the attachments of the
+// POE, attaching a file with the name of an existing attachment replaces it
+// unless the attachment is read only. fileName parameter is the name of the
+// uploaded file. size parameter is the number of bytes to upload, or -1 if
+// unknown.
+//
+func (w *WalletClient) AttachPOEFile(header http.Header, poeID did.Identifier, name string, fileName string, size int64, r io.Reader, readOnly bool) (result *POEAttachment, err error) {
+	if poeID == "" {
+		err = fmt.Errorf("poe id must be set when attaching poe file")
+		return
+	}
+	if name == "" {
+		err = fmt.Errorf("attachment name must be set when attaching poe file")
+		return
+	}
+	if fileName == "" {
+		err = fmt.Errorf("file name must be set when attaching poe file")
+		return
+	}
+	if r == nil {
+		err = fmt.Errorf("reader must be set when attaching poe file")
+		return
+	}
+
+	form := &poeForm{poeID: string(poeID), fileName: fileName, readOnly: readOnly, attachment: name}
+	err = w.uploadPOEForm(header, "/v2/poe/attachments", form, r, size, &result)
+
+	return
+}
+
+// ListPOEAttachments is used to list the attachments of POE digital asset.
+//
+func (w *WalletClient) ListPOEAttachments(header http.Header, poeID did.Identifier) (result []*POEAttachment, err error) {
+	if poeID == "" {
+		err = fmt.Errorf("poe id invalid")
+		return
+	}
+
+	err = w.doJSON(header, "GET", "/v2/poe/attachments", url.Values{"id": {string(poeID)}}, nil, &result)
+
+	return
+}
+
+// DeletePOEAttachment is used to delete an attachment of POE digital asset,
+// read only attachments cannot be deleted.
+//
+func (w *WalletClient) DeletePOEAttachment(header http.Header, poeID did.Identifier, name string) (err error) {
+	if poeID == "" || name == "" {
+		err = fmt.Errorf("poe id and attachment name must be set")
+		return
+	}
+
+	params := url.Values{}
+	params.Set("id", string(poeID))
+	params.Set("name", name)
+	err = w.doJSON(header, "DELETE", "/v2/poe/attachments", params, nil, nil)
+
+	return
+}
@@ -0,0 +1,100 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestAttachPOEFile(t *testing.T) {
+	respBody, err := json.Marshal(payloadResponse(t, &POEAttachment{
+		PoeId:    "did:axn:poe-001",
+		Name:     "annex-1",
+		FileName: "annex.pdf",
+		Size:     7,
+	}))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	transport := &formTransport{fields: map[string]string{}, resp: respBody}
+	client, err := NewWalletClient(&restapi.Config{
+		Address:    "http://127.0.0.1:8006",
+		HttpClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+
+	attachment, err := client.AttachPOEFile(http.Header{}, "did:axn:poe-001", "annex-1", "annex.pdf", 7, strings.NewReader("annex 1"), true)
+	if err != nil {
+		t.Fatalf("attach poe file fail: %v", err)
+	}
+	if attachment.Name != "annex-1" || attachment.Size != 7 {
+		t.Fatalf("attachment should be returned, got %+v", attachment)
+	}
+	if transport.fields[attachmentNameField] != "annex-1" || transport.fields[wallet.OffchainPOEID] != "did:axn:poe-001" ||
+		transport.fields[wallet.OffchainReadOnly] != "true" || transport.fields[wallet.OffchainPOEFile] != "annex 1" {
+		t.Fatalf("attachment form should be uploaded, got %v", transport.fields)
+	}
+
+	if _, err = client.AttachPOEFile(http.Header{}, "did:axn:poe-001", "", "annex.pdf", 7, strings.NewReader("annex 1"), true); err == nil {
+		t.Fatalf("err should not be nil without attachment name")
+	}
+}
+
+func TestPOEAttachments(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/poe/attachments").
+		MatchParam("id", "did:axn:poe-001").
+		Reply(200).
+		JSON(payloadResponse(t, []*POEAttachment{
+			{Name: "annex-1", FileName: "annex.pdf"},
+			{Name: "annex-2", FileName: "photo.png", ContentType: "image/png"},
+		}))
+	gock.New("http://127.0.0.1:8006").
+		Delete("/v2/poe/attachments").
+		MatchParam("id", "did:axn:poe-001").
+		MatchParam("name", "annex-2").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletResponse{}))
+
+	attachments, err := client.ListPOEAttachments(http.Header{}, "did:axn:poe-001")
+	if err != nil {
+		t.Fatalf("list poe attachments fail: %v", err)
+	}
+	if len(attachments) != 2 || attachments[1].ContentType != "image/png" {
+		t.Fatalf("attachments should be listed, got %+v", attachments)
+	}
+	if err = client.DeletePOEAttachment(http.Header{}, "did:axn:poe-001", "annex-2"); err != nil {
+		t.Fatalf("delete poe attachment fail: %v", err)
+	}
+	if err = client.DeletePOEAttachment(http.Header{}, "did:axn:poe-001", ""); err == nil {
+		t.Fatalf("err should not be nil without attachment name")
+	}
+}
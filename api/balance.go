@@ -0,0 +1,94 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// BalanceAmount is the amount of a colored token or a digital asset
+// held by a wallet.
+//
+type BalanceAmount struct {
+	Id     string `json:"id"`
+	Amount int64  `json:"amount"`
+}
+
+// WalletBalances lists the colored token and digital asset balances
+// of a wallet, sorted by id.
+//
+type WalletBalances struct {
+	Id     did.Identifier   `json:"id"`
+	Tokens []*BalanceAmount `json:"tokens"`
+	Assets []*BalanceAmount `json:"assets"`
+}
+
+// TokenIds returns the ids of the colored tokens held by the wallet.
+//
+func (b *WalletBalances) TokenIds() []string {
+	return balanceIds(b.Tokens)
+}
+
+// AssetIds returns the ids of the digital assets held by the wallet.
+//
+func (b *WalletBalances) AssetIds() []string {
+	return balanceIds(b.Assets)
+}
+
+// QueryWalletBalance is used to query the colored token and digital asset
+// balances of a wallet.
+//
+func (w *WalletClient) QueryWalletBalance(header http.Header, id did.Identifier) (result *WalletBalances, err error) {
+	balance, err := w.GetWalletBalance(header, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result = &WalletBalances{Id: id}
+	if balance != nil {
+		result.Tokens = balanceAmounts(balance.ColoredTokens)
+		result.Assets = balanceAmounts(balance.DigitalAssets)
+	}
+
+	return
+}
+
+func balanceAmounts(balances map[string]*wallet.Balance) []*BalanceAmount {
+	amounts := make([]*BalanceAmount, 0, len(balances))
+	for id, balance := range balances {
+		if balance == nil {
+			continue
+		}
+		amounts = append(amounts, &BalanceAmount{Id: id, Amount: balance.Amount})
+	}
+	sort.Slice(amounts, func(i, j int) bool {
+		return amounts[i].Id < amounts[j].Id
+	})
+	return amounts
+}
+
+func balanceIds(amounts []*BalanceAmount) []string {
+	ids := make([]string, len(amounts))
+	for i, amount := range amounts {
+		ids[i] = amount.Id
+	}
+	return ids
+}
@@ -0,0 +1,130 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestQueryWalletBalanceSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		id    = did.Identifier("did:axn:001")
+		token = "user-token-001"
+	)
+
+	//build response body
+	payload := &wallet.WalletBalance{
+		ColoredTokens: map[string]*wallet.Balance{
+			"colored-token-002": {Id: "colored-token-002", Amount: 200},
+			"colored-token-001": {Id: "colored-token-001", Amount: 5000},
+		},
+		DigitalAssets: map[string]*wallet.Balance{
+			"asset-id-001": {Id: "asset-id-001", Amount: 1},
+		},
+	}
+	byPayload, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody := &rtstructs.Response{
+		ErrCode: 0,
+		Payload: string(byPayload),
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/balance").
+		MatchParam("id", string(id)).
+		MatchHeader("X-Auth-Token", token).
+		Reply(200).
+		JSON(respBody)
+
+	//set header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	//do query wallet balance
+	result, err := client.QueryWalletBalance(header, id)
+	if err != nil {
+		t.Fatalf("query wallet balance fail: %v", err)
+	}
+	if result == nil || result.Id != id {
+		t.Fatalf("wallet balances of %v should be returned", id)
+	}
+	if ids := result.TokenIds(); !reflect.DeepEqual(ids, []string{"colored-token-001", "colored-token-002"}) {
+		t.Fatalf("token ids should be sorted, got %v", ids)
+	}
+	if result.Tokens[0].Amount != 5000 || result.Tokens[1].Amount != 200 {
+		t.Fatalf("token amounts mismatch: %v, %v", result.Tokens[0], result.Tokens[1])
+	}
+	if ids := result.AssetIds(); !reflect.DeepEqual(ids, []string{"asset-id-001"}) {
+		t.Fatalf("asset ids mismatch, got %v", ids)
+	}
+}
+
+func TestQueryWalletBalanceFail(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		id      = did.Identifier("did:axn:001")
+		token   = "user-token-001"
+		errCode = 8000
+		errMsg  = "wallet not found"
+	)
+
+	//build response body
+	respBody := &rtstructs.Response{
+		ErrCode:    errCode,
+		ErrMessage: errMsg,
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/balance").
+		MatchParam("id", string(id)).
+		Reply(200).
+		JSON(respBody)
+
+	//set header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	//do query wallet balance
+	result, err := client.QueryWalletBalance(header, id)
+	if err == nil {
+		t.Fatalf("err should not be nil when query fail")
+	}
+	if result != nil {
+		t.Fatalf("result should be nil when query fail")
+	}
+}
@@ -0,0 +1,320 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// DefaultBatchSize is the default maximum number of items sent in one batch
+// request, larger batches are split in several requests.
+//
+const DefaultBatchSize = 500
+
+// ErrTravelRuleBatch is the error of the batch transfers which reach the
+// travel-rule threshold, the travel-rule envelope is attached per request,
+// so they must be sent with TransferCToken.
+//
+var ErrTravelRuleBatch = fmt.Errorf("transfer reaches the travel rule threshold, send it with TransferCToken")
+
+// BatchItemResult is the result of one item of a batch request.
+//
+type BatchItemResult struct {
+	// Index is the index of the item in the batch
+	Index int
+	// Id is the id of the transaction or of the asset of the item, if it
+	// succeeded
+	Id string
+	// Err is the error of the item, nil if it succeeded
+	Err error
+}
+
+// BatchResult holds the results of the items of a batch request, in the
+// order of the items.
+//
+type BatchResult struct {
+	Items []*BatchItemResult
+}
+
+// Failed returns the results of the items which failed.
+//
+func (r *BatchResult) Failed() []*BatchItemResult {
+	var failed []*BatchItemResult
+	for _, item := range r.Items {
+		if item.Err != nil {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// Succeeded returns the number of items which succeeded.
+//
+func (r *BatchResult) Succeeded() int {
+	return len(r.Items) - len(r.Failed())
+}
+
+func newBatchResult(n int) *BatchResult {
+	result := &BatchResult{Items: make([]*BatchItemResult, n)}
+	for i := range result.Items {
+		result.Items[i] = &BatchItemResult{Index: i}
+	}
+	return result
+}
+
+// batchItemResponse is the result of one item in a batch response, index
+// is the index of the item in the request.
+type batchItemResponse struct {
+	Index   int    `json:"index"`
+	Id      string `json:"id"`
+	ErrCode int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type batchResponse struct {
+	Results []*batchItemResponse `json:"results"`
+}
+
+type transferCTokenBatchBody struct {
+	Transfers []*wallet.TransferCTokenBody `json:"transfers"`
+}
+
+type issueAssetBatchBody struct {
+	Assets []*wallet.IssueAssetBody `json:"assets"`
+}
+
+type poeBatchBody struct {
+	POEs []*wallet.POEBody `json:"poes"`
+}
+
+// SetBatchSize sets the maximum number of items sent in one batch request,
+// zero means DefaultBatchSize.
+//
+func (w *WalletClient) SetBatchSize(size int) {
+	w.batchSize = size
+}
+
+func (w *WalletClient) getBatchSize() int {
+	if w.batchSize > 0 {
+		return w.batchSize
+	}
+	return DefaultBatchSize
+}
+
+// TransferCTokenBatch is used to transfer colored tokens to many recipients
+// in as few requests as possible, the transfers are signed once per request.
+//
+// The transfers are split in requests of the batch size, see SetBatchSize.
+// Each transfer goes through the same counterparty checks as TransferCToken,
+// the transfers reaching the travel-rule threshold fail with
+// ErrTravelRuleBatch.
+//
+// The result holds the transaction id or the error of every transfer, err
+// is only returned when transfers is empty.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+func (w *WalletClient) TransferCTokenBatch(header http.Header, transfers []*wallet.TransferCTokenBody, signParams *pki.SignatureParam) (result *BatchResult, err error) {
+	if len(transfers) == 0 {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+
+	result = newBatchResult(len(transfers))
+	screened := make([]func() error, len(transfers))
+	for i, body := range transfers {
+		item := result.Items[i]
+		if body == nil {
+			item.Err = fmt.Errorf("request payload invalid")
+			continue
+		}
+		if w.travelRuleApplies(body) {
+			item.Err = ErrTravelRuleBatch
+			continue
+		}
+		if item.Err = w.checkCounterpartyKYC(header, body.To); item.Err != nil {
+			continue
+		}
+		screened[i] = w.screenAsync(screeningTypeCToken, body.From, body.To, body)
+	}
+
+	// wait for the screening decisions before submission
+	var indexes []int
+	for i, wait := range screened {
+		if wait == nil {
+			continue
+		}
+		if result.Items[i].Err = wait(); result.Items[i].Err == nil {
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) == 0 {
+		return result, nil
+	}
+
+	w.submitBatch(header, "/v1/transaction/tokens/transfer/batch", indexes, func(chunk []int) interface{} {
+		body := &transferCTokenBatchBody{Transfers: make([]*wallet.TransferCTokenBody, len(chunk))}
+		for i, index := range chunk {
+			body.Transfers[i] = transfers[index]
+		}
+		return body
+	}, signParams, result)
+
+	return result, nil
+}
+
+// IssueAssetBatch is used to issue many digital assets in as few requests as
+// possible, the assets are signed once per request.
+//
+// The assets are split in requests of the batch size, see SetBatchSize.
+//
+// The result holds the asset id or the error of every asset, err is only
+// returned when assets is empty.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+func (w *WalletClient) IssueAssetBatch(header http.Header, assets []*wallet.IssueAssetBody, signParams *pki.SignatureParam) (result *BatchResult, err error) {
+	if len(assets) == 0 {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+
+	result = newBatchResult(len(assets))
+	var indexes []int
+	for i, body := range assets {
+		if body == nil {
+			result.Items[i].Err = fmt.Errorf("request payload invalid")
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+	if len(indexes) == 0 {
+		return result, nil
+	}
+
+	w.submitBatch(header, "/v1/transaction/assets/issue/batch", indexes, func(chunk []int) interface{} {
+		body := &issueAssetBatchBody{Assets: make([]*wallet.IssueAssetBody, len(chunk))}
+		for i, index := range chunk {
+			body.Assets[i] = assets[index]
+		}
+		return body
+	}, signParams, result)
+
+	return result, nil
+}
+
+// CreatePOEBatch is used to create many POE digital assets in as few
+// requests as possible, the POEs are signed once per request.
+//
+// The POEs are split in requests of the batch size, see SetBatchSize.
+//
+// The result holds the POE id or the error of every POE, err is only
+// returned when poes is empty.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) CreatePOEBatch(header http.Header, poes []*wallet.POEBody, signParams *pki.SignatureParam) (result *BatchResult, err error) {
+	if len(poes) == 0 {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+
+	result = newBatchResult(len(poes))
+	var indexes []int
+	for i, body := range poes {
+		if body == nil {
+			result.Items[i].Err = fmt.Errorf("request payload invalid")
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+	if len(indexes) == 0 {
+		return result, nil
+	}
+
+	w.submitBatch(header, "/v1/poe/create/batch", indexes, func(chunk []int) interface{} {
+		body := &poeBatchBody{POEs: make([]*wallet.POEBody, len(chunk))}
+		for i, index := range chunk {
+			body.POEs[i] = poes[index]
+		}
+		return body
+	}, signParams, result)
+
+	return result, nil
+}
+
+// submitBatch sends the items at indexes in requests of the batch size, the
+// body of each request built by chunkBody and signed once, and sets the
+// results of the items. A failed request fails all its items.
+func (w *WalletClient) submitBatch(header http.Header, path string, indexes []int, chunkBody func(chunk []int) interface{}, signParams *pki.SignatureParam, result *BatchResult) {
+	size := w.getBatchSize()
+	for lo := 0; lo < len(indexes); lo += size {
+		hi := lo + size
+		if hi > len(indexes) {
+			hi = len(indexes)
+		}
+		chunk := indexes[lo:hi]
+		if err := w.submitBatchChunk(header, path, chunk, chunkBody(chunk), signParams, result); err != nil {
+			for _, index := range chunk {
+				result.Items[index].Err = err
+			}
+		}
+	}
+}
+
+func (w *WalletClient) submitBatchChunk(header http.Header, path string, chunk []int, body interface{}, signParams *pki.SignatureParam, result *BatchResult) error {
+	// Build request body
+	reqBody, err := w.buildWalletRequest(header, body, signParams)
+	if err != nil {
+		return err
+	}
+
+	var resp batchResponse
+	if err = w.doJSON(header, "POST", path, nil, reqBody, &resp); err != nil {
+		return err
+	}
+
+	done := make([]bool, len(chunk))
+	for _, res := range resp.Results {
+		if res == nil || res.Index < 0 || res.Index >= len(chunk) || done[res.Index] {
+			continue
+		}
+		done[res.Index] = true
+		item := result.Items[chunk[res.Index]]
+		if res.ErrCode != 0 || res.Message != "" {
+			item.Err = &Error{Method: "POST", Endpoint: path, StatusCode: http.StatusOK, ErrCode: res.ErrCode, Message: res.Message}
+			continue
+		}
+		item.Id = res.Id
+	}
+	for i, ok := range done {
+		if !ok {
+			result.Items[chunk[i]].Err = fmt.Errorf("no result returned for the batch item")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestTransferCTokenBatch(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+	client.SetBatchSize(2)
+
+	transfers := []*wallet.TransferCTokenBody{
+		{From: "did:axn:001", To: "did:axn:002"},
+		nil,
+		{From: "did:axn:001", To: "did:axn:003"},
+		{From: "did:axn:001", To: "did:axn:004"},
+	}
+
+	// first request with transfers 0 and 2, second with transfer 3
+	gock.New("http://127.0.0.1:8006").
+		Post("/v1/transaction/tokens/transfer/batch").
+		Reply(200).
+		JSON(payloadResponse(t, &batchResponse{Results: []*batchItemResponse{
+			{Index: 0, Id: "tx-000"},
+			{Index: 1, ErrCode: 8000, Message: "insufficient balance"},
+		}}))
+	gock.New("http://127.0.0.1:8006").
+		Post("/v1/transaction/tokens/transfer/batch").
+		Reply(200).
+		JSON(payloadResponse(t, &batchResponse{Results: []*batchItemResponse{
+			{Index: 0, Id: "tx-003"},
+		}}))
+
+	result, err := client.TransferCTokenBatch(http.Header{}, transfers, verifySignParams)
+	if err != nil {
+		t.Fatalf("transfer batch fail: %v", err)
+	}
+	if !gock.IsDone() {
+		t.Fatalf("transfers should be sent in two requests")
+	}
+	if len(result.Items) != 4 || result.Succeeded() != 2 {
+		t.Fatalf("two transfers should succeed, not %d", result.Succeeded())
+	}
+	if result.Items[0].Id != "tx-000" || result.Items[3].Id != "tx-003" {
+		t.Fatalf("transaction ids mismatch")
+	}
+	if result.Items[1].Err == nil {
+		t.Fatalf("invalid transfer should fail")
+	}
+	apiErr, ok := result.Items[2].Err.(*Error)
+	if !ok || apiErr.ErrCode != 8000 {
+		t.Fatalf("rejected transfer should fail with the gateway error, not %v", result.Items[2].Err)
+	}
+	if failed := result.Failed(); len(failed) != 2 || failed[0].Index != 1 || failed[1].Index != 2 {
+		t.Fatalf("transfers 1 and 2 should fail")
+	}
+}
+
+func TestTransferCTokenBatchRequestFail(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	if _, err := client.TransferCTokenBatch(http.Header{}, nil, verifySignParams); err == nil {
+		t.Fatalf("err should not be nil when there is no transfer")
+	}
+
+	gock.New("http://127.0.0.1:8006").
+		Post("/v1/transaction/tokens/transfer/batch").
+		Reply(503)
+
+	transfers := []*wallet.TransferCTokenBody{
+		{From: "did:axn:001", To: "did:axn:002"},
+		{From: "did:axn:001", To: "did:axn:003"},
+	}
+	result, err := client.TransferCTokenBatch(http.Header{}, transfers, verifySignParams)
+	if err != nil {
+		t.Fatalf("transfer batch fail: %v", err)
+	}
+	for _, item := range result.Items {
+		if item.Err == nil {
+			t.Fatalf("transfers of a failed request should fail")
+		}
+	}
+}
+
+func TestIssueAssetBatch(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Post("/v1/transaction/assets/issue/batch").
+		Reply(200).
+		JSON(payloadResponse(t, &batchResponse{Results: []*batchItemResponse{
+			{Index: 1, Id: "did:axn:asset-002"},
+			{Index: 0, ErrCode: 8001, Message: "asset already exists"},
+		}}))
+
+	assets := []*wallet.IssueAssetBody{
+		{Issuer: "did:axn:001", Owner: "did:axn:002", AssetId: "did:axn:asset-001"},
+		{Issuer: "did:axn:001", Owner: "did:axn:002", AssetId: "did:axn:asset-002"},
+		{Issuer: "did:axn:001", Owner: "did:axn:002", AssetId: "did:axn:asset-003"},
+	}
+	result, err := client.IssueAssetBatch(http.Header{}, assets, verifySignParams)
+	if err != nil {
+		t.Fatalf("issue asset batch fail: %v", err)
+	}
+	if result.Items[1].Err != nil || result.Items[1].Id != "did:axn:asset-002" {
+		t.Fatalf("asset 1 should be issued")
+	}
+	if result.Items[0].Err == nil {
+		t.Fatalf("asset 0 should fail")
+	}
+	if result.Items[2].Err == nil {
+		t.Fatalf("asset 2 should fail when the response has no result for it")
+	}
+}
+
+func TestCreatePOEBatch(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+	client.SetBatchSize(2)
+
+	gock.New("http://127.0.0.1:8006").
+		Post("/v1/poe/create/batch").
+		Reply(200).
+		JSON(payloadResponse(t, &batchResponse{Results: []*batchItemResponse{
+			{Index: 0, Id: "did:axn:poe-001"},
+			{Index: 1, ErrCode: 8001, Message: "poe already exists"},
+		}}))
+	gock.New("http://127.0.0.1:8006").
+		Post("/v1/poe/create/batch").
+		Reply(200).
+		JSON(payloadResponse(t, &batchResponse{Results: []*batchItemResponse{
+			{Index: 0, Id: "did:axn:poe-004"},
+		}}))
+
+	poes := []*wallet.POEBody{
+		{Name: "certificate-001", Owner: "did:axn:001", Hash: "hash-001"},
+		{Name: "certificate-002", Owner: "did:axn:001", Hash: "hash-002"},
+		nil,
+		{Name: "certificate-004", Owner: "did:axn:001", Hash: "hash-004"},
+	}
+	result, err := client.CreatePOEBatch(http.Header{}, poes, verifySignParams)
+	if err != nil {
+		t.Fatalf("create poe batch fail: %v", err)
+	}
+	if result.Items[0].Err != nil || result.Items[0].Id != "did:axn:poe-001" {
+		t.Fatalf("poe 0 should be created")
+	}
+	if result.Items[1].Err == nil || result.Items[2].Err == nil {
+		t.Fatalf("poe 1 and nil poe 2 should fail")
+	}
+	if result.Items[3].Err != nil || result.Items[3].Id != "did:axn:poe-004" {
+		t.Fatalf("poe 3 should be created in the second request")
+	}
+	if result.Succeeded() != 2 {
+		t.Fatalf("2 poes should succeed, got %d", result.Succeeded())
+	}
+
+	if _, err = client.CreatePOEBatch(http.Header{}, nil, verifySignParams); err == nil {
+		t.Fatalf("err should not be nil without poes")
+	}
+}
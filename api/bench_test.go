@@ -0,0 +1,87 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+var benchSignParams = &pki.SignatureParam{
+	Creator:    "did:axn:001",
+	Nonce:      "helloalice",
+	PrivateKey: "WBZNmTTf34Kg+pQOTSIRL+JeQYDfj7InWc0A/9kvNvQSI8Ue8iRD8gn9CNmGO2EjJILF/3RELmEcbuS5G0d+Mg==",
+}
+
+var benchTransferBody = &wallet.TransferCTokenBody{
+	From: "did:axn:001",
+	To:   "did:axn:002",
+	Tokens: []*wallet.TokenAmount{
+		{TokenId: "ctoken-001", Amount: 100},
+		{TokenId: "ctoken-002", Amount: 200},
+	},
+}
+
+func BenchmarkBuildSignatureBody(b *testing.B) {
+	data := []byte(`{"from":"did:axn:001","to":"did:axn:002","tokens":[{"token_id":"ctoken-001","amount":100}]}`)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := buildSignatureBody(benchSignParams, data); err != nil {
+			b.Fatalf("%v", err)
+		}
+	}
+}
+
+func BenchmarkBuildWalletRequest(b *testing.B) {
+	client := &WalletClient{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.buildWalletRequest(nil, benchTransferBody, benchSignParams); err != nil {
+			b.Fatalf("%v", err)
+		}
+	}
+}
+
+func BenchmarkPrepareCreatePOE(b *testing.B) {
+	client := &WalletClient{}
+	body := &wallet.POEBody{
+		Name:     "piaoju001",
+		Owner:    "did:axn:001",
+		Metadata: []byte("this is metadata"),
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.PrepareCreatePOE(nil, body, benchSignParams); err != nil {
+			b.Fatalf("%v", err)
+		}
+	}
+}
+
+func BenchmarkCanonicalJSON(b *testing.B) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := CanonicalJSON(benchTransferBody); err != nil {
+			b.Fatalf("%v", err)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending the request while the circuit
+// breaker of the client is open, see SetCircuitBreaker. It is retryable.
+//
+var ErrCircuitOpen = fmt.Errorf("wallet gateway circuit open, request not sent")
+
+// Defaults of the circuit breaker settings.
+//
+const (
+	DefaultBreakerFailureThreshold = 5
+	DefaultBreakerOpenTimeout      = 30 * time.Second
+	DefaultBreakerHalfOpenProbes   = 1
+)
+
+// BreakerConfig sets the circuit breaker of a wallet client, zero values
+// are replaced with the defaults.
+//
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures opening the
+	// circuit
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before probing the
+	// gateway again
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of concurrent requests let through to
+	// probe the gateway, the first success closes the circuit and a failure
+	// opens it again
+	HalfOpenProbes int
+}
+
+// CircuitState is the state of the circuit breaker of a wallet client.
+//
+type CircuitState int
+
+// Circuit breaker states.
+//
+const (
+	// CircuitClosed lets the requests through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails the requests with ErrCircuitOpen
+	CircuitOpen
+	// CircuitHalfOpen lets probe requests through
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+//
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// circuitBreaker counts the consecutive failures of the requests, shared by
+// the copies of the client made with WithContext
+type circuitBreaker struct {
+	config BreakerConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probes   int
+	// generation changes with the state, the outcome of a request allowed
+	// in a previous state is ignored
+	generation uint64
+}
+
+func newCircuitBreaker(config BreakerConfig) *circuitBreaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultBreakerFailureThreshold
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = DefaultBreakerOpenTimeout
+	}
+	if config.HalfOpenProbes <= 0 {
+		config.HalfOpenProbes = DefaultBreakerHalfOpenProbes
+	}
+	return &circuitBreaker{config: config, now: time.Now}
+}
+
+// setState changes the state, the lock must be held.
+func (b *circuitBreaker) setState(state CircuitState) {
+	b.state = state
+	b.failures = 0
+	b.probes = 0
+	b.generation++
+	if state == CircuitOpen {
+		b.openedAt = b.now()
+	}
+}
+
+// allow returns the generation of a request allowed to be sent, or
+// ErrCircuitOpen.
+func (b *circuitBreaker) allow() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen {
+		if b.now().Sub(b.openedAt) < b.config.OpenTimeout {
+			return 0, ErrCircuitOpen
+		}
+		b.setState(CircuitHalfOpen)
+	}
+	if b.state == CircuitHalfOpen {
+		if b.probes >= b.config.HalfOpenProbes {
+			return 0, ErrCircuitOpen
+		}
+		b.probes++
+	}
+	return b.generation, nil
+}
+
+// done records the outcome of a request allowed in generation, abandoned
+// requests are neither successes nor failures. It returns the new state and
+// whether it changed.
+func (b *circuitBreaker) done(generation uint64, failed, abandoned bool) (CircuitState, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation != b.generation {
+		return b.state, false
+	}
+	switch {
+	case abandoned:
+		if b.state == CircuitHalfOpen {
+			b.probes--
+		}
+		return b.state, false
+	case !failed:
+		if b.state == CircuitHalfOpen {
+			b.setState(CircuitClosed)
+			return b.state, true
+		}
+		b.failures = 0
+		return b.state, false
+	case b.state == CircuitHalfOpen:
+		b.setState(CircuitOpen)
+		return b.state, true
+	}
+	b.failures++
+	if b.failures >= b.config.FailureThreshold {
+		b.setState(CircuitOpen)
+		return b.state, true
+	}
+	return b.state, false
+}
+
+// currentState returns the state, an open circuit whose timeout expired is
+// reported half-open.
+func (b *circuitBreaker) currentState() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.config.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// SetCircuitBreaker enables the circuit breaker of the client, nil disables
+// it, which is the default.
+//
+// While the gateway is unhealthy, i.e. after FailureThreshold consecutive
+// requests failed to reach it or got a server error status, the requests
+// fail fast with ErrCircuitOpen instead of waiting for timeouts. After
+// OpenTimeout, probe requests are let through and the first success closes
+// the circuit. The breaker is shared with the copies of the client made with
+// WithContext afterwards.
+//
+func (w *WalletClient) SetCircuitBreaker(config *BreakerConfig) {
+	if config == nil {
+		w.breaker = nil
+		return
+	}
+	w.breaker = newCircuitBreaker(*config)
+}
+
+// CircuitState returns the state of the circuit breaker of the client,
+// CircuitClosed if it has none.
+//
+func (w *WalletClient) CircuitState() CircuitState {
+	if w.breaker == nil {
+		return CircuitClosed
+	}
+	return w.breaker.currentState()
+}
+
+// breakerDone records the outcome of a request sent with the breaker.
+func (w *WalletClient) breakerDone(generation uint64, resp *http.Response, err error) {
+	abandoned := err != nil && w.ctx != nil && w.ctx.Err() != nil
+	failed := err != nil || resp.StatusCode >= http.StatusInternalServerError
+	state, changed := w.breaker.done(generation, failed, abandoned)
+	if !changed {
+		return
+	}
+	if state == CircuitOpen {
+		w.logger().Warnf("wallet gateway circuit open for %v", w.breaker.config.OpenTimeout)
+		return
+	}
+	w.logger().Infof("wallet gateway circuit %s", state)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+	client.SetCircuitBreaker(&BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute})
+	defer client.SetCircuitBreaker(nil)
+	now := time.Now()
+	client.breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		gock.New("http://127.0.0.1:8006").
+			Get("/v1/wallet/balance").
+			Reply(503)
+		if _, err := client.GetWalletBalance(http.Header{}, "did:axn:001"); err == nil || err == ErrCircuitOpen {
+			t.Fatalf("request %d should fail with the gateway error, got %v", i, err)
+		}
+	}
+	if client.CircuitState() != CircuitOpen {
+		t.Fatalf("circuit should be open, got %v", client.CircuitState())
+	}
+	if _, err := client.GetWalletBalance(http.Header{}, "did:axn:001"); err != ErrCircuitOpen {
+		t.Fatalf("err should be ErrCircuitOpen, got %v", err)
+	}
+	if ClassifyError(ErrCircuitOpen) != ErrorRetryable {
+		t.Fatalf("ErrCircuitOpen should be retryable")
+	}
+
+	// a failed probe opens the circuit again
+	now = now.Add(time.Minute)
+	if client.CircuitState() != CircuitHalfOpen {
+		t.Fatalf("circuit should be half-open, got %v", client.CircuitState())
+	}
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/balance").
+		Reply(500)
+	if _, err := client.GetWalletBalance(http.Header{}, "did:axn:001"); err == nil || err == ErrCircuitOpen {
+		t.Fatalf("probe should fail with the gateway error, got %v", err)
+	}
+	if client.CircuitState() != CircuitOpen {
+		t.Fatalf("circuit should be open again, got %v", client.CircuitState())
+	}
+
+	// a successful probe closes it
+	now = now.Add(time.Minute)
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/balance").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletBalance{}))
+	if _, err := client.GetWalletBalance(http.Header{}, "did:axn:001"); err != nil {
+		t.Fatalf("probe should succeed, got %v", err)
+	}
+	if client.CircuitState() != CircuitClosed {
+		t.Fatalf("circuit should be closed, got %v", client.CircuitState())
+	}
+}
+
+func TestCircuitBreakerProbes(t *testing.T) {
+	b := newCircuitBreaker(BreakerConfig{FailureThreshold: 1, HalfOpenProbes: 2})
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	gen, err := b.allow()
+	if err != nil {
+		t.Fatalf("closed circuit should allow requests")
+	}
+	if state, changed := b.done(gen, true, false); state != CircuitOpen || !changed {
+		t.Fatalf("failure should open the circuit")
+	}
+
+	now = now.Add(DefaultBreakerOpenTimeout)
+	g1, err1 := b.allow()
+	g2, err2 := b.allow()
+	if err1 != nil || err2 != nil {
+		t.Fatalf("half-open circuit should allow 2 probes")
+	}
+	if _, err = b.allow(); err != ErrCircuitOpen {
+		t.Fatalf("third probe should fail with ErrCircuitOpen, got %v", err)
+	}
+
+	// an abandoned probe frees its slot
+	b.done(g1, true, true)
+	g3, err := b.allow()
+	if err != nil {
+		t.Fatalf("abandoned probe slot should be free")
+	}
+	if state, _ := b.done(g2, false, false); state != CircuitClosed {
+		t.Fatalf("successful probe should close the circuit")
+	}
+	// outcome of a probe of the previous state is ignored
+	if state, changed := b.done(g3, true, false); state != CircuitClosed || changed {
+		t.Fatalf("stale outcome should be ignored")
+	}
+}
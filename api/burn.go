@@ -0,0 +1,108 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// BurnCTokenBody is the request body of BurnCToken.
+//
+// The tokens are destroyed from the Owner wallet, e.g. redeemed points.
+//
+type BurnCTokenBody struct {
+	Owner  string                `json:"owner"`
+	Tokens []*wallet.TokenAmount `json:"tokens"`
+	Memo   string                `json:"memo,omitempty"`
+	Fee    *wallet.Fee           `json:"fee,omitempty"`
+}
+
+// BurnCToken is used to destroy colored tokens of the owner.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) BurnCToken(header http.Header, body *BurnCTokenBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	txs, err := w.BurnCTokenSign(header, body, signParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3 call ProcessTx to burn formally
+	return w.ProcessTx(header, txs)
+}
+
+// BurnCTokenSign is used to get the signed transactions of BurnCToken
+// without processing them, they are submitted later with ProcessTx.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) BurnCTokenSign(header http.Header, body *BurnCTokenBody, signParams *pki.SignatureParam) (txs []*pw.TX, err error) {
+	if body == nil || body.Owner == "" || len(body.Tokens) == 0 {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+
+	if w.s != nil {
+		signParams, err = w.queryPrivateKey(header, signParams)
+		if err != nil {
+			return
+		}
+	}
+
+	// 1 send burn proposal to get wallet.Tx
+	txs, err = w.SendBurnCTokenProposal(header, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2 sign public key as signature
+	err = w.SignTxs(txs, signParams)
+	if err != nil {
+		err = fmt.Errorf("sign Txs error: %v", err)
+		return nil, err
+	}
+	return txs, nil
+}
+
+// SendBurnCTokenProposal is used to send burn colored token proposal to get wallet.Tx to be signed.
+//
+func (w *WalletClient) SendBurnCTokenProposal(header http.Header, body *BurnCTokenBody) (result []*pw.TX, err error) {
+	if body == nil {
+		err = fmt.Errorf("request payload invalid")
+		return nil, err
+	}
+
+	err = w.doJSON(header, "POST", "/v2/transaction/tokens/burn/prepare", nil, body, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, err
+}
@@ -0,0 +1,89 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestBurnCTokenSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const owner = did.Identifier("did:axn:001")
+	signer := &fakeSigner{creator: owner}
+	client.SetSigner(owner, signer)
+
+	script, err := json.Marshal(&pw.UTXOSignature{PublicKey: []byte("public-key")})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	txs := []map[string]interface{}{{
+		"founder": string(owner),
+		"txout":   []map[string]interface{}{{"script": script}},
+	}}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/tokens/burn/prepare").
+		Reply(200).
+		JSON(payloadResponse(t, txs))
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/process").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletResponse{Id: "burn-tx-001"}))
+
+	body := &BurnCTokenBody{
+		Owner:  string(owner),
+		Tokens: []*wallet.TokenAmount{{TokenId: "ctoken-001", Amount: 10}},
+	}
+	resp, err := client.BurnCToken(http.Header{}, body, &pki.SignatureParam{Creator: owner})
+	if err != nil {
+		t.Fatalf("burn colored token fail: %v", err)
+	}
+	if resp == nil || resp.Id != "burn-tx-001" {
+		t.Fatalf("response id should be burn-tx-001")
+	}
+	if len(signer.signed) != 1 {
+		t.Fatalf("burn transaction should be signed by the owner")
+	}
+}
+
+func TestBurnCTokenInvalidBody(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	resp, err := client.BurnCToken(http.Header{}, &BurnCTokenBody{Owner: "did:axn:001"}, verifySignParams)
+	if err == nil {
+		t.Fatalf("err should not be nil when no token is burnt")
+	}
+	if resp != nil {
+		t.Fatalf("response object should be nil when no token is burnt")
+	}
+}
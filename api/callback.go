@@ -0,0 +1,128 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+)
+
+// Callback event types, see CallbackBody.
+//
+const (
+	// CallbackEventTxConfirmed is sent when a transaction is confirmed on chain
+	CallbackEventTxConfirmed = "transaction.confirmed"
+	// CallbackEventTxFailed is sent when a transaction is rejected
+	CallbackEventTxFailed = "transaction.failed"
+)
+
+// CallbackBody is the request body of RegisterCallback and UpdateCallback.
+//
+type CallbackBody struct {
+	WalletId did.Identifier `json:"wallet_id"`
+	// URL receives the events of the wallet in POST requests
+	URL string `json:"url"`
+	// Events are the event types sent to URL, all of them if empty
+	Events []string `json:"events,omitempty"`
+}
+
+// CallbackRegistration is the callback registered for a wallet.
+//
+type CallbackRegistration struct {
+	WalletId did.Identifier `json:"wallet_id"`
+	URL      string         `json:"url"`
+	Events   []string       `json:"events,omitempty"`
+	// Secret signs the events sent to URL, it is only returned by
+	// RegisterCallback, see the webhook package to verify the events
+	Secret  string `json:"secret,omitempty"`
+	Created int64  `json:"created"`
+	Updated int64  `json:"updated"`
+}
+
+func (b *CallbackBody) valid() error {
+	if b == nil || b.WalletId == "" {
+		return fmt.Errorf("request payload invalid")
+	}
+	return checkCallbackURL(b.URL)
+}
+
+// checkCallbackURL checks a callback URL is an absolute http or https URL.
+func checkCallbackURL(callbackURL string) error {
+	u, err := url.Parse(callbackURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("callback url %q invalid", callbackURL)
+	}
+	return nil
+}
+
+// RegisterCallback is used to register the URL receiving the blockchain
+// transaction events of a wallet, instead of passing it with every call in
+// the Callback-Url header. The returned secret signs the events, it must be
+// kept by the receiver to verify them.
+//
+func (w *WalletClient) RegisterCallback(header http.Header, body *CallbackBody) (result *CallbackRegistration, err error) {
+	if err = body.valid(); err != nil {
+		return
+	}
+
+	err = w.doJSON(header, "POST", "/v2/wallet/callback", nil, body, &result)
+
+	return
+}
+
+// UpdateCallback is used to update the URL or the event types of the
+// callback of a wallet, the signing secret is kept.
+//
+func (w *WalletClient) UpdateCallback(header http.Header, body *CallbackBody) (result *CallbackRegistration, err error) {
+	if err = body.valid(); err != nil {
+		return
+	}
+
+	err = w.doJSON(header, "PUT", "/v2/wallet/callback", nil, body, &result)
+
+	return
+}
+
+// QueryCallback is used to query the callback registered for a wallet.
+//
+func (w *WalletClient) QueryCallback(header http.Header, walletID did.Identifier) (result *CallbackRegistration, err error) {
+	if walletID == "" {
+		err = fmt.Errorf("wallet id invalid")
+		return
+	}
+
+	err = w.doJSON(header, "GET", "/v2/wallet/callback", url.Values{"id": {string(walletID)}}, nil, &result)
+
+	return
+}
+
+// DeleteCallback is used to remove the callback of a wallet, its events are
+// no longer sent.
+//
+func (w *WalletClient) DeleteCallback(header http.Header, walletID did.Identifier) (err error) {
+	if walletID == "" {
+		err = fmt.Errorf("wallet id invalid")
+		return
+	}
+
+	err = w.doJSON(header, "DELETE", "/v2/wallet/callback", url.Values{"id": {string(walletID)}}, nil, nil)
+
+	return
+}
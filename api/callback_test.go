@@ -0,0 +1,78 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestRegisterCallback(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	body := &CallbackBody{
+		WalletId: "did:axn:wallet-001",
+		URL:      "https://example.com/wallet/events",
+		Events:   []string{CallbackEventTxConfirmed},
+	}
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/wallet/callback").
+		JSON(body).
+		Reply(200).
+		JSON(payloadResponse(t, &CallbackRegistration{WalletId: body.WalletId, URL: body.URL, Events: body.Events, Secret: "secret-001"}))
+
+	reg, err := client.RegisterCallback(http.Header{}, body)
+	if err != nil {
+		t.Fatalf("register callback fail: %v", err)
+	}
+	if reg.Secret != "secret-001" || reg.URL != body.URL {
+		t.Fatalf("callback registration should be returned, got %+v", reg)
+	}
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/wallet/callback").
+		MatchParam("id", "did:axn:wallet-001").
+		Reply(200).
+		JSON(payloadResponse(t, &CallbackRegistration{WalletId: body.WalletId, URL: body.URL}))
+	if reg, err = client.QueryCallback(http.Header{}, "did:axn:wallet-001"); err != nil || reg.URL != body.URL {
+		t.Fatalf("query callback fail: %v", err)
+	}
+
+	gock.New("http://127.0.0.1:8006").
+		Delete("/v2/wallet/callback").
+		MatchParam("id", "did:axn:wallet-001").
+		Reply(200).
+		JSON(payloadResponse(t, struct{}{}))
+	if err = client.DeleteCallback(http.Header{}, "did:axn:wallet-001"); err != nil {
+		t.Fatalf("delete callback fail: %v", err)
+	}
+
+	for _, invalid := range []*CallbackBody{
+		nil,
+		{WalletId: "did:axn:wallet-001", URL: "ftp://example.com"},
+		{URL: "https://example.com"},
+	} {
+		if _, err = client.UpdateCallback(http.Header{}, invalid); err == nil {
+			t.Fatalf("err should not be nil for %+v", invalid)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	cases := []struct {
+		in, out string
+	}{
+		{`{"b": 1, "a": {"d": [1, 2], "c": null}}`, `{"a":{"c":null,"d":[1,2]},"b":1}`},
+		{`{"html": "<a&b>", "unicode": "é"}`, `{"html":"<a&b>","unicode":"é"}`},
+		{`[12345678901234567890, -0, 1.50, 1e3, 0.0, 1e-7, 1.5e21]`, `[12345678901234567890,0,1.5,1000,0,1e-7,1.5e+21]`},
+		{`"line\nbreak"`, `"line\nbreak"`},
+		{`"q\"b\\s\/t\tc\u0001\u0008l\u2028\u00e9"`, `"q\"b\\s/t\tc\u0001\u0008l\u2028é"`},
+		{"\"bad\xffutf8\"", "\"bad\ufffdutf8\""},
+	}
+	for _, c := range cases {
+		out, err := Canonicalize([]byte(c.in))
+		if err != nil {
+			t.Fatalf("canonicalize %s fail: %v", c.in, err)
+		}
+		if string(out) != c.out {
+			t.Fatalf("canonical form of %s should be %s, not %s", c.in, c.out, out)
+		}
+	}
+	if _, err := Canonicalize([]byte(`{"a":1} {}`)); err == nil {
+		t.Fatalf("err should not be nil for trailing data")
+	}
+}
+
+func TestCanonicalString(t *testing.T) {
+	for _, s := range []string{"", "plain", "<a&b>", "quote\" back\\", "\x00\x1f\n\r\t", "é\u2028\u2029€", "bad\xff\xfe"} {
+		var buf bytes.Buffer
+		writeCanonicalString(&buf, s)
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		mark := buf.Len()
+		enc.Encode(s)
+		if expected := buf.String()[mark : buf.Len()-1]; buf.String()[:mark] != expected {
+			t.Fatalf("canonical string %q should be %s, not %s", s, expected, buf.String()[:mark])
+		}
+	}
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	body := &struct {
+		To     string   `json:"to"`
+		From   string   `json:"from"`
+		Amount float64  `json:"amount"`
+		Tags   []string `json:"tags"`
+	}{To: "did:axn:002", From: "did:axn:001", Amount: 10}
+	fromStruct, err := CanonicalJSON(body)
+	if err != nil {
+		t.Fatalf("canonical json fail: %v", err)
+	}
+	fromMap, err := CanonicalJSON(map[string]interface{}{
+		"from":   "did:axn:001",
+		"amount": 10.0,
+		"to":     "did:axn:002",
+		"tags":   nil,
+	})
+	if err != nil {
+		t.Fatalf("canonical json fail: %v", err)
+	}
+	if string(fromStruct) != string(fromMap) {
+		t.Fatalf("struct and map encodings should match: %s != %s", fromStruct, fromMap)
+	}
+}
+
+func TestBuildWalletRequestCanonical(t *testing.T) {
+	initWalletClient(t)
+	client := walletClient.(*WalletClient)
+
+	body := map[string]interface{}{"name": "<poe>", "id": "did:axn:poe"}
+	reqBody, err := client.buildWalletRequest(http.Header{}, body, verifySignParams)
+	if err != nil {
+		t.Fatalf("build wallet request fail: %v", err)
+	}
+	if reqBody.Payload != `{"id":"did:axn:poe","name":"<poe>"}` {
+		t.Fatalf("request payload should be canonical, not %s", reqBody.Payload)
+	}
+}
@@ -0,0 +1,345 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// DefaultUploadChunkSize is the chunk size used when none is given to
+// StartChunkedUpload.
+//
+const DefaultUploadChunkSize = 4 << 20
+
+// DefaultUploadConcurrency is the number of chunks uploaded at the same
+// time when none is set with SetUploadConcurrency.
+//
+const DefaultUploadConcurrency = 1
+
+// UploadSession tracks a chunked POE file upload. It is JSON encodable, so
+// it can be persisted to continue the upload after the process restarts.
+//
+type UploadSession struct {
+	Id        string `json:"id"`
+	PoeId     string `json:"poe_id"`
+	FileName  string `json:"file_name"`
+	Size      int64  `json:"size"`
+	ChunkSize int64  `json:"chunk_size"`
+	Chunks    int    `json:"chunks"`
+	FileHash  string `json:"file_hash"`
+	ReadOnly  bool   `json:"read_only"`
+	// Uploaded lists the indexes of the chunks received by the gateway
+	Uploaded []int `json:"uploaded"`
+}
+
+// Done reports whether all the chunks have been uploaded.
+//
+func (s *UploadSession) Done() bool {
+	return len(s.Uploaded) == s.Chunks
+}
+
+// chunkLen returns the size of the chunk of index i.
+func (s *UploadSession) chunkLen(i int) int64 {
+	n := s.Size - int64(i)*s.ChunkSize
+	if n > s.ChunkSize {
+		n = s.ChunkSize
+	}
+	return n
+}
+
+// uploadedBytes returns the size of the uploaded chunks.
+func (s *UploadSession) uploadedBytes() (n int64) {
+	for _, index := range s.Uploaded {
+		n += s.chunkLen(index)
+	}
+	return n
+}
+
+// uploadSessionStatus is the upload session state kept by the gateway
+type uploadSessionStatus struct {
+	Id       string `json:"id"`
+	Received []int  `json:"received"`
+}
+
+// completeUploadBody is the request body completing an upload session
+type completeUploadBody struct {
+	Id       string `json:"id"`
+	FileHash string `json:"file_hash"`
+}
+
+// StartChunkedUpload is used to start a chunked upload of the file for
+// specified POE digital asset. The file is uploaded with ContinueChunkedUpload.
+//
+// poeID parameter is the POE digital asset ID pre-created using CreatePOE API.
+//
+// poeFile parameter is the path to file to be uploaded, it must not change
+// until the upload is completed. chunkSize parameter is the size of the
+// uploaded chunks, DefaultUploadChunkSize if not positive.
+//
+func (w *WalletClient) StartChunkedUpload(header http.Header, poeID string, poeFile string, chunkSize int64, readOnly bool) (session *UploadSession, err error) {
+	if poeID == "" {
+		err = fmt.Errorf("poe id must be set when uploading poe file")
+		return
+	}
+	if poeFile == "" {
+		err = fmt.Errorf("poe file must be set when uploading poe file")
+		return
+	}
+	if chunkSize <= 0 {
+		chunkSize = DefaultUploadChunkSize
+	}
+
+	// Hash the whole file, checked by the gateway on completion
+	f, err := os.Open(poeFile)
+	if err != nil {
+		if w.privacy {
+			err = fmt.Errorf("open %s file fail", w.sensitive(poeFile))
+		}
+		return
+	}
+	defer f.Close()
+	h := sha256.New()
+	copyBuf := getCopyBuffer()
+	size, err := io.CopyBuffer(h, f, *copyBuf)
+	putCopyBuffer(copyBuf)
+	if err != nil {
+		return
+	}
+
+	session = &UploadSession{
+		PoeId:     poeID,
+		FileName:  poeFile,
+		Size:      size,
+		ChunkSize: chunkSize,
+		Chunks:    int((size + chunkSize - 1) / chunkSize),
+		FileHash:  hex.EncodeToString(h.Sum(nil)),
+		ReadOnly:  readOnly,
+		Uploaded:  []int{},
+	}
+
+	var status *uploadSessionStatus
+	if err = w.doJSON(header, "POST", "/v1/poe/upload/session", nil, session, &status); err != nil {
+		return nil, err
+	}
+	if status == nil || status.Id == "" {
+		return nil, fmt.Errorf("upload session id invalid")
+	}
+	session.Id = status.Id
+
+	return session, nil
+}
+
+// ContinueChunkedUpload is used to upload the chunks of session not received
+// by the gateway yet, then to complete the upload. It can be called again
+// with the same session after an interruption, the uploaded chunks are
+// recorded in session as they are acknowledged.
+//
+// Each chunk is sent with its sha256 checksum, verified by the gateway.
+// Chunks are uploaded in parallel as set with SetUploadConcurrency, the
+// gateway reassembles them in order. progress, if not nil, is called after
+// each uploaded chunk, never concurrently.
+//
+func (w *WalletClient) ContinueChunkedUpload(header http.Header, session *UploadSession, poeFile string, progress func(uploaded, chunks int)) (result *wallet.UploadResponse, err error) {
+	if session == nil || session.Id == "" {
+		err = fmt.Errorf("upload session invalid")
+		return
+	}
+
+	f, err := os.Open(poeFile)
+	if err != nil {
+		if w.privacy {
+			err = fmt.Errorf("open %s file fail", w.sensitive(poeFile))
+		}
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return
+	}
+	if info.Size() != session.Size {
+		err = fmt.Errorf("file size changed: %d bytes, %d expected", info.Size(), session.Size)
+		return
+	}
+
+	// Sync with the chunks received by the gateway
+	if err = w.syncUploadSession(header, session); err != nil {
+		return
+	}
+
+	received := make(map[int]bool, len(session.Uploaded))
+	for _, index := range session.Uploaded {
+		received[index] = true
+	}
+	pending := []int{}
+	for index := 0; index < session.Chunks; index++ {
+		if !received[index] {
+			pending = append(pending, index)
+		}
+	}
+	err = w.uploadChunks(header, session, f, pending, progress)
+	sort.Ints(session.Uploaded)
+	if err != nil {
+		return nil, err
+	}
+
+	// Complete the upload
+	if err = w.doJSON(header, "POST", "/v1/poe/upload/complete", nil, &completeUploadBody{Id: session.Id, FileHash: session.FileHash}, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UploadPOEFileChunked is used to upload file for specified POE digital asset
+// in chunks, it starts the upload session and uploads all the chunks.
+//
+// On failure the returned session, if not nil, can be passed to
+// ContinueChunkedUpload to continue the upload.
+//
+func (w *WalletClient) UploadPOEFileChunked(header http.Header, poeID string, poeFile string, chunkSize int64, readOnly bool) (result *wallet.UploadResponse, session *UploadSession, err error) {
+	session, err = w.StartChunkedUpload(header, poeID, poeFile, chunkSize, readOnly)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err = w.ContinueChunkedUpload(header, session, poeFile, nil)
+	return result, session, err
+}
+
+// SetUploadConcurrency sets the number of chunks uploaded at the same time
+// by ContinueChunkedUpload and UploadPOEFileChunked, zero means
+// DefaultUploadConcurrency. A few parallel uploads can use the bandwidth
+// available better than a single stream.
+//
+func (w *WalletClient) SetUploadConcurrency(n int) {
+	w.chunkWorkers = n
+}
+
+func (w *WalletClient) getUploadConcurrency() int {
+	if w.chunkWorkers > 0 {
+		return w.chunkWorkers
+	}
+	return DefaultUploadConcurrency
+}
+
+// uploadChunks uploads the pending chunks of session read from f, with up
+// to the upload concurrency of the client chunks in flight. It stops at the
+// first error, the uploaded chunks are recorded in session.
+func (w *WalletClient) uploadChunks(header http.Header, session *UploadSession, f io.ReaderAt, pending []int, progress func(uploaded, chunks int)) error {
+	workers := w.getUploadConcurrency()
+	if workers > len(pending) {
+		workers = len(pending)
+	}
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		sent     = session.uploadedBytes()
+		wg       sync.WaitGroup
+	)
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+	indexes := make(chan int)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			chunk := make([]byte, session.ChunkSize)
+			for index := range indexes {
+				n, err := f.ReadAt(chunk[:session.chunkLen(index)], int64(index)*session.ChunkSize)
+				if err == nil || err == io.EOF {
+					err = w.uploadChunk(header, session.Id, index, chunk[:n])
+				}
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+				} else {
+					session.Uploaded = append(session.Uploaded, index)
+					sent += int64(n)
+					if progress != nil {
+						progress(len(session.Uploaded), session.Chunks)
+					}
+					if w.upProgress != nil {
+						w.upProgress(sent, session.Size)
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, index := range pending {
+		if failed() {
+			break
+		}
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	return firstErr
+}
+
+// syncUploadSession updates the uploaded chunks of session with the ones
+// received by the gateway.
+func (w *WalletClient) syncUploadSession(header http.Header, session *UploadSession) error {
+	var status *uploadSessionStatus
+	if err := w.doJSON(header, "GET", "/v1/poe/upload/session", url.Values{"id": {session.Id}}, nil, &status); err != nil {
+		return err
+	}
+	uploaded := []int{}
+	if status != nil {
+		seen := make(map[int]bool, len(status.Received))
+		for _, index := range status.Received {
+			if index >= 0 && index < session.Chunks && !seen[index] {
+				seen[index] = true
+				uploaded = append(uploaded, index)
+			}
+		}
+	}
+	sort.Ints(uploaded)
+	session.Uploaded = uploaded
+	return nil
+}
+
+// uploadChunk uploads a chunk with its sha256 checksum.
+func (w *WalletClient) uploadChunk(header http.Header, sessionID string, index int, data []byte) error {
+	sum := sha256.Sum256(data)
+
+	params := url.Values{}
+	params.Set("id", sessionID)
+	params.Set("index", strconv.Itoa(index))
+	params.Set("checksum", hex.EncodeToString(sum[:]))
+
+	header = ApplyOptions(header, WithHeader("Content-Type", "application/octet-stream"))
+	return w.doJSON(header, "PUT", "/v1/poe/upload/chunk", params, data, nil)
+}
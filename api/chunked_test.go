@@ -0,0 +1,262 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+// payloadResponse returns the gateway response carrying payload
+func payloadResponse(t *testing.T, payload interface{}) *rtstructs.Response {
+	byPayload, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	return &rtstructs.Response{Payload: string(byPayload)}
+}
+
+func TestChunkedUploadResume(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		token     = "user-token-001"
+		poeID     = "did:axn:poe-id-001"
+		sessionID = "session-001"
+		content   = "0123456789"
+	)
+
+	tmpfile, err := ioutil.TempFile("", "test")
+	if err != nil {
+		t.Fatalf("create tmp file fail: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	tmpfile.WriteString(content)
+	tmpfile.Close()
+
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	// start the session
+	gock.New("http://127.0.0.1:8006").
+		Post("/v1/poe/upload/session").
+		MatchHeader("X-Auth-Token", token).
+		Reply(200).
+		JSON(payloadResponse(t, &uploadSessionStatus{Id: sessionID}))
+
+	session, err := client.StartChunkedUpload(header, poeID, tmpfile.Name(), 4, false)
+	if err != nil {
+		t.Fatalf("start chunked upload fail: %v", err)
+	}
+	fileHash := sha256.Sum256([]byte(content))
+	if session.Id != sessionID || session.Chunks != 3 || session.Size != int64(len(content)) || session.FileHash != hex.EncodeToString(fileHash[:]) {
+		t.Fatalf("upload session mismatch: %#v", session)
+	}
+
+	// the second chunk fails
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/poe/upload/session").
+		MatchParam("id", sessionID).
+		Reply(200).
+		JSON(payloadResponse(t, &uploadSessionStatus{Id: sessionID, Received: []int{}}))
+	gock.New("http://127.0.0.1:8006").
+		Put("/v1/poe/upload/chunk").
+		MatchParam("index", "^0$").
+		Reply(200).
+		JSON(payloadResponse(t, struct{}{}))
+	gock.New("http://127.0.0.1:8006").
+		Put("/v1/poe/upload/chunk").
+		MatchParam("index", "^1$").
+		Reply(503).
+		JSON(&rtstructs.Response{})
+
+	if _, err = client.ContinueChunkedUpload(header, session, tmpfile.Name(), nil); err == nil {
+		t.Fatalf("err should not be nil when a chunk upload fails")
+	}
+	if !reflect.DeepEqual(session.Uploaded, []int{0}) || session.Done() {
+		t.Fatalf("only the first chunk should be uploaded, got %v", session.Uploaded)
+	}
+
+	// the upload continues with the missing chunks
+	lastSum := sha256.Sum256([]byte(content[8:]))
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/poe/upload/session").
+		MatchParam("id", sessionID).
+		Reply(200).
+		JSON(payloadResponse(t, &uploadSessionStatus{Id: sessionID, Received: []int{0, 0, 5}}))
+	gock.New("http://127.0.0.1:8006").
+		Put("/v1/poe/upload/chunk").
+		MatchParam("index", "^1$").
+		Reply(200).
+		JSON(payloadResponse(t, struct{}{}))
+	gock.New("http://127.0.0.1:8006").
+		Put("/v1/poe/upload/chunk").
+		MatchParam("index", "^2$").
+		MatchParam("checksum", hex.EncodeToString(lastSum[:])).
+		Reply(200).
+		JSON(payloadResponse(t, struct{}{}))
+	gock.New("http://127.0.0.1:8006").
+		Post("/v1/poe/upload/complete").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.UploadResponse{Id: poeID}))
+
+	var progress []int
+	result, err := client.ContinueChunkedUpload(header, session, tmpfile.Name(), func(uploaded, chunks int) {
+		progress = append(progress, uploaded)
+	})
+	if err != nil {
+		t.Fatalf("continue chunked upload fail: %v", err)
+	}
+	if result == nil || result.Id != poeID {
+		t.Fatalf("response POE asset id should be %v", poeID)
+	}
+	if !session.Done() || !reflect.DeepEqual(progress, []int{2, 3}) {
+		t.Fatalf("all chunks should be uploaded, got %v, progress %v", session.Uploaded, progress)
+	}
+	if !gock.IsDone() {
+		t.Fatalf("all the chunks should be uploaded once")
+	}
+}
+
+func TestChunkedUploadFileChanged(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	tmpfile, err := createFile()
+	if err != nil {
+		t.Fatalf("create tmp file fail: %v", err)
+	}
+	defer os.Remove(tmpfile)
+
+	session := &UploadSession{Id: "session-001", Size: 1, ChunkSize: 1, Chunks: 1}
+	if _, err = client.ContinueChunkedUpload(http.Header{}, session, tmpfile, nil); err == nil {
+		t.Fatalf("err should not be nil when the file size changed")
+	}
+}
+
+// chunkTransport serves an upload session, recording the uploaded chunks
+// and the maximum number of chunks uploaded at the same time
+type chunkTransport struct {
+	mu          sync.Mutex
+	chunks      map[int][]byte
+	inFlight    int
+	maxInFlight int
+}
+
+func (c *chunkTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var payload interface{} = struct{}{}
+	switch req.URL.Path {
+	case "/v1/poe/upload/session":
+		payload = &uploadSessionStatus{Id: "session-001", Received: []int{}}
+	case "/v1/poe/upload/chunk":
+		data, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		index, _ := strconv.Atoi(req.URL.Query().Get("index"))
+		c.mu.Lock()
+		c.inFlight++
+		if c.inFlight > c.maxInFlight {
+			c.maxInFlight = c.inFlight
+		}
+		c.mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		c.mu.Lock()
+		c.inFlight--
+		c.chunks[index] = data
+		c.mu.Unlock()
+	case "/v1/poe/upload/complete":
+		payload = &wallet.UploadResponse{Id: "did:axn:poe-id-001"}
+	}
+	byPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(&rtstructs.Response{Payload: string(byPayload)})
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestChunkedUploadParallel(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	tmpfile, err := ioutil.TempFile("", "test")
+	if err != nil {
+		t.Fatalf("create tmp file fail: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	tmpfile.Write(content)
+	tmpfile.Close()
+
+	transport := &chunkTransport{chunks: map[int][]byte{}}
+	client, err := NewWalletClient(&restapi.Config{
+		Address:    "http://127.0.0.1:8006",
+		HttpClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+	client.SetUploadConcurrency(4)
+
+	session := &UploadSession{Id: "session-001", Size: int64(len(content)), ChunkSize: 64, Chunks: 16}
+	var progress []int
+	result, err := client.ContinueChunkedUpload(http.Header{}, session, tmpfile.Name(), func(uploaded, chunks int) {
+		progress = append(progress, uploaded)
+	})
+	if err != nil {
+		t.Fatalf("continue chunked upload fail: %v", err)
+	}
+	if result == nil || !session.Done() || len(progress) != 16 || progress[15] != 16 {
+		t.Fatalf("all chunks should be uploaded, got %v, progress %v", session.Uploaded, progress)
+	}
+	if transport.maxInFlight < 2 || transport.maxInFlight > 4 {
+		t.Fatalf("up to 4 chunks should be uploaded at the same time, got %d", transport.maxInFlight)
+	}
+	var reassembled []byte
+	for index := 0; index < session.Chunks; index++ {
+		reassembled = append(reassembled, transport.chunks[index]...)
+	}
+	if !bytes.Equal(reassembled, content) {
+		t.Fatalf("reassembled chunks should match the file")
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// Client is the interface of the wallet, POE, colored token and digital
+// asset APIs of WalletClient. Code depending on it can be tested offline
+// with the fake of the mocks package.
+//
+type Client interface {
+	wallet.IWalletClient
+
+	QueryWalletInfo(header http.Header, id did.Identifier) (*wallet.WalletInfo, error)
+	QueryWalletBalance(header http.Header, id did.Identifier) (*WalletBalances, error)
+	QueryTransactionLogsPage(header http.Header, query *TransactionLogsQuery) (*TransactionLogsPage, error)
+	TransactionLogs(header http.Header, query *TransactionLogsQuery) *TransactionLogIterator
+	RevokePOE(header http.Header, body *RevokePOEBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	QueryPOEHistory(header http.Header, id did.Identifier) ([]*POEVersion, error)
+	VerifyPOE(header http.Header, id did.Identifier, file io.Reader) (*POEVerification, error)
+	AttachPOEFile(header http.Header, poeID did.Identifier, name string, fileName string, size int64, r io.Reader, readOnly bool) (*POEAttachment, error)
+	ListPOEAttachments(header http.Header, poeID did.Identifier) ([]*POEAttachment, error)
+	DeletePOEAttachment(header http.Header, poeID did.Identifier, name string) error
+	QueryPOEList(header http.Header, owner did.Identifier, filter *POEFilter, page *PageRequest) (*POEListPage, error)
+	UploadPOEFileFromReader(header http.Header, poeID string, fileName string, size int64, r io.Reader, readOnly bool) (*wallet.UploadResponse, error)
+	UploadPOEFileEncrypted(header http.Header, poeID string, poeFile string, readOnly bool, key *POEFileKey) (*EncryptedUploadResponse, error)
+	GeneratePOEDownloadURL(header http.Header, poeID did.Identifier, ttl time.Duration) (*POEDownloadURL, error)
+	RegisterCallback(header http.Header, body *CallbackBody) (*CallbackRegistration, error)
+	UpdateCallback(header http.Header, body *CallbackBody) (*CallbackRegistration, error)
+	QueryCallback(header http.Header, walletID did.Identifier) (*CallbackRegistration, error)
+	DeleteCallback(header http.Header, walletID did.Identifier) error
+	QueryOperationStatus(header http.Header, id string) (*Operation, error)
+	ListPendingOperations(header http.Header, walletID did.Identifier) ([]*Operation, error)
+	QueryCTokenInfo(header http.Header, tokenID string) (*CTokenInfo, error)
+	ListCTokens(header http.Header, issuer did.Identifier) ([]*CTokenInfo, error)
+	QueryAsset(header http.Header, assetID string) (*AssetInfo, error)
+	QueryAssetHistory(header http.Header, assetID string) ([]*AssetEvent, error)
+	ListWalletAssets(header http.Header, id did.Identifier, page *PageRequest) (*AssetListPage, error)
+	ListWalletCTokens(header http.Header, id did.Identifier, page *PageRequest) (*CTokenListPage, error)
+	IssueNFT(header http.Header, body *IssueNFTBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	TransferNFT(header http.Header, body *TransferNFTBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	ReissueCToken(header http.Header, body *ReissueCTokenBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	SetSupplyCap(header http.Header, body *SupplyCapBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	SplitCToken(header http.Header, body *SplitCTokenBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	MergeCToken(header http.Header, body *MergeCTokenBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	TrustKeyPair(header http.Header, body *TrustKeyPairBody) (string, error)
+	UntrustKeyPair(header http.Header, id did.Identifier, securityCode string) (*KeyPair, error)
+	RotateSecurityCode(header http.Header, id did.Identifier, securityCode string) (string, error)
+	RotateWalletKey(header http.Header, id did.Identifier, newPublicKey string, newSigner Signer, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+
+	PrepareCreatePOE(header http.Header, body *wallet.POEBody, signParams *pki.SignatureParam) ([]byte, error)
+	PrepareUpdatePOE(header http.Header, body *wallet.POEBody, signParams *pki.SignatureParam) ([]byte, error)
+	PrepareIssueCToken(header http.Header, body *wallet.IssueBody, signParams *pki.SignatureParam) ([]byte, error)
+	PrepareIssueAsset(header http.Header, body *wallet.IssueAssetBody, signParams *pki.SignatureParam) ([]byte, error)
+	PrepareTransferCToken(header http.Header, body *wallet.TransferCTokenBody, signParams *pki.SignatureParam) ([]byte, error)
+	PrepareTransferAsset(header http.Header, body *wallet.TransferAssetBody, signParams *pki.SignatureParam) ([]byte, error)
+	SubmitPrepared(header http.Header, data []byte) (*wallet.WalletResponse, error)
+	SubmitSignedTransaction(header http.Header, tx *SignedTransaction) (*wallet.WalletResponse, error)
+	SubmitMultiSigTransaction(header http.Header, tx *PendingTransaction) (*wallet.WalletResponse, error)
+	BurnCToken(header http.Header, body *BurnCTokenBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	BurnCTokenSign(header http.Header, body *BurnCTokenBody, signParams *pki.SignatureParam) ([]*pw.TX, error)
+	EstimateFee(header http.Header, txType string, body interface{}) (*wallet.Fee, error)
+	TransferCTokenHTLC(header http.Header, body *HTLCTransferBody, signParams *pki.SignatureParam) (*HTLCResponse, error)
+	TransferCTokenMemo(header http.Header, body *MemoTransferBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	ClaimHTLC(header http.Header, body *ClaimHTLCBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	RefundHTLC(header http.Header, body *RefundHTLCBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	QueryHTLC(header http.Header, htlcId string) (*HTLC, error)
+	ExchangeAsset(header http.Header, body *SwapBody, buyerSignParams, sellerSignParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	Approve(header http.Header, body *ApproveBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	TransferFrom(header http.Header, body *TransferFromBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	QueryAllowance(header http.Header, owner, spender did.Identifier, tokenId string) (*Allowance, error)
+	TransferCTokenBatch(header http.Header, transfers []*wallet.TransferCTokenBody, signParams *pki.SignatureParam) (*BatchResult, error)
+	IssueAssetBatch(header http.Header, assets []*wallet.IssueAssetBody, signParams *pki.SignatureParam) (*BatchResult, error)
+	CreatePOEBatch(header http.Header, poes []*wallet.POEBody, signParams *pki.SignatureParam) (*BatchResult, error)
+	DistributeCToken(header http.Header, body *DistributeCTokenBody, opts *DistributionOptions, signParams *pki.SignatureParam) (*DistributionReport, error)
+	HoldCToken(header http.Header, body *HoldCTokenBody, signParams *pki.SignatureParam) (*HoldResponse, error)
+	CaptureHold(header http.Header, body *CaptureHoldBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	VoidHold(header http.Header, body *VoidHoldBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	VoidExpiredHolds(header http.Header, id did.Identifier, signParams *pki.SignatureParam) ([]string, error)
+	QueryHolds(header http.Header, id did.Identifier, status HoldStatus) ([]*Hold, error)
+	QueryActiveHolds(header http.Header, id did.Identifier) ([]*Hold, error)
+	Refund(header http.Header, body *RefundBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	QueryRefunds(header http.Header, originalTxID string) ([]*Refund, error)
+	OpenDispute(header http.Header, body *OpenDisputeBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	AddDisputeEvidence(header http.Header, body *DisputeEvidenceBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	ResolveDispute(header http.Header, body *ResolveDisputeBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	QueryDisputes(header http.Header, txID string) ([]*Dispute, error)
+	SetWalletKYCStatus(header http.Header, body *KYCStatusBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	QueryWalletKYCStatus(header http.Header, id did.Identifier) (*KYCStatus, error)
+	RecordConsent(header http.Header, receipt *ConsentReceipt, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	RevokeConsent(header http.Header, id did.Identifier, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	QueryConsent(header http.Header, id did.Identifier) (*ConsentReceipt, error)
+	EraseOffchainData(header http.Header, body *EraseOffchainBody, signParams *pki.SignatureParam) (*OffchainErasure, error)
+	QueryOffchainErasure(header http.Header, poeID did.Identifier) (*OffchainErasure, error)
+	VerifySignatureRemote(header http.Header, payload []byte, signParams *pki.SignatureParam) (*VerifySignatureResult, error)
+	QueryTravelRuleEnvelope(header http.Header, txID string) (*TravelRuleEnvelope, error)
+	SubscribeTransactionEvents(ctx context.Context, header http.Header, walletID did.Identifier, filter *EventFilter) (<-chan *Event, error)
+	UploadPOEFileChunked(header http.Header, poeID string, poeFile string, chunkSize int64, readOnly bool) (*wallet.UploadResponse, *UploadSession, error)
+	StartChunkedUpload(header http.Header, poeID string, poeFile string, chunkSize int64, readOnly bool) (*UploadSession, error)
+	ContinueChunkedUpload(header http.Header, session *UploadSession, poeFile string, progress func(uploaded, chunks int)) (*wallet.UploadResponse, error)
+	DownloadPOEFile(header http.Header, poeID did.Identifier, out io.Writer) (*wallet.POEPayload, error)
+	DownloadPOEFileDecrypted(header http.Header, poeID did.Identifier, out io.Writer, key *POEFileKey) (*wallet.POEPayload, *POEEncryption, error)
+	QueryTransactionStatus(header http.Header, txID string) (*TransactionReceipt, error)
+	WaitForConfirmation(ctx context.Context, header http.Header, txID string, opts *PollOptions) (*TransactionReceipt, error)
+}
+
+var _ Client = (*WalletClient)(nil)
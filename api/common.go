@@ -17,13 +17,22 @@ limitations under the License.
 package api
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	"github.com/arxanchain/sdk-go-common/crypto/sign/ed25519"
 	"github.com/arxanchain/sdk-go-common/errors"
-	"github.com/arxanchain/sdk-go-common/rest"
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
 	"github.com/arxanchain/sdk-go-common/structs/did"
 	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
 	"github.com/arxanchain/sdk-go-common/utils"
 )
 
@@ -44,41 +53,63 @@ func checkSignParams(signParams *pki.SignatureParam) error {
 	return nil
 }
 
-func buildSignature(signParams *pki.SignatureParam, data []byte) (*pki.Signature, error) {
+// buildSignature signs data with the private key of the signature params,
+// with the algorithm of the key, and returns the signature and the name of
+// the algorithm. The decoded private key is zeroed once used.
+func buildSignature(signParams *pki.SignatureParam, data []byte) (*pki.Signature, string, error) {
 	var err error
 	err = checkSignParams(signParams)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	privateKey, err := utils.DecodeBase64(signParams.PrivateKey)
+	alg, key, err := keyAlgorithm(signParams.PrivateKey)
 	if err != nil {
-		return nil, rest.CodedError(errors.SDKInvalidBase64Data, err.Error())
+		return nil, "", err
 	}
-
-	pri := &ed25519.PrivateKey{
-		PrivateKeyData: []byte(privateKey),
+	privateKey, err := SecretFromBase64(key)
+	if err != nil {
+		return nil, "", err
 	}
+	defer privateKey.Zero()
+
+	return signWithKey(alg, privateKey, signParams, data)
+}
 
+// signWithKey signs data with the private key, nil algorithm signs with the
+// ed25519 key the default way.
+func signWithKey(alg SignatureAlgorithm, privateKey *SecretBytes, signParams *pki.SignatureParam, data []byte) (*pki.Signature, string, error) {
 	sh := &pki.SignatureHeader{
 		Creator: did.Identifier(signParams.Creator),
 		Nonce:   []byte(signParams.Nonce),
 	}
 
+	if alg != nil {
+		sig, err := alg.Sign(privateKey.Bytes(), data)
+		if err != nil {
+			return nil, "", err
+		}
+		return &pki.Signature{Header: sh, Sign: sig}, alg.Name(), nil
+	}
+
+	pri := &ed25519.PrivateKey{
+		PrivateKeyData: privateKey.Bytes(),
+	}
+
 	sd := &pki.SignedData{
 		Data:   data,
 		Header: sh,
 	}
 	signData, err := sd.DoSign(pri)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return signData, nil
+	return signData, AlgorithmEd25519, nil
 }
 
 func buildSignatureBody(signParams *pki.SignatureParam, data []byte) (*pki.SignatureBody, error) {
-	signData, err := buildSignature(signParams, data)
+	signData, alg, err := buildSignature(signParams, data)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +119,7 @@ func buildSignatureBody(signParams *pki.SignatureParam, data []byte) (*pki.Signa
 		Creator:        signParams.Creator,
 		Created:        signParams.Created,
 		Nonce:          signParams.Nonce,
-		SignatureValue: signBase64,
+		SignatureValue: signatureValue(alg, signBase64),
 	}
 
 	return sign, nil
@@ -96,7 +127,7 @@ func buildSignatureBody(signParams *pki.SignatureParam, data []byte) (*pki.Signa
 
 // without base64 encode
 func buildSignatureBodyBase(signParams *pki.SignatureParam, data []byte) (*pki.SignatureBody, error) {
-	signData, err := buildSignature(signParams, data)
+	signData, alg, err := buildSignature(signParams, data)
 	if err != nil {
 		return nil, err
 	}
@@ -105,8 +136,262 @@ func buildSignatureBodyBase(signParams *pki.SignatureParam, data []byte) (*pki.S
 		Creator:        signParams.Creator,
 		Created:        signParams.Created,
 		Nonce:          signParams.Nonce,
-		SignatureValue: string(signData.Sign),
+		SignatureValue: signatureValue(alg, string(signData.Sign)),
 	}
 
 	return sign, nil
 }
+
+// doJSON builds a request with the header, query params and json body, any of
+// them can be nil, sends it and decodes the response payload into result.
+// Endpoints exchanging json use it, the ones streaming files or needing the
+// raw http response build their request and call doRequest.
+//
+// The result can be nil if the response payload is not needed.
+//
+// The header is merged with the default header of the client, the POST
+// requests get an idempotency key, see SetAutoIdempotencyKey, and
+// the failed requests are sent again according to the retry policy.
+func (w *WalletClient) doJSON(header http.Header, method, path string, params url.Values, body, result interface{}) error {
+	header, err := w.idempotentHeader(method, w.withDefaultHeader(header))
+	if err != nil {
+		return err
+	}
+	if callbackURL := header.Get(CallbackURLHeader); callbackURL != "" {
+		if err = checkCallbackURL(callbackURL); err != nil {
+			return err
+		}
+	}
+	for attempts := 1; ; attempts++ {
+		err = w.sendJSON(header, method, path, params, body, result)
+		if err == nil || !w.shouldRetry(method, header, attempts, err) {
+			return err
+		}
+		w.logger().Debugf("%s %s attempt %d failed, retrying: %v", method, path, attempts, err)
+		if werr := w.retryWait(attempts); werr != nil {
+			return werr
+		}
+	}
+}
+
+// sendJSON sends the request of doJSON once.
+func (w *WalletClient) sendJSON(header http.Header, method, path string, params url.Values, body, result interface{}) error {
+	endpoint := w.endpoint(path)
+	r := w.c.NewRequest(method, endpoint)
+	r.SetHeaders(header)
+	binding, err := w.bindResponse(r, method, endpoint)
+	if err != nil {
+		return err
+	}
+	for k := range params {
+		r.SetParam(k, params.Get(k))
+	}
+	if body != nil {
+		r.SetBody(body)
+	}
+
+	trace := w.startCall(r, method, path, params, header)
+	if data, ok := body.([]byte); ok {
+		// raw bodies are file contents
+		trace.uploaded(int64(len(data)))
+	}
+	err = w.doRequest(r, binding, result)
+	trace.finish(err)
+	return err
+}
+
+// doRequest sends the http request and decodes the response payload into result.
+//
+// The result can be nil if the response payload is not needed.
+func (w *WalletClient) doRequest(r *restapi.Request, binding *responseBinding, result interface{}) error {
+	// Do http request
+	_, resp, err := w.requireOK(w.do(r))
+	if err != nil {
+		return err
+	}
+	return w.decodeVerified(resp, binding, result)
+}
+
+// decodeResponse decodes the payload of a successful http response into result,
+// the response body is closed. A malformed response making the decoding panic
+// is returned as a *PanicError.
+func decodeResponse(resp *http.Response, result interface{}) (err error) {
+	defer resp.Body.Close()
+	defer RecoverPanic(&err)
+
+	// Parse http response
+	var payload responsePayload
+	respBody := rtstructs.Response{Payload: &payload}
+	if err = restapi.DecodeBody(resp, &respBody); err != nil {
+		return err
+	}
+
+	if respBody.ErrCode != errors.SuccCode {
+		return newError(resp, int(respBody.ErrCode), respBody.ErrMessage, payload.data)
+	}
+
+	if payload.invalid != "" || respBody.Payload == nil {
+		return fmt.Errorf("response payload type invalid: %s", payload.kind())
+	}
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(payload.data, result)
+}
+
+// responsePayload receives the response payload, a json document encoded as
+// a json string. The string is unescaped straight into data while decoding
+// the response, without going through an interface{} and a string copy.
+type responsePayload struct {
+	data    []byte
+	invalid string
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It never fails so that the
+// error code of the response is always decoded, an invalid payload is
+// reported by decodeResponse.
+func (p *responsePayload) UnmarshalJSON(raw []byte) error {
+	if len(raw) < 2 || raw[0] != '"' {
+		p.invalid = string(raw[:1])
+		return nil
+	}
+	data, ok := unescapeJSONString(raw[1 : len(raw)-1])
+	if !ok {
+		p.invalid = `"`
+		return nil
+	}
+	p.data = data
+	return nil
+}
+
+func (p *responsePayload) kind() string {
+	switch p.invalid {
+	case "":
+		return "null"
+	case "{":
+		return "object"
+	case "[":
+		return "array"
+	case "t", "f":
+		return "bool"
+	case "n":
+		return "null"
+	case `"`:
+		return "malformed string"
+	}
+	return "number"
+}
+
+// unescapeJSONString unescapes the contents of a json string literal, the
+// literal has already been validated by the json decoder.
+func unescapeJSONString(s []byte) ([]byte, bool) {
+	if bytes.IndexByte(s, '\\') < 0 {
+		data := make([]byte, len(s))
+		copy(data, s)
+		return data, true
+	}
+
+	data := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			data = append(data, c)
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return nil, false
+		}
+		switch s[i] {
+		case '"', '\\', '/':
+			data = append(data, s[i])
+		case 'b':
+			data = append(data, '\b')
+		case 'f':
+			data = append(data, '\f')
+		case 'n':
+			data = append(data, '\n')
+		case 'r':
+			data = append(data, '\r')
+		case 't':
+			data = append(data, '\t')
+		case 'u':
+			r, ok := hex4(s[i+1:])
+			if !ok {
+				return nil, false
+			}
+			i += 4
+			if utf16.IsSurrogate(r) {
+				r2, ok := rune(-1), false
+				if i+2 < len(s) && s[i+1] == '\\' && s[i+2] == 'u' {
+					r2, ok = hex4(s[i+3:])
+				}
+				if dec := utf16.DecodeRune(r, r2); ok && dec != unicode.ReplacementChar {
+					r = dec
+					i += 6
+				} else {
+					r = unicode.ReplacementChar
+				}
+			}
+			var buf [utf8.UTFMax]byte
+			n := utf8.EncodeRune(buf[:], r)
+			data = append(data, buf[:n]...)
+		default:
+			return nil, false
+		}
+	}
+	return data, true
+}
+
+func hex4(s []byte) (rune, bool) {
+	if len(s) < 4 {
+		return -1, false
+	}
+	var r rune
+	for _, c := range s[:4] {
+		switch {
+		case '0' <= c && c <= '9':
+			c = c - '0'
+		case 'a' <= c && c <= 'f':
+			c = c - 'a' + 10
+		case 'A' <= c && c <= 'F':
+			c = c - 'A' + 10
+		default:
+			return -1, false
+		}
+		r = r*16 + rune(c)
+	}
+	return r, true
+}
+
+// buildWalletRequest is used to build the signed request body of write operations.
+//
+func (w *WalletClient) buildWalletRequest(header http.Header, body interface{}, signParams *pki.SignatureParam) (reqBody *wallet.WalletRequest, err error) {
+	if w.s != nil {
+		signParams, err = w.queryPrivateKey(header, signParams)
+		if err != nil {
+			return
+		}
+	}
+
+	// Build request signature
+	reqPayload, err := CanonicalJSON(body)
+	if err != nil {
+		return
+	}
+	if signParams, err = w.withNonce(signParams); err != nil {
+		return
+	}
+	sign, err := w.signPayload(signParams, reqPayload)
+	if err != nil {
+		return
+	}
+	w.dumpSignature(SignKindRequest, signParams, reqPayload, sign.SignatureValue)
+
+	reqBody = &wallet.WalletRequest{
+		Payload:   string(reqPayload),
+		Signature: sign,
+	}
+	return
+}
@@ -0,0 +1,140 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TxStatus is the status of a blockchain transaction.
+//
+type TxStatus string
+
+const (
+	// TxPending means the transaction is not confirmed yet
+	TxPending TxStatus = "pending"
+	// TxConfirmed means the transaction is confirmed on chain
+	TxConfirmed TxStatus = "confirmed"
+	// TxFailed means the transaction has been rejected
+	TxFailed TxStatus = "failed"
+)
+
+// TransactionReceipt is the status of a blockchain transaction.
+//
+type TransactionReceipt struct {
+	TxId        string   `json:"tx_id"`
+	Status      TxStatus `json:"status"`
+	BlockNumber uint64   `json:"block_number,omitempty"`
+	Timestamp   int64    `json:"timestamp,omitempty"`
+	Reason      string   `json:"reason,omitempty"`
+}
+
+// PollOptions configures the polling of WaitForConfirmation. The interval
+// starts at Interval and is multiplied by Multiplier after each poll, up to
+// MaxInterval.
+//
+type PollOptions struct {
+	// Interval is the first polling interval, 1 second if zero
+	Interval time.Duration
+	// MaxInterval bounds the polling interval, 30 seconds if zero
+	MaxInterval time.Duration
+	// Multiplier is the interval backoff factor, 2 if less than 1
+	Multiplier float64
+	// Timeout bounds the whole wait, no timeout other than the context if zero
+	Timeout time.Duration
+}
+
+const (
+	defaultPollInterval    = time.Second
+	defaultMaxPollInterval = 30 * time.Second
+	defaultPollMultiplier  = 2
+)
+
+// QueryTransactionStatus is used to query the status of a blockchain transaction.
+//
+func (w *WalletClient) QueryTransactionStatus(header http.Header, txID string) (result *TransactionReceipt, err error) {
+	if txID == "" {
+		err = fmt.Errorf("transaction id invalid")
+		return
+	}
+
+	if err = w.doJSON(header, "GET", "/v1/transaction/status", url.Values{"tx_id": {txID}}, nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WaitForConfirmation polls the status of a transaction sent in asynchronous
+// invoking mode until it is confirmed or failed, and returns its receipt.
+//
+// Retryable query errors, according to ClassifyError, are ignored until the
+// next poll. A failed transaction is returned with an error. The wait gives
+// up with the context error when ctx is done or opts.Timeout expires.
+//
+func (w *WalletClient) WaitForConfirmation(ctx context.Context, header http.Header, txID string, opts *PollOptions) (*TransactionReceipt, error) {
+	var o PollOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Interval <= 0 {
+		o.Interval = defaultPollInterval
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = defaultMaxPollInterval
+	}
+	if o.Multiplier < 1 {
+		o.Multiplier = defaultPollMultiplier
+	}
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	client := w.WithContext(ctx)
+	interval := o.Interval
+	for {
+		receipt, err := client.QueryTransactionStatus(header, txID)
+		if err != nil && ctx.Err() == nil && w.ClassifyError(err) != ErrorRetryable {
+			return nil, err
+		}
+		if err == nil && receipt != nil {
+			switch receipt.Status {
+			case TxConfirmed:
+				return receipt, nil
+			case TxFailed:
+				return receipt, fmt.Errorf("transaction %s failed: %s", w.sensitive(txID), receipt.Reason)
+			}
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		interval = time.Duration(float64(interval) * o.Multiplier)
+		if interval > o.MaxInterval {
+			interval = o.MaxInterval
+		}
+	}
+}
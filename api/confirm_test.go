@@ -0,0 +1,112 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+var fastPoll = &PollOptions{Interval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+
+func mockTxStatus(t *testing.T, txID string, status TxStatus) {
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/transaction/status").
+		MatchParam("tx_id", txID).
+		Reply(200).
+		JSON(payloadResponse(t, &TransactionReceipt{TxId: txID, Status: status, Reason: "double spending"}))
+}
+
+func TestWaitForConfirmationSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const txID = "tx-001"
+	mockTxStatus(t, txID, TxPending)
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/transaction/status").
+		Reply(503).
+		JSON(&rtstructs.Response{})
+	mockTxStatus(t, txID, TxConfirmed)
+
+	receipt, err := client.WaitForConfirmation(context.Background(), http.Header{}, txID, fastPoll)
+	if err != nil {
+		t.Fatalf("wait for confirmation fail: %v", err)
+	}
+	if receipt == nil || receipt.Status != TxConfirmed {
+		t.Fatalf("transaction should be confirmed, got %v", receipt)
+	}
+	if !gock.IsDone() {
+		t.Fatalf("transaction status should be polled until confirmed")
+	}
+}
+
+func TestWaitForConfirmationFailed(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const txID = "tx-001"
+	mockTxStatus(t, txID, TxFailed)
+
+	receipt, err := client.WaitForConfirmation(context.Background(), http.Header{}, txID, fastPoll)
+	if err == nil {
+		t.Fatalf("err should not be nil when the transaction failed")
+	}
+	if receipt == nil || receipt.Status != TxFailed {
+		t.Fatalf("failed receipt should be returned, got %v", receipt)
+	}
+}
+
+func TestWaitForConfirmationFailErrCode(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/transaction/status").
+		Reply(200).
+		JSON(&rtstructs.Response{ErrCode: 8000, ErrMessage: "transaction not found"})
+
+	_, err := client.WaitForConfirmation(context.Background(), http.Header{}, "tx-001", fastPoll)
+	if e, ok := err.(*Error); !ok || e.ErrCode != 8000 {
+		t.Fatalf("non retryable error should be returned, got %v", err)
+	}
+}
+
+func TestWaitForConfirmationTimeout(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	opts := *fastPoll
+	opts.Timeout = 20 * time.Millisecond
+	_, err := client.WaitForConfirmation(context.Background(), http.Header{}, "tx-001", &opts)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("err should be context.DeadlineExceeded, got %v", err)
+	}
+}
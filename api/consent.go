@@ -0,0 +1,162 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+const (
+	// ConsentReceiptType marks the metadata of a POE digital asset holding a consent receipt
+	ConsentReceiptType = "consent_receipt"
+	// ConsentReceiptVersion is the version of the consent receipt metadata schema
+	ConsentReceiptVersion = "1"
+)
+
+// ConsentReceipt is the consent a data subject gave to a data controller,
+// anchored as the metadata of a POE digital asset.
+//
+// Granted, Expires and Revoked are unix seconds, zero Expires means the
+// consent never expires.
+//
+type ConsentReceipt struct {
+	Type           string         `json:"type"`
+	Version        string         `json:"version"`
+	Subject        did.Identifier `json:"subject"`
+	Controller     did.Identifier `json:"controller"`
+	Purposes       []string       `json:"purposes"`
+	DataCategories []string       `json:"data_categories,omitempty"`
+	Jurisdiction   string         `json:"jurisdiction,omitempty"`
+	PolicyURL      string         `json:"policy_url,omitempty"`
+	Granted        int64          `json:"granted"`
+	Expires        int64          `json:"expires,omitempty"`
+	Revoked        int64          `json:"revoked,omitempty"`
+}
+
+// Active reports whether the consent is neither revoked nor expired at now.
+//
+func (c *ConsentReceipt) Active(now time.Time) bool {
+	if c == nil || c.Revoked > 0 {
+		return false
+	}
+	return c.Expires == 0 || now.Unix() < c.Expires
+}
+
+// consentPOEBody builds the POE body anchoring the consent receipt.
+func consentPOEBody(id did.Identifier, receipt *ConsentReceipt) (*wallet.POEBody, error) {
+	metadata, err := json.Marshal(receipt)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(metadata)
+	return &wallet.POEBody{
+		Id:       id,
+		Name:     fmt.Sprintf("consent:%s", receipt.Subject),
+		Owner:    receipt.Controller,
+		Hash:     hex.EncodeToString(hash[:]),
+		Metadata: metadata,
+	}, nil
+}
+
+// RecordConsent is used to anchor a consent receipt as a POE digital asset
+// owned by the data controller, the returned response Id is the consent ID.
+//
+// Granted defaults to the current time when not set.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) RecordConsent(header http.Header, receipt *ConsentReceipt, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if receipt == nil || receipt.Subject == "" || receipt.Controller == "" || len(receipt.Purposes) == 0 {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+
+	rc := *receipt
+	rc.Type = ConsentReceiptType
+	rc.Version = ConsentReceiptVersion
+	rc.Revoked = 0
+	if rc.Granted == 0 {
+		rc.Granted = time.Now().Unix()
+	}
+
+	body, err := consentPOEBody("", &rc)
+	if err != nil {
+		return nil, err
+	}
+	return w.CreatePOE(header, body, signParams)
+}
+
+// QueryConsent is used to query the consent receipt anchored by RecordConsent.
+//
+func (w *WalletClient) QueryConsent(header http.Header, id did.Identifier) (result *ConsentReceipt, err error) {
+	if id == "" {
+		err = fmt.Errorf("consent id invalid")
+		return
+	}
+
+	poe, err := w.QueryPOE(header, id)
+	if err != nil {
+		return nil, err
+	}
+	if poe == nil {
+		return nil, fmt.Errorf("consent %s not found", w.sensitive(string(id)))
+	}
+
+	if err = json.Unmarshal(poe.Metadata, &result); err != nil || result == nil || result.Type != ConsentReceiptType {
+		return nil, fmt.Errorf("poe %s is not a consent receipt", w.sensitive(string(id)))
+	}
+	return result, nil
+}
+
+// RevokeConsent is used to revoke a consent receipt, the revocation time is
+// anchored by updating the POE digital asset.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) RevokeConsent(header http.Header, id did.Identifier, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	receipt, err := w.QueryConsent(header, id)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Revoked > 0 {
+		return nil, fmt.Errorf("consent %s already revoked", w.sensitive(string(id)))
+	}
+	receipt.Revoked = time.Now().Unix()
+
+	body, err := consentPOEBody(id, receipt)
+	if err != nil {
+		return nil, err
+	}
+	return w.UpdatePOE(header, body, signParams)
+}
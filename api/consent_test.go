@@ -0,0 +1,134 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestRevokeConsentSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		token     = "user-token-001"
+		consentID = "did:axn:poe-id-001"
+		transID   = "trans-id-001"
+	)
+
+	//response bodies
+	receipt := &ConsentReceipt{
+		Type:       ConsentReceiptType,
+		Version:    ConsentReceiptVersion,
+		Subject:    "did:axn:001",
+		Controller: "did:axn:002",
+		Purposes:   []string{"marketing"},
+		Granted:    time.Now().Add(-time.Hour).Unix(),
+	}
+	metadata, err := json.Marshal(receipt)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	byPOE, err := json.Marshal(&wallet.POEPayload{
+		Id:       consentID,
+		Owner:    receipt.Controller,
+		Metadata: metadata,
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	byPayload, err := json.Marshal(&wallet.WalletResponse{
+		TransactionIds: []string{transID},
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	sign := &pki.SignatureParam{
+		Creator:    "did:axn:002",
+		Nonce:      "helloalice",
+		PrivateKey: "WBZNmTTf34Kg+pQOTSIRL+JeQYDfj7InWc0A/9kvNvQSI8Ue8iRD8gn9CNmGO2EjJILF/3RELmEcbuS5G0d+Mg==",
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/poe").
+		MatchParam("id", consentID).
+		MatchHeader("X-Auth-Token", token).
+		Reply(200).
+		JSON(&rtstructs.Response{ErrCode: 0, Payload: string(byPOE)})
+	gock.New("http://127.0.0.1:8006").
+		Put("/v1/poe/update").
+		MatchHeader("X-Auth-Token", token).
+		Reply(200).
+		JSON(&rtstructs.Response{ErrCode: 0, Payload: string(byPayload)})
+
+	//set http header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	//do query consent
+	consent, err := client.QueryConsent(header, consentID)
+	if err != nil {
+		t.Fatalf("query consent fail: %v", err)
+	}
+	if !consent.Active(time.Now()) {
+		t.Fatalf("consent should be active")
+	}
+
+	//do revoke consent
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/poe").
+		MatchParam("id", consentID).
+		Reply(200).
+		JSON(&rtstructs.Response{ErrCode: 0, Payload: string(byPOE)})
+	resp, err := client.RevokeConsent(header, consentID, sign)
+	if err != nil {
+		t.Fatalf("revoke consent fail: %v", err)
+	}
+	if resp == nil || len(resp.TransactionIds) == 0 || resp.TransactionIds[0] != transID {
+		t.Fatalf("response transaction id should be %v", transID)
+	}
+}
+
+func TestRecordConsentFail(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	//consent without purposes is rejected before any request
+	resp, err := client.RecordConsent(http.Header{}, &ConsentReceipt{
+		Subject:    "did:axn:001",
+		Controller: "did:axn:002",
+	}, &pki.SignatureParam{})
+	if err == nil {
+		t.Fatalf("err should not be nil when consent purposes are empty")
+	}
+	if resp != nil {
+		t.Fatalf("response object should be nil when consent purposes are empty")
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+)
+
+// contextHeader carries the id of the context of a request from send to
+// the transport of the client, which removes it.
+const contextHeader = "X-Wallet-Sdk-Context"
+
+// WithContext returns a shallow copy of the client whose requests are bound
+// to ctx. Every method of the copy, including POE uploads and sync mode
+// invocations, returns ctx.Err() as soon as ctx is cancelled or its deadline
+// expires: the http requests are aborted by the transport. nil ctx is
+// context.Background. The configuration set on the client is shared with
+// the copy.
+//
+// The gateway may still process a request aborted after it was sent, write
+// requests should be queried before being sent again.
+//
+func (w *WalletClient) WithContext(ctx context.Context) *WalletClient {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	w2 := *w
+	w2.ctx = ctx
+	return &w2
+}
+
+// Context returns the context of the client, context.Background if none
+// was set with WithContext.
+//
+func (w *WalletClient) Context() context.Context {
+	if w.ctx != nil {
+		return w.ctx
+	}
+	return context.Background()
+}
+
+// do sends the http request through the circuit breaker of the client, if
+// any, giving up when the client context is done.
+func (w *WalletClient) do(r *restapi.Request) (time.Duration, *http.Response, error) {
+	if w.breaker == nil {
+		return w.send(r)
+	}
+	generation, err := w.breaker.allow()
+	if err != nil {
+		return 0, nil, err
+	}
+	d, resp, err := w.send(r)
+	w.breakerDone(generation, resp, err)
+	return d, resp, err
+}
+
+// send sends the http request bound to the client context, the transport
+// aborts it when the context is done.
+func (w *WalletClient) send(r *restapi.Request) (time.Duration, *http.Response, error) {
+	if w.ctx == nil {
+		return w.c.DoRequest(r)
+	}
+	if err := w.ctx.Err(); err != nil {
+		return 0, nil, err
+	}
+	if w.middleware != nil {
+		id := w.middleware.bindContext(w.ctx)
+		defer w.middleware.unbindContext(id)
+		r.SetHeader(contextHeader, id)
+	}
+
+	d, resp, err := w.c.DoRequest(r)
+	if err != nil && w.ctx.Err() != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return d, nil, w.ctx.Err()
+	}
+	return d, resp, err
+}
+
+// bindContext registers the context of a request, it returns the id the
+// request carries in the contextHeader.
+func (c *middlewareChain) bindContext(ctx context.Context) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.contexts == nil {
+		c.contexts = make(map[string]context.Context)
+	}
+	c.contextSeq++
+	id := strconv.FormatUint(c.contextSeq, 10)
+	c.contexts[id] = ctx
+	return id
+}
+
+// unbindContext removes the context of a request once sent.
+func (c *middlewareChain) unbindContext(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.contexts, id)
+}
+
+// withBoundContext returns the request without the contextHeader, bound to
+// the context registered with its id if any.
+func (c *middlewareChain) withBoundContext(req *http.Request) *http.Request {
+	id := req.Header.Get(contextHeader)
+	if id == "" {
+		return req
+	}
+	c.mu.RLock()
+	ctx := c.contexts[id]
+	c.mu.RUnlock()
+
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	r.Header.Del(contextHeader)
+	if ctx != nil {
+		r = r.WithContext(ctx)
+	}
+	return r
+}
@@ -0,0 +1,160 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+// blockingTransport holds every request until released or its context is
+// done
+type blockingTransport struct {
+	release chan struct{}
+	aborted chan *http.Request
+}
+
+func (b *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case <-b.release:
+		return nil, context.Canceled
+	case <-req.Context().Done():
+		b.aborted <- req
+		return nil, req.Context().Err()
+	}
+}
+
+func TestWithContextSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		token = "user-token-001"
+		id    = did.Identifier("did:axn:001")
+	)
+
+	byPayload, err := json.Marshal(&wallet.POEPayload{Id: id})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody := &rtstructs.Response{
+		ErrCode: 0,
+		Payload: string(byPayload),
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/poe").
+		MatchParam("id", string(id)).
+		MatchHeader("X-Auth-Token", token).
+		Reply(200).
+		JSON(respBody)
+
+	//set header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctxClient := client.WithContext(ctx)
+	if ctxClient.Context() != ctx {
+		t.Fatalf("context should be bound to the client copy")
+	}
+	if client.Context() != context.Background() {
+		t.Fatalf("original client context should be background")
+	}
+
+	result, err := ctxClient.QueryPOE(header, id)
+	if err != nil {
+		t.Fatalf("query poe fail: %v", err)
+	}
+	if result == nil || result.Id != id {
+		t.Fatalf("poe id should be %v, got %v", id, result)
+	}
+}
+
+func TestWithContextCanceled(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/poe").
+		Reply(200).
+		JSON(&rtstructs.Response{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.WithContext(ctx).QueryPOE(http.Header{}, "did:axn:001")
+	if err != context.Canceled {
+		t.Fatalf("err should be context.Canceled, got %v", err)
+	}
+	if gock.IsDone() {
+		t.Fatalf("request should not be sent when the context is canceled")
+	}
+}
+
+func TestWithContextDeadline(t *testing.T) {
+	transport := &blockingTransport{release: make(chan struct{}), aborted: make(chan *http.Request, 1)}
+	defer close(transport.release)
+	client, err := NewWalletClient(&restapi.Config{
+		Address:    "http://127.0.0.1:8006",
+		HttpClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = client.WithContext(ctx).QueryPOE(http.Header{}, "did:axn:001")
+	if err != context.DeadlineExceeded {
+		t.Fatalf("err should be context.DeadlineExceeded, got %v", err)
+	}
+	select {
+	case req := <-transport.aborted:
+		if req.Header.Get(contextHeader) != "" {
+			t.Fatalf("the context header should not be sent")
+		}
+	default:
+		t.Fatalf("the http request should be aborted by the transport")
+	}
+	if n := len(client.middleware.contexts); n != 0 {
+		t.Fatalf("the contexts of the sent requests should be released, %d left", n)
+	}
+}
+
+func TestWithContextNil(t *testing.T) {
+	client := &WalletClient{}
+	if client.WithContext(nil).Context() != context.Background() {
+		t.Fatalf("nil context should be context.Background")
+	}
+}
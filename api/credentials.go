@@ -0,0 +1,231 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+)
+
+// ErrCredentialNotFound is returned by the credential stores which have no
+// credential of the name.
+//
+var ErrCredentialNotFound = fmt.Errorf("credential not found")
+
+// CredentialStore returns the credentials of the client at runtime, e.g.
+// from HashiCorp Vault or the OS keychain, so that they are not passed as
+// plain strings in the code and configuration files. The credentials/vault
+// and credentials/keyring packages provide stores.
+//
+type CredentialStore interface {
+	Credential(ctx context.Context, name string) (string, error)
+}
+
+// CredentialFunc adapts a function to a CredentialStore.
+//
+type CredentialFunc func(ctx context.Context, name string) (string, error)
+
+// Credential implements CredentialStore.
+//
+func (f CredentialFunc) Credential(ctx context.Context, name string) (string, error) {
+	return f(ctx, name)
+}
+
+// CredentialNames are the names of the credentials of the client in the
+// credential store, the credentials with no name are not looked up.
+//
+type CredentialNames struct {
+	// APIKey is the name of the API key, sent to the gateway and the safebox
+	APIKey string
+	// AccessToken is the name of the user access token, sent in the
+	// X-Auth-Token header of the calls which have none
+	AccessToken string
+	// SecurityCode returns the name of the security code of the trusted key
+	// pair of a wallet, used when the signature params of the wallet carry
+	// neither a private key nor a security code
+	SecurityCode func(id did.Identifier) string
+}
+
+type credentialSource struct {
+	store CredentialStore
+	names CredentialNames
+}
+
+// SetCredentialStore makes the client look up its credentials in store
+// for every call, by their names:
+//
+//	client.SetCredentialStore(api.CachedCredentials(store, time.Minute), &api.CredentialNames{
+//		APIKey:       "wallet/api-key",
+//		AccessToken:  "wallet/access-token",
+//		SecurityCode: func(id did.Identifier) string { return "wallet/codes/" + string(id) },
+//	})
+//
+// The headers set by the caller take precedence over the store, as does the
+// API key of the configuration. nil store disables the lookups.
+//
+func (w *WalletClient) SetCredentialStore(store CredentialStore, names *CredentialNames) {
+	if w.middleware == nil {
+		w.middleware = &middlewareChain{}
+	}
+	var src *credentialSource
+	if store != nil && names != nil {
+		src = &credentialSource{store: store, names: *names}
+	}
+	w.middleware.mu.Lock()
+	defer w.middleware.mu.Unlock()
+	w.middleware.credentials = src
+}
+
+// credentialSource returns the credential store of the client, nil if none.
+func (w *WalletClient) credentialSource() *credentialSource {
+	if w.middleware == nil {
+		return nil
+	}
+	w.middleware.mu.RLock()
+	defer w.middleware.mu.RUnlock()
+	return w.middleware.credentials
+}
+
+// apiKey returns the API key of the configuration, or from the credential
+// store.
+func (w *WalletClient) apiKey() (string, error) {
+	if w.cfg.ApiKey != "" {
+		return w.cfg.ApiKey, nil
+	}
+	src := w.credentialSource()
+	if src == nil || src.names.APIKey == "" {
+		return "", nil
+	}
+	return src.lookup(w.Context(), src.names.APIKey)
+}
+
+// securityCode returns the security code of the wallet from the credential
+// store, empty if there is none.
+func (w *WalletClient) securityCode(id did.Identifier) (string, error) {
+	src := w.credentialSource()
+	if src == nil || src.names.SecurityCode == nil {
+		return "", nil
+	}
+	name := src.names.SecurityCode(id)
+	if name == "" {
+		return "", nil
+	}
+	return src.lookup(w.Context(), name)
+}
+
+func (s *credentialSource) lookup(ctx context.Context, name string) (string, error) {
+	value, err := s.store.Credential(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("credential %s: %v", name, err)
+	}
+	return value, nil
+}
+
+// apply returns a copy of the gateway request with the API key and access
+// token of the store, unless the request has them.
+func (s *credentialSource) apply(req *http.Request, configured bool) (*http.Request, error) {
+	set := make(map[string]string, 2)
+	if !configured && s.names.APIKey != "" && req.Header.Get(structs.APIKeyHeader) == "" {
+		key, err := s.lookup(req.Context(), s.names.APIKey)
+		if err != nil {
+			return nil, err
+		}
+		set[structs.APIKeyHeader] = key
+	}
+	if s.names.AccessToken != "" && req.Header.Get(AuthTokenHeader) == "" {
+		token, err := s.lookup(req.Context(), s.names.AccessToken)
+		if err != nil {
+			return nil, err
+		}
+		set[AuthTokenHeader] = token
+	}
+	if len(set) == 0 {
+		return req, nil
+	}
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header)+len(set))
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	for k, v := range set {
+		r.Header.Set(k, v)
+	}
+	return r, nil
+}
+
+type cachedCredential struct {
+	value   *SecretBytes
+	expires time.Time
+}
+
+type credentialCache struct {
+	store CredentialStore
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*cachedCredential
+}
+
+// CachedCredentials returns a CredentialStore caching for ttl the
+// credentials returned by store, so that it is not queried for every call.
+// The expired credentials are zeroed.
+//
+func CachedCredentials(store CredentialStore, ttl time.Duration) CredentialStore {
+	return &credentialCache{
+		store:   store,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*cachedCredential),
+	}
+}
+
+// Credential implements CredentialStore.
+//
+func (c *credentialCache) Credential(ctx context.Context, name string) (string, error) {
+	now := c.now()
+	c.mu.Lock()
+	if e, ok := c.entries[name]; ok {
+		if now.Before(e.expires) {
+			value := string(e.value.Bytes())
+			c.mu.Unlock()
+			return value, nil
+		}
+		e.value.Zero()
+		delete(c.entries, name)
+	}
+	c.mu.Unlock()
+
+	value, err := c.store.Credential(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	if e, ok := c.entries[name]; ok {
+		e.value.Zero()
+	}
+	c.entries[name] = &cachedCredential{value: NewSecretBytes([]byte(value)), expires: now.Add(c.ttl)}
+	c.mu.Unlock()
+	return value, nil
+}
@@ -0,0 +1,156 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/rest/api"
+	"github.com/arxanchain/sdk-go-common/structs"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/safebox"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+type mapCredentials struct {
+	values  map[string]string
+	lookups int
+}
+
+func (m *mapCredentials) Credential(ctx context.Context, name string) (string, error) {
+	m.lookups++
+	value, ok := m.values[name]
+	if !ok {
+		return "", ErrCredentialNotFound
+	}
+	return value, nil
+}
+
+func TestCredentialStoreHeaders(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	store := &mapCredentials{values: map[string]string{"api-key": "key-001", "token": "token-001"}}
+	client.SetCredentialStore(store, &CredentialNames{APIKey: "api-key", AccessToken: "token"})
+	defer client.SetCredentialStore(nil, nil)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/balance").
+		MatchHeader(structs.APIKeyHeader, "key-001").
+		MatchHeader(AuthTokenHeader, "token-001").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletBalance{}))
+	header := http.Header{}
+	if _, err := client.GetWalletBalance(header, "did:axn:001"); err != nil {
+		t.Fatalf("get wallet balance fail: %v", err)
+	}
+	if header.Get(AuthTokenHeader) != "" {
+		t.Fatalf("the header of the caller should not be modified")
+	}
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/balance").
+		MatchHeader(structs.APIKeyHeader, "key-001").
+		MatchHeader(AuthTokenHeader, "token-002").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletBalance{}))
+	header.Set(AuthTokenHeader, "token-002")
+	if _, err := client.GetWalletBalance(header, "did:axn:001"); err != nil {
+		t.Fatalf("the header of the caller should take precedence: %v", err)
+	}
+}
+
+func TestCredentialStoreError(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	client.SetCredentialStore(&mapCredentials{}, &CredentialNames{AccessToken: "token"})
+	defer client.SetCredentialStore(nil, nil)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/balance").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletBalance{}))
+	if _, err := client.GetWalletBalance(http.Header{}, "did:axn:001"); err == nil {
+		t.Fatalf("the lookup error should be returned")
+	}
+}
+
+func TestCredentialStoreSecurityCode(t *testing.T) {
+	box := &fakeSafebox{keys: map[string]*safebox.KeyPairResponse{
+		"did:axn:001": {Code: "code-1", PrivateKey: "private-key"},
+	}}
+	client := &WalletClient{cfg: &api.Config{}, s: box}
+	client.SetCredentialStore(&mapCredentials{values: map[string]string{"codes/did:axn:001": "code-1"}}, &CredentialNames{
+		SecurityCode: func(id did.Identifier) string { return "codes/" + string(id) },
+	})
+
+	signParams := &pki.SignatureParam{Creator: "did:axn:001"}
+	params, err := client.queryPrivateKey(http.Header{}, signParams)
+	if err != nil {
+		t.Fatalf("query private key fail: %v", err)
+	}
+	if params.PrivateKey != "private-key" || params.SecurityCode != "" {
+		t.Fatalf("the security code should be looked up, got %+v", params)
+	}
+	if params == signParams || signParams.PrivateKey != "" || signParams.SecurityCode != "" {
+		t.Fatalf("signature params of the caller should not be modified, got %+v", signParams)
+	}
+
+	if _, err = client.queryPrivateKey(http.Header{}, &pki.SignatureParam{Creator: "did:axn:002"}); err == nil {
+		t.Fatalf("the missing security code should fail")
+	}
+}
+
+func TestCachedCredentials(t *testing.T) {
+	store := &mapCredentials{values: map[string]string{"token": "token-001"}}
+	cached := CachedCredentials(store, time.Minute).(*credentialCache)
+	now := time.Unix(1000, 0)
+	cached.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if value, err := cached.Credential(context.Background(), "token"); err != nil || value != "token-001" {
+			t.Fatalf("credential %d: %q, %v", i, value, err)
+		}
+	}
+	if store.lookups != 1 {
+		t.Fatalf("the credential should be cached, %d lookups", store.lookups)
+	}
+
+	entry := cached.entries["token"].value
+	store.values["token"] = "token-002"
+	now = now.Add(2 * time.Minute)
+	if value, _ := cached.Credential(context.Background(), "token"); value != "token-002" {
+		t.Fatalf("the expired credential should be looked up again, got %q", value)
+	}
+	if entry.Len() != 0 {
+		t.Fatalf("the expired credential should be zeroed")
+	}
+
+	if _, err := cached.Credential(context.Background(), "missing"); err != ErrCredentialNotFound {
+		t.Fatalf("the store error should be returned, got %v", err)
+	}
+}
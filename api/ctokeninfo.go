@@ -0,0 +1,85 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+)
+
+// CTokenIssuance is an issuance of colored tokens.
+//
+type CTokenIssuance struct {
+	Amount int64 `json:"amount"`
+	// Owner is the wallet which received the issued tokens
+	Owner did.Identifier `json:"owner"`
+	// AssetId is the digital asset backing the issued tokens
+	AssetId        string   `json:"asset_id"`
+	TransactionIds []string `json:"transaction_ids"`
+	// Timestamp is the time of the issuance, in unix seconds
+	Timestamp int64 `json:"timestamp"`
+}
+
+// CTokenInfo describes a colored token.
+//
+type CTokenInfo struct {
+	Id     string         `json:"id"`
+	Issuer did.Identifier `json:"issuer"`
+	// TotalSupply is the amount issued minus the amount burnt
+	TotalSupply int64 `json:"total_supply"`
+	// Decimals is the number of decimal places of the amounts
+	Decimals int `json:"decimals"`
+	// SupplyCap is the maximum total supply set with SetSupplyCap, zero if
+	// the supply is not capped
+	SupplyCap int64  `json:"supply_cap,omitempty"`
+	Metadata  []byte `json:"metadata"`
+	Created   int64  `json:"created"`
+	// Issuances are the issuances of the token, from the oldest to the
+	// latest, only returned by QueryCTokenInfo
+	Issuances []*CTokenIssuance `json:"issuances,omitempty"`
+}
+
+// QueryCTokenInfo is used to query the issuer, supply, metadata and
+// issuance history of a colored token.
+//
+func (w *WalletClient) QueryCTokenInfo(header http.Header, tokenID string) (result *CTokenInfo, err error) {
+	if tokenID == "" {
+		err = fmt.Errorf("token id invalid")
+		return
+	}
+
+	err = w.doJSON(header, "GET", "/v2/ctoken/info", url.Values{"id": {tokenID}}, nil, &result)
+
+	return
+}
+
+// ListCTokens is used to list the colored tokens issued by a wallet, without
+// their issuance history.
+//
+func (w *WalletClient) ListCTokens(header http.Header, issuer did.Identifier) (result []*CTokenInfo, err error) {
+	if issuer == "" {
+		err = fmt.Errorf("issuer invalid")
+		return
+	}
+
+	err = w.doJSON(header, "GET", "/v2/ctoken/list", url.Values{"issuer": {string(issuer)}}, nil, &result)
+
+	return
+}
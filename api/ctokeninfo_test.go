@@ -0,0 +1,77 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestQueryCTokenInfo(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/ctoken/info").
+		MatchParam("id", "token-001").
+		Reply(200).
+		JSON(payloadResponse(t, &CTokenInfo{
+			Id:          "token-001",
+			Issuer:      "did:axn:issuer-001",
+			TotalSupply: 1000,
+			Decimals:    2,
+			Metadata:    []byte("points"),
+			Issuances:   []*CTokenIssuance{{Amount: 1000, Owner: "did:axn:wallet-001", TransactionIds: []string{"tx-001"}}},
+		}))
+
+	info, err := client.QueryCTokenInfo(http.Header{}, "token-001")
+	if err != nil {
+		t.Fatalf("query ctoken info fail: %v", err)
+	}
+	if info.Issuer != "did:axn:issuer-001" || info.TotalSupply != 1000 || info.Decimals != 2 || len(info.Issuances) != 1 {
+		t.Fatalf("token info should be returned, got %+v", info)
+	}
+
+	if _, err = client.QueryCTokenInfo(http.Header{}, ""); err == nil {
+		t.Fatalf("err should not be nil without token id")
+	}
+}
+
+func TestListCTokens(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/ctoken/list").
+		MatchParam("issuer", "did:axn:issuer-001").
+		Reply(200).
+		JSON(payloadResponse(t, []*CTokenInfo{{Id: "token-001"}, {Id: "token-002"}}))
+
+	tokens, err := client.ListCTokens(http.Header{}, "did:axn:issuer-001")
+	if err != nil {
+		t.Fatalf("list ctokens fail: %v", err)
+	}
+	if len(tokens) != 2 || tokens[1].Id != "token-002" {
+		t.Fatalf("issued tokens should be returned, got %+v", tokens)
+	}
+}
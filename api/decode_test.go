@@ -0,0 +1,130 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+)
+
+func newResponse(body []byte) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+	}
+}
+
+func utxoResponseBody(tb testing.TB, n int) []byte {
+	utxos := make([]*pw.UTXO, n)
+	for i := range utxos {
+		utxos[i] = &pw.UTXO{
+			SourceTxDataHash: "243eaa6e695cc4ce736e765395a64b8b917ff13a6c6500a11558b5e94e02556a",
+			CTokenId:         "ctoken-001",
+			Value:            100,
+			Addr:             "did:axn:002",
+			Founder:          "did:axn:001",
+		}
+	}
+	payload, err := json.Marshal(utxos)
+	if err != nil {
+		tb.Fatalf("%v", err)
+	}
+	body, err := json.Marshal(&rtstructs.Response{Payload: string(payload)})
+	if err != nil {
+		tb.Fatalf("%v", err)
+	}
+	return body
+}
+
+func TestDecodeResponse(t *testing.T) {
+	var result []*pw.UTXO
+	if err := decodeResponse(newResponse(utxoResponseBody(t, 3)), &result); err != nil {
+		t.Fatalf("decode response fail: %v", err)
+	}
+	if len(result) != 3 || result[0].CTokenId != "ctoken-001" {
+		t.Fatalf("decoded payload mismatch: %+v", result)
+	}
+
+	// payload must be a json string
+	body, _ := json.Marshal(&rtstructs.Response{Payload: map[string]string{"id": "did:axn:001"}})
+	err := decodeResponse(newResponse(body), &result)
+	if err == nil || !strings.Contains(err.Error(), "payload type invalid") {
+		t.Fatalf("err should report invalid payload type, not %v", err)
+	}
+}
+
+func TestUnescapeJSONString(t *testing.T) {
+	for _, quoted := range []string{
+		`"{\"id\":\"did:axn:001\",\"memo\":\"a\\tb\\\\c\/d\"}"`,
+		`"unicode \u00e9 \ud83d\ude00 \ud83d alone \ude00"`,
+		`"\b\f\n\r\t"`,
+		`""`,
+	} {
+		var expected string
+		if err := json.Unmarshal([]byte(quoted), &expected); err != nil {
+			t.Fatalf("%v", err)
+		}
+		data, ok := unescapeJSONString([]byte(quoted[1 : len(quoted)-1]))
+		if !ok || string(data) != expected {
+			t.Fatalf("unescape %s should be %q, not %q", quoted, expected, data)
+		}
+	}
+}
+
+func BenchmarkDecodeResponse(b *testing.B) {
+	body := utxoResponseBody(b, 20)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var result []*pw.UTXO
+		if err := decodeResponse(newResponse(body), &result); err != nil {
+			b.Fatalf("%v", err)
+		}
+	}
+}
+
+// BenchmarkDecodeResponseInterface is the former decoding, through an
+// interface{} payload and a reflect type check, kept for comparison.
+func BenchmarkDecodeResponseInterface(b *testing.B) {
+	body := utxoResponseBody(b, 20)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var respBody rtstructs.Response
+		if err := restapi.DecodeBody(newResponse(body), &respBody); err != nil {
+			b.Fatalf("%v", err)
+		}
+		payload, ok := respBody.Payload.(string)
+		if !ok {
+			b.Fatalf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
+		}
+		var result []*pw.UTXO
+		if err := json.Unmarshal([]byte(payload), &result); err != nil {
+			b.Fatalf("%v", err)
+		}
+	}
+}
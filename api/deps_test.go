@@ -0,0 +1,55 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"go/build"
+	"strings"
+	"testing"
+)
+
+// coreImports are the non standard library imports allowed in the core package.
+var coreImports = []string{
+	"github.com/arxanchain/sdk-go-common/",
+	"github.com/arxanchain/safebox-sdk-go/",
+	"github.com/arxanchain/wallet-sdk-go/crypto/",
+	// already required by the ed25519 signatures of sdk-go-common
+	"golang.org/x/crypto/ed25519",
+}
+
+func TestCoreDependencies(t *testing.T) {
+	pkg, err := build.ImportDir(".", 0)
+	if err != nil {
+		t.Fatalf("import core package fail: %v", err)
+	}
+	for _, path := range pkg.Imports {
+		if !strings.Contains(strings.SplitN(path, "/", 2)[0], ".") {
+			// standard library
+			continue
+		}
+		allowed := false
+		for _, prefix := range coreImports {
+			if strings.HasPrefix(path, prefix) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			t.Errorf("core package must not import %s, move the feature to its own package", path)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/utils"
+)
+
+const (
+	// SignKindRequest marks the signature of a wallet request payload
+	SignKindRequest = "request"
+	// SignKindUTXO marks the signature of an UTXO script public key
+	SignKindUTXO = "utxo"
+)
+
+// SignatureDump is the detail of a signature computed by the client, used to
+// troubleshoot signature verify failures against the gateway.
+//
+// Data is the base64 of the exact bytes being signed, DataText is the same
+// bytes as text when they are a json payload.
+//
+type SignatureDump struct {
+	Kind       string `json:"kind"`
+	Creator    string `json:"creator"`
+	Created    int64  `json:"created"`
+	Nonce      string `json:"nonce"`
+	Data       string `json:"data"`
+	DataText   string `json:"data_text,omitempty"`
+	DataSHA256 string `json:"data_sha256"`
+	Signature  string `json:"signature"`
+}
+
+// DumpSignature signs data with the signature params and returns the detail
+// of the signature, exactly as the client computes it.
+//
+func DumpSignature(signParams *pki.SignatureParam, data []byte) (*SignatureDump, error) {
+	signData, alg, err := buildSignature(signParams, data)
+	if err != nil {
+		return nil, err
+	}
+	return newSignatureDump(SignKindRequest, signParams, data, signatureValue(alg, utils.EncodeBase64(signData.Sign))), nil
+}
+
+func newSignatureDump(kind string, signParams *pki.SignatureParam, data []byte, sign string) *SignatureDump {
+	sum := sha256.Sum256(data)
+	dump := &SignatureDump{
+		Kind:       kind,
+		Creator:    string(signParams.Creator),
+		Created:    signParams.Created,
+		Nonce:      signParams.Nonce,
+		Data:       utils.EncodeBase64(data),
+		DataSHA256: hex.EncodeToString(sum[:]),
+		Signature:  sign,
+	}
+	var v interface{}
+	if json.Unmarshal(data, &v) == nil {
+		dump.DataText = string(data)
+	}
+	return dump
+}
+
+type signDiagnostics struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// SetSignDiagnostics makes the client write a SignatureDump, as one json
+// line, to out for every signature it computes. nil out disables it.
+//
+// The dump contains the full signed payload, privacy mode does not apply to
+// it, so it should only be enabled while troubleshooting.
+//
+func (w *WalletClient) SetSignDiagnostics(out io.Writer) {
+	if out == nil {
+		w.diagnostics = nil
+		return
+	}
+	w.diagnostics = &signDiagnostics{out: out}
+}
+
+// dumpSignature writes the signature detail when diagnostics are enabled,
+// sign is the base64 of the signature.
+func (w *WalletClient) dumpSignature(kind string, signParams *pki.SignatureParam, data []byte, sign string) {
+	d := w.diagnostics
+	if d == nil {
+		return
+	}
+	line, err := json.Marshal(newSignatureDump(kind, signParams, data, sign))
+	if err != nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.out.Write(append(line, '\n'))
+}
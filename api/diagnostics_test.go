@@ -0,0 +1,65 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+)
+
+func TestSignDiagnostics(t *testing.T) {
+	var out bytes.Buffer
+	client := &WalletClient{}
+	client.SetSignDiagnostics(&out)
+
+	sign := &pki.SignatureParam{
+		Creator:    "did:axn:001",
+		Created:    1520000000,
+		Nonce:      "helloalice",
+		PrivateKey: "WBZNmTTf34Kg+pQOTSIRL+JeQYDfj7InWc0A/9kvNvQSI8Ue8iRD8gn9CNmGO2EjJILF/3RELmEcbuS5G0d+Mg==",
+	}
+	reqBody, err := client.buildWalletRequest(nil, map[string]string{"id": "did:axn:002"}, sign)
+	if err != nil {
+		t.Fatalf("build wallet request fail: %v", err)
+	}
+
+	var dump SignatureDump
+	if err = json.Unmarshal(out.Bytes(), &dump); err != nil {
+		t.Fatalf("diagnostics output should be a signature dump: %v", err)
+	}
+	if dump.Kind != SignKindRequest || dump.Nonce != sign.Nonce || dump.Creator != "did:axn:001" {
+		t.Fatalf("signature dump header mismatch: %+v", dump)
+	}
+	if dump.DataText != reqBody.Payload {
+		t.Fatalf("signed data should be the request payload %s, not %s", reqBody.Payload, dump.DataText)
+	}
+	if dump.Signature != reqBody.Signature.SignatureValue {
+		t.Fatalf("dumped signature should be the request signature")
+	}
+
+	// the standalone dump computes the same signature
+	standalone, err := DumpSignature(sign, []byte(reqBody.Payload))
+	if err != nil {
+		t.Fatalf("dump signature fail: %v", err)
+	}
+	if standalone.Signature != dump.Signature || standalone.DataSHA256 != dump.DataSHA256 {
+		t.Fatalf("standalone dump should match the client dump")
+	}
+}
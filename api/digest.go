@@ -0,0 +1,101 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+)
+
+// digestField is the upload form field of the file digest, written after
+// the file contents as they are hashed while streamed. Its value has the
+// format of the RFC 3230 Digest header, e.g. "SHA-256=<base64>,MD5=<base64>".
+const digestField = "file_digest"
+
+// ErrUploadDigestMismatch is returned by the POE file uploads when the hash
+// recorded by the gateway is not the one of the sent file, e.g. when the
+// file was corrupted in transit.
+//
+var ErrUploadDigestMismatch = fmt.Errorf("poe file upload digest mismatch")
+
+// ErrUploadDigestMissing is returned by the POE file uploads when the
+// gateway does not return the hash it recorded for the file.
+//
+var ErrUploadDigestMissing = fmt.Errorf("poe file upload digest not recorded")
+
+// ErrUploadTruncated is returned by the POE file uploads when the gateway
+// replies before reading the whole form, so the file it recorded may be
+// truncated and its digest was not computed.
+//
+var ErrUploadTruncated = fmt.Errorf("poe file upload truncated")
+
+// SetUploadMD5 sets whether the POE file uploads send the MD5 digest of the
+// file besides its SHA-256 digest, for the gateways checking it. It is
+// disabled by default.
+//
+func (w *WalletClient) SetUploadMD5(enabled bool) {
+	w.uploadMD5 = enabled
+}
+
+// uploadDigest hashes an uploaded file.
+type uploadDigest struct {
+	sha256 hash.Hash
+	md5    hash.Hash
+}
+
+func (w *WalletClient) newUploadDigest() *uploadDigest {
+	d := &uploadDigest{sha256: sha256.New()}
+	if w.uploadMD5 {
+		d.md5 = md5.New()
+	}
+	return d
+}
+
+// writer returns the writer hashing the data.
+func (d *uploadDigest) writer() io.Writer {
+	if d.md5 == nil {
+		return d.sha256
+	}
+	return io.MultiWriter(d.sha256, d.md5)
+}
+
+// value returns the digest field value.
+func (d *uploadDigest) value() string {
+	v := "SHA-256=" + base64.StdEncoding.EncodeToString(d.sha256.Sum(nil))
+	if d.md5 != nil {
+		v += ",MD5=" + base64.StdEncoding.EncodeToString(d.md5.Sum(nil))
+	}
+	return v
+}
+
+// check compares the sha256 hash of the file, hex encoded as in the POE
+// digital assets, with the hash recorded by the gateway.
+func (d *uploadDigest) check(recorded string) error {
+	if recorded == "" {
+		return ErrUploadDigestMissing
+	}
+	if !strings.EqualFold(recorded, hex.EncodeToString(d.sha256.Sum(nil))) {
+		return ErrUploadDigestMismatch
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// digestClient returns a client replying to the uploads with the recorded hash
+func digestClient(t *testing.T, hash string) (*WalletClient, *formTransport) {
+	byPayload, err := json.Marshal(map[string]interface{}{"Id": "did:axn:poe-id-001", "hash": hash})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody, err := json.Marshal(&rtstructs.Response{Payload: string(byPayload)})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	transport := &formTransport{fields: map[string]string{}, resp: respBody}
+	client, err := NewWalletClient(&restapi.Config{
+		Address:    "http://127.0.0.1:8006",
+		HttpClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+	return client, transport
+}
+
+func TestUploadDigest(t *testing.T) {
+	content := []byte("poe file content")
+	sum := sha256.Sum256(content)
+	md5Sum := md5.Sum(content)
+
+	client, transport := digestClient(t, hex.EncodeToString(sum[:]))
+	client.SetUploadMD5(true)
+	result, err := client.UploadPOEFileFromReader(http.Header{}, "did:axn:poe-id-001", "file", -1, bytes.NewReader(content), false)
+	if err != nil {
+		t.Fatalf("upload poe file fail: %v", err)
+	}
+	if result == nil || result.Id != "did:axn:poe-id-001" {
+		t.Fatalf("upload response should be decoded, got %+v", result)
+	}
+	expected := "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:]) + ",MD5=" + base64.StdEncoding.EncodeToString(md5Sum[:])
+	if transport.fields[digestField] != expected {
+		t.Fatalf("digest field should be %s, got %s", expected, transport.fields[digestField])
+	}
+	if transport.fields[wallet.OffchainPOEFile] != string(content) {
+		t.Fatalf("file contents should be uploaded")
+	}
+}
+
+func TestUploadDigestMismatch(t *testing.T) {
+	sum := sha256.Sum256([]byte("corrupted content"))
+	client, transport := digestClient(t, hex.EncodeToString(sum[:]))
+
+	_, err := client.UploadPOEFileFromReader(http.Header{}, "did:axn:poe-id-001", "file", -1, bytes.NewReader([]byte("poe file content")), false)
+	if err != ErrUploadDigestMismatch {
+		t.Fatalf("err should be ErrUploadDigestMismatch, got %v", err)
+	}
+	if digest := transport.fields[digestField]; digest == "" || bytes.Contains([]byte(digest), []byte("MD5")) {
+		t.Fatalf("only the SHA-256 digest should be sent by default, got %q", digest)
+	}
+}
+
+func TestUploadDigestNotRecorded(t *testing.T) {
+	content := []byte("poe file content")
+
+	client, transport := digestClient(t, "")
+	transport.raw = true
+	_, err := client.UploadPOEFileFromReader(http.Header{}, "did:axn:poe-id-001", "file", -1, bytes.NewReader(content), false)
+	if err != ErrUploadDigestMissing {
+		t.Fatalf("err should be ErrUploadDigestMissing, got %v", err)
+	}
+
+	respBody, err := json.Marshal(payloadResponse(t, map[string]interface{}{"Id": "did:axn:poe-id-001", "hash": 1}))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	transport.resp = respBody
+	if _, err = client.UploadPOEFileFromReader(http.Header{}, "did:axn:poe-id-001", "file", -1, bytes.NewReader(content), false); err == nil {
+		t.Fatalf("err should not be nil when the recorded hash is invalid")
+	}
+}
+
+// cancelTransport cancels the context once the form is uploaded
+type cancelTransport struct {
+	*formTransport
+	cancel context.CancelFunc
+}
+
+func (c *cancelTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := c.formTransport.RoundTrip(req)
+	c.cancel()
+	return resp, err
+}
+
+func TestUploadDigestCancelled(t *testing.T) {
+	sum := sha256.Sum256([]byte("corrupted content"))
+	_, transport := digestClient(t, hex.EncodeToString(sum[:]))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client, err := NewWalletClient(&restapi.Config{
+		Address:    "http://127.0.0.1:8006",
+		HttpClient: &http.Client{Transport: &cancelTransport{formTransport: transport, cancel: cancel}},
+	})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+
+	_, err = client.WithContext(ctx).UploadPOEFileFromReader(http.Header{}, "did:axn:poe-id-001", "file", -1, bytes.NewReader([]byte("poe file content")), false)
+	if err != ErrUploadDigestMismatch && err != context.Canceled {
+		t.Fatalf("the digest should be checked when the context is done, got %v", err)
+	}
+}
+
+// earlyReplyTransport replies before reading the upload form
+type earlyReplyTransport struct {
+	resp []byte
+}
+
+func (e *earlyReplyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.Body.Close()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(e.resp)),
+		Request:    req,
+	}, nil
+}
+
+func TestUploadDigestEarlyReply(t *testing.T) {
+	content := []byte("poe file content")
+	sum := sha256.Sum256(content)
+	byPayload, err := json.Marshal(map[string]interface{}{"Id": "did:axn:poe-id-001", "hash": hex.EncodeToString(sum[:])})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody, err := json.Marshal(&rtstructs.Response{Payload: string(byPayload)})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	client, err := NewWalletClient(&restapi.Config{
+		Address:    "http://127.0.0.1:8006",
+		HttpClient: &http.Client{Transport: &earlyReplyTransport{resp: respBody}},
+	})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+
+	_, err = client.UploadPOEFileFromReader(http.Header{}, "did:axn:poe-id-001", "file", -1, bytes.NewReader(content), false)
+	if err != ErrUploadTruncated {
+		t.Fatalf("err should be ErrUploadTruncated, got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// DisputeStatus is the status of a dispute.
+//
+type DisputeStatus string
+
+const (
+	// DisputeOpened means the dispute is waiting for resolution
+	DisputeOpened DisputeStatus = "opened"
+	// DisputeResolved means the dispute has been resolved
+	DisputeResolved DisputeStatus = "resolved"
+)
+
+// DisputeResolution is the outcome of a resolved dispute.
+//
+type DisputeResolution string
+
+const (
+	// ResolutionRefunded means the payer won and has been refunded
+	ResolutionRefunded DisputeResolution = "refunded"
+	// ResolutionRejected means the payee won and the transaction stands
+	ResolutionRejected DisputeResolution = "rejected"
+	// ResolutionWithdrawn means the dispute has been withdrawn by its opener
+	ResolutionWithdrawn DisputeResolution = "withdrawn"
+)
+
+// OpenDisputeBody is the request body of OpenDispute.
+//
+type OpenDisputeBody struct {
+	TxId           string           `json:"tx_id"`
+	OpenedBy       did.Identifier   `json:"opened_by"`
+	Reason         string           `json:"reason"`
+	EvidencePOEIds []did.Identifier `json:"evidence_poe_ids,omitempty"`
+}
+
+// DisputeEvidenceBody is the request body of AddDisputeEvidence.
+//
+// The evidences are POE digital assets created beforehand with CreatePOE.
+//
+type DisputeEvidenceBody struct {
+	DisputeId      string           `json:"dispute_id"`
+	SubmittedBy    did.Identifier   `json:"submitted_by"`
+	EvidencePOEIds []did.Identifier `json:"evidence_poe_ids"`
+	Comment        string           `json:"comment,omitempty"`
+}
+
+// ResolveDisputeBody is the request body of ResolveDispute.
+//
+type ResolveDisputeBody struct {
+	DisputeId  string            `json:"dispute_id"`
+	ResolvedBy did.Identifier    `json:"resolved_by"`
+	Resolution DisputeResolution `json:"resolution"`
+	RefundTxId string            `json:"refund_tx_id,omitempty"`
+	Comment    string            `json:"comment,omitempty"`
+}
+
+// DisputeEvidence is one evidence submission of a dispute.
+//
+type DisputeEvidence struct {
+	SubmittedBy    did.Identifier   `json:"submitted_by"`
+	EvidencePOEIds []did.Identifier `json:"evidence_poe_ids"`
+	Comment        string           `json:"comment,omitempty"`
+	Created        int64            `json:"created"`
+}
+
+// Dispute is the dispute record attached to a transaction.
+//
+type Dispute struct {
+	DisputeId  string             `json:"dispute_id"`
+	TxId       string             `json:"tx_id"`
+	OpenedBy   did.Identifier     `json:"opened_by"`
+	Reason     string             `json:"reason"`
+	Status     DisputeStatus      `json:"status"`
+	Evidences  []*DisputeEvidence `json:"evidences,omitempty"`
+	Resolution DisputeResolution  `json:"resolution,omitempty"`
+	ResolvedBy did.Identifier     `json:"resolved_by,omitempty"`
+	RefundTxId string             `json:"refund_tx_id,omitempty"`
+	Created    int64              `json:"created"`
+	Updated    int64              `json:"updated"`
+}
+
+// OpenDispute is used to open a dispute on a transaction.
+//
+// The response Id is the dispute ID.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) OpenDispute(header http.Header, body *OpenDisputeBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.TxId == "" || body.OpenedBy == "" {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	return w.sendDisputeRequest(header, "/v2/transaction/disputes/open", body, signParams)
+}
+
+// AddDisputeEvidence is used to attach evidence POE digital assets to an opened dispute.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) AddDisputeEvidence(header http.Header, body *DisputeEvidenceBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.DisputeId == "" || len(body.EvidencePOEIds) == 0 {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	return w.sendDisputeRequest(header, "/v2/transaction/disputes/evidence", body, signParams)
+}
+
+// ResolveDispute is used to record the resolution of a dispute.
+//
+// When the resolution is a refund, RefundTxId should be set to the
+// transaction created by Refund.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) ResolveDispute(header http.Header, body *ResolveDisputeBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.DisputeId == "" || body.Resolution == "" {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	return w.sendDisputeRequest(header, "/v2/transaction/disputes/resolve", body, signParams)
+}
+
+func (w *WalletClient) sendDisputeRequest(header http.Header, path string, body interface{}, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	// Build request body
+	reqBody, err := w.buildWalletRequest(header, body, signParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = w.doJSON(header, "POST", path, nil, reqBody, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// QueryDisputes is used to query the disputes attached to a transaction.
+//
+func (w *WalletClient) QueryDisputes(header http.Header, txID string) (result []*Dispute, err error) {
+	if txID == "" {
+		err = fmt.Errorf("transaction id invalid")
+		return
+	}
+
+	if err = w.doJSON(header, "GET", "/v2/transaction/disputes", url.Values{"tx_id": {txID}}, nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestOpenDisputeSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		token     = "user-token-001"
+		disputeID = "dispute-id-001"
+		transID   = "trans-id-002"
+	)
+
+	//request body & response body
+	reqBody := &OpenDisputeBody{
+		TxId:           "trans-id-001",
+		OpenedBy:       "did:axn:001",
+		Reason:         "goods not received",
+		EvidencePOEIds: []did.Identifier{"did:axn:poe-id-001"},
+	}
+	sign := &pki.SignatureParam{
+		Creator:    "did:axn:001",
+		Nonce:      "helloalice",
+		PrivateKey: "WBZNmTTf34Kg+pQOTSIRL+JeQYDfj7InWc0A/9kvNvQSI8Ue8iRD8gn9CNmGO2EjJILF/3RELmEcbuS5G0d+Mg==",
+	}
+	payload := &wallet.WalletResponse{
+		Id:             disputeID,
+		TransactionIds: []string{transID},
+	}
+	byPayload, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody := &rtstructs.Response{
+		ErrCode: 0,
+		Payload: string(byPayload),
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/disputes/open").
+		MatchHeader("X-Auth-Token", token).
+		Reply(200).
+		JSON(respBody)
+
+	//set http header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	//do open dispute
+	resp, err := client.OpenDispute(header, reqBody, sign)
+	if err != nil {
+		t.Fatalf("open dispute fail: %v", err)
+	}
+	if resp == nil || resp.Id != disputeID {
+		t.Fatalf("response dispute id should be %v", disputeID)
+	}
+}
+
+func TestQueryDisputesSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		token     = "user-token-001"
+		txID      = "trans-id-001"
+		disputeID = "dispute-id-001"
+	)
+
+	//response body
+	payload := []*Dispute{
+		{
+			DisputeId:  disputeID,
+			TxId:       txID,
+			Status:     DisputeResolved,
+			Resolution: ResolutionRefunded,
+		},
+	}
+	byPayload, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody := &rtstructs.Response{
+		ErrCode: 0,
+		Payload: string(byPayload),
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/transaction/disputes").
+		MatchParam("tx_id", txID).
+		MatchHeader("X-Auth-Token", token).
+		Reply(200).
+		JSON(respBody)
+
+	//set http header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	//do query disputes
+	disputes, err := client.QueryDisputes(header, txID)
+	if err != nil {
+		t.Fatalf("query disputes fail: %v", err)
+	}
+	if len(disputes) != 1 || disputes[0].Resolution != ResolutionRefunded {
+		t.Fatalf("dispute resolution should be %v", ResolutionRefunded)
+	}
+}
@@ -0,0 +1,284 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// Recipient is a recipient of a colored token distribution.
+//
+type Recipient struct {
+	To     string `json:"to"`
+	Amount int64  `json:"amount"`
+}
+
+// ReadRecipients reads the recipients of a distribution from CSV records of
+// two fields, the recipient wallet and the amount. A first record whose
+// amount is not a number, e.g. "wallet,amount", is skipped as header.
+//
+func ReadRecipients(r io.Reader) ([]*Recipient, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	var recipients []*Recipient
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return recipients, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		amount, err := strconv.ParseInt(strings.TrimSpace(record[1]), 10, 64)
+		if err != nil {
+			if line == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: amount invalid", line)
+		}
+		to := strings.TrimSpace(record[0])
+		if to == "" || amount <= 0 {
+			return nil, fmt.Errorf("line %d: recipient invalid", line)
+		}
+		recipients = append(recipients, &Recipient{To: to, Amount: amount})
+	}
+}
+
+// DistributeCTokenBody is the request body of DistributeCToken.
+//
+type DistributeCTokenBody struct {
+	From       string
+	TokenId    string
+	Recipients []*Recipient
+}
+
+// DistributionOptions sets the pace of DistributeCToken, zero values mean no
+// wait and no retry.
+//
+type DistributionOptions struct {
+	// Interval is the minimum time between two batch requests
+	Interval time.Duration
+	// Retries is the number of times a transfer failing with a retryable
+	// error, see ClassifyError, is sent again
+	Retries int
+	// RetryDelay is the wait before sending again the failed transfers
+	RetryDelay time.Duration
+}
+
+// DistributionItem is the outcome of the transfer to one recipient.
+//
+type DistributionItem struct {
+	Recipient
+	// TransactionId is the id of the transfer, if it succeeded
+	TransactionId string
+	// Attempts is the number of times the transfer was sent
+	Attempts int
+	// Err is the error of the last attempt, nil if it succeeded
+	Err error
+}
+
+// DistributionReport holds the outcome of the transfers of a distribution,
+// in the order of the recipients.
+//
+type DistributionReport struct {
+	Items []*DistributionItem
+}
+
+// Failed returns the transfers which failed.
+//
+func (r *DistributionReport) Failed() []*DistributionItem {
+	var failed []*DistributionItem
+	for _, item := range r.Items {
+		if item.Err != nil {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// Distributed returns the amount transferred by the transfers which
+// succeeded.
+//
+func (r *DistributionReport) Distributed() int64 {
+	var total int64
+	for _, item := range r.Items {
+		if item.Err == nil {
+			total += item.Amount
+		}
+	}
+	return total
+}
+
+// DistributeCToken is used to distribute colored tokens from one wallet to
+// many recipients, e.g. to pay dividends or to airdrop tokens.
+//
+// The transfers are sent with TransferCTokenBatch in requests of the batch
+// size, see SetBatchSize, at most one request per opts.Interval. The
+// transfers failing with a retryable error are sent again opts.Retries
+// times. A batch request whose outcome is unknown is sent again with the
+// same idempotency key, so the gateway does not process it twice.
+//
+// The report holds the outcome of every recipient, err is only returned
+// when the body is invalid or when the client context is done, with the
+// report of the transfers sent so far.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+func (w *WalletClient) DistributeCToken(header http.Header, body *DistributeCTokenBody, opts *DistributionOptions, signParams *pki.SignatureParam) (report *DistributionReport, err error) {
+	if body == nil || body.From == "" || body.TokenId == "" || len(body.Recipients) == 0 {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	for i, r := range body.Recipients {
+		if r == nil || r.To == "" || r.Amount <= 0 {
+			err = fmt.Errorf("recipient %d invalid", i)
+			return
+		}
+	}
+	if opts == nil {
+		opts = &DistributionOptions{}
+	}
+
+	report = &DistributionReport{Items: make([]*DistributionItem, len(body.Recipients))}
+	pending := make([]int, len(body.Recipients))
+	for i, r := range body.Recipients {
+		report.Items[i] = &DistributionItem{Recipient: *r}
+		pending[i] = i
+	}
+
+	d := &distribution{w: w, header: header, body: body, opts: opts, signParams: signParams, report: report}
+	for attempt := 0; len(pending) > 0; attempt++ {
+		if attempt > 0 {
+			if err = d.wait(opts.RetryDelay); err != nil {
+				return
+			}
+		}
+		var retry []int
+		size := w.getBatchSize()
+		for lo := 0; lo < len(pending); lo += size {
+			hi := lo + size
+			if hi > len(pending) {
+				hi = len(pending)
+			}
+			failed, err := d.send(pending[lo:hi])
+			if err != nil {
+				return report, err
+			}
+			retry = append(retry, failed...)
+		}
+		pending = retry
+	}
+
+	return report, nil
+}
+
+// distribution is the state of a DistributeCToken call.
+type distribution struct {
+	w          *WalletClient
+	header     http.Header
+	body       *DistributeCTokenBody
+	opts       *DistributionOptions
+	signParams *pki.SignatureParam
+	report     *DistributionReport
+	last       time.Time
+}
+
+// send sends the transfers to the recipients at indexes in one batch
+// request, sending it again with the same idempotency key while the request
+// fails as a whole, and returns the transfers to send again.
+func (d *distribution) send(indexes []int) (retry []int, err error) {
+	key, err := NewIdempotencyKey()
+	if err != nil {
+		return nil, err
+	}
+	header := ApplyOptions(d.header, WithIdempotencyKey(key))
+	transfers := make([]*wallet.TransferCTokenBody, len(indexes))
+	for i, index := range indexes {
+		r := d.report.Items[index]
+		transfers[i] = &wallet.TransferCTokenBody{
+			From:   d.body.From,
+			To:     r.To,
+			Tokens: []*wallet.TokenAmount{{TokenId: d.body.TokenId, Amount: r.Amount}},
+		}
+	}
+
+	for {
+		if err = d.wait(d.opts.Interval - time.Since(d.last)); err != nil {
+			return nil, err
+		}
+		d.last = time.Now()
+		result, err := d.w.TransferCTokenBatch(header, transfers, d.signParams)
+		if err != nil {
+			return nil, err
+		}
+
+		retry = retry[:0]
+		for i, res := range result.Items {
+			item := d.report.Items[indexes[i]]
+			item.Attempts++
+			item.TransactionId, item.Err = res.Id, res.Err
+			if res.Err != nil && item.Attempts <= d.opts.Retries && d.w.ClassifyError(res.Err) == ErrorRetryable {
+				retry = append(retry, indexes[i])
+			}
+		}
+		if len(retry) < len(indexes) || !batchRequestFailed(result) {
+			return retry, nil
+		}
+		// the request failed as a whole, its outcome is unknown
+		if err = d.wait(d.opts.RetryDelay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// wait waits for d, giving up when the client context is done.
+func (d *distribution) wait(delay time.Duration) error {
+	if delay <= 0 {
+		return d.w.Context().Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-d.w.Context().Done():
+		return d.w.Context().Err()
+	}
+}
+
+// batchRequestFailed reports whether every item of a batch failed with the
+// same error, the error of the batch request.
+func batchRequestFailed(result *BatchResult) bool {
+	for _, item := range result.Items {
+		if item.Err == nil || item.Err != result.Items[0].Err {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,159 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+)
+
+func TestReadRecipients(t *testing.T) {
+	recipients, err := ReadRecipients(strings.NewReader("wallet,amount\ndid:axn:001, 100\ndid:axn:002,250\n"))
+	if err != nil {
+		t.Fatalf("read recipients fail: %v", err)
+	}
+	if len(recipients) != 2 || recipients[0].To != "did:axn:001" || recipients[1].Amount != 250 {
+		t.Fatalf("recipients should be read, got %+v", recipients)
+	}
+
+	for _, data := range []string{
+		"did:axn:001,100\ndid:axn:002,many\n",
+		"did:axn:001,100\ndid:axn:002,-5\n",
+		"did:axn:001\n",
+	} {
+		if _, err = ReadRecipients(strings.NewReader(data)); err == nil {
+			t.Errorf("err should not be nil reading %q", data)
+		}
+	}
+}
+
+func TestDistributeCTokenRetries(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		keys []string
+	)
+	replies := []func(w http.ResponseWriter){
+		// the first request fails as a whole
+		func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+		// the same request succeeds for the first recipient only
+		func(w http.ResponseWriter) {
+			writeBatchResponse(t, w, &batchResponse{Results: []*batchItemResponse{
+				{Index: 0, Id: "tx-001"},
+				{Index: 1, ErrCode: 9001, Message: "utxo locked"},
+			}})
+		},
+		// the last recipient, in the second batch
+		func(w http.ResponseWriter) {
+			writeBatchResponse(t, w, &batchResponse{Results: []*batchItemResponse{{Index: 0, Id: "tx-003"}}})
+		},
+		// the failed transfer is sent again alone
+		func(w http.ResponseWriter) {
+			writeBatchResponse(t, w, &batchResponse{Results: []*batchItemResponse{{Index: 0, Id: "tx-002"}}})
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		n := len(keys)
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		mu.Unlock()
+		if r.URL.Path != "/v1/transaction/tokens/transfer/batch" || n >= len(replies) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		replies[n](w)
+	}))
+	defer server.Close()
+
+	client, err := NewWalletClient(&restapi.Config{Address: server.URL, HttpClient: &http.Client{}})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+	client.SetBatchSize(2)
+	client.SetErrorClass(9001, ErrorRetryable)
+
+	body := &DistributeCTokenBody{
+		From:    "did:axn:issuer-001",
+		TokenId: "token-001",
+		Recipients: []*Recipient{
+			{To: "did:axn:001", Amount: 10},
+			{To: "did:axn:002", Amount: 20},
+			{To: "did:axn:003", Amount: 30},
+		},
+	}
+	report, err := client.DistributeCToken(http.Header{}, body, &DistributionOptions{Retries: 2}, verifySignParams)
+	if err != nil {
+		t.Fatalf("distribute ctoken fail: %v", err)
+	}
+	if len(report.Failed()) != 0 || report.Distributed() != 60 {
+		t.Fatalf("every transfer should succeed, got %+v", report.Failed())
+	}
+	if report.Items[1].TransactionId != "tx-002" || report.Items[1].Attempts != 3 || report.Items[2].TransactionId != "tx-003" {
+		t.Fatalf("report should hold the outcome of every recipient, got %+v %+v", report.Items[1], report.Items[2])
+	}
+
+	if len(keys) != 4 {
+		t.Fatalf("4 batch requests should be sent, got %d", len(keys))
+	}
+	if keys[0] == "" || keys[0] != keys[1] {
+		t.Fatalf("the failed request should be sent again with the same idempotency key")
+	}
+	if keys[2] == keys[1] || keys[3] == keys[2] {
+		t.Fatalf("new batches should get new idempotency keys")
+	}
+}
+
+func TestDistributeCTokenNoRetry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := NewWalletClient(&restapi.Config{Address: server.URL, HttpClient: &http.Client{}})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+	body := &DistributeCTokenBody{
+		From:       "did:axn:issuer-001",
+		TokenId:    "token-001",
+		Recipients: []*Recipient{{To: "did:axn:001", Amount: 10}},
+	}
+	report, err := client.DistributeCToken(http.Header{}, body, nil, verifySignParams)
+	if err != nil {
+		t.Fatalf("distribute ctoken fail: %v", err)
+	}
+	if failed := report.Failed(); len(failed) != 1 || failed[0].Attempts != 1 {
+		t.Fatalf("the transfer should fail after one attempt, got %+v", failed)
+	}
+
+	body.Recipients = []*Recipient{{To: "did:axn:001"}}
+	if _, err = client.DistributeCToken(http.Header{}, body, nil, verifySignParams); err == nil {
+		t.Fatalf("err should not be nil without amount")
+	}
+}
+
+func writeBatchResponse(t *testing.T, w http.ResponseWriter, resp *batchResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(payloadResponse(t, resp)); err != nil {
+		t.Errorf("%v", err)
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package api is the core of the wallet SDK: the wallet client with the
+// wallet, POE, colored token and digital asset APIs, and request signing.
+//
+// The core only depends on the standard library, sdk-go-common, safebox-sdk-go
+// and the crypto packages of this repository. Heavier or optional features
+// live in their own packages on top of it, so programs only pull the
+// dependencies of the features they import:
+//
+//	outbox       offline queue and exactly-once submission
+//	saga         multi-step flows with compensation
+//	statements   periodic account statements
+//	audit        audit log and signed audit reports
+//	mocks        fake Client for offline unit tests
+//	keystore     encrypted local key storage
+//	logadapter   Logger adapters
+//	headers      http header builder
+//	config       configuration files and environment
+//	storage      offchain stores of the POE files
+//	webhook      signed callback events
+//	tracing/...  Tracer implementations
+//	metrics/...  Metrics implementations
+//	signer/...   Signer implementations backed by HSMs and key services
+//	cmd/...      command line tools
+//
+// New integrations, such as key management services or metrics exporters,
+// belong in such packages and must not be imported by the core.
+package api
@@ -0,0 +1,101 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// ErrPOEFileHashMismatch is returned by DownloadPOEFile when the downloaded
+// file does not match the hash recorded on chain.
+//
+var ErrPOEFileHashMismatch = fmt.Errorf("poe file hash mismatch")
+
+// DownloadPOEFile is used to download the offchain file of specified POE
+// digital asset, the file contents are streamed to out.
+//
+// The sha256 hash of the downloaded file is verified against the hash of
+// the POE digital asset recorded on chain, which is returned on success.
+// On ErrPOEFileHashMismatch, the data written to out must be discarded.
+//
+func (w *WalletClient) DownloadPOEFile(header http.Header, poeID did.Identifier, out io.Writer) (poe *wallet.POEPayload, err error) {
+	if poeID == "" {
+		err = fmt.Errorf("poe id invalid")
+		return
+	}
+	if out == nil {
+		err = fmt.Errorf("writer must be set when downloading poe file")
+		return
+	}
+
+	// Query the hash recorded on chain
+	poe, err = w.QueryPOE(header, poeID)
+	if err != nil {
+		return nil, err
+	}
+	if poe == nil || poe.Hash == "" {
+		return nil, fmt.Errorf("poe %s has no file hash", w.sensitive(string(poeID)))
+	}
+
+	// Build http request
+	header = w.withDefaultHeader(header)
+	r := w.c.NewRequest("GET", w.endpoint("/v1/poe/download"))
+	r.SetHeaders(header)
+	r.SetParam("id", string(poeID))
+	trace := w.startCall(r, "GET", "/v1/poe/download", url.Values{"id": {string(poeID)}}, header)
+	defer func() { trace.finish(err) }()
+
+	_, resp, err := w.requireOK(w.do(r))
+	if err != nil {
+		return nil, err
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == "application/json" {
+		// errors are returned in the response envelope
+		if err = decodeResponse(resp, nil); err == nil {
+			err = fmt.Errorf("poe file download response invalid")
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if w.downProgress != nil {
+		out = &progressWriter{w: out, progress: w.downProgress, total: resp.ContentLength}
+	}
+
+	// Stream the file while hashing it
+	h := sha256.New()
+	copyBuf := getCopyBuffer()
+	_, err = io.CopyBuffer(io.MultiWriter(out, h), resp.Body, *copyBuf)
+	putCopyBuffer(copyBuf)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(hex.EncodeToString(h.Sum(nil)), poe.Hash) {
+		return nil, ErrPOEFileHashMismatch
+	}
+
+	return poe, nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func mockDownload(t *testing.T, poeID did.Identifier, hash string, content string) {
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/poe$").
+		MatchParam("id", string(poeID)).
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.POEPayload{Id: poeID, Hash: hash}))
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/poe/download").
+		MatchParam("id", string(poeID)).
+		Reply(200).
+		SetHeader("Content-Type", "application/octet-stream").
+		BodyString(content)
+}
+
+func TestDownloadPOEFileSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		poeID   = did.Identifier("did:axn:poe-id-001")
+		content = "temporary file's content"
+	)
+	sum := sha256.Sum256([]byte(content))
+	mockDownload(t, poeID, hex.EncodeToString(sum[:]), content)
+
+	var out bytes.Buffer
+	poe, err := client.DownloadPOEFile(http.Header{}, poeID, &out)
+	if err != nil {
+		t.Fatalf("download poe file fail: %v", err)
+	}
+	if poe == nil || poe.Id != poeID {
+		t.Fatalf("POE digital asset %v should be returned", poeID)
+	}
+	if out.String() != content {
+		t.Fatalf("downloaded content should be %q, got %q", content, out.String())
+	}
+}
+
+func TestDownloadPOEFileHashMismatch(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const poeID = did.Identifier("did:axn:poe-id-001")
+	sum := sha256.Sum256([]byte("original content"))
+	mockDownload(t, poeID, hex.EncodeToString(sum[:]), "tampered content")
+
+	var out bytes.Buffer
+	poe, err := client.DownloadPOEFile(http.Header{}, poeID, &out)
+	if err != ErrPOEFileHashMismatch {
+		t.Fatalf("err should be ErrPOEFileHashMismatch, got %v", err)
+	}
+	if poe != nil {
+		t.Fatalf("POE digital asset should be nil when the hash mismatches")
+	}
+}
+
+func TestDownloadPOEFileFailErrCode(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		poeID   = did.Identifier("did:axn:poe-id-001")
+		errCode = 8000
+		errMsg  = "offchain file erased"
+	)
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/poe$").
+		MatchParam("id", string(poeID)).
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.POEPayload{Id: poeID, Hash: "00"}))
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/poe/download").
+		Reply(200).
+		JSON(&rtstructs.Response{ErrCode: errCode, ErrMessage: errMsg})
+
+	_, err := client.DownloadPOEFile(http.Header{}, poeID, &bytes.Buffer{})
+	if e, ok := err.(*Error); !ok || e.ErrCode != errCode || e.Error() != errMsg {
+		t.Fatalf("err should carry the gateway error code, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+)
+
+// EraseOffchainBody is the request body of EraseOffchainData.
+//
+type EraseOffchainBody struct {
+	PoeId  did.Identifier `json:"poe_id"`
+	Reason string         `json:"reason,omitempty"`
+}
+
+// OffchainErasure is the erasure marker recorded when the offchain copy of
+// a POE file is deleted, the file hash stays on chain.
+//
+type OffchainErasure struct {
+	PoeId    did.Identifier `json:"poe_id"`
+	FileHash string         `json:"file_hash"`
+	Reason   string         `json:"reason,omitempty"`
+	ErasedBy did.Identifier `json:"erased_by"`
+	Erased   int64          `json:"erased"`
+	TxId     string         `json:"tx_id"`
+}
+
+// EraseOffchainData is used to delete the offchain copy of the file uploaded
+// for a POE digital asset, e.g. to honour a right-to-be-forgotten request.
+//
+// The gateway deletes the offchain copy and records an erasure marker on
+// chain, only the hash of the file is kept. The erasure can be proven later
+// with QueryOffchainErasure.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) EraseOffchainData(header http.Header, body *EraseOffchainBody, signParams *pki.SignatureParam) (result *OffchainErasure, err error) {
+	if body == nil || body.PoeId == "" {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+
+	// Build request body
+	reqBody, err := w.buildWalletRequest(header, body, signParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = w.doJSON(header, "POST", "/v1/poe/offchain/erase", nil, reqBody, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// QueryOffchainErasure is used to query the erasure marker of a POE digital
+// asset, proving its offchain data has been erased.
+//
+func (w *WalletClient) QueryOffchainErasure(header http.Header, poeID did.Identifier) (result *OffchainErasure, err error) {
+	if poeID == "" {
+		err = fmt.Errorf("poe id invalid")
+		return
+	}
+
+	if err = w.doJSON(header, "GET", "/v1/poe/offchain/erasure", url.Values{"id": {string(poeID)}}, nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/rest"
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestEraseOffchainDataSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		token = "user-token-001"
+		poeID = "did:axn:poe-id-001"
+	)
+
+	//request body & response body
+	reqBody := &EraseOffchainBody{
+		PoeId:  poeID,
+		Reason: "right to be forgotten",
+	}
+	sign := &pki.SignatureParam{
+		Creator:    "did:axn:001",
+		Nonce:      "helloalice",
+		PrivateKey: "WBZNmTTf34Kg+pQOTSIRL+JeQYDfj7InWc0A/9kvNvQSI8Ue8iRD8gn9CNmGO2EjJILF/3RELmEcbuS5G0d+Mg==",
+	}
+	payload := &OffchainErasure{
+		PoeId:    poeID,
+		FileHash: "8b1a9953c4611296a827abf8c47804d7",
+		ErasedBy: "did:axn:001",
+		TxId:     "trans-id-001",
+	}
+	byPayload, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody := &rtstructs.Response{
+		ErrCode: 0,
+		Payload: string(byPayload),
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Post("/v1/poe/offchain/erase").
+		MatchHeader("X-Auth-Token", token).
+		Reply(200).
+		JSON(respBody)
+
+	//set http header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	//do erase offchain data
+	resp, err := client.EraseOffchainData(header, reqBody, sign)
+	if err != nil {
+		t.Fatalf("erase offchain data fail: %v", err)
+	}
+	if resp == nil || resp.FileHash != payload.FileHash {
+		t.Fatalf("erasure file hash should be %v", payload.FileHash)
+	}
+}
+
+func TestQueryOffchainErasureFailErrCode(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		token   = "user-token-001"
+		poeID   = "did:axn:poe-id-001"
+		errCode = 1000
+		errMsg  = "erasure not found"
+	)
+
+	//response body
+	respBody := &rtstructs.Response{
+		ErrCode:    errCode,
+		ErrMessage: errMsg,
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/poe/offchain/erasure").
+		MatchParam("id", poeID).
+		MatchHeader("X-Auth-Token", token).
+		Reply(200).
+		JSON(respBody)
+
+	//set http header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	//do query erasure
+	resp, err := client.QueryOffchainErasure(header, poeID)
+	if err == nil {
+		t.Fatalf("err should not be nil when no erasure was recorded")
+	}
+	if resp != nil {
+		t.Fatalf("response object should be nil when no erasure was recorded")
+	}
+	errWithCode, ok := err.(rest.HTTPCodedError)
+	if !ok {
+		t.Fatalf("err type should be HTTPCodedError")
+	}
+	if errWithCode.Code() != errCode {
+		t.Fatalf("return error code should be %v", errCode)
+	}
+}
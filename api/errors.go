@@ -0,0 +1,151 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/errors"
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+)
+
+// Error is returned when the gateway rejects a request, either with an
+// unexpected http status or with an error code in the response body.
+// It implements rest.HTTPCodedError, Code returns the gateway error code.
+//
+// With Go 1.13 and later, errors.As gets the *Error from a returned error
+// and errors.Is matches it against the sentinel errors below.
+//
+type Error struct {
+	// Method and Endpoint are the http method and path of the request
+	Method   string
+	Endpoint string
+	// StatusCode is the http status of the response
+	StatusCode int
+	// ErrCode is the gateway error code, zero if the response had none
+	ErrCode int
+	// Message is the error message
+	Message string
+	// Payload is the raw response payload or body
+	Payload string
+}
+
+// Error returns the error message.
+//
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.ErrCode != 0 {
+		return fmt.Sprintf("error code %d", e.ErrCode)
+	}
+	return http.StatusText(e.StatusCode)
+}
+
+// Code returns the gateway error code.
+//
+func (e *Error) Code() int {
+	return e.ErrCode
+}
+
+// Is reports whether the error matches target, an *Error whose non zero
+// StatusCode and ErrCode must be the same as the ones of the error.
+//
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok || (t.StatusCode == 0 && t.ErrCode == 0) {
+		return false
+	}
+	if t.StatusCode != 0 && t.StatusCode != e.StatusCode {
+		return false
+	}
+	if t.ErrCode != 0 && t.ErrCode != e.ErrCode {
+		return false
+	}
+	return true
+}
+
+// Sentinel errors matched by errors.Is.
+//
+var (
+	// ErrBadRequest matches errors with the 400 http status
+	ErrBadRequest = &Error{StatusCode: http.StatusBadRequest, Message: "bad request"}
+	// ErrUnauthorized matches errors with the 401 http status
+	ErrUnauthorized = &Error{StatusCode: http.StatusUnauthorized, Message: "unauthorized"}
+	// ErrForbidden matches errors with the 403 http status
+	ErrForbidden = &Error{StatusCode: http.StatusForbidden, Message: "forbidden"}
+	// ErrNotFound matches errors with the 404 http status
+	ErrNotFound = &Error{StatusCode: http.StatusNotFound, Message: "not found"}
+	// ErrTooManyRequests matches errors with the 429 http status
+	ErrTooManyRequests = &Error{StatusCode: http.StatusTooManyRequests, Message: "too many requests"}
+	// ErrServiceUnavailable matches errors with the 503 http status
+	ErrServiceUnavailable = &Error{StatusCode: http.StatusServiceUnavailable, Message: "service unavailable"}
+	// ErrInvalidBase64Data matches errors with the invalid base64 data code
+	ErrInvalidBase64Data = &Error{ErrCode: errors.SDKInvalidBase64Data, Message: "invalid base64 data"}
+)
+
+// newError returns the *Error of a response, its request method and path
+// are taken from the response.
+func newError(resp *http.Response, code int, message string, payload []byte) *Error {
+	e := &Error{
+		StatusCode: resp.StatusCode,
+		ErrCode:    code,
+		Message:    message,
+		Payload:    string(payload),
+	}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		if resp.Request.URL != nil {
+			e.Endpoint = resp.Request.URL.Path
+		}
+	}
+	return e
+}
+
+// maxErrorBody is the most of an error response body read by requireOK.
+const maxErrorBody = 64 << 10
+
+// requireOK is like restapi.RequireOK, the error of an unexpected http
+// status is an *Error carrying the gateway error code of the body, if any.
+// At most maxErrorBody bytes of the body are read, the body is redacted
+// from the error message in privacy mode.
+func (w *WalletClient) requireOK(d time.Duration, resp *http.Response, err error) (time.Duration, *http.Response, error) {
+	if err != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return d, nil, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return d, resp, nil
+	}
+
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	resp.Body.Close()
+	var respBody rtstructs.Response
+	code := 0
+	if json.Unmarshal(body, &respBody) == nil {
+		code = int(respBody.ErrCode)
+	}
+	message := fmt.Sprintf("Unexpected response code: %d (%s)", resp.StatusCode, w.sensitive(string(body)))
+	return d, nil, newError(resp, code, message, body)
+}
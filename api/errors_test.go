@@ -0,0 +1,188 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/rest"
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestErrorCoded(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		id      = "did:axn:001"
+		errCode = 8000
+		errMsg  = "poe not found"
+	)
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/poe").
+		MatchParam("id", id).
+		Reply(200).
+		JSON(&rtstructs.Response{ErrCode: errCode, ErrMessage: errMsg})
+
+	_, err := client.QueryPOE(http.Header{}, id)
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("error type should be *Error, got %#v", err)
+	}
+	if e.Method != "GET" || e.Endpoint != "/v1/poe" {
+		t.Fatalf("error endpoint should be GET /v1/poe, got %s %s", e.Method, e.Endpoint)
+	}
+	if e.StatusCode != http.StatusOK || e.ErrCode != errCode || e.Error() != errMsg {
+		t.Fatalf("error fields mismatch: %#v", e)
+	}
+	if coded, ok := err.(rest.HTTPCodedError); !ok || coded.Code() != errCode {
+		t.Fatalf("error should be a HTTPCodedError with code %d", errCode)
+	}
+	if !e.Is(&Error{ErrCode: errCode}) {
+		t.Fatalf("error should match its error code")
+	}
+	if e.Is(ErrNotFound) {
+		t.Fatalf("error should not match the 404 http status")
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		id      = "did:axn:001"
+		errCode = 8000
+		errMsg  = "poe not found"
+	)
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/poe").
+		MatchParam("id", id).
+		Reply(http.StatusNotFound).
+		JSON(&rtstructs.Response{ErrCode: errCode, ErrMessage: errMsg})
+
+	_, err := client.QueryPOE(http.Header{}, id)
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("error type should be *Error, got %#v", err)
+	}
+	if e.StatusCode != http.StatusNotFound || e.ErrCode != errCode {
+		t.Fatalf("error fields mismatch: %#v", e)
+	}
+	if !strings.Contains(e.Error(), errMsg) || !strings.Contains(e.Payload, errMsg) {
+		t.Fatalf("error should carry the response body, got %#v", e)
+	}
+	if !e.Is(ErrNotFound) {
+		t.Fatalf("error should match ErrNotFound")
+	}
+	if e.Is(ErrUnauthorized) {
+		t.Fatalf("error should not match ErrUnauthorized")
+	}
+}
+
+func TestErrorStatusBody(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		id     = "did:axn:001"
+		errMsg = "poe of did:axn:001 not found"
+	)
+
+	//mock http requests
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/poe").
+		MatchParam("id", id).
+		Reply(http.StatusBadGateway).
+		BodyString(strings.Repeat("x", 2*maxErrorBody))
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/poe").
+		MatchParam("id", id).
+		Reply(http.StatusNotFound).
+		JSON(&rtstructs.Response{ErrCode: 8000, ErrMessage: errMsg})
+
+	_, err := client.QueryPOE(http.Header{}, id)
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("error type should be *Error, got %#v", err)
+	}
+	if len(e.Payload) != maxErrorBody {
+		t.Fatalf("error body should be limited to %d bytes, got %d", maxErrorBody, len(e.Payload))
+	}
+
+	client.SetPrivacyMode(true)
+	_, err = client.QueryPOE(http.Header{}, id)
+	if e, ok = err.(*Error); !ok {
+		t.Fatalf("error type should be *Error, got %#v", err)
+	}
+	if strings.Contains(e.Error(), id) {
+		t.Fatalf("error string should not carry the response body: %s", e)
+	}
+	if e.ErrCode != 8000 || !strings.Contains(e.Payload, errMsg) {
+		t.Fatalf("error fields should keep the response body: %#v", e)
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	e := &Error{StatusCode: http.StatusServiceUnavailable, ErrCode: 8000}
+	cases := []struct {
+		target error
+		match  bool
+	}{
+		{ErrServiceUnavailable, true},
+		{&Error{StatusCode: http.StatusServiceUnavailable, ErrCode: 8000}, true},
+		{&Error{StatusCode: http.StatusServiceUnavailable, ErrCode: 8001}, false},
+		{&Error{}, false},
+		{ErrInvalidBase64Data, false},
+		{rest.CodedError(8000, "busy"), false},
+	}
+	for i, c := range cases {
+		if match := e.Is(c.target); match != c.match {
+			t.Errorf("case %d: Is should return %v", i, c.match)
+		}
+	}
+}
+
+func TestClassifyErrorStatus(t *testing.T) {
+	cases := []struct {
+		err   error
+		class ErrorClass
+	}{
+		{&Error{StatusCode: http.StatusServiceUnavailable}, ErrorRetryable},
+		{&Error{StatusCode: http.StatusTooManyRequests, ErrCode: 8000}, ErrorRetryable},
+		{&Error{StatusCode: http.StatusUnauthorized}, ErrorNonRetryable},
+		{&Error{StatusCode: http.StatusOK, ErrCode: 8000}, ErrorNonRetryable},
+	}
+	for i, c := range cases {
+		if class := ClassifyError(c.err); class != c.class {
+			t.Errorf("case %d: class should be %v, got %v", i, c.class, class)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+)
+
+// EventBalanceChanged is the type of the events sent when the balance of
+// a wallet changes.
+//
+const EventBalanceChanged = "balance.changed"
+
+// Event is a wallet event, sent to the callback URLs, see the webhook
+// package, or streamed by SubscribeTransactionEvents.
+//
+type Event struct {
+	Id string `json:"id"`
+	// Type is the event type, e.g. CallbackEventTxConfirmed
+	Type     string         `json:"type"`
+	WalletId did.Identifier `json:"wallet_id"`
+	// Timestamp is the time of the event, in unix seconds
+	Timestamp int64 `json:"timestamp"`
+	// Data is the payload of the event, depending on its type
+	Data json.RawMessage `json:"data"`
+}
+
+// Decode decodes the payload of the event into v.
+//
+func (e *Event) Decode(v interface{}) error {
+	if len(e.Data) == 0 {
+		return fmt.Errorf("event %s has no data", e.Id)
+	}
+	return json.Unmarshal(e.Data, v)
+}
+
+// TxTimestamp is the time of a blockchain transaction.
+//
+type TxTimestamp struct {
+	Seconds int64 `json:"seconds"`
+	Nanos   int32 `json:"nanos"`
+}
+
+// Time returns the timestamp as a time.Time.
+//
+func (t *TxTimestamp) Time() time.Time {
+	return time.Unix(t.Seconds, int64(t.Nanos))
+}
+
+// TxEvent is the payload of the blockchain transaction events, the
+// BcTxEventPayload sent to the Callback-Url header of the calls.
+//
+type TxEvent struct {
+	BlockNumber   uint64       `json:"block_number"`
+	BlockHash     []byte       `json:"block_hash"`
+	ChannelId     string       `json:"channel_id"`
+	ChaincodeId   string       `json:"chaincode_id"`
+	TransactionId string       `json:"transaction_id"`
+	Timestamp     *TxTimestamp `json:"timestamp"`
+	IsInvalid     bool         `json:"is_invalid"`
+	// Payload is the payload of the transaction, depending on its type
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Transaction returns the transaction of the CallbackEventTxConfirmed and
+// CallbackEventTxFailed events.
+//
+func (e *Event) Transaction() (*TxEvent, error) {
+	if e.Type != CallbackEventTxConfirmed && e.Type != CallbackEventTxFailed {
+		return nil, fmt.Errorf("event %s is not a transaction event: %s", e.Id, e.Type)
+	}
+	tx := &TxEvent{}
+	if err := e.Decode(tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// BalanceEvent is the payload of the EventBalanceChanged events.
+//
+type BalanceEvent struct {
+	// Id is the colored token or digital asset id
+	Id string `json:"id"`
+	// Delta is the change of the amount, negative for a debit
+	Delta int64 `json:"delta"`
+	// Amount is the amount after the change
+	Amount        int64  `json:"amount"`
+	TransactionId string `json:"transaction_id"`
+}
+
+// Balance returns the balance change of the EventBalanceChanged events.
+//
+func (e *Event) Balance() (*BalanceEvent, error) {
+	if e.Type != EventBalanceChanged {
+		return nil, fmt.Errorf("event %s is not a balance event: %s", e.Id, e.Type)
+	}
+	b := &BalanceEvent{}
+	if err := e.Decode(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// EventFilter selects the events of SubscribeTransactionEvents.
+//
+type EventFilter struct {
+	// Types are the event types, all of them if empty
+	Types []string
+	// LastEventId resumes the stream after this event, e.g. the id of the
+	// last event processed before a restart
+	LastEventId string
+}
+
+// Delays between the reconnections of the event subscriptions.
+//
+const (
+	DefaultEventReconnectDelay = time.Second
+	MaxEventReconnectDelay     = 30 * time.Second
+)
+
+// SubscribeTransactionEvents is used to subscribe to the events of a
+// wallet, e.g. the confirmed and failed transactions and the balance
+// changes, instead of polling their status. The events are streamed by the
+// gateway as server-sent events.
+//
+// The connection is opened before returning, so that its errors are
+// returned. The subscription then reconnects after a network or server
+// error, resuming after the last received event, and ends closing the
+// returned channel when ctx is done or on a non-retryable error. The events
+// must be received promptly, the stream is not read while one is pending.
+//
+// The http client of the subscriptions must not have a timeout.
+//
+func (w *WalletClient) SubscribeTransactionEvents(ctx context.Context, header http.Header, walletID did.Identifier, filter *EventFilter) (<-chan *Event, error) {
+	if walletID == "" {
+		return nil, fmt.Errorf("wallet id invalid")
+	}
+	if filter == nil {
+		filter = &EventFilter{}
+	}
+	s := &eventStream{
+		w:        w.WithContext(ctx),
+		header:   header,
+		walletID: walletID,
+		types:    strings.Join(filter.Types, ","),
+		lastId:   filter.LastEventId,
+		delay:    DefaultEventReconnectDelay,
+	}
+	resp, err := s.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan *Event)
+	go s.run(resp, events)
+	return events, nil
+}
+
+// eventStream reads the events of a subscription.
+type eventStream struct {
+	w        *WalletClient
+	header   http.Header
+	walletID did.Identifier
+	types    string
+	lastId   string
+	// delay is the reconnection delay, which the server can set
+	delay time.Duration
+}
+
+func (s *eventStream) connect() (resp *http.Response, err error) {
+	params := url.Values{"id": {string(s.walletID)}}
+	if s.types != "" {
+		params.Set("types", s.types)
+	}
+	header := s.w.withDefaultHeader(s.header)
+	r := s.w.c.NewRequest("GET", s.w.endpoint("/v2/wallet/events"))
+	r.SetHeaders(header)
+	r.SetHeader("Accept", "text/event-stream")
+	if s.lastId != "" {
+		r.SetHeader("Last-Event-ID", s.lastId)
+	}
+	for k, vs := range params {
+		r.SetParam(k, vs[0])
+	}
+	trace := s.w.startCall(r, "GET", "/v2/wallet/events", params, header)
+	defer func() { trace.finish(err) }()
+
+	_, resp, err = s.w.requireOK(s.w.do(r))
+	return
+}
+
+// run reads the events of resp, and reconnects until the context is done.
+func (s *eventStream) run(resp *http.Response, events chan<- *Event) {
+	defer close(events)
+	ctx := s.w.Context()
+	failures := 0
+	for {
+		if s.read(ctx, resp, events) {
+			failures = 0
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Reconnect
+		var err error
+		for {
+			failures++
+			delay := s.delay
+			for i := 1; i < failures && delay < MaxEventReconnectDelay; i++ {
+				delay *= 2
+			}
+			if delay > MaxEventReconnectDelay {
+				delay = MaxEventReconnectDelay
+			}
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+			resp, err = s.connect()
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if s.w.ClassifyError(err) != ErrorRetryable {
+				s.w.logger().Errorf("Subscribe to %s events fail: %v", s.w.sensitive(string(s.walletID)), err)
+				return
+			}
+			s.w.logger().Warnf("Reconnect to %s events fail: %v", s.w.sensitive(string(s.walletID)), err)
+		}
+	}
+}
+
+// read parses the server-sent events of resp and reports whether an event
+// was received.
+func (s *eventStream) read(ctx context.Context, resp *http.Response, events chan<- *Event) (received bool) {
+	defer resp.Body.Close()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			// unblock the read
+			resp.Body.Close()
+		case <-stop:
+		}
+	}()
+
+	var id, typ string
+	var data []string
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 4096), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// dispatch the event
+			if len(data) > 0 {
+				if event := s.event(id, typ, strings.Join(data, "\n")); event != nil {
+					select {
+					case events <- event:
+						received = true
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+			id, typ, data = "", "", nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			// comment, e.g. keep-alive
+			continue
+		}
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "id":
+			id = value
+		case "event":
+			typ = value
+		case "data":
+			data = append(data, value)
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				s.delay = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		s.w.logger().Warnf("Read %s events fail: %v", s.w.sensitive(string(s.walletID)), err)
+	}
+	return
+}
+
+// event decodes an event, recording its id to resume the stream.
+func (s *eventStream) event(id, typ, data string) *Event {
+	if id != "" {
+		s.lastId = id
+	}
+	event := &Event{}
+	if err := json.Unmarshal([]byte(data), event); err != nil {
+		s.w.logger().Warnf("Event %s of %s invalid: %v", id, s.w.sensitive(string(s.walletID)), err)
+		return nil
+	}
+	if event.Id == "" {
+		event.Id = id
+	}
+	if event.Type == "" {
+		event.Type = typ
+	}
+	return event
+}
@@ -0,0 +1,117 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+)
+
+func TestSubscribeTransactionEvents(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		connections int
+		lastIds     []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/wallet/events" || r.URL.Query().Get("id") != "did:axn:wallet-001" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		connections++
+		n := connections
+		lastIds = append(lastIds, r.Header.Get("Last-Event-ID"))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		if n == 1 {
+			// two events, then the connection drops
+			fmt.Fprint(w, "retry: 10\n\n")
+			fmt.Fprint(w, ": keep-alive\n\n")
+			fmt.Fprint(w, "id: 1\nevent: transaction.confirmed\n")
+			fmt.Fprint(w, `data: {"wallet_id":"did:axn:wallet-001","data":{"transaction_id":"tx-001","block_number":7}}`+"\n\n")
+			fmt.Fprint(w, "id: 2\nevent: balance.changed\n")
+			fmt.Fprint(w, `data: {"wallet_id":"did:axn:wallet-001",`+"\n"+`data: "data":{"id":"token-001","delta":-5,"amount":95}}`+"\n\n")
+			return
+		}
+		fmt.Fprint(w, "id: 3\nevent: transaction.failed\n")
+		fmt.Fprint(w, `data: {"data":{"transaction_id":"tx-002","is_invalid":true}}`+"\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client, err := NewWalletClient(&restapi.Config{Address: server.URL, HttpClient: &http.Client{}})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := client.SubscribeTransactionEvents(ctx, http.Header{}, "did:axn:wallet-001", nil)
+	if err != nil {
+		t.Fatalf("subscribe transaction events fail: %v", err)
+	}
+
+	next := func() *Event {
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatalf("event should be received")
+		}
+		return nil
+	}
+	tx, err := next().Transaction()
+	if err != nil || tx.TransactionId != "tx-001" || tx.BlockNumber != 7 {
+		t.Fatalf("confirmed transaction should be received, got %+v, %v", tx, err)
+	}
+	balance, err := next().Balance()
+	if err != nil || balance.Id != "token-001" || balance.Delta != -5 || balance.Amount != 95 {
+		t.Fatalf("balance change should be received, got %+v, %v", balance, err)
+	}
+	e := next()
+	if tx, err = e.Transaction(); err != nil || e.Id != "3" || e.Type != CallbackEventTxFailed || !tx.IsInvalid {
+		t.Fatalf("failed transaction should be received after reconnecting, got %+v, %v", e, err)
+	}
+	mu.Lock()
+	if len(lastIds) != 2 || lastIds[0] != "" || lastIds[1] != "2" {
+		t.Fatalf("stream should be resumed after the last event, got %v", lastIds)
+	}
+	mu.Unlock()
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Fatalf("no more event should be received")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("events should be closed once the context is done")
+	}
+
+	if _, err = client.SubscribeTransactionEvents(context.Background(), http.Header{}, "did:axn:unknown", nil); err == nil {
+		t.Fatalf("err should not be nil when the subscription fails")
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// feeEstimateRequest is the request body of EstimateFee.
+type feeEstimateRequest struct {
+	Type string      `json:"type"`
+	Body interface{} `json:"body"`
+}
+
+// EstimateFee is used to query the fee the gateway currently expects for a
+// transaction.
+//
+// txType is one of the TxKind constants and body the request body of the
+// matching method, e.g. a *wallet.TransferCTokenBody for TxKindTransferCToken.
+//
+func (w *WalletClient) EstimateFee(header http.Header, txType string, body interface{}) (result *wallet.Fee, err error) {
+	if txType == "" || body == nil {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+
+	if err = w.doJSON(header, "POST", "/v2/transaction/fee/estimate", nil, &feeEstimateRequest{Type: txType, Body: body}, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetAutoFee sets whether IssueCToken, IssueAsset, TransferCToken and
+// TransferAsset set the fee of request bodies without fee with EstimateFee.
+//
+func (w *WalletClient) SetAutoFee(enabled bool) {
+	w.autoFee = enabled
+}
+
+// fillFee sets *fee to the estimated fee of the transaction when the
+// automatic fee is enabled and the fee is not set yet.
+func (w *WalletClient) fillFee(header http.Header, txType string, body interface{}, fee **wallet.Fee) error {
+	if !w.autoFee || *fee != nil {
+		return nil
+	}
+	estimated, err := w.EstimateFee(header, txType, body)
+	if err != nil {
+		return fmt.Errorf("estimate fee error: %v", err)
+	}
+	*fee = estimated
+	return nil
+}
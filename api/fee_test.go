@@ -0,0 +1,125 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestEstimateFeeSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/fee/estimate").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.Fee{Amount: 3}))
+
+	body := &wallet.TransferCTokenBody{From: "did:axn:001", To: "did:axn:002"}
+	fee, err := client.EstimateFee(http.Header{}, TxKindTransferCToken, body)
+	if err != nil {
+		t.Fatalf("estimate fee fail: %v", err)
+	}
+	if fee == nil || fee.Amount != 3 {
+		t.Fatalf("estimated fee should be 3")
+	}
+	if _, err = client.EstimateFee(http.Header{}, "", body); err == nil {
+		t.Fatalf("err should not be nil without transaction type")
+	}
+}
+
+func TestAutoFee(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+	client.SetAutoFee(true)
+
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/fee/estimate").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.Fee{Amount: 3}))
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/assets/transfer/prepare").
+		AddMatcher(feeMatcher(3)).
+		Reply(503)
+
+	body := &wallet.TransferAssetBody{From: "did:axn:001", To: "did:axn:002", Assets: []string{"asset-001"}}
+	if _, err := client.TransferAsset(http.Header{}, body, verifySignParams); err == nil {
+		t.Fatalf("err should not be nil when the proposal fails")
+	}
+	if !gock.IsDone() {
+		t.Fatalf("proposal should be sent with the estimated fee")
+	}
+	if body.Fee != nil {
+		t.Fatalf("estimated fee should not be set in the body of the caller")
+	}
+
+	// the fee is estimated again when the body is reused
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/fee/estimate").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.Fee{Amount: 4}))
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/assets/transfer/prepare").
+		AddMatcher(feeMatcher(4)).
+		Reply(503)
+	if _, err := client.TransferAsset(http.Header{}, body, verifySignParams); err == nil {
+		t.Fatalf("err should not be nil when the proposal fails")
+	}
+	if !gock.IsDone() {
+		t.Fatalf("proposal should be sent with the new estimated fee")
+	}
+
+	// an explicit fee is kept
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/assets/transfer/prepare").
+		AddMatcher(feeMatcher(5)).
+		Reply(503)
+	body.Fee = &wallet.Fee{Amount: 5}
+	if _, err := client.TransferAsset(http.Header{}, body, verifySignParams); err == nil {
+		t.Fatalf("err should not be nil when the proposal fails")
+	}
+	if !gock.IsDone() {
+		t.Fatalf("explicit fee should not be replaced")
+	}
+}
+
+// feeMatcher matches the transfer proposals with the given fee amount.
+func feeMatcher(amount int64) gock.MatchFunc {
+	return func(req *http.Request, _ *gock.Request) (bool, error) {
+		data, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return false, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(data))
+		var body wallet.TransferAssetBody
+		if err = json.Unmarshal(data, &body); err != nil {
+			return false, err
+		}
+		return body.Fee != nil && int64(body.Fee.Amount) == amount, nil
+	}
+}
@@ -0,0 +1,290 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// HoldStatus is the status of a hold.
+//
+type HoldStatus string
+
+const (
+	// HoldActive means the tokens are still reserved
+	HoldActive HoldStatus = "active"
+	// HoldCaptured means the tokens have been transferred to the payee
+	HoldCaptured HoldStatus = "captured"
+	// HoldVoided means the tokens have been released to the payer
+	HoldVoided HoldStatus = "voided"
+	// HoldExpired means the hold has been voided automatically on expiry
+	HoldExpired HoldStatus = "expired"
+)
+
+// HoldCTokenBody is the request body of HoldCToken.
+//
+// The tokens are reserved in the From wallet and can only be captured to the To wallet.
+// When Expires (unix seconds) is reached, the hold is voided automatically.
+// MemoEncryption is the scheme the memo is encrypted with, empty for a
+// plaintext memo, see EncryptMemo.
+//
+type HoldCTokenBody struct {
+	From           string                `json:"from"`
+	To             string                `json:"to"`
+	Tokens         []*wallet.TokenAmount `json:"tokens"`
+	Memo           string                `json:"memo,omitempty"`
+	MemoEncryption string                `json:"memo_encryption,omitempty"`
+	Expires        int64                 `json:"expires,omitempty"`
+}
+
+// CaptureHoldBody is the request body of CaptureHold.
+//
+// A zero Amount captures the whole held amount, a smaller one captures part
+// of it and releases the rest back to the payer.
+//
+type CaptureHoldBody struct {
+	HoldId string `json:"hold_id"`
+	Amount int64  `json:"amount,omitempty"`
+}
+
+// VoidHoldBody is the request body of VoidHold.
+//
+type VoidHoldBody struct {
+	HoldId string `json:"hold_id"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// HoldPrepareResponse is the response of a hold proposal.
+//
+type HoldPrepareResponse struct {
+	HoldId string   `json:"hold_id"`
+	Txs    []*pw.TX `json:"txs"`
+}
+
+// Hold is a colored tokens reservation.
+//
+type Hold struct {
+	HoldId         string                `json:"hold_id"`
+	From           string                `json:"from"`
+	To             string                `json:"to"`
+	Tokens         []*wallet.TokenAmount `json:"tokens"`
+	Memo           string                `json:"memo,omitempty"`
+	MemoEncryption string                `json:"memo_encryption,omitempty"`
+	Status         HoldStatus            `json:"status"`
+	Created        int64                 `json:"created"`
+	Expires        int64                 `json:"expires,omitempty"`
+}
+
+// Expired reports whether the hold is expired at the specified time.
+//
+func (h *Hold) Expired(now time.Time) bool {
+	return h.Expires > 0 && now.Unix() >= h.Expires
+}
+
+// HoldResponse is the response of HoldCToken.
+//
+type HoldResponse struct {
+	*wallet.WalletResponse
+	HoldId string `json:"hold_id"`
+}
+
+// HoldCToken is used to reserve colored tokens of the payer, to be captured
+// later by CaptureHold or released by VoidHold.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) HoldCToken(header http.Header, body *HoldCTokenBody, signParams *pki.SignatureParam) (result *HoldResponse, err error) {
+	if body == nil {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	// the defaults are filled in a copy, the body of the caller is left as is
+	hold := *body
+	body = &hold
+	if body.Expires == 0 && w.holdTTL > 0 {
+		body.Expires = time.Now().Add(w.holdTTL).Unix()
+	}
+	if body.Memo, body.MemoEncryption, err = w.encryptMemo(body.Memo, body.MemoEncryption, body.To); err != nil {
+		return
+	}
+
+	if err = w.checkCounterpartyKYC(header, body.To); err != nil {
+		return
+	}
+	screened := w.screenAsync(screeningTypeHold, body.From, body.To, body)
+
+	if w.s != nil {
+		signParams, err = w.queryPrivateKey(header, signParams)
+		if err != nil {
+			return
+		}
+	}
+
+	// 1 send hold proposal to get wallet.Tx
+	holdPreRsp, err := w.SendHoldCTokenProposal(header, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2 sign public key as signature
+	err = w.SignTxs(holdPreRsp.Txs, signParams)
+	if err != nil {
+		err = fmt.Errorf("sign Txs error: %v", err)
+		return nil, err
+	}
+
+	// wait for the screening decision before submission
+	if err = screened(); err != nil {
+		return nil, err
+	}
+
+	// 3 call ProcessTx to hold formally
+	resp, err := w.ProcessTx(header, holdPreRsp.Txs)
+	if err != nil {
+		return nil, err
+	}
+	return &HoldResponse{WalletResponse: resp, HoldId: holdPreRsp.HoldId}, nil
+}
+
+// SendHoldCTokenProposal is used to send hold colored tokens proposal to get wallet.Tx to be signed.
+//
+func (w *WalletClient) SendHoldCTokenProposal(header http.Header, body *HoldCTokenBody) (result *HoldPrepareResponse, err error) {
+	if body == nil {
+		err = fmt.Errorf("request payload invalid")
+		return nil, err
+	}
+
+	if err = w.doJSON(header, "POST", "/v2/transaction/tokens/hold/prepare", nil, body, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CaptureHold is used to transfer the held colored tokens to the payee.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) CaptureHold(header http.Header, body *CaptureHoldBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.HoldId == "" {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	return w.processProposal(header, "/v2/transaction/tokens/hold/capture/prepare", body, signParams)
+}
+
+// VoidHold is used to release the held colored tokens back to the payer.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) VoidHold(header http.Header, body *VoidHoldBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.HoldId == "" {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	return w.processProposal(header, "/v2/transaction/tokens/hold/void/prepare", body, signParams)
+}
+
+// SetHoldTTL sets the default expiration of holds created by HoldCToken
+// without an explicit expiration, zero means holds never expire.
+//
+func (w *WalletClient) SetHoldTTL(ttl time.Duration) {
+	w.holdTTL = ttl
+}
+
+// QueryHolds is used to query the holds of a wallet, either as payer or payee.
+//
+// status filters the holds by status, empty means all holds.
+//
+func (w *WalletClient) QueryHolds(header http.Header, id did.Identifier, status HoldStatus) (result []*Hold, err error) {
+	if id == "" {
+		err = fmt.Errorf("request id invalid")
+		return
+	}
+
+	params := url.Values{}
+	params.Set("id", string(id))
+	if status != "" {
+		params.Set("status", string(status))
+	}
+
+	if err = w.doJSON(header, "GET", "/v2/transaction/tokens/holds", params, nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// QueryActiveHolds is used to query the active holds of a wallet.
+//
+func (w *WalletClient) QueryActiveHolds(header http.Header, id did.Identifier) ([]*Hold, error) {
+	return w.QueryHolds(header, id, HoldActive)
+}
+
+// VoidExpiredHolds is used to void the active holds of the payer wallet which
+// are already expired but have not been voided by the wallet service yet.
+//
+// It returns the IDs of the voided holds.
+//
+func (w *WalletClient) VoidExpiredHolds(header http.Header, id did.Identifier, signParams *pki.SignatureParam) (voided []string, err error) {
+	holds, err := w.QueryActiveHolds(header, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, h := range holds {
+		if h.From != string(id) || !h.Expired(now) {
+			continue
+		}
+		_, err = w.VoidHold(header, &VoidHoldBody{HoldId: h.HoldId, Reason: string(HoldExpired)}, signParams)
+		if err != nil {
+			return voided, err
+		}
+		voided = append(voided, h.HoldId)
+	}
+	return voided, nil
+}
@@ -0,0 +1,244 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/rest"
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestHoldCTokenFail(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		token   = "user-token-001"
+		errCode = 5015
+		errMsg  = "BalancesNotSufficient"
+	)
+
+	//request body & response body
+	reqBody := &HoldCTokenBody{
+		From: "did:axn:001",
+		To:   "did:axn:002",
+		Tokens: []*wallet.TokenAmount{
+			{TokenId: "ctoken-001", Amount: 100},
+		},
+	}
+	sign := &pki.SignatureParam{
+		Creator:    "did:axn:001",
+		Nonce:      "helloalice",
+		PrivateKey: "WBZNmTTf34Kg+pQOTSIRL+JeQYDfj7InWc0A/9kvNvQSI8Ue8iRD8gn9CNmGO2EjJILF/3RELmEcbuS5G0d+Mg==",
+	}
+	respBody := &rtstructs.Response{
+		ErrCode:    errCode,
+		ErrMessage: errMsg,
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/tokens/hold/prepare").
+		MatchHeader("X-Auth-Token", token).
+		Reply(errCode).
+		JSON(respBody)
+
+	//set http header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	//do hold colored token
+	resp, err := client.HoldCToken(header, reqBody, sign)
+	if err == nil {
+		t.Fatalf("err should not be nil when hold colored token fail")
+	}
+	if !strings.Contains(err.Error(), errMsg) {
+		t.Fatalf("err message should contains [%v]", errMsg)
+	}
+	if resp != nil {
+		t.Fatalf("response object should be nil when hold colored token fail")
+	}
+}
+
+func TestHoldCTokenBodyUnchanged(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+	client.SetHoldTTL(time.Hour)
+
+	reqBody := &HoldCTokenBody{
+		From: "did:axn:001",
+		To:   "did:axn:002",
+		Tokens: []*wallet.TokenAmount{
+			{TokenId: "ctoken-001", Amount: 100},
+		},
+	}
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/tokens/hold/prepare").
+		Reply(5015).
+		JSON(&rtstructs.Response{ErrCode: 5015, ErrMessage: "BalancesNotSufficient"})
+
+	if _, err := client.HoldCToken(http.Header{}, reqBody, &pki.SignatureParam{Creator: "did:axn:001"}); err == nil {
+		t.Fatalf("err should not be nil when hold colored token fail")
+	}
+	if reqBody.Expires != 0 {
+		t.Fatalf("the expiry should not be filled in the body of the caller")
+	}
+}
+
+func TestCaptureHoldFailErrCode(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		token   = "user-token-001"
+		errCode = 5016
+		errMsg  = "HoldNotFound"
+	)
+
+	//request body & response body
+	reqBody := &CaptureHoldBody{
+		HoldId: "hold-id-001",
+	}
+	sign := &pki.SignatureParam{
+		Creator:    "did:axn:002",
+		Nonce:      "helloalice",
+		PrivateKey: "WBZNmTTf34Kg+pQOTSIRL+JeQYDfj7InWc0A/9kvNvQSI8Ue8iRD8gn9CNmGO2EjJILF/3RELmEcbuS5G0d+Mg==",
+	}
+	respBody := &rtstructs.Response{
+		ErrCode:    errCode,
+		ErrMessage: errMsg,
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/tokens/hold/capture/prepare").
+		MatchHeader("X-Auth-Token", token).
+		Reply(200).
+		JSON(respBody)
+
+	//set http header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	//do capture hold
+	resp, err := client.CaptureHold(header, reqBody, sign)
+	if err == nil {
+		t.Fatalf("err should not be nil when capture hold fail")
+	}
+	errWitherrCode, ok := err.(rest.HTTPCodedError)
+	if !ok {
+		t.Fatalf("error type should be HTTPCodedError not %v", reflect.TypeOf(err))
+	}
+	if errWitherrCode.Code() != errCode {
+		t.Fatalf("Error code should be %d", errCode)
+	}
+	if resp != nil {
+		t.Fatalf("response object should be nil when capture hold fail")
+	}
+}
+
+func TestVoidHoldInvalidBody(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	resp, err := client.VoidHold(http.Header{}, &VoidHoldBody{}, &pki.SignatureParam{})
+	if err == nil {
+		t.Fatalf("err should not be nil when hold id is empty")
+	}
+	if resp != nil {
+		t.Fatalf("response object should be nil when hold id is empty")
+	}
+}
+
+func TestQueryActiveHoldsSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		token   = "user-token-001"
+		id      = "did:axn:001"
+		holdID  = "hold-id-001"
+		expires = 1530000000
+		payee   = "did:axn:002"
+	)
+
+	//response body
+	payload := []*Hold{
+		{
+			HoldId:  holdID,
+			From:    id,
+			To:      payee,
+			Status:  HoldActive,
+			Expires: expires,
+		},
+	}
+	byPayload, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody := &rtstructs.Response{
+		ErrCode: 0,
+		Payload: string(byPayload),
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/transaction/tokens/holds").
+		MatchParam("id", id).
+		MatchParam("status", string(HoldActive)).
+		MatchHeader("X-Auth-Token", token).
+		Reply(200).
+		JSON(respBody)
+
+	//set http header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	//do query active holds
+	holds, err := client.QueryActiveHolds(header, id)
+	if err != nil {
+		t.Fatalf("query active holds fail: %v", err)
+	}
+	if len(holds) != 1 {
+		t.Fatalf("holds length should be 1")
+	}
+	if holds[0].HoldId != holdID {
+		t.Fatalf("hold id should be %v", holdID)
+	}
+	if !holds[0].Expired(time.Now()) {
+		t.Fatalf("hold should be expired")
+	}
+}
@@ -0,0 +1,277 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// HTLCStatus is the status of a hash time-locked contract.
+//
+type HTLCStatus string
+
+const (
+	// HTLCLocked means the tokens are locked until claimed or refunded
+	HTLCLocked HTLCStatus = "locked"
+	// HTLCClaimed means the tokens have been claimed by the recipient with the preimage
+	HTLCClaimed HTLCStatus = "claimed"
+	// HTLCRefunded means the tokens have been refunded to the sender after the timeout
+	HTLCRefunded HTLCStatus = "refunded"
+)
+
+// HTLCTransferBody is the request body of TransferCTokenHTLC.
+//
+// The tokens are locked in the From wallet and can be claimed by the To
+// wallet with the preimage of HashLock, the hex encoded SHA-256 hash of a
+// secret, until Timeout (unix seconds). Once Timeout is reached, they can
+// only be refunded to the From wallet.
+//
+type HTLCTransferBody struct {
+	From     string                `json:"from"`
+	To       string                `json:"to"`
+	Tokens   []*wallet.TokenAmount `json:"tokens"`
+	HashLock string                `json:"hash_lock"`
+	Timeout  int64                 `json:"timeout"`
+	Fee      *wallet.Fee           `json:"fee,omitempty"`
+}
+
+// ClaimHTLCBody is the request body of ClaimHTLC.
+//
+// Preimage is the hex encoded secret whose SHA-256 hash is the hash lock.
+//
+type ClaimHTLCBody struct {
+	HTLCId   string `json:"htlc_id"`
+	Preimage string `json:"preimage"`
+}
+
+// RefundHTLCBody is the request body of RefundHTLC.
+//
+type RefundHTLCBody struct {
+	HTLCId string `json:"htlc_id"`
+}
+
+// HTLCPrepareResponse is the response of a hash time-locked transfer proposal.
+//
+type HTLCPrepareResponse struct {
+	HTLCId string   `json:"htlc_id"`
+	Txs    []*pw.TX `json:"txs"`
+}
+
+// HTLCResponse is the response of TransferCTokenHTLC.
+//
+type HTLCResponse struct {
+	*wallet.WalletResponse
+	HTLCId string `json:"htlc_id"`
+}
+
+// HTLC is a hash time-locked colored tokens transfer.
+//
+// Preimage is only set once the contract has been claimed, so that the
+// sender can reuse it on the other ledger.
+//
+type HTLC struct {
+	HTLCId   string                `json:"htlc_id"`
+	From     string                `json:"from"`
+	To       string                `json:"to"`
+	Tokens   []*wallet.TokenAmount `json:"tokens"`
+	HashLock string                `json:"hash_lock"`
+	Timeout  int64                 `json:"timeout"`
+	Preimage string                `json:"preimage,omitempty"`
+	Status   HTLCStatus            `json:"status"`
+	Created  int64                 `json:"created"`
+}
+
+// Expired reports whether the contract timeout is reached at the specified time.
+//
+func (h *HTLC) Expired(now time.Time) bool {
+	return now.Unix() >= h.Timeout
+}
+
+// NewHashLock returns the hash lock of the specified preimage.
+//
+func NewHashLock(preimage []byte) string {
+	sum := sha256.Sum256(preimage)
+	return hex.EncodeToString(sum[:])
+}
+
+// NewPreimage generates a random 32 bytes preimage and returns it, hex
+// encoded, with its hash lock.
+//
+func NewPreimage() (preimage, hashLock string, err error) {
+	secret := make([]byte, sha256.Size)
+	if _, err = rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(secret), NewHashLock(secret), nil
+}
+
+// TransferCTokenHTLC is used to transfer colored tokens under a hash lock
+// and a timeout: the recipient claims them with ClaimHTLC and the preimage
+// before the timeout, otherwise the sender gets them back with RefundHTLC.
+//
+// The transfer goes through the same counterparty checks as TransferCToken.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) TransferCTokenHTLC(header http.Header, body *HTLCTransferBody, signParams *pki.SignatureParam) (result *HTLCResponse, err error) {
+	if body == nil || body.From == "" || body.To == "" || len(body.Tokens) == 0 {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	if lock, e := hex.DecodeString(body.HashLock); e != nil || len(lock) != sha256.Size {
+		err = fmt.Errorf("hash lock invalid")
+		return
+	}
+	if body.Timeout <= time.Now().Unix() {
+		err = fmt.Errorf("timeout invalid")
+		return
+	}
+
+	transfer := &wallet.TransferCTokenBody{From: body.From, To: body.To, Tokens: body.Tokens, Fee: body.Fee}
+	if err = w.checkCounterpartyKYC(header, body.To); err != nil {
+		return
+	}
+	screened := w.screenAsync(screeningTypeCToken, body.From, body.To, transfer)
+	submitHeader, err := w.travelRuleHeader(header, transfer)
+	if err != nil {
+		return
+	}
+
+	if w.s != nil {
+		signParams, err = w.queryPrivateKey(header, signParams)
+		if err != nil {
+			return
+		}
+	}
+
+	// 1 send htlc proposal to get wallet.Tx
+	htlcPreRsp, err := w.SendHTLCTransferProposal(header, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2 sign public key as signature
+	err = w.SignTxs(htlcPreRsp.Txs, signParams)
+	if err != nil {
+		err = fmt.Errorf("sign Txs error: %v", err)
+		return nil, err
+	}
+
+	// wait for the screening decision before submission
+	if err = screened(); err != nil {
+		return nil, err
+	}
+
+	// 3 call ProcessTx to lock formally
+	resp, err := w.ProcessTx(submitHeader, htlcPreRsp.Txs)
+	if err != nil {
+		return nil, err
+	}
+	return &HTLCResponse{WalletResponse: resp, HTLCId: htlcPreRsp.HTLCId}, nil
+}
+
+// SendHTLCTransferProposal is used to send hash time-locked transfer proposal to get wallet.Tx to be signed.
+//
+func (w *WalletClient) SendHTLCTransferProposal(header http.Header, body *HTLCTransferBody) (result *HTLCPrepareResponse, err error) {
+	if body == nil {
+		err = fmt.Errorf("request payload invalid")
+		return nil, err
+	}
+
+	if err = w.doJSON(header, "POST", "/v2/transaction/tokens/htlc/prepare", nil, body, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ClaimHTLC is used by the recipient to receive the locked colored tokens
+// by revealing the preimage of the hash lock.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) ClaimHTLC(header http.Header, body *ClaimHTLCBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.HTLCId == "" {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	if _, e := hex.DecodeString(body.Preimage); e != nil || body.Preimage == "" {
+		err = fmt.Errorf("preimage invalid")
+		return
+	}
+	return w.processProposal(header, "/v2/transaction/tokens/htlc/claim/prepare", body, signParams)
+}
+
+// RefundHTLC is used by the sender to get back the locked colored tokens
+// once the timeout is reached.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) RefundHTLC(header http.Header, body *RefundHTLCBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.HTLCId == "" {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	return w.processProposal(header, "/v2/transaction/tokens/htlc/refund/prepare", body, signParams)
+}
+
+// QueryHTLC is used to query the state of a hash time-locked contract.
+//
+func (w *WalletClient) QueryHTLC(header http.Header, htlcId string) (result *HTLC, err error) {
+	if htlcId == "" {
+		err = fmt.Errorf("request id invalid")
+		return
+	}
+
+	if err = w.doJSON(header, "GET", "/v2/transaction/tokens/htlc", url.Values{"id": {htlcId}}, nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
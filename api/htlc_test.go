@@ -0,0 +1,149 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestNewPreimage(t *testing.T) {
+	preimage, hashLock, err := NewPreimage()
+	if err != nil {
+		t.Fatalf("new preimage fail: %v", err)
+	}
+	if len(preimage) != 64 || len(hashLock) != 64 {
+		t.Fatalf("preimage and hash lock should be 32 bytes hex encoded")
+	}
+	// sha256("")
+	if NewHashLock(nil) != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Fatalf("hash lock should be the sha256 of the preimage")
+	}
+}
+
+func TestTransferCTokenHTLCSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const sender = did.Identifier("did:axn:001")
+	signer := &fakeSigner{creator: sender}
+	client.SetSigner(sender, signer)
+
+	script, err := json.Marshal(&pw.UTXOSignature{PublicKey: []byte("public-key")})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	preRsp := map[string]interface{}{
+		"htlc_id": "htlc-001",
+		"txs": []map[string]interface{}{{
+			"founder": string(sender),
+			"txout":   []map[string]interface{}{{"script": script}},
+		}},
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/tokens/htlc/prepare").
+		Reply(200).
+		JSON(payloadResponse(t, preRsp))
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/process").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletResponse{Id: "htlc-tx-001"}))
+
+	_, hashLock, err := NewPreimage()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	body := &HTLCTransferBody{
+		From:     string(sender),
+		To:       "did:axn:002",
+		Tokens:   []*wallet.TokenAmount{{TokenId: "ctoken-001", Amount: 10}},
+		HashLock: hashLock,
+		Timeout:  time.Now().Add(time.Hour).Unix(),
+	}
+	resp, err := client.TransferCTokenHTLC(http.Header{}, body, &pki.SignatureParam{Creator: sender})
+	if err != nil {
+		t.Fatalf("htlc transfer fail: %v", err)
+	}
+	if resp.HTLCId != "htlc-001" || resp.Id != "htlc-tx-001" {
+		t.Fatalf("response should carry the htlc id and the transaction id")
+	}
+	if len(signer.signed) != 1 {
+		t.Fatalf("transfer should be signed by the sender")
+	}
+}
+
+func TestTransferCTokenHTLCInvalidBody(t *testing.T) {
+	initWalletClient(t)
+	client := walletClient.(*WalletClient)
+
+	body := &HTLCTransferBody{
+		From:     "did:axn:001",
+		To:       "did:axn:002",
+		Tokens:   []*wallet.TokenAmount{{TokenId: "ctoken-001", Amount: 10}},
+		HashLock: "not-a-hash",
+		Timeout:  time.Now().Add(time.Hour).Unix(),
+	}
+	if _, err := client.TransferCTokenHTLC(http.Header{}, body, verifySignParams); err == nil {
+		t.Fatalf("err should not be nil when the hash lock is invalid")
+	}
+
+	body.HashLock = NewHashLock([]byte("secret"))
+	body.Timeout = time.Now().Add(-time.Minute).Unix()
+	if _, err := client.TransferCTokenHTLC(http.Header{}, body, verifySignParams); err == nil {
+		t.Fatalf("err should not be nil when the timeout is already reached")
+	}
+
+	if _, err := client.ClaimHTLC(http.Header{}, &ClaimHTLCBody{HTLCId: "htlc-001", Preimage: "xyz"}, verifySignParams); err == nil {
+		t.Fatalf("err should not be nil when the preimage is not hex encoded")
+	}
+	if _, err := client.RefundHTLC(http.Header{}, &RefundHTLCBody{}, verifySignParams); err == nil {
+		t.Fatalf("err should not be nil without htlc id")
+	}
+}
+
+func TestQueryHTLCSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/transaction/tokens/htlc").
+		MatchParam("id", "htlc-001").
+		Reply(200).
+		JSON(payloadResponse(t, &HTLC{HTLCId: "htlc-001", Status: HTLCClaimed, Preimage: "736563726574"}))
+
+	htlc, err := client.QueryHTLC(http.Header{}, "htlc-001")
+	if err != nil {
+		t.Fatalf("query htlc fail: %v", err)
+	}
+	if htlc.Status != HTLCClaimed || htlc.Preimage != "736563726574" {
+		t.Fatalf("htlc should be claimed with its preimage")
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// NewIdempotencyKey returns a random idempotency key, to be set with
+// WithIdempotencyKey.
+//
+func NewIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// WithIdempotencyKey sets the idempotency key of a call. The gateway drops
+// the requests with the key of a request it already processed, so a write
+// call whose outcome is unknown, e.g. after a timeout, can be sent again
+// with the same key without being processed twice:
+//
+//	header := api.NewHeader(api.WithIdempotencyKey(key))
+//	resp, err := walletClient.TransferCToken(header, body, signParams)
+//	if err != nil && walletClient.ClassifyError(err) == api.ErrorRetryable {
+//		resp, err = walletClient.TransferCToken(header, body, signParams)
+//	}
+//
+func WithIdempotencyKey(key string) CallOption {
+	return WithHeader(IdempotencyKeyHeader, key)
+}
+
+// SetAutoIdempotencyKey sets whether the POST requests without idempotency
+// key get a new one, which is the default. The key is set before the request
+// goes through the middleware, so a middleware sending the request again
+// sends the same key.
+//
+func (w *WalletClient) SetAutoIdempotencyKey(enabled bool) {
+	w.noIdemKey = !enabled
+}
+
+// idempotentHeader returns the header of a request, a copy with a new
+// idempotency key for the POST requests without key.
+func (w *WalletClient) idempotentHeader(method string, header http.Header) (http.Header, error) {
+	if w.noIdemKey || method != "POST" || header.Get(IdempotencyKeyHeader) != "" {
+		return header, nil
+	}
+	key, err := NewIdempotencyKey()
+	if err != nil {
+		return nil, err
+	}
+	return ApplyOptions(header, WithIdempotencyKey(key)), nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestAutoIdempotencyKey(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	var keys []string
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			keys = append(keys, req.Header.Get(IdempotencyKeyHeader))
+			return next(req)
+		}
+	})
+	mock := func(n int) {
+		for i := 0; i < n; i++ {
+			gock.New("http://127.0.0.1:8006").
+				Post("/v2/transaction/fee/estimate").
+				Reply(200).
+				JSON(payloadResponse(t, &wallet.Fee{Amount: 3}))
+		}
+	}
+	body := &wallet.TransferCTokenBody{From: "did:axn:001", To: "did:axn:002"}
+
+	// every POST gets its own key
+	mock(2)
+	for i := 0; i < 2; i++ {
+		if _, err := client.EstimateFee(http.Header{}, TxKindTransferCToken, body); err != nil {
+			t.Fatalf("estimate fee fail: %v", err)
+		}
+	}
+	if len(keys) != 2 || len(keys[0]) != 32 || keys[0] == keys[1] {
+		t.Fatalf("requests should get distinct idempotency keys, got %v", keys)
+	}
+
+	// the key of the caller is kept
+	keys = nil
+	mock(1)
+	header := NewHeader(WithIdempotencyKey("transfer-42"))
+	if _, err := client.EstimateFee(header, TxKindTransferCToken, body); err != nil {
+		t.Fatalf("estimate fee fail: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "transfer-42" {
+		t.Fatalf("idempotency key should be the one set, got %v", keys)
+	}
+
+	// GET requests and disabled automatic keys get none
+	keys = nil
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/balance").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletBalance{}))
+	if _, err := client.GetWalletBalance(http.Header{}, "did:axn:001"); err != nil {
+		t.Fatalf("get wallet balance fail: %v", err)
+	}
+	client.SetAutoIdempotencyKey(false)
+	defer client.SetAutoIdempotencyKey(true)
+	mock(1)
+	if _, err := client.EstimateFee(http.Header{}, TxKindTransferCToken, body); err != nil {
+		t.Fatalf("estimate fee fail: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "" || keys[1] != "" {
+		t.Fatalf("requests should not get idempotency keys, got %v", keys)
+	}
+}
@@ -17,15 +17,9 @@ limitations under the License.
 package api
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
-	"reflect"
 
-	"github.com/arxanchain/sdk-go-common/errors"
-	"github.com/arxanchain/sdk-go-common/rest"
-	restapi "github.com/arxanchain/sdk-go-common/rest/api"
-	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
 	"github.com/arxanchain/sdk-go-common/structs/wallet"
 )
 
@@ -44,36 +38,7 @@ func (w *WalletClient) IndexSet(header http.Header, body *wallet.IndexSetPayload
 		return
 	}
 
-	// Build http request
-	r := w.c.NewRequest("POST", "/v1/index/set")
-	r.SetHeaders(header)
-	r.SetBody(body)
-
-	// Do http request
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-
-	// Parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
-		return
-	}
-
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
-		return
-	}
-
-	payload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
-		return
-	}
-
-	err = json.Unmarshal([]byte(payload), &txIDs)
+	err = w.doJSON(header, "POST", "/v1/index/set", nil, body, &txIDs)
 
 	return
 }
@@ -86,36 +51,7 @@ func (w *WalletClient) IndexGet(header http.Header, body *wallet.IndexGetPayload
 		return
 	}
 
-	// Build http request
-	r := w.c.NewRequest("POST", "/v1/index/get")
-	r.SetHeaders(header)
-	r.SetBody(body)
-
-	// Do http request
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-
-	// Parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
-		return
-	}
-
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
-		return
-	}
-
-	payload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
-		return
-	}
-
-	err = json.Unmarshal([]byte(payload), &IDs)
+	err = w.doJSON(header, "POST", "/v1/index/get", nil, body, &IDs)
 
 	return
 }
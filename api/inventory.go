@@ -0,0 +1,99 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+)
+
+// DefaultInventoryPageSize is the page size of the wallet inventory queries
+// without page size.
+//
+const DefaultInventoryPageSize = 100
+
+// AssetListPage is a page of the digital assets owned by a wallet.
+//
+type AssetListPage struct {
+	Assets []*AssetInfo `json:"assets"`
+	// NextCursor is the cursor of the next page, empty on the last page
+	NextCursor string `json:"next_cursor"`
+}
+
+// CTokenHolding is the amount of a colored token held by a wallet.
+//
+type CTokenHolding struct {
+	TokenId string `json:"token_id"`
+	// AssetId is the digital asset backing the token
+	AssetId  string         `json:"asset_id"`
+	Issuer   did.Identifier `json:"issuer"`
+	Amount   int64          `json:"amount"`
+	Decimals int            `json:"decimals"`
+}
+
+// CTokenListPage is a page of the colored tokens held by a wallet.
+//
+type CTokenListPage struct {
+	CTokens []*CTokenHolding `json:"ctokens"`
+	// NextCursor is the cursor of the next page, empty on the last page
+	NextCursor string `json:"next_cursor"`
+}
+
+// ListWalletAssets is used to query a page of the digital assets owned by a
+// wallet account, the pages are enumerated following the returned cursors as
+// with QueryPOEList. page can be nil for the first page.
+//
+func (w *WalletClient) ListWalletAssets(header http.Header, id did.Identifier, page *PageRequest) (result *AssetListPage, err error) {
+	if id == "" {
+		err = fmt.Errorf("request id invalid")
+		return
+	}
+
+	params := url.Values{"id": {string(id)}}
+	page.setParams(params, DefaultInventoryPageSize)
+
+	err = w.doJSON(header, "GET", "/v2/wallet/assets", params, nil, &result)
+	if err == nil && result == nil {
+		result = &AssetListPage{}
+	}
+
+	return
+}
+
+// ListWalletCTokens is used to query a page of the colored tokens held by a
+// wallet account, the pages are enumerated following the returned cursors as
+// with QueryPOEList. page can be nil for the first page.
+//
+func (w *WalletClient) ListWalletCTokens(header http.Header, id did.Identifier, page *PageRequest) (result *CTokenListPage, err error) {
+	if id == "" {
+		err = fmt.Errorf("request id invalid")
+		return
+	}
+
+	params := url.Values{"id": {string(id)}}
+	page.setParams(params, DefaultInventoryPageSize)
+
+	err = w.doJSON(header, "GET", "/v2/wallet/ctokens", params, nil, &result)
+	if err == nil && result == nil {
+		result = &CTokenListPage{}
+	}
+
+	return
+}
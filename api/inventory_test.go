@@ -0,0 +1,96 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestListWalletAssets(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/wallet/assets").
+		MatchParam("id", "did:axn:owner-001").
+		MatchParam("size", "100").
+		Reply(200).
+		JSON(payloadResponse(t, &AssetListPage{
+			Assets:     []*AssetInfo{{Id: "asset-001"}},
+			NextCursor: "cursor-001",
+		}))
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/wallet/assets").
+		MatchParam("cursor", "cursor-001").
+		MatchParam("size", "10").
+		Reply(200).
+		JSON(payloadResponse(t, &AssetListPage{
+			Assets: []*AssetInfo{{Id: "asset-002"}},
+		}))
+
+	assets, err := client.ListWalletAssets(http.Header{}, "did:axn:owner-001", nil)
+	if err != nil {
+		t.Fatalf("list wallet assets fail: %v", err)
+	}
+	if len(assets.Assets) != 1 || assets.NextCursor != "cursor-001" {
+		t.Fatalf("first page should be returned, got %+v", assets)
+	}
+
+	assets, err = client.ListWalletAssets(http.Header{}, "did:axn:owner-001", &PageRequest{Size: 10, Cursor: assets.NextCursor})
+	if err != nil {
+		t.Fatalf("list wallet assets fail: %v", err)
+	}
+	if len(assets.Assets) != 1 || assets.Assets[0].Id != "asset-002" || assets.NextCursor != "" {
+		t.Fatalf("last page should be returned, got %+v", assets)
+	}
+
+	if _, err = client.ListWalletAssets(http.Header{}, "", nil); err == nil {
+		t.Fatalf("err should not be nil without wallet id")
+	}
+}
+
+func TestListWalletCTokens(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/wallet/ctokens").
+		MatchParam("id", "did:axn:owner-001").
+		Reply(200).
+		JSON(payloadResponse(t, &CTokenListPage{
+			CTokens: []*CTokenHolding{{TokenId: "token-001", AssetId: "asset-001", Amount: 250}},
+		}))
+
+	tokens, err := client.ListWalletCTokens(http.Header{}, "did:axn:owner-001", nil)
+	if err != nil {
+		t.Fatalf("list wallet ctokens fail: %v", err)
+	}
+	if len(tokens.CTokens) != 1 || tokens.CTokens[0].Amount != 250 {
+		t.Fatalf("held tokens should be returned, got %+v", tokens)
+	}
+
+	if _, err = client.ListWalletCTokens(http.Header{}, "", nil); err == nil {
+		t.Fatalf("err should not be nil without wallet id")
+	}
+}
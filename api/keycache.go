@@ -0,0 +1,204 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/utils"
+)
+
+// ErrKeyNotCached is returned when signing with a cached private key which
+// expired or was invalidated since it was queried.
+//
+var ErrKeyNotCached = fmt.Errorf("cached private key expired")
+
+type keyCacheEntry struct {
+	codeHash [sha256.Size]byte
+	alg      SignatureAlgorithm
+	key      *SecretBytes
+	expires  time.Time
+}
+
+// keyCache caches the private keys queried from the safebox with the
+// security codes, shared by the copies of the client made with WithContext.
+type keyCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[did.Identifier]*keyCacheEntry
+}
+
+// SetKeyCache makes the client cache for ttl the private keys it queries
+// from the safebox with the security codes of the signature params, saving
+// a round trip to the safebox per signed request. Zero ttl disables the
+// cache and clears the cached keys.
+//
+// A cached key is only used with the security code it was queried with.
+// The keys are decoded into SecretBytes, each signature uses a copy zeroed
+// once signed. They are cleared from the cache memory when they expire or
+// are invalidated with InvalidateKey, they are invalidated by TrustKeyPair,
+// RotateSecurityCode, UntrustKeyPair and RotateWalletKey too.
+//
+func (w *WalletClient) SetKeyCache(ttl time.Duration) {
+	if w.keys != nil {
+		w.keys.clear("")
+	}
+	if ttl <= 0 {
+		w.keys = nil
+		return
+	}
+	w.keys = &keyCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[did.Identifier]*keyCacheEntry),
+	}
+}
+
+// InvalidateKey removes the cached private key of the DID, empty id removes
+// all of them.
+//
+func (w *WalletClient) InvalidateKey(id did.Identifier) {
+	if w.keys != nil {
+		w.keys.clear(id)
+	}
+}
+
+// lookup returns the entry of the DID queried with the security code, nil
+// if none. c.mu must be held.
+func (c *keyCache) lookup(id did.Identifier, code string) *keyCacheEntry {
+	hash := sha256.Sum256([]byte(code))
+	e, ok := c.entries[id]
+	if !ok {
+		return nil
+	}
+	if !c.now().Before(e.expires) {
+		c.evict(id, e)
+		return nil
+	}
+	if subtle.ConstantTimeCompare(e.codeHash[:], hash[:]) != 1 {
+		return nil
+	}
+	return e
+}
+
+// has reports whether the private key of the DID queried with the security
+// code is cached.
+func (c *keyCache) has(id did.Identifier, code string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lookup(id, code) != nil
+}
+
+// get returns the algorithm and a copy of the cached private key of the
+// DID queried with the security code, nil key if none. The caller zeroes
+// the copy once used.
+func (c *keyCache) get(id did.Identifier, code string) (SignatureAlgorithm, *SecretBytes) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := c.lookup(id, code)
+	if e == nil {
+		return nil, nil
+	}
+	return e.alg, NewSecretBytes(append([]byte(nil), e.key.Bytes()...))
+}
+
+// put caches the private key of the DID queried with the security code, it
+// returns false if the key can not be decoded.
+func (c *keyCache) put(id did.Identifier, code, privateKey string) bool {
+	alg, encoded, err := keyAlgorithm(privateKey)
+	if err != nil {
+		return false
+	}
+	key, err := SecretFromBase64(encoded)
+	if err != nil {
+		return false
+	}
+	now := c.now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		if k == id || !now.Before(e.expires) {
+			c.evict(k, e)
+		}
+	}
+	c.entries[id] = &keyCacheEntry{
+		codeHash: sha256.Sum256([]byte(code)),
+		alg:      alg,
+		key:      key,
+		expires:  now.Add(c.ttl),
+	}
+	return true
+}
+
+// clear removes the cached key of the DID, all of them when id is empty.
+func (c *keyCache) clear(id did.Identifier) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		if id == "" || k == id {
+			c.evict(k, e)
+		}
+	}
+}
+
+// evict zeroes the cached key before removing it.
+func (c *keyCache) evict(id did.Identifier, e *keyCacheEntry) {
+	e.key.Zero()
+	delete(c.entries, id)
+}
+
+// cachedKeySigner signs with a copy of the private key of signature params
+// left in the key cache by queryPrivateKey, zeroed once signed.
+type cachedKeySigner struct {
+	keys   *keyCache
+	params *pki.SignatureParam
+}
+
+// cachedSigner returns the signer of signature params whose private key is
+// in the key cache, nil if it is not.
+func (w *WalletClient) cachedSigner(signParams *pki.SignatureParam) Signer {
+	if w.keys == nil || signParams == nil || signParams.PrivateKey != "" || signParams.SecurityCode == "" {
+		return nil
+	}
+	return &cachedKeySigner{keys: w.keys, params: signParams}
+}
+
+func (s *cachedKeySigner) Sign(payload []byte) (*pki.SignatureBody, error) {
+	alg, key := s.keys.get(s.params.Creator, s.params.SecurityCode)
+	if key == nil {
+		return nil, ErrKeyNotCached
+	}
+	defer key.Zero()
+	signData, name, err := signWithKey(alg, key, s.params, payload)
+	if err != nil {
+		return nil, err
+	}
+	return &pki.SignatureBody{
+		Creator:        s.params.Creator,
+		Created:        s.params.Created,
+		Nonce:          s.params.Nonce,
+		SignatureValue: signatureValue(name, utils.EncodeBase64(signData.Sign)),
+	}, nil
+}
@@ -0,0 +1,148 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/safebox"
+	"github.com/arxanchain/sdk-go-common/utils"
+	"golang.org/x/crypto/ed25519"
+)
+
+// countingSafebox counts the private key queries.
+type countingSafebox struct {
+	*fakeSafebox
+	queries int
+}
+
+func (s *countingSafebox) QueryPrivateKey(header http.Header, body *safebox.OperateKeyInfo) (*safebox.KeyPairResponse, error) {
+	s.queries++
+	return s.fakeSafebox.QueryPrivateKey(header, body)
+}
+
+func TestKeyCache(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	privateKey := utils.EncodeBase64(priv)
+	box := &countingSafebox{fakeSafebox: &fakeSafebox{keys: make(map[string]*safebox.KeyPairResponse)}}
+	client := &WalletClient{cfg: &restapi.Config{}, s: box}
+	code, err := client.TrustKeyPair(http.Header{}, &TrustKeyPairBody{Id: "did:axn:001", PrivateKey: privateKey, PublicKey: "public-key"})
+	if err != nil {
+		t.Fatalf("trust key pair fail: %v", err)
+	}
+
+	client.SetKeyCache(time.Minute)
+	now := time.Unix(1500000000, 0)
+	client.keys.now = func() time.Time { return now }
+
+	query := func(code string) (*pki.SignatureParam, error) {
+		return client.queryPrivateKey(http.Header{}, &pki.SignatureParam{Creator: "did:axn:001", SecurityCode: code})
+	}
+	for i := 0; i < 3; i++ {
+		params, err := query(code)
+		if err != nil || params.PrivateKey != "" || params.SecurityCode != code {
+			t.Fatalf("the private key should be left in the cache: %+v, %v", params, err)
+		}
+	}
+	if box.queries != 1 {
+		t.Fatalf("private key should be queried once, got %d queries", box.queries)
+	}
+
+	// the signatures copy the cached key
+	params, err := query(code)
+	if err != nil {
+		t.Fatalf("query private key fail: %v", err)
+	}
+	params.Nonce = "nonce"
+	sign, err := client.signPayload(params, []byte("payload"))
+	if err != nil {
+		t.Fatalf("sign with cached key fail: %v", err)
+	}
+	expected, err := SignDetached([]byte("payload"), &pki.SignatureParam{Creator: "did:axn:001", Nonce: "nonce", PrivateKey: privateKey})
+	if err != nil {
+		t.Fatalf("sign detached fail: %v", err)
+	}
+	if sign.SignatureValue != expected.SignatureValue {
+		t.Fatalf("the cached key should sign like the private key")
+	}
+	if client.keys.entries["did:axn:001"].key.Len() != len(priv) {
+		t.Fatalf("the cached key should be left intact by the signature")
+	}
+
+	// the cached key is not served for another security code
+	if _, err = query("wrong-code"); err == nil {
+		t.Fatalf("wrong security code should fail")
+	}
+	if box.queries != 2 {
+		t.Fatalf("wrong security code should be checked by the safebox, got %d queries", box.queries)
+	}
+
+	// expired keys are queried again and zeroed
+	cached := client.keys.entries["did:axn:001"].key.Bytes()
+	now = now.Add(time.Minute)
+	if _, err = query(code); err != nil {
+		t.Fatalf("query private key fail: %v", err)
+	}
+	if box.queries != 3 {
+		t.Fatalf("expired key should be queried again, got %d queries", box.queries)
+	}
+	for _, b := range cached {
+		if b != 0 {
+			t.Fatalf("evicted key should be zeroed: %q", cached)
+		}
+	}
+
+	// rotating the security code invalidates the cached key
+	cached = client.keys.entries["did:axn:001"].key.Bytes()
+	newCode, err := client.RotateSecurityCode(http.Header{}, "did:axn:001", code)
+	if err != nil {
+		t.Fatalf("rotate security code fail: %v", err)
+	}
+	if _, ok := client.keys.entries["did:axn:001"]; ok || cached[0] != 0 {
+		t.Fatalf("rotated key should be invalidated")
+	}
+	if _, err = query(code); err == nil {
+		t.Fatalf("old security code should fail after the rotation")
+	}
+	if _, err = query(newCode); err != nil {
+		t.Fatalf("query private key fail: %v", err)
+	}
+
+	params, err = query(newCode)
+	if err != nil {
+		t.Fatalf("query private key fail: %v", err)
+	}
+	client.InvalidateKey("")
+	if len(client.keys.entries) != 0 {
+		t.Fatalf("all cached keys should be invalidated")
+	}
+	if _, err = client.signPayload(params, []byte("payload")); err != ErrKeyNotCached {
+		t.Fatalf("err should be ErrKeyNotCached once the key is invalidated, got %v", err)
+	}
+	client.SetKeyCache(0)
+	if client.keys != nil {
+		t.Fatalf("key cache should be disabled")
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// KYCLevel is the KYC verification level of a wallet.
+//
+type KYCLevel int
+
+const (
+	// KYCNone means the wallet owner has not been verified
+	KYCNone KYCLevel = iota
+	// KYCBasic means the wallet owner identity has been checked
+	KYCBasic
+	// KYCVerified means the wallet owner identity has been verified with documents
+	KYCVerified
+	// KYCEnhanced means enhanced due diligence has been performed
+	KYCEnhanced
+)
+
+// KYCStatusBody is the request body of SetWalletKYCStatus.
+//
+// Expires is the expiry of the verification in unix seconds, zero means it never expires.
+//
+type KYCStatusBody struct {
+	Id        did.Identifier `json:"id"`
+	Level     KYCLevel       `json:"level"`
+	Expires   int64          `json:"expires,omitempty"`
+	Provider  string         `json:"provider,omitempty"`
+	Reference string         `json:"reference,omitempty"`
+}
+
+// KYCStatus is the KYC status attached to a wallet.
+//
+type KYCStatus struct {
+	Id        did.Identifier `json:"id"`
+	Level     KYCLevel       `json:"level"`
+	Expires   int64          `json:"expires,omitempty"`
+	Provider  string         `json:"provider,omitempty"`
+	Reference string         `json:"reference,omitempty"`
+	SetBy     did.Identifier `json:"set_by"`
+	Updated   int64          `json:"updated"`
+}
+
+// Satisfies reports whether the status reaches the required level and is not expired at now.
+//
+func (s *KYCStatus) Satisfies(required KYCLevel, now time.Time) bool {
+	if s == nil {
+		return required <= KYCNone
+	}
+	if s.Expires > 0 && now.Unix() >= s.Expires {
+		return required <= KYCNone
+	}
+	return s.Level >= required
+}
+
+// KYCError is returned when the counterparty of a transfer does not reach
+// the KYC level required by the client.
+//
+type KYCError struct {
+	Id       did.Identifier
+	Required KYCLevel
+	Status   *KYCStatus
+
+	redacted bool
+}
+
+func (e *KYCError) Error() string {
+	id := string(e.Id)
+	if e.redacted {
+		id = Redact(id)
+	}
+	if e.Status == nil {
+		return fmt.Sprintf("wallet %s KYC level insufficient: required %d, no KYC status", id, e.Required)
+	}
+	return fmt.Sprintf("wallet %s KYC level insufficient: required %d, actual %d (expires %d)", id, e.Required, e.Status.Level, e.Status.Expires)
+}
+
+// SetWalletKYCStatus is used to attach a KYC status to a wallet.
+//
+// The request must be signed by an administrator wallet of the platform.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) SetWalletKYCStatus(header http.Header, body *KYCStatusBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.Id == "" {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+
+	// Build request body
+	reqBody, err := w.buildWalletRequest(header, body, signParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = w.doJSON(header, "POST", "/v1/wallet/kyc", nil, reqBody, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// QueryWalletKYCStatus is used to query the KYC status of a wallet.
+//
+func (w *WalletClient) QueryWalletKYCStatus(header http.Header, id did.Identifier) (result *KYCStatus, err error) {
+	if id == "" {
+		err = fmt.Errorf("request id invalid")
+		return
+	}
+
+	if err = w.doJSON(header, "GET", "/v1/wallet/kyc", url.Values{"id": {string(id)}}, nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetTransferKYCRequirement makes transfers fail fast on the client side,
+// with a *KYCError, when the receiving wallet does not reach the required
+// KYC level. KYCNone disables the check.
+//
+func (w *WalletClient) SetTransferKYCRequirement(level KYCLevel) {
+	w.transferKYC = level
+}
+
+// checkCounterpartyKYC verifies the KYC status of the receiving wallet of a transfer.
+func (w *WalletClient) checkCounterpartyKYC(header http.Header, id string) error {
+	if w.transferKYC <= KYCNone {
+		return nil
+	}
+	status, err := w.QueryWalletKYCStatus(header, did.Identifier(id))
+	if err != nil {
+		return err
+	}
+	if !status.Satisfies(w.transferKYC, time.Now()) {
+		return &KYCError{Id: did.Identifier(id), Required: w.transferKYC, Status: status, redacted: w.privacy}
+	}
+	return nil
+}
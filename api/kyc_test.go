@@ -0,0 +1,138 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestQueryWalletKYCStatusSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		token = "user-token-001"
+		id    = "did:axn:001"
+	)
+
+	//response body
+	payload := &KYCStatus{
+		Id:      id,
+		Level:   KYCVerified,
+		Expires: time.Now().Add(time.Hour).Unix(),
+	}
+	byPayload, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody := &rtstructs.Response{
+		ErrCode: 0,
+		Payload: string(byPayload),
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/kyc").
+		MatchParam("id", id).
+		MatchHeader("X-Auth-Token", token).
+		Reply(200).
+		JSON(respBody)
+
+	//set http header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	//do query kyc status
+	status, err := client.QueryWalletKYCStatus(header, id)
+	if err != nil {
+		t.Fatalf("query kyc status fail: %v", err)
+	}
+	if !status.Satisfies(KYCBasic, time.Now()) {
+		t.Fatalf("kyc status should satisfy level %v", KYCBasic)
+	}
+	if status.Satisfies(KYCEnhanced, time.Now()) {
+		t.Fatalf("kyc status should not satisfy level %v", KYCEnhanced)
+	}
+}
+
+func TestTransferCTokenKYCInsufficient(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+	client.SetTransferKYCRequirement(KYCVerified)
+
+	const (
+		token = "user-token-001"
+		to    = "did:axn:002"
+	)
+
+	//response body with expired kyc status
+	payload := &KYCStatus{
+		Id:      to,
+		Level:   KYCEnhanced,
+		Expires: time.Now().Add(-time.Hour).Unix(),
+	}
+	byPayload, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody := &rtstructs.Response{
+		ErrCode: 0,
+		Payload: string(byPayload),
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/kyc").
+		MatchParam("id", to).
+		Reply(200).
+		JSON(respBody)
+
+	//set http header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	//do transfer colored token
+	reqBody := &wallet.TransferCTokenBody{
+		From: "did:axn:001",
+		To:   to,
+		Tokens: []*wallet.TokenAmount{
+			{TokenId: "ctoken-001", Amount: 100},
+		},
+	}
+	resp, err := client.TransferCToken(header, reqBody, &pki.SignatureParam{})
+	if err == nil {
+		t.Fatalf("err should not be nil when counterparty kyc is insufficient")
+	}
+	if _, ok := err.(*KYCError); !ok {
+		t.Fatalf("error type should be *KYCError, not %T", err)
+	}
+	if resp != nil {
+		t.Fatalf("response object should be nil when counterparty kyc is insufficient")
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"log"
+)
+
+// Logger receives the log messages of the wallet client.
+//
+// The methods are the ones of *zap.SugaredLogger and of the logrus loggers,
+// which can be set as is with SetLogger. The slog package adapts a
+// *slog.Logger and NewStdLogger a *log.Logger.
+//
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// LogLevel is the severity of a log message.
+//
+type LogLevel int
+
+// Log levels, from the most to the least verbose.
+//
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+// String returns the name of the level.
+//
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "DEBUG"
+	case LogInfo:
+		return "INFO"
+	case LogWarn:
+		return "WARN"
+	case LogError:
+		return "ERROR"
+	}
+	return fmt.Sprintf("LEVEL(%d)", int(l))
+}
+
+// NopLogger discards all messages, it is the logger of the clients without
+// logger set.
+//
+var NopLogger Logger = nopLogger{}
+
+type nopLogger struct{}
+
+func (nopLogger) Debugf(format string, args ...interface{}) {}
+func (nopLogger) Infof(format string, args ...interface{})  {}
+func (nopLogger) Warnf(format string, args ...interface{})  {}
+func (nopLogger) Errorf(format string, args ...interface{}) {}
+
+// stdLogger writes the messages of level or above to a *log.Logger.
+type stdLogger struct {
+	l     *log.Logger
+	level LogLevel
+}
+
+// NewStdLogger returns a Logger writing the messages of the specified level
+// or above to l, prefixed with their level. A nil l writes to the standard
+// logger of the log package.
+//
+func NewStdLogger(l *log.Logger, level LogLevel) Logger {
+	return &stdLogger{l: l, level: level}
+}
+
+func (s *stdLogger) logf(level LogLevel, format string, args []interface{}) {
+	if level < s.level {
+		return
+	}
+	msg := level.String() + " " + fmt.Sprintf(format, args...)
+	if s.l == nil {
+		log.Output(3, msg)
+		return
+	}
+	s.l.Output(3, msg)
+}
+
+func (s *stdLogger) Debugf(format string, args ...interface{}) { s.logf(LogDebug, format, args) }
+func (s *stdLogger) Infof(format string, args ...interface{})  { s.logf(LogInfo, format, args) }
+func (s *stdLogger) Warnf(format string, args ...interface{})  { s.logf(LogWarn, format, args) }
+func (s *stdLogger) Errorf(format string, args ...interface{}) { s.logf(LogError, format, args) }
+
+// SetLogger sets the logger of the client, nil discards the messages, which
+// is the default.
+//
+func (w *WalletClient) SetLogger(logger Logger) {
+	w.log = logger
+}
+
+// logger returns the logger of the client.
+func (w *WalletClient) logger() Logger {
+	if w.log == nil {
+		return NopLogger
+	}
+	return w.log
+}
@@ -0,0 +1,78 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+// recordLogger records the messages by level
+type recordLogger struct {
+	messages map[string][]string
+}
+
+func (r *recordLogger) record(level, format string) {
+	if r.messages == nil {
+		r.messages = map[string][]string{}
+	}
+	r.messages[level] = append(r.messages[level], format)
+}
+
+func (r *recordLogger) Debugf(format string, args ...interface{}) { r.record("debug", format) }
+func (r *recordLogger) Infof(format string, args ...interface{})  { r.record("info", format) }
+func (r *recordLogger) Warnf(format string, args ...interface{})  { r.record("warn", format) }
+func (r *recordLogger) Errorf(format string, args ...interface{}) { r.record("error", format) }
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewStdLogger(log.New(&buf, "", 0), LogWarn)
+	logger.Debugf("debug %d", 1)
+	logger.Infof("info %d", 2)
+	logger.Warnf("warn %d", 3)
+	logger.Errorf("error %d", 4)
+	if buf.String() != "WARN warn 3\nERROR error 4\n" {
+		t.Fatalf("only warnings and errors should be logged, got %q", buf.String())
+	}
+}
+
+func TestSetLogger(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	if client.logger() != NopLogger {
+		t.Fatalf("default logger should discard the messages")
+	}
+	logger := &recordLogger{}
+	client.SetLogger(logger)
+	defer client.SetLogger(nil)
+
+	if _, err := client.UploadPOEFile(http.Header{}, "did:axn:poe-001", "/nonexistent/poe-file", false); err == nil {
+		t.Fatalf("err should not be nil when the file does not exist")
+	}
+	if len(logger.messages["debug"]) != 1 || len(logger.messages["error"]) != 1 ||
+		!strings.Contains(logger.messages["error"][0], "fail") {
+		t.Fatalf("upload should log its call and its failure, got %v", logger.messages)
+	}
+}
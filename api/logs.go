@@ -0,0 +1,255 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+)
+
+// DefaultLogsPageSize is the page size of the transaction logs queries
+// without an explicit page size.
+//
+const DefaultLogsPageSize = 100
+
+// ErrIteratorDone is returned by the Next method of iterators when there
+// are no more items.
+//
+var ErrIteratorDone = fmt.Errorf("no more items in iterator")
+
+// TransactionLogsQuery is the query of a page of transaction logs.
+//
+type TransactionLogsQuery struct {
+	// Id is the wallet account to query the logs of
+	Id did.Identifier
+	// TxType is "in" for income, "out" for spending, other for both
+	TxType string
+	// PageSize is the maximum number of logs of a page, DefaultLogsPageSize
+	// if zero
+	PageSize int32
+	// Cursor is the position to read the page from, the NextCursor of the
+	// previous page, empty for the first page
+	Cursor string
+
+	// The optional filters below are applied by the gateway, zero values
+	// do not filter.
+
+	// StartTime and EndTime bound the creation time of the logs, in unix
+	// seconds, both inclusive
+	StartTime int64
+	EndTime   int64
+	// TokenId and AssetId select the logs of a colored token or of an asset
+	TokenId string
+	AssetId string
+	// Counterparty selects the logs of the transactions with that wallet
+	Counterparty did.Identifier
+	// MinAmount and MaxAmount bound the amount of the logs, both inclusive
+	MinAmount int64
+	MaxAmount int64
+}
+
+// checkFilters checks the filter ranges are not empty.
+func (q *TransactionLogsQuery) checkFilters() error {
+	if q.StartTime < 0 || q.EndTime < 0 || (q.EndTime > 0 && q.StartTime > q.EndTime) {
+		return fmt.Errorf("time range invalid: %d to %d", q.StartTime, q.EndTime)
+	}
+	if q.MinAmount < 0 || q.MaxAmount < 0 || (q.MaxAmount > 0 && q.MinAmount > q.MaxAmount) {
+		return fmt.Errorf("amount range invalid: %d to %d", q.MinAmount, q.MaxAmount)
+	}
+	return nil
+}
+
+// setFilterParams sets the query params of the filters which are set.
+func (q *TransactionLogsQuery) setFilterParams(params url.Values) {
+	setInt := func(name string, v int64) {
+		if v > 0 {
+			params.Set(name, strconv.FormatInt(v, 10))
+		}
+	}
+	setString := func(name, v string) {
+		if v != "" {
+			params.Set(name, v)
+		}
+	}
+	setInt("start_time", q.StartTime)
+	setInt("end_time", q.EndTime)
+	setString("token_id", q.TokenId)
+	setString("asset_id", q.AssetId)
+	setString("counterparty", string(q.Counterparty))
+	setInt("min_amount", q.MinAmount)
+	setInt("max_amount", q.MaxAmount)
+}
+
+// TransactionLogsPage is a page of transaction logs.
+//
+type TransactionLogsPage struct {
+	Logs []*pw.UTXO `json:"logs"`
+	// Memos are the memos of the transactions of the logs, by the
+	// SourceTxDataHash of the logs, see Memo
+	Memos map[string]*TransactionMemo `json:"memos,omitempty"`
+	// NextCursor is the cursor of the next page, empty on the last page
+	NextCursor string `json:"next_cursor"`
+}
+
+// QueryTransactionLogsPage is used to query a page of transaction logs,
+// busy wallets are read page by page following the returned cursors. The
+// logs can be filtered by time range, token, asset, counterparty and amount
+// range, see TransactionLogsQuery.
+//
+// TransactionLogs returns an iterator paging through the logs.
+//
+func (w *WalletClient) QueryTransactionLogsPage(header http.Header, query *TransactionLogsQuery) (result *TransactionLogsPage, err error) {
+	if query == nil || query.Id == "" {
+		err = fmt.Errorf("request id invalid")
+		return
+	}
+	if err = query.checkFilters(); err != nil {
+		return
+	}
+	pageSize := query.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultLogsPageSize
+	}
+
+	params := url.Values{}
+	params.Set("id", string(query.Id))
+	params.Set("type", query.TxType)
+	params.Set("size", strconv.Itoa(int(pageSize)))
+	if query.Cursor != "" {
+		params.Set("cursor", query.Cursor)
+	}
+	query.setFilterParams(params)
+
+	err = w.doJSON(header, "GET", "/v2/transaction/logs/page", params, nil, &result)
+	if err == nil && result == nil {
+		result = &TransactionLogsPage{}
+	}
+
+	return
+}
+
+// TransactionLogsPager queries the pages of transaction logs, e.g. a
+// WalletClient.
+//
+type TransactionLogsPager interface {
+	QueryTransactionLogsPage(header http.Header, query *TransactionLogsQuery) (*TransactionLogsPage, error)
+}
+
+// TransactionLogIterator iterates over transaction logs, querying the pages
+// as needed.
+//
+type TransactionLogIterator struct {
+	pager  TransactionLogsPager
+	header http.Header
+	query  TransactionLogsQuery
+	// pageCursor is the cursor of the page of logs
+	pageCursor string
+	logs       []*pw.UTXO
+	done       bool
+}
+
+// TransactionLogs returns an iterator over the transaction logs of the
+// query, from its cursor on:
+//
+//	logs := client.TransactionLogs(header, &api.TransactionLogsQuery{Id: id})
+//	for {
+//		log, err := logs.Next(ctx)
+//		if err == api.ErrIteratorDone {
+//			break
+//		}
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+func (w *WalletClient) TransactionLogs(header http.Header, query *TransactionLogsQuery) *TransactionLogIterator {
+	return NewTransactionLogIterator(w, header, query)
+}
+
+// NewTransactionLogIterator returns an iterator over the transaction logs
+// of the query, whose pages are queried with pager. The page queries of a
+// WalletClient are bound to the context passed to Next, the other pagers
+// are only not called once it is done.
+//
+func NewTransactionLogIterator(pager TransactionLogsPager, header http.Header, query *TransactionLogsQuery) *TransactionLogIterator {
+	it := &TransactionLogIterator{pager: pager, header: header}
+	if query != nil {
+		it.query = *query
+	}
+	return it
+}
+
+// Next returns the next transaction log, ErrIteratorDone when there are no
+// more logs. The page queries are bound to ctx, a failed query can be
+// retried by calling Next again.
+//
+func (it *TransactionLogIterator) Next(ctx context.Context) (*pw.UTXO, error) {
+	for len(it.logs) == 0 {
+		if it.done {
+			return nil, ErrIteratorDone
+		}
+		if err := it.fetch(ctx); err != nil {
+			return nil, err
+		}
+	}
+	log := it.logs[0]
+	it.logs = it.logs[1:]
+	return log, nil
+}
+
+// Cursor returns the cursor to resume the iteration from later, in the
+// query of another iterator. While the logs of a page are being returned,
+// it is the cursor of that page, whose logs are all returned again.
+//
+func (it *TransactionLogIterator) Cursor() string {
+	if len(it.logs) > 0 {
+		return it.pageCursor
+	}
+	return it.query.Cursor
+}
+
+func (it *TransactionLogIterator) fetch(ctx context.Context) error {
+	pager := it.pager
+	if ctx != nil {
+		if w, ok := pager.(*WalletClient); ok {
+			pager = w.WithContext(ctx)
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	page, err := pager.QueryTransactionLogsPage(it.header, &it.query)
+	if err != nil {
+		return err
+	}
+	if page == nil {
+		page = &TransactionLogsPage{}
+	}
+	it.pageCursor = it.query.Cursor
+	it.logs = page.Logs
+	if page.NextCursor == "" || page.NextCursor == it.query.Cursor {
+		it.done = true
+	}
+	it.query.Cursor = page.NextCursor
+	return nil
+}
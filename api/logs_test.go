@@ -0,0 +1,155 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestTransactionLogs(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/transaction/logs/page").
+		MatchParam("id", "did:axn:001").
+		MatchParam("size", "2").
+		Reply(200).
+		JSON(payloadResponse(t, &TransactionLogsPage{
+			Logs:       []*pw.UTXO{{Ix: "1"}, {Ix: "2"}},
+			NextCursor: "cursor-2",
+		}))
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/transaction/logs/page").
+		MatchParam("cursor", "cursor-2").
+		Reply(200).
+		JSON(payloadResponse(t, &TransactionLogsPage{
+			Logs: []*pw.UTXO{{Ix: "3"}},
+		}))
+
+	logs := client.TransactionLogs(http.Header{}, &TransactionLogsQuery{Id: "did:axn:001", PageSize: 2})
+	var ixs []string
+	for {
+		log, err := logs.Next(context.Background())
+		if err == ErrIteratorDone {
+			break
+		}
+		if err != nil {
+			t.Fatalf("next transaction log fail: %v", err)
+		}
+		ixs = append(ixs, log.Ix)
+		if log.Ix == "1" && logs.Cursor() != "" {
+			t.Fatalf("cursor should be the one of the first page, not %s", logs.Cursor())
+		}
+	}
+	if len(ixs) != 3 || ixs[0] != "1" || ixs[2] != "3" {
+		t.Fatalf("logs of both pages should be returned in order, got %v", ixs)
+	}
+	if !gock.IsDone() {
+		t.Fatalf("both pages should be queried")
+	}
+	if _, err := logs.Next(context.Background()); err != ErrIteratorDone {
+		t.Fatalf("err should be ErrIteratorDone once the logs are read, not %v", err)
+	}
+}
+
+func TestTransactionLogsFail(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	if _, err := client.QueryTransactionLogsPage(http.Header{}, &TransactionLogsQuery{}); err == nil {
+		t.Fatalf("err should not be nil when the id is not set")
+	}
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/transaction/logs/page").
+		Reply(503)
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/transaction/logs/page").
+		Reply(200).
+		JSON(payloadResponse(t, &TransactionLogsPage{Logs: []*pw.UTXO{{Ix: "1"}}}))
+
+	logs := client.TransactionLogs(http.Header{}, &TransactionLogsQuery{Id: "did:axn:001"})
+	if _, err := logs.Next(context.Background()); err == nil {
+		t.Fatalf("err should not be nil when the page query fails")
+	}
+	log, err := logs.Next(context.Background())
+	if err != nil || log.Ix != "1" {
+		t.Fatalf("next should retry the failed page query, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	logs = client.TransactionLogs(http.Header{}, &TransactionLogsQuery{Id: "did:axn:001"})
+	if _, err = logs.Next(ctx); err != context.Canceled {
+		t.Fatalf("err should be context.Canceled, not %v", err)
+	}
+}
+
+func TestQueryTransactionLogsPageFilters(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/transaction/logs/page").
+		MatchParam("start_time", "^1500000000$").
+		MatchParam("end_time", "^1600000000$").
+		MatchParam("token_id", "^token-001$").
+		MatchParam("counterparty", "^did:axn:002$").
+		MatchParam("min_amount", "^10$").
+		MatchParam("asset_id", "^$").
+		MatchParam("max_amount", "^$").
+		Reply(200).
+		JSON(payloadResponse(t, &TransactionLogsPage{Logs: []*pw.UTXO{{Ix: "1"}}}))
+
+	page, err := client.QueryTransactionLogsPage(http.Header{}, &TransactionLogsQuery{
+		Id:           "did:axn:001",
+		StartTime:    1500000000,
+		EndTime:      1600000000,
+		TokenId:      "token-001",
+		Counterparty: "did:axn:002",
+		MinAmount:    10,
+	})
+	if err != nil {
+		t.Fatalf("query transaction logs page fail: %v", err)
+	}
+	if len(page.Logs) != 1 {
+		t.Fatalf("page should hold one log")
+	}
+
+	invalid := []*TransactionLogsQuery{
+		{Id: "did:axn:001", StartTime: 1600000000, EndTime: 1500000000},
+		{Id: "did:axn:001", MinAmount: 10, MaxAmount: 5},
+		{Id: "did:axn:001", MinAmount: -1},
+	}
+	for _, query := range invalid {
+		if _, err = client.QueryTransactionLogsPage(http.Header{}, query); err == nil {
+			t.Fatalf("err should not be nil for the invalid filters %+v", query)
+		}
+	}
+}
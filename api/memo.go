@@ -0,0 +1,168 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/ecdsa"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/wallet-sdk-go/crypto/ecies"
+)
+
+// MemoTransferBody is the request body of TransferCTokenMemo.
+//
+// The memo is sent in the transfer proposal, so it is recorded on chain
+// with the signed transactions. MemoEncryption is the scheme the memo is
+// encrypted with, as returned by EncryptMemo, empty for a plaintext memo.
+//
+type MemoTransferBody struct {
+	From           string                `json:"from"`
+	To             string                `json:"to"`
+	AssetId        string                `json:"asset_id,omitempty"`
+	Tokens         []*wallet.TokenAmount `json:"tokens"`
+	Fee            *wallet.Fee           `json:"fee,omitempty"`
+	Memo           string                `json:"memo,omitempty"`
+	MemoEncryption string                `json:"memo_encryption,omitempty"`
+}
+
+// TransactionMemo is the memo of a transaction, see TransactionLogsPage.
+//
+type TransactionMemo struct {
+	Memo string `json:"memo"`
+	// Encryption is the scheme the memo is encrypted with, empty for a
+	// plaintext memo
+	Encryption string `json:"encryption,omitempty"`
+}
+
+// EncryptMemo encrypts a memo to the public key of the recipient, only the
+// recipient can read it with DecryptMemo. The key can be on any of the NIST
+// curves, the returned encryption names the scheme used.
+//
+func EncryptMemo(memo string, recipientKey *ecdsa.PublicKey) (ciphertext, encryption string, err error) {
+	if recipientKey == nil {
+		err = fmt.Errorf("recipient key must be set")
+		return
+	}
+	data, err := ecies.Encrypt(recipientKey, []byte(memo))
+	if err != nil {
+		return
+	}
+	return base64.StdEncoding.EncodeToString(data), ecies.AlgorithmOf(recipientKey.Curve), nil
+}
+
+// DecryptMemo decrypts a memo encrypted with EncryptMemo with the private
+// key of the recipient, plaintext memos (empty encryption) are returned as is.
+//
+func DecryptMemo(memo, encryption string, key *ecdsa.PrivateKey) (string, error) {
+	if encryption == "" {
+		return memo, nil
+	}
+	if key == nil {
+		return "", fmt.Errorf("recipient key must be set")
+	}
+	if encryption != ecies.AlgorithmOf(key.Curve) {
+		return "", fmt.Errorf("memo encryption %s not supported by the key", encryption)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(memo)
+	if err != nil {
+		return "", fmt.Errorf("encrypted memo invalid: %v", err)
+	}
+	data, err := ecies.Decrypt(key, ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// MemoKeyFunc returns the public key to encrypt the memos sent to a
+// recipient wallet to.
+//
+type MemoKeyFunc func(recipient string) (*ecdsa.PublicKey, error)
+
+// SetMemoEncryption makes TransferCTokenMemo and HoldCToken encrypt the
+// plaintext memos to the public key of the recipient returned by keys, nil
+// disables it. The calls fail when the key can not be found.
+//
+func (w *WalletClient) SetMemoEncryption(keys MemoKeyFunc) {
+	w.memoKeys = keys
+}
+
+// encryptMemo encrypts a plaintext memo to the recipient when the memo
+// encryption is enabled, memos with an encryption are returned as is.
+func (w *WalletClient) encryptMemo(memo, encryption, recipient string) (string, string, error) {
+	if w.memoKeys == nil || memo == "" || encryption != "" {
+		return memo, encryption, nil
+	}
+	key, err := w.memoKeys(recipient)
+	if err != nil {
+		return "", "", fmt.Errorf("memo key of %s error: %v", recipient, err)
+	}
+	return EncryptMemo(memo, key)
+}
+
+// TransferCTokenMemo is used to transfer colored tokens from one user to
+// another with a memo, see MemoTransferBody. It goes through the same
+// counterparty checks as TransferCToken.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) TransferCTokenMemo(header http.Header, body *MemoTransferBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	// the memo is encrypted in a copy, the body of the caller is left as is
+	proposal := *body
+	body = &proposal
+	if body.Memo, body.MemoEncryption, err = w.encryptMemo(body.Memo, body.MemoEncryption, body.To); err != nil {
+		return
+	}
+
+	transfer := &wallet.TransferCTokenBody{From: body.From, To: body.To, AssetId: body.AssetId, Tokens: body.Tokens, Fee: body.Fee}
+	if err = w.fillFee(header, TxKindTransferCToken, transfer, &transfer.Fee); err != nil {
+		return
+	}
+	body.Fee = transfer.Fee
+	return w.transferCToken(header, transfer, body, signParams)
+}
+
+// Memo returns the memo of the transaction of a log, decrypted with the
+// private key of the recipient if it is encrypted.
+//
+func (p *TransactionLogsPage) Memo(log *pw.UTXO, key *ecdsa.PrivateKey) (string, error) {
+	if log == nil {
+		return "", nil
+	}
+	memo := p.Memos[log.SourceTxDataHash]
+	if memo == nil {
+		return "", nil
+	}
+	return DecryptMemo(memo.Memo, memo.Encryption, key)
+}
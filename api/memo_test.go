@@ -0,0 +1,144 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/wallet-sdk-go/crypto/ecies"
+)
+
+func TestEncryptMemo(t *testing.T) {
+	priv, err := ecies.GenerateKey()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	memo, encryption, err := EncryptMemo("invoice 2018-042", &priv.PublicKey)
+	if err != nil {
+		t.Fatalf("encrypt memo fail: %v", err)
+	}
+	if encryption != ecies.Algorithm || memo == "invoice 2018-042" {
+		t.Fatalf("memo should be encrypted, got %q, %q", memo, encryption)
+	}
+
+	plain, err := DecryptMemo(memo, encryption, priv)
+	if err != nil || plain != "invoice 2018-042" {
+		t.Fatalf("memo should be decrypted, got %q, %v", plain, err)
+	}
+	other, _ := ecies.GenerateKey()
+	if _, err = DecryptMemo(memo, encryption, other); err == nil {
+		t.Fatalf("err should not be nil decrypting with another key")
+	}
+	// plaintext memos are told by their empty encryption, not by their content
+	if plain, err = DecryptMemo("ecies:plain memo", "", priv); err != nil || plain != "ecies:plain memo" {
+		t.Fatalf("plaintext memo should be returned as is, got %q, %v", plain, err)
+	}
+}
+
+func TestEncryptMemoCurves(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	memo, encryption, err := EncryptMemo("invoice 2018-042", &priv.PublicKey)
+	if err != nil {
+		t.Fatalf("encrypt memo with a P-384 key fail: %v", err)
+	}
+	if plain, err := DecryptMemo(memo, encryption, priv); err != nil || plain != "invoice 2018-042" {
+		t.Fatalf("memo should be decrypted, got %q, %v", plain, err)
+	}
+	p256, _ := ecies.GenerateKey()
+	if _, err = DecryptMemo(memo, encryption, p256); err == nil {
+		t.Fatalf("err should not be nil decrypting with a key of another curve")
+	}
+}
+
+func TestTransferCTokenMemo(t *testing.T) {
+	priv, err := ecies.GenerateKey()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	// the invalid proposal response stops the transfer after the prepare request
+	respBody, err := json.Marshal(payloadResponse(t, map[string]string{}))
+	if err != nil {
+		t.Fatalf("marshal response fail: %v", err)
+	}
+	transport := &captureTransport{resp: respBody}
+	client, err := NewWalletClient(&restapi.Config{
+		Address:    "http://127.0.0.1:8006",
+		HttpClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+	client.SetMemoEncryption(func(recipient string) (*ecdsa.PublicKey, error) {
+		if recipient != "did:axn:002" {
+			return nil, fmt.Errorf("unknown recipient")
+		}
+		return &priv.PublicKey, nil
+	})
+
+	body := &MemoTransferBody{
+		From:   "did:axn:001",
+		To:     "did:axn:002",
+		Tokens: []*wallet.TokenAmount{{TokenId: "ctoken-001", Amount: 10}},
+		Memo:   "invoice 2018-042",
+	}
+	if _, err = client.TransferCTokenMemo(http.Header{}, body, verifySignParams); err == nil {
+		t.Fatalf("err should not be nil with an invalid proposal response")
+	}
+	if body.Memo != "invoice 2018-042" || body.MemoEncryption != "" {
+		t.Fatalf("body of the caller should not be modified")
+	}
+	if transport.path != "/v2/transaction/tokens/transfer/prepare" {
+		t.Fatalf("memo should be sent in the transfer proposal, got %s", transport.path)
+	}
+	var proposal MemoTransferBody
+	if err = json.Unmarshal(transport.body, &proposal); err != nil {
+		t.Fatalf("unmarshal proposal fail: %v", err)
+	}
+	if proposal.From != body.From || proposal.To != body.To || proposal.MemoEncryption != ecies.Algorithm {
+		t.Fatalf("proposal should carry the transfer and the memo encryption, got %+v", proposal)
+	}
+	page := &TransactionLogsPage{Memos: map[string]*TransactionMemo{
+		"tx-hash-001": {Memo: proposal.Memo, Encryption: proposal.MemoEncryption},
+	}}
+	memo, err := page.Memo(&pw.UTXO{SourceTxDataHash: "tx-hash-001"}, priv)
+	if err != nil || memo != "invoice 2018-042" {
+		t.Fatalf("memo of the log should be decrypted, got %q, %v", memo, err)
+	}
+
+	body.To = "did:axn:003"
+	if _, err = client.TransferCTokenMemo(http.Header{}, body, verifySignParams); err == nil {
+		t.Fatalf("err should not be nil without recipient key")
+	}
+	body.Memo = ""
+	transport.path = ""
+	client.TransferCTokenMemo(http.Header{}, body, verifySignParams)
+	if transport.path == "" {
+		t.Fatalf("transfers without memo should not need a key")
+	}
+}
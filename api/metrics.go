@@ -0,0 +1,42 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"time"
+)
+
+// Metrics receives the measures of the calls to the wallet gateway, e.g. to
+// export them to Prometheus, see the metrics/prometheus package.
+//
+// The methods are called concurrently by the calls of the client.
+//
+type Metrics interface {
+	// ObserveCall is called at the end of every call with its duration and
+	// error, an *Error when the gateway rejected the call
+	ObserveCall(call *Call, duration time.Duration, err error)
+	// ObserveUpload is called with the file bytes sent by the POE file
+	// upload calls, before the end of the call
+	ObserveUpload(call *Call, bytes int64)
+}
+
+// SetMetrics sets the metrics receiving the measures of the calls, nil
+// disables them, which is the default.
+//
+func (w *WalletClient) SetMetrics(metrics Metrics) {
+	w.metrics = metrics
+}
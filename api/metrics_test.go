@@ -0,0 +1,118 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+// fakeMetrics records the measures
+type fakeMetrics struct {
+	mu       sync.Mutex
+	calls    []string
+	errs     []error
+	uploaded int64
+}
+
+func (f *fakeMetrics) ObserveCall(call *Call, duration time.Duration, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, call.Method+" "+call.Endpoint)
+	f.errs = append(f.errs, err)
+}
+
+func (f *fakeMetrics) ObserveUpload(call *Call, bytes int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.uploaded += bytes
+}
+
+func TestSetMetrics(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	metrics := &fakeMetrics{}
+	client.SetMetrics(metrics)
+	defer client.SetMetrics(nil)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/balance").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletBalance{}))
+	gock.New("http://127.0.0.1:8006").
+		Put("/v1/poe/upload/chunk").
+		Reply(200).
+		JSON(&rtstructs.Response{ErrCode: 8000, ErrMessage: "upload session not found"})
+
+	if _, err := client.GetWalletBalance(http.Header{}, "did:axn:001"); err != nil {
+		t.Fatalf("get wallet balance fail: %v", err)
+	}
+	if err := client.uploadChunk(http.Header{}, "session-001", 0, []byte("chunk")); err == nil {
+		t.Fatalf("err should not be nil when the gateway returns an error code")
+	}
+
+	if len(metrics.calls) != 2 || metrics.calls[0] != "GET /v1/wallet/balance" || metrics.calls[1] != "PUT /v1/poe/upload/chunk" {
+		t.Fatalf("calls should be measured, got %v", metrics.calls)
+	}
+	if e, ok := metrics.errs[1].(*Error); metrics.errs[0] != nil || !ok || e.ErrCode != 8000 {
+		t.Fatalf("call errors should be measured, got %v", metrics.errs)
+	}
+	if metrics.uploaded != int64(len("chunk")) {
+		t.Fatalf("chunk bytes should be measured, got %d", metrics.uploaded)
+	}
+}
+
+func TestMetricsPOEUpload(t *testing.T) {
+	const content = "generated poe file content"
+
+	byPayload, err := json.Marshal(&wallet.UploadResponse{Id: "did:axn:poe-id-001"})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody, err := json.Marshal(&rtstructs.Response{Payload: string(byPayload)})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	client, err := NewWalletClient(&restapi.Config{
+		Address:    "http://127.0.0.1:8006",
+		HttpClient: &http.Client{Transport: &formTransport{fields: map[string]string{}, resp: respBody}},
+	})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+	metrics := &fakeMetrics{}
+	client.SetMetrics(metrics)
+
+	if _, err = client.UploadPOEFileFromReader(http.Header{}, "did:axn:poe-id-001", "contract.pdf", -1, strings.NewReader(content), false); err != nil {
+		t.Fatalf("upload poe file fail: %v", err)
+	}
+	if metrics.uploaded != int64(len(content)) || len(metrics.calls) != 1 || metrics.calls[0] != "POST /v1/poe/upload" {
+		t.Fatalf("upload should be measured, got %d bytes and calls %v", metrics.uploaded, metrics.calls)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+)
+
+// RoundTripFunc sends a http request to the wallet gateway and returns its
+// response, like http.RoundTripper.
+//
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the sending of the http requests to the wallet gateway,
+// e.g. to log them, refresh credentials, capture them for audit or inject
+// failures in tests. It returns the RoundTripFunc calling next to send the
+// request:
+//
+//	func(next api.RoundTripFunc) api.RoundTripFunc {
+//		return func(req *http.Request) (*http.Response, error) {
+//			req.Header.Set("X-Request-Id", newRequestID())
+//			return next(req)
+//		}
+//	}
+//
+// Like http.RoundTripper, a middleware must not modify the request, but
+// clone it to change it.
+//
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// middlewareChain holds the middleware of a client, shared by the copies
+// made with WithContext
+type middlewareChain struct {
+	mu          sync.RWMutex
+	middleware  []Middleware
+	credentials *credentialSource
+	tokens      *tokenAuth
+	// contexts are the contexts of the requests being sent, by id
+	contexts   map[string]context.Context
+	contextSeq uint64
+}
+
+// middlewareTransport sends the requests through the middleware chain, then
+// with next, the transport of the http client of the configuration.
+//
+// The credentials of the credential store are set on the requests first,
+// configured tells whether the configuration has an API key. The access
+// token of the token source is set last, the middleware see it.
+type middlewareTransport struct {
+	chain      *middlewareChain
+	next       http.RoundTripper
+	configured bool
+}
+
+// RoundTrip implements http.RoundTripper.
+//
+func (t *middlewareTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	rt := RoundTripFunc(next.RoundTrip)
+
+	t.chain.mu.RLock()
+	middleware := t.chain.middleware
+	credentials := t.chain.credentials
+	tokens := t.chain.tokens
+	t.chain.mu.RUnlock()
+	req = t.chain.withBoundContext(req)
+	if credentials != nil {
+		var err error
+		if req, err = credentials.apply(req, t.configured); err != nil {
+			return nil, err
+		}
+	}
+	for i := len(middleware) - 1; i >= 0; i-- {
+		rt = middleware[i](rt)
+	}
+	if tokens != nil && req.Header.Get(AuthorizationHeader) == "" {
+		return tokens.roundTrip(req, rt)
+	}
+	return rt(req)
+}
+
+// Use appends middleware to the chain the requests of the client go through,
+// the first one added is the outermost. The chain applies to the requests of
+// all the endpoints and to the copies of the client made with WithContext.
+//
+func (w *WalletClient) Use(middleware ...Middleware) {
+	w.middleware.mu.Lock()
+	defer w.middleware.mu.Unlock()
+	chain := make([]Middleware, 0, len(w.middleware.middleware)+len(middleware))
+	chain = append(chain, w.middleware.middleware...)
+	for _, m := range middleware {
+		if m != nil {
+			chain = append(chain, m)
+		}
+	}
+	w.middleware.middleware = chain
+}
+
+// newRestClient returns the rest client of the configuration, its requests
+// going through the middleware chain. The configuration and its http client
+// are not modified, a copy of the http client with the options applied
+// sends the requests.
+func newRestClient(config *restapi.Config, chain *middlewareChain, o *clientOptions) (*restapi.Client, error) {
+	httpClient := config.HttpClient
+	if httpClient == nil {
+		var err error
+		if httpClient, err = defaultHTTPClient(config); err != nil {
+			return nil, err
+		}
+	}
+	httpClient, err := o.apply(httpClient)
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Transport = &middlewareTransport{chain: chain, next: httpClient.Transport, configured: config.ApiKey != ""}
+
+	clientConfig := *config
+	clientConfig.HttpClient = httpClient
+	return restapi.NewClient(&clientConfig)
+}
+
+// defaultHTTPClient returns the default http client the rest client sets up
+// for the configuration, with its TLS settings. It is set up on a copy of
+// the configuration.
+func defaultHTTPClient(config *restapi.Config) (*http.Client, error) {
+	defaults := *config
+	defaults.HttpClient = nil
+	if _, err := restapi.NewClient(&defaults); err != nil {
+		return nil, err
+	}
+	if defaults.HttpClient == nil {
+		return &http.Client{}, nil
+	}
+	return defaults.HttpClient, nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestUseMiddleware(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, name+" "+req.URL.Path)
+				clone := *req
+				clone.Header = ApplyOptions(req.Header, WithHeader("X-Middleware", name))
+				return next(&clone)
+			}
+		}
+	}
+	client.Use(trace("outer"), nil, trace("inner"))
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/balance").
+		MatchHeader("X-Middleware", "inner").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletBalance{}))
+
+	if _, err := client.WithContext(context.Background()).GetWalletBalance(http.Header{}, "did:axn:001"); err != nil {
+		t.Fatalf("get wallet balance fail: %v", err)
+	}
+	if len(order) != 2 || order[0] != "outer /v1/wallet/balance" || order[1] != "inner /v1/wallet/balance" {
+		t.Fatalf("requests should go through the middleware in order, got %v", order)
+	}
+
+	// a middleware can fail the requests without sending them
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return nil, fmt.Errorf("chaos: connection reset")
+		}
+	})
+	_, err := client.GetWalletInfo(http.Header{}, "did:axn:001")
+	if err == nil || !strings.Contains(err.Error(), "chaos") {
+		t.Fatalf("err should be the middleware error, got %v", err)
+	}
+}
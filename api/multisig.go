@@ -0,0 +1,183 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// PendingTransaction is a transaction payload awaiting the signatures of N
+// of its M key holders.
+//
+// It is passed around the key holders, each of them adding a signature with
+// Sign or CollectSignature, then submitted with SubmitMultiSigTransaction
+// once the threshold is reached. Its JSON encoding keeps the payload bytes
+// as built.
+//
+type PendingTransaction struct {
+	Kind       string               `json:"kind"`
+	Payload    []byte               `json:"payload"`
+	Threshold  int                  `json:"threshold"`
+	Signers    []did.Identifier     `json:"signers"`
+	Signatures []*pki.SignatureBody `json:"signatures,omitempty"`
+}
+
+// multiSigRequest is the request body of SubmitMultiSigTransaction.
+type multiSigRequest struct {
+	Kind       string               `json:"kind"`
+	Payload    string               `json:"payload"`
+	Threshold  int                  `json:"threshold"`
+	Signatures []*pki.SignatureBody `json:"signatures"`
+}
+
+// NewPendingTransaction returns a pending transaction of the payload, built
+// with one of the Build*Payload functions, requiring the signatures of
+// threshold of the signers.
+//
+func NewPendingTransaction(kind string, payload []byte, threshold int, signers []did.Identifier) (*PendingTransaction, error) {
+	if _, ok := signedTxPaths[kind]; !ok {
+		return nil, fmt.Errorf("signed transaction kind invalid: %s", kind)
+	}
+	if len(payload) == 0 {
+		return nil, fmt.Errorf("request payload invalid")
+	}
+	seen := make(map[did.Identifier]bool, len(signers))
+	for i, signer := range signers {
+		if signer == "" || seen[signer] {
+			return nil, fmt.Errorf("signer %d invalid", i)
+		}
+		seen[signer] = true
+	}
+	if threshold < 1 || threshold > len(signers) {
+		return nil, fmt.Errorf("signature threshold invalid: %d of %d signers", threshold, len(signers))
+	}
+	return &PendingTransaction{
+		Kind:      kind,
+		Payload:   payload,
+		Threshold: threshold,
+		Signers:   signers,
+	}, nil
+}
+
+// CollectSignature adds the signature of one of the signers, made with
+// SignDetached on the payload. A signer signs only once.
+//
+func (p *PendingTransaction) CollectSignature(sign *pki.SignatureBody) error {
+	if sign == nil || sign.SignatureValue == "" {
+		return fmt.Errorf("request signature invalid")
+	}
+	if !p.isSigner(sign.Creator) {
+		return fmt.Errorf("signature creator is not a signer of the transaction")
+	}
+	if p.hasSigned(sign.Creator) {
+		return fmt.Errorf("signature creator already signed the transaction")
+	}
+	p.Signatures = append(p.Signatures, sign)
+	return nil
+}
+
+// Sign signs the payload with the signature params and adds the signature.
+//
+func (p *PendingTransaction) Sign(signParams *pki.SignatureParam) error {
+	if signParams != nil && !p.isSigner(signParams.Creator) {
+		return fmt.Errorf("signature creator is not a signer of the transaction")
+	}
+	sign, err := SignDetached(p.Payload, signParams)
+	if err != nil {
+		return err
+	}
+	return p.CollectSignature(sign)
+}
+
+// Ready reports whether the threshold of signatures is reached.
+//
+func (p *PendingTransaction) Ready() bool {
+	return len(p.Signatures) >= p.Threshold
+}
+
+// Missing returns the signers who have not signed yet.
+//
+func (p *PendingTransaction) Missing() []did.Identifier {
+	var missing []did.Identifier
+	for _, signer := range p.Signers {
+		if !p.hasSigned(signer) {
+			missing = append(missing, signer)
+		}
+	}
+	return missing
+}
+
+func (p *PendingTransaction) isSigner(creator did.Identifier) bool {
+	for _, signer := range p.Signers {
+		if signer == creator {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *PendingTransaction) hasSigned(creator did.Identifier) bool {
+	for _, sign := range p.Signatures {
+		if sign.Creator == creator {
+			return true
+		}
+	}
+	return false
+}
+
+// SubmitMultiSigTransaction is used to submit a pending transaction once the
+// threshold of signatures is reached.
+//
+// The payload is sent as is with all the collected signatures.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+func (w *WalletClient) SubmitMultiSigTransaction(header http.Header, tx *PendingTransaction) (result *wallet.WalletResponse, err error) {
+	if tx == nil || len(tx.Payload) == 0 {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	if _, ok := signedTxPaths[tx.Kind]; !ok {
+		err = fmt.Errorf("signed transaction kind invalid: %s", tx.Kind)
+		return
+	}
+	if !tx.Ready() {
+		err = fmt.Errorf("transaction has %d of %d signatures required", len(tx.Signatures), tx.Threshold)
+		return
+	}
+
+	reqBody := &multiSigRequest{
+		Kind:       tx.Kind,
+		Payload:    string(tx.Payload),
+		Threshold:  tx.Threshold,
+		Signatures: tx.Signatures,
+	}
+
+	err = w.doJSON(header, "POST", "/v1/transaction/multisig", nil, reqBody, &result)
+
+	return
+}
@@ -0,0 +1,125 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+func TestMultiSigTransaction(t *testing.T) {
+	payload, err := BuildTransferPayload(&wallet.TransferCTokenBody{From: "did:axn:vault", To: "did:axn:002"})
+	if err != nil {
+		t.Fatalf("build transfer payload fail: %v", err)
+	}
+	signers := []did.Identifier{"did:axn:001", "did:axn:002", "did:axn:003"}
+	if _, err = NewPendingTransaction(TxKindTransferCToken, payload, 4, signers); err == nil {
+		t.Fatalf("err should not be nil when the threshold exceeds the signers")
+	}
+	pending, err := NewPendingTransaction(TxKindTransferCToken, payload, 2, signers)
+	if err != nil {
+		t.Fatalf("new pending transaction fail: %v", err)
+	}
+
+	// first key holder
+	if err = pending.Sign(verifySignParams); err != nil {
+		t.Fatalf("sign pending transaction fail: %v", err)
+	}
+	if err = pending.Sign(verifySignParams); err == nil {
+		t.Fatalf("err should not be nil when a signer signs twice")
+	}
+	outsider := *verifySignParams
+	outsider.Creator = "did:axn:004"
+	if err = pending.Sign(&outsider); err == nil {
+		t.Fatalf("err should not be nil when the creator is not a signer")
+	}
+	if pending.Ready() {
+		t.Fatalf("pending transaction should not be ready with one signature")
+	}
+
+	client, err := NewWalletClient(&restapi.Config{
+		Address:    "http://127.0.0.1:8006",
+		HttpClient: &http.Client{Transport: &captureTransport{}},
+	})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+	if _, err = client.SubmitMultiSigTransaction(http.Header{}, pending); err == nil {
+		t.Fatalf("err should not be nil before the threshold is reached")
+	}
+
+	// second key holder, on another machine
+	data, err := json.Marshal(pending)
+	if err != nil {
+		t.Fatalf("marshal pending transaction fail: %v", err)
+	}
+	var received PendingTransaction
+	if err = json.Unmarshal(data, &received); err != nil {
+		t.Fatalf("unmarshal pending transaction fail: %v", err)
+	}
+	second := *verifySignParams
+	second.Creator = "did:axn:003"
+	sign, err := SignDetached(received.Payload, &second)
+	if err != nil {
+		t.Fatalf("sign payload fail: %v", err)
+	}
+	if err = received.CollectSignature(sign); err != nil {
+		t.Fatalf("collect signature fail: %v", err)
+	}
+	if !received.Ready() {
+		t.Fatalf("pending transaction should be ready with two signatures")
+	}
+	if missing := received.Missing(); len(missing) != 1 || missing[0] != "did:axn:002" {
+		t.Fatalf("missing signer should be did:axn:002, not %v", missing)
+	}
+
+	respBody, err := json.Marshal(payloadResponse(t, &wallet.WalletResponse{Id: "tx-001"}))
+	if err != nil {
+		t.Fatalf("marshal response fail: %v", err)
+	}
+	transport := &captureTransport{resp: respBody}
+	client, err = NewWalletClient(&restapi.Config{
+		Address:    "http://127.0.0.1:8006",
+		HttpClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+	result, err := client.SubmitMultiSigTransaction(http.Header{}, &received)
+	if err != nil {
+		t.Fatalf("submit multi sig transaction fail: %v", err)
+	}
+	if result == nil || result.Id != "tx-001" {
+		t.Fatalf("response id should be tx-001")
+	}
+	var reqBody struct {
+		Payload    string               `json:"payload"`
+		Signatures []*pki.SignatureBody `json:"signatures"`
+	}
+	if err = json.Unmarshal(transport.body, &reqBody); err != nil {
+		t.Fatalf("unmarshal request body fail: %v", err)
+	}
+	if reqBody.Payload != string(payload) || len(reqBody.Signatures) != 2 {
+		t.Fatalf("payload should be sent as built with the two signatures")
+	}
+}
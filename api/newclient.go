@@ -0,0 +1,176 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+)
+
+// New returns a WalletClient instance set with the options, the gateway
+// address must be set with WithBaseURL:
+//
+//	client, err := api.New(
+//		api.WithBaseURL("https://wallet.example.com:9143"),
+//		api.WithAPIKey(apiKey),
+//		api.WithRetry(&api.RetryPolicy{MaxAttempts: 3}),
+//		api.WithLogger(logger),
+//	)
+//
+// NewWalletClient takes a restapi.Config instead, for the settings without
+// option, e.g. the TLS certificates or the trusted key pairs.
+//
+func New(opts ...ClientOption) (*WalletClient, error) {
+	o := newClientOptions(opts)
+	if o.err != nil {
+		return nil, o.err
+	}
+	if o.address == "" {
+		return nil, fmt.Errorf("base url must be set")
+	}
+	return newWalletClient(&restapi.Config{}, o)
+}
+
+// WithBaseURL sets the address of the wallet gateway, e.g.
+// "http://127.0.0.1:8006".
+//
+func WithBaseURL(address string) ClientOption {
+	return func(o *clientOptions) {
+		o.address = address
+	}
+}
+
+// WithAPIKey sets the API key sent with the requests.
+//
+func WithAPIKey(apiKey string) ClientOption {
+	return func(o *clientOptions) {
+		o.apiKey = apiKey
+	}
+}
+
+// WithHTTPClient sets the http client sending the requests, it is not
+// modified by the client.
+//
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(o *clientOptions) {
+		o.httpClient = client
+	}
+}
+
+// WithLogger sets the logger of the client, see SetLogger.
+//
+func WithLogger(logger Logger) ClientOption {
+	return func(o *clientOptions) {
+		o.logger = logger
+	}
+}
+
+// WithSigner sets the signer of the creator, see SetSigner.
+//
+func WithSigner(creator did.Identifier, signer Signer) ClientOption {
+	return func(o *clientOptions) {
+		if o.signers == nil {
+			o.signers = make(map[did.Identifier]Signer)
+		}
+		o.signers[creator] = signer
+	}
+}
+
+// WithRetry sets the retry policy of the client, see SetRetry.
+//
+func WithRetry(policy *RetryPolicy) ClientOption {
+	return func(o *clientOptions) {
+		o.retry = policy
+	}
+}
+
+// WithEndpoints overrides the paths of the gateway endpoints, keyed by
+// their default path, e.g. for a gateway serving an endpoint under another
+// path:
+//
+//	api.WithEndpoints(map[string]string{"/v1/wallet/balance": "/wallet/v1/balance"})
+//
+// The tracers and metrics still see the default paths.
+//
+func WithEndpoints(endpoints map[string]string) ClientOption {
+	return func(o *clientOptions) {
+		if o.endpoints == nil {
+			o.endpoints = make(map[string]string, len(endpoints))
+		}
+		for path, override := range endpoints {
+			o.endpoints[path] = override
+		}
+	}
+}
+
+// newClientOptions returns the settings of the options.
+func newClientOptions(opts []ClientOption) *clientOptions {
+	o := &clientOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// clientConfig returns the configuration with the overrides of the options,
+// a copy of config if there are any.
+func (o *clientOptions) clientConfig(config *restapi.Config) *restapi.Config {
+	if o.address == "" && o.apiKey == "" && o.httpClient == nil {
+		return config
+	}
+	c := *config
+	if o.address != "" {
+		c.Address = o.address
+	}
+	if o.apiKey != "" {
+		c.ApiKey = o.apiKey
+	}
+	if o.httpClient != nil {
+		c.HttpClient = o.httpClient
+	}
+	return &c
+}
+
+// setup applies the client settings of the options to the new client.
+func (o *clientOptions) setup(w *WalletClient) {
+	if o.logger != nil {
+		w.SetLogger(o.logger)
+	}
+	for creator, signer := range o.signers {
+		w.SetSigner(creator, signer)
+	}
+	if o.retry != nil {
+		w.SetRetry(o.retry)
+	}
+	if o.tokens != nil {
+		w.SetTokenSource(o.tokens)
+	}
+	w.endpoints = o.endpoints
+}
+
+// endpoint returns the path of the endpoint with default path.
+func (w *WalletClient) endpoint(path string) string {
+	if override, ok := w.endpoints[path]; ok {
+		return override
+	}
+	return path
+}
@@ -0,0 +1,149 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	if _, err := New(WithAPIKey("key")); err == nil {
+		t.Fatalf("err should not be nil without base url")
+	}
+
+	httpClient := &http.Client{Transport: &http.Transport{}}
+	gock.InterceptClient(httpClient)
+	defer gock.Off()
+
+	signer := &fakeSigner{creator: "did:axn:issuer"}
+	logger := NewStdLogger(nil, LogError)
+	client, err := New(
+		WithBaseURL("http://127.0.0.1:8006"),
+		WithAPIKey("api-key"),
+		WithHTTPClient(httpClient),
+		WithLogger(logger),
+		WithSigner("did:axn:issuer", signer),
+		WithRetry(&RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}),
+		WithEndpoints(map[string]string{"/v1/wallet/balance": "/wallet/v1/balance"}),
+	)
+	if err != nil {
+		t.Fatalf("new wallet client fail: %v", err)
+	}
+	if client.cfg.Address != "http://127.0.0.1:8006" || client.cfg.ApiKey != "api-key" {
+		t.Fatalf("config should be set with the options")
+	}
+	if client.logger() != logger {
+		t.Fatalf("logger should be set")
+	}
+	if client.signerFor(&pki.SignatureParam{Creator: "did:axn:issuer"}) != signer {
+		t.Fatalf("signer should be set")
+	}
+	if client.retry.MaxBackoff != DefaultRetryMaxBackoff {
+		t.Fatalf("retry policy should get the defaults")
+	}
+
+	// the failed request is sent again to the overridden endpoint
+	gock.New("http://127.0.0.1:8006").
+		Get("/wallet/v1/balance").
+		Reply(503)
+	gock.New("http://127.0.0.1:8006").
+		Get("/wallet/v1/balance").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletBalance{}))
+	if _, err = client.GetWalletBalance(http.Header{}, "did:axn:001"); err != nil {
+		t.Fatalf("get wallet balance should succeed on retry: %v", err)
+	}
+}
+
+func TestRetryPOST(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+	client.SetRetry(&RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond})
+
+	var keys []string
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			keys = append(keys, req.Header.Get(IdempotencyKeyHeader))
+			return next(req)
+		}
+	})
+	body := &wallet.TransferCTokenBody{From: "did:axn:001", To: "did:axn:002"}
+
+	// retried with the same idempotency key
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/fee/estimate").
+		Reply(503)
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/fee/estimate").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.Fee{Amount: 3}))
+	if _, err := client.EstimateFee(http.Header{}, TxKindTransferCToken, body); err != nil {
+		t.Fatalf("estimate fee should succeed on retry: %v", err)
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Fatalf("retry should send the same idempotency key, got %v", keys)
+	}
+
+	// not retried without idempotency key
+	keys = nil
+	client.SetAutoIdempotencyKey(false)
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/fee/estimate").
+		Reply(503)
+	if _, err := client.EstimateFee(http.Header{}, TxKindTransferCToken, body); err == nil {
+		t.Fatalf("err should not be nil")
+	}
+	if len(keys) != 1 {
+		t.Fatalf("request without idempotency key should not be retried, sent %d times", len(keys))
+	}
+
+	// non retryable errors are returned at once
+	keys = nil
+	client.SetAutoIdempotencyKey(true)
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/fee/estimate").
+		Reply(400)
+	if _, err := client.EstimateFee(http.Header{}, TxKindTransferCToken, body); err == nil {
+		t.Fatalf("err should not be nil")
+	}
+	if len(keys) != 1 {
+		t.Fatalf("non retryable error should not be retried, sent %d times", len(keys))
+	}
+}
+
+func TestNewWalletClientConfigUnchanged(t *testing.T) {
+	config := &restapi.Config{Address: "http://127.0.0.1:8006"}
+	client, err := NewWalletClient(config)
+	if err != nil {
+		t.Fatalf("new wallet client fail: %v", err)
+	}
+	if config.HttpClient != nil {
+		t.Fatalf("the http client of the config should not be set")
+	}
+	if client.c == nil {
+		t.Fatalf("rest client should be set")
+	}
+}
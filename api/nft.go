@@ -0,0 +1,198 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// NFTItem is a unique item of a non-fungible digital asset.
+//
+type NFTItem struct {
+	// Id is unique among the items of the asset
+	Id string `json:"id"`
+	// Metadata is a JSON object describing the item, validated against the
+	// schema of the issuance if any
+	Metadata json.RawMessage `json:"metadata"`
+	// Media is the optional POE digital asset holding the media of the item,
+	// created with CreatePOE and uploaded with UploadPOEFile
+	Media did.Identifier `json:"media,omitempty"`
+	// Owner is the wallet owning the item, set by the gateway
+	Owner did.Identifier `json:"owner,omitempty"`
+}
+
+// NFTSchema is the schema of the metadata of NFT items.
+//
+type NFTSchema struct {
+	// Required are the fields every metadata must have
+	Required []string `json:"required,omitempty"`
+	// Properties are the JSON types of the fields, one of "string",
+	// "number", "boolean", "object" and "array", the fields not listed
+	// are not checked
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// Validate checks that the metadata is a JSON object matching the schema.
+//
+func (s *NFTSchema) Validate(metadata json.RawMessage) error {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(metadata, &fields); err != nil || fields == nil {
+		return fmt.Errorf("metadata must be a JSON object")
+	}
+	if s == nil {
+		return nil
+	}
+	for _, name := range s.Required {
+		if _, ok := fields[name]; !ok {
+			return fmt.Errorf("metadata field %q missing", name)
+		}
+	}
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, ok := fields[name]
+		if !ok {
+			continue
+		}
+		if typ := s.Properties[name]; jsonType(value) != typ {
+			return fmt.Errorf("metadata field %q must be of type %s", name, typ)
+		}
+	}
+	return nil
+}
+
+// jsonType returns the JSON type of a decoded JSON value.
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return "null"
+}
+
+// IssueNFTBody is the request body of IssueNFT.
+//
+// Unlike IssueAsset, the asset is issued as unique items, which are
+// transferred one by one with TransferNFT instead of by amounts.
+//
+type IssueNFTBody struct {
+	Issuer  string     `json:"issuer"`
+	Owner   string     `json:"owner"`
+	AssetId string     `json:"asset_id"`
+	Items   []*NFTItem `json:"items"`
+	// Schema is recorded with the asset, the metadata of the items issued
+	// later must match it too
+	Schema *NFTSchema  `json:"schema,omitempty"`
+	Fee    *wallet.Fee `json:"fee,omitempty"`
+}
+
+// TransferNFTBody is the request body of TransferNFT.
+//
+type TransferNFTBody struct {
+	From    string      `json:"from"`
+	To      string      `json:"to"`
+	AssetId string      `json:"asset_id"`
+	ItemIds []string    `json:"item_ids"`
+	Fee     *wallet.Fee `json:"fee,omitempty"`
+}
+
+// IssueNFT is used to issue a non-fungible digital asset, every item with
+// its own ID and metadata.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) IssueNFT(header http.Header, body *IssueNFTBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.Issuer == "" || body.Owner == "" || body.AssetId == "" || len(body.Items) == 0 {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	ids := make(map[string]bool, len(body.Items))
+	for _, item := range body.Items {
+		if item == nil || item.Id == "" {
+			err = fmt.Errorf("item id invalid")
+			return
+		}
+		if ids[item.Id] {
+			err = fmt.Errorf("item %s duplicated", item.Id)
+			return
+		}
+		ids[item.Id] = true
+		if err = body.Schema.Validate(item.Metadata); err != nil {
+			err = fmt.Errorf("item %s: %v", item.Id, err)
+			return
+		}
+	}
+	return w.processProposal(header, "/v2/transaction/nft/issue/prepare", body, signParams)
+}
+
+// TransferNFT is used to transfer items of a non-fungible digital asset.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) TransferNFT(header http.Header, body *TransferNFTBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.From == "" || body.To == "" || body.AssetId == "" || len(body.ItemIds) == 0 {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	for _, id := range body.ItemIds {
+		if id == "" {
+			err = fmt.Errorf("item id invalid")
+			return
+		}
+	}
+
+	if err = w.checkCounterpartyKYC(header, body.To); err != nil {
+		return
+	}
+	screened := w.screenAsync(screeningTypeAsset, body.From, body.To, body)
+
+	return w.processScreenedProposal(header, "/v2/transaction/nft/transfer/prepare", body, signParams, screened)
+}
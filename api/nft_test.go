@@ -0,0 +1,185 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestNFTSchemaValidate(t *testing.T) {
+	schema := &NFTSchema{
+		Required:   []string{"name"},
+		Properties: map[string]string{"name": "string", "edition": "number"},
+	}
+	cases := []struct {
+		metadata string
+		valid    bool
+	}{
+		{`{"name": "card #1", "edition": 1}`, true},
+		{`{"name": "card #1"}`, true},
+		{`{"edition": 1}`, false},
+		{`{"name": "card #1", "edition": "first"}`, false},
+		{`["name"]`, false},
+		{`null`, false},
+	}
+	for _, c := range cases {
+		err := schema.Validate(json.RawMessage(c.metadata))
+		if (err == nil) != c.valid {
+			t.Errorf("validate %s: got %v", c.metadata, err)
+		}
+	}
+
+	var noSchema *NFTSchema
+	if err := noSchema.Validate(json.RawMessage(`{"any": true}`)); err != nil {
+		t.Fatalf("any object should be valid without schema: %v", err)
+	}
+}
+
+func TestIssueNFTSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const issuer = did.Identifier("did:axn:001")
+	signer := &fakeSigner{creator: issuer}
+	client.SetSigner(issuer, signer)
+
+	script, err := json.Marshal(&pw.UTXOSignature{PublicKey: []byte("public-key")})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	preRsp := []map[string]interface{}{{
+		"founder": string(issuer),
+		"txout":   []map[string]interface{}{{"script": script}},
+	}}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/nft/issue/prepare").
+		Reply(200).
+		JSON(payloadResponse(t, preRsp))
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/process").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletResponse{Id: "nft-tx-001"}))
+
+	body := &IssueNFTBody{
+		Issuer:  string(issuer),
+		Owner:   "did:axn:002",
+		AssetId: "did:axn:asset-001",
+		Items: []*NFTItem{
+			{Id: "item-001", Metadata: json.RawMessage(`{"name": "card #1"}`)},
+			{Id: "item-002", Metadata: json.RawMessage(`{"name": "card #2"}`), Media: "did:axn:poe-002"},
+		},
+		Schema: &NFTSchema{Required: []string{"name"}},
+	}
+	resp, err := client.IssueNFT(http.Header{}, body, &pki.SignatureParam{Creator: issuer})
+	if err != nil {
+		t.Fatalf("issue nft fail: %v", err)
+	}
+	if resp.Id != "nft-tx-001" {
+		t.Fatalf("response should carry the transaction id")
+	}
+	if len(signer.signed) != 1 {
+		t.Fatalf("issuance should be signed by the issuer")
+	}
+}
+
+func TestIssueNFTInvalidBody(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	item := func(id, metadata string) *NFTItem {
+		return &NFTItem{Id: id, Metadata: json.RawMessage(metadata)}
+	}
+	bodies := map[string]*IssueNFTBody{
+		"no items":     {Issuer: "did:axn:001", Owner: "did:axn:002", AssetId: "asset-001"},
+		"no item id":   {Issuer: "did:axn:001", Owner: "did:axn:002", AssetId: "asset-001", Items: []*NFTItem{item("", `{}`)}},
+		"duplicated":   {Issuer: "did:axn:001", Owner: "did:axn:002", AssetId: "asset-001", Items: []*NFTItem{item("a", `{}`), item("a", `{}`)}},
+		"not object":   {Issuer: "did:axn:001", Owner: "did:axn:002", AssetId: "asset-001", Items: []*NFTItem{item("a", `"a"`)}},
+		"schema error": {Issuer: "did:axn:001", Owner: "did:axn:002", AssetId: "asset-001", Items: []*NFTItem{item("a", `{}`)}, Schema: &NFTSchema{Required: []string{"name"}}},
+	}
+	for name, body := range bodies {
+		if _, err := client.IssueNFT(http.Header{}, body, &pki.SignatureParam{}); err == nil {
+			t.Errorf("%s: err should not be nil", name)
+		}
+	}
+}
+
+func TestTransferNFTInvalidBody(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	body := &TransferNFTBody{From: "did:axn:001", To: "did:axn:002", AssetId: "asset-001", ItemIds: []string{""}}
+	if _, err := client.TransferNFT(http.Header{}, body, &pki.SignatureParam{}); err == nil {
+		t.Fatalf("err should not be nil with empty item id")
+	}
+	body.ItemIds = nil
+	if _, err := client.TransferNFT(http.Header{}, body, &pki.SignatureParam{}); err == nil {
+		t.Fatalf("err should not be nil without items")
+	}
+}
+
+func TestTransferNFTScreened(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const owner = did.Identifier("did:axn:001")
+	client.SetSigner(owner, &fakeSigner{creator: owner})
+	screener := &fakeScreener{}
+	client.SetScreener(screener, time.Minute, time.Second)
+
+	script, err := json.Marshal(&pw.UTXOSignature{PublicKey: []byte("public-key")})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	preRsp := []map[string]interface{}{{
+		"founder": string(owner),
+		"txout":   []map[string]interface{}{{"script": script}},
+	}}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/nft/transfer/prepare").
+		Reply(200).
+		JSON(payloadResponse(t, preRsp))
+
+	body := &TransferNFTBody{From: string(owner), To: "did:axn:002", AssetId: "asset-001", ItemIds: []string{"item-001"}}
+	_, err = client.TransferNFT(http.Header{}, body, &pki.SignatureParam{Creator: owner})
+	if _, ok := err.(*ComplianceError); !ok {
+		t.Fatalf("err should be a *ComplianceError, got %v", err)
+	}
+	if screener.calls != 1 {
+		t.Fatalf("transfer should be screened once, got %d", screener.calls)
+	}
+}
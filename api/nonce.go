@@ -0,0 +1,105 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+)
+
+// NonceFunc returns a new nonce for a signature of the creator, it must not
+// return the same nonce twice for a creator.
+//
+type NonceFunc func(creator did.Identifier) (string, error)
+
+// NonceGenerator generates the nonces of the signatures. The nonces of a
+// creator are made of a counter, never decreasing even across restarts as
+// it starts from the current time, and of random bytes, so they are unique
+// and ordered for the creator.
+//
+// It is safe for concurrent use.
+//
+type NonceGenerator struct {
+	mu   sync.Mutex
+	last map[did.Identifier]int64
+}
+
+// NewNonceGenerator returns a NonceGenerator instance.
+//
+func NewNonceGenerator() *NonceGenerator {
+	return &NonceGenerator{last: make(map[did.Identifier]int64)}
+}
+
+// Nonce returns a new nonce for the creator.
+//
+func (g *NonceGenerator) Nonce(creator did.Identifier) (string, error) {
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	g.mu.Lock()
+	counter := time.Now().UnixNano()
+	if last := g.last[creator]; counter <= last {
+		counter = last + 1
+	}
+	g.last[creator] = counter
+	g.mu.Unlock()
+
+	return fmt.Sprintf("%016x-%s", counter, hex.EncodeToString(random)), nil
+}
+
+// defaultNonces generates the nonces of the clients without NonceFunc and
+// of the KeySigners.
+var defaultNonces = NewNonceGenerator()
+
+// SetNonceFunc overrides how the client generates the nonces of the
+// signature params without nonce, e.g. to use nonces stored by the
+// application. nil restores the default NonceGenerator.
+//
+// The signature params with a nonce are used as is, the signatures made by
+// a Signer set with SetSigner carry the nonce of the signer.
+//
+func (w *WalletClient) SetNonceFunc(fn NonceFunc) {
+	w.nonces = fn
+}
+
+// withNonce returns the signature params, a copy with a new nonce when they
+// have none, so that every signature of the client has its own nonce and
+// signed payloads can not be replayed.
+func (w *WalletClient) withNonce(signParams *pki.SignatureParam) (*pki.SignatureParam, error) {
+	if signParams == nil || signParams.Nonce != "" || w.signerFor(signParams) != nil {
+		return signParams, nil
+	}
+	nonces := w.nonces
+	if nonces == nil {
+		nonces = defaultNonces.Nonce
+	}
+	nonce, err := nonces(signParams.Creator)
+	if err != nil {
+		return nil, fmt.Errorf("generate nonce error: %v", err)
+	}
+	params := *signParams
+	params.Nonce = nonce
+	return &params, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+)
+
+func TestNonceGenerator(t *testing.T) {
+	g := NewNonceGenerator()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		nonces = make(map[string]bool)
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var last string
+			for j := 0; j < 100; j++ {
+				nonce, err := g.Nonce("did:axn:001")
+				if err != nil {
+					t.Errorf("generate nonce fail: %v", err)
+					return
+				}
+				if nonce <= last {
+					t.Errorf("nonces should increase, got %s after %s", nonce, last)
+				}
+				last = nonce
+				mu.Lock()
+				nonces[nonce] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(nonces) != 800 {
+		t.Fatalf("nonces should be unique, got %d of 800", len(nonces))
+	}
+}
+
+func TestWithNonce(t *testing.T) {
+	client := &WalletClient{}
+
+	signParams := &pki.SignatureParam{Creator: "did:axn:001", PrivateKey: "key"}
+	first, err := client.withNonce(signParams)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	second, err := client.withNonce(signParams)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if first.Nonce == "" || first.Nonce == second.Nonce {
+		t.Fatalf("every signature should get a new nonce")
+	}
+	if signParams.Nonce != "" {
+		t.Fatalf("signature params of the caller should not be modified")
+	}
+
+	signParams.Nonce = "explicit"
+	if params, _ := client.withNonce(signParams); params.Nonce != "explicit" {
+		t.Fatalf("explicit nonce should be kept, got %s", params.Nonce)
+	}
+
+	client.SetNonceFunc(func(creator did.Identifier) (string, error) {
+		return "app-" + string(creator), nil
+	})
+	params, err := client.withNonce(&pki.SignatureParam{Creator: "did:axn:001", PrivateKey: "key"})
+	if err != nil || params.Nonce != "app-did:axn:001" {
+		t.Fatalf("nonce func should be used, got %+v, %v", params, err)
+	}
+}
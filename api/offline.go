@@ -0,0 +1,139 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// Kinds of transactions. Only the colored token ones can be submitted with
+// SubmitSignedTransaction, all of them can be passed to EstimateFee.
+//
+const (
+	TxKindIssueCToken    = "issue_ctoken"
+	TxKindTransferCToken = "transfer_ctoken"
+	TxKindIssueAsset     = "issue_asset"
+	TxKindTransferAsset  = "transfer_asset"
+)
+
+// signedTxPaths are the endpoints of the signed transaction kinds.
+var signedTxPaths = map[string]string{
+	TxKindIssueCToken:    "/v1/transaction/tokens/issue",
+	TxKindTransferCToken: "/v1/transaction/tokens/transfer",
+}
+
+// SignedTransaction is a transaction payload built with one of the Build*Payload
+// functions and its detached signature.
+//
+// The payload bytes are kept as built, they can be moved between machines
+// in the JSON encoding of SignedTransaction without being altered.
+//
+type SignedTransaction struct {
+	Kind      string             `json:"kind"`
+	Payload   []byte             `json:"payload"`
+	Signature *pki.SignatureBody `json:"signature"`
+}
+
+// BuildIssueCTokenPayload returns the canonical payload of the colored token
+// issuance, to be signed on another machine with SignDetached.
+//
+// It does not need a wallet client nor network access.
+//
+func BuildIssueCTokenPayload(body *wallet.IssueBody) ([]byte, error) {
+	if body == nil {
+		return nil, fmt.Errorf("request payload invalid")
+	}
+	return CanonicalJSON(body)
+}
+
+// BuildTransferPayload returns the canonical payload of the colored token
+// transfer, to be signed on another machine with SignDetached.
+//
+// It does not need a wallet client nor network access.
+//
+func BuildTransferPayload(body *wallet.TransferCTokenBody) ([]byte, error) {
+	if body == nil {
+		return nil, fmt.Errorf("request payload invalid")
+	}
+	return CanonicalJSON(body)
+}
+
+// SignDetached signs the payload with the private key of the signature
+// params, the same way the client signs its requests. It does not need a
+// wallet client nor network access.
+//
+// A new nonce is generated when the signature params have none, the params
+// of the caller are left as is.
+//
+func SignDetached(payload []byte, signParams *pki.SignatureParam) (*pki.SignatureBody, error) {
+	if len(payload) == 0 {
+		return nil, fmt.Errorf("request payload invalid")
+	}
+	if err := checkSignParams(signParams); err != nil {
+		return nil, err
+	}
+	params := *signParams
+	if params.Nonce == "" {
+		nonce, err := defaultNonces.Nonce(params.Creator)
+		if err != nil {
+			return nil, err
+		}
+		params.Nonce = nonce
+	}
+	return buildSignatureBody(&params, payload)
+}
+
+// SubmitSignedTransaction is used to submit a payload signed offline.
+//
+// The payload is sent as is, it is not marshalled again, so the signature
+// made on another machine stays valid.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+func (w *WalletClient) SubmitSignedTransaction(header http.Header, tx *SignedTransaction) (result *wallet.WalletResponse, err error) {
+	if tx == nil || len(tx.Payload) == 0 {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	path, ok := signedTxPaths[tx.Kind]
+	if !ok {
+		err = fmt.Errorf("signed transaction kind invalid: %s", tx.Kind)
+		return
+	}
+	if tx.Signature == nil || tx.Signature.Creator == "" || tx.Signature.SignatureValue == "" {
+		err = fmt.Errorf("request signature invalid")
+		return
+	}
+
+	reqBody := &wallet.WalletRequest{
+		Payload:   string(tx.Payload),
+		Signature: tx.Signature,
+	}
+
+	err = w.doJSON(header, "POST", path, nil, reqBody, &result)
+
+	return
+}
@@ -0,0 +1,157 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+// captureTransport records the last request body and replies with resp
+type captureTransport struct {
+	path string
+	body []byte
+	resp []byte
+}
+
+func (c *captureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	defer req.Body.Close()
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.path, c.body = req.URL.Path, body
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(c.resp)),
+		Request:    req,
+	}, nil
+}
+
+func TestSubmitSignedTransactionSucc(t *testing.T) {
+	// built and signed on the offline machine
+	payload, err := BuildTransferPayload(&wallet.TransferCTokenBody{
+		From: "did:axn:001",
+		To:   "did:axn:002",
+	})
+	if err != nil {
+		t.Fatalf("build transfer payload fail: %v", err)
+	}
+	sign, err := SignDetached(payload, verifySignParams)
+	if err != nil {
+		t.Fatalf("sign payload fail: %v", err)
+	}
+	data, err := json.Marshal(&SignedTransaction{Kind: TxKindTransferCToken, Payload: payload, Signature: sign})
+	if err != nil {
+		t.Fatalf("marshal signed transaction fail: %v", err)
+	}
+
+	// submitted from the online machine
+	var tx SignedTransaction
+	if err = json.Unmarshal(data, &tx); err != nil {
+		t.Fatalf("unmarshal signed transaction fail: %v", err)
+	}
+	respBody, err := json.Marshal(payloadResponse(t, &wallet.WalletResponse{Id: "tx-001"}))
+	if err != nil {
+		t.Fatalf("marshal response fail: %v", err)
+	}
+	transport := &captureTransport{resp: respBody}
+	client, err := NewWalletClient(&restapi.Config{
+		Address:    "http://127.0.0.1:8006",
+		HttpClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+	result, err := client.SubmitSignedTransaction(http.Header{}, &tx)
+	if err != nil {
+		t.Fatalf("submit signed transaction fail: %v", err)
+	}
+	if result == nil || result.Id != "tx-001" {
+		t.Fatalf("response id should be tx-001")
+	}
+	if transport.path != "/v1/transaction/tokens/transfer" {
+		t.Fatalf("transfer should be submitted to the transfer endpoint, not %s", transport.path)
+	}
+	var reqBody wallet.WalletRequest
+	if err = json.Unmarshal(transport.body, &reqBody); err != nil {
+		t.Fatalf("unmarshal request body fail: %v", err)
+	}
+	if reqBody.Payload != string(payload) {
+		t.Fatalf("payload should be sent as built, got %s", reqBody.Payload)
+	}
+	if reqBody.Signature == nil || reqBody.Signature.SignatureValue != sign.SignatureValue {
+		t.Fatalf("detached signature should be sent")
+	}
+}
+
+func TestSubmitSignedTransactionFail(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	payload, err := BuildIssueCTokenPayload(&wallet.IssueBody{Issuer: "did:axn:001"})
+	if err != nil {
+		t.Fatalf("build issue payload fail: %v", err)
+	}
+	sign, err := SignDetached(payload, verifySignParams)
+	if err != nil {
+		t.Fatalf("sign payload fail: %v", err)
+	}
+	if _, err = client.SubmitSignedTransaction(http.Header{}, &SignedTransaction{Kind: "unknown", Payload: payload, Signature: sign}); err == nil {
+		t.Fatalf("err should not be nil when the kind is unknown")
+	}
+	if _, err = client.SubmitSignedTransaction(http.Header{}, &SignedTransaction{Kind: TxKindIssueCToken, Payload: payload}); err == nil {
+		t.Fatalf("err should not be nil when the signature is missing")
+	}
+}
+
+func TestSignDetachedNonce(t *testing.T) {
+	signParams := *verifySignParams
+	signParams.Nonce = ""
+	first, err := SignDetached([]byte("payload"), &signParams)
+	if err != nil {
+		t.Fatalf("sign payload fail: %v", err)
+	}
+	second, err := SignDetached([]byte("payload"), &signParams)
+	if err != nil {
+		t.Fatalf("sign payload fail: %v", err)
+	}
+	if first.Nonce == "" || first.Nonce == second.Nonce {
+		t.Fatalf("every detached signature should get a new nonce, got %q and %q", first.Nonce, second.Nonce)
+	}
+	if signParams.Nonce != "" {
+		t.Fatalf("signature params of the caller should not be modified")
+	}
+
+	sign, err := SignDetached([]byte("payload"), verifySignParams)
+	if err != nil {
+		t.Fatalf("sign payload fail: %v", err)
+	}
+	if sign.Nonce != verifySignParams.Nonce {
+		t.Fatalf("nonce should be %s, got %s", verifySignParams.Nonce, sign.Nonce)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+)
+
+// OperationState is the state of an asynchronous operation, the same for
+// all the endpoints invoked in asynchronous mode.
+//
+type OperationState string
+
+const (
+	// OperationPending means the operation is submitted but not committed yet
+	OperationPending OperationState = "pending"
+	// OperationCommitted means the transactions of the operation are
+	// committed on chain
+	OperationCommitted OperationState = "committed"
+	// OperationInvalid means the operation has been rejected, see Reason
+	OperationInvalid OperationState = "invalid"
+)
+
+// Done reports whether the state is final.
+//
+func (s OperationState) Done() bool {
+	return s == OperationCommitted || s == OperationInvalid
+}
+
+// Operation is an operation submitted in asynchronous invoking mode, e.g. a
+// transfer, with its transactions.
+//
+type Operation struct {
+	// Id is the id of the operation, the first transaction id of the
+	// response of the asynchronous call
+	Id       string         `json:"id"`
+	WalletId did.Identifier `json:"wallet_id"`
+	// Type is the transaction type, e.g. "transfer"
+	Type           string         `json:"type"`
+	State          OperationState `json:"state"`
+	TransactionIds []string       `json:"transaction_ids"`
+	// Reason is the rejection reason of the invalid operations
+	Reason string `json:"reason,omitempty"`
+	// Submitted and Updated are unix seconds
+	Submitted int64 `json:"submitted"`
+	Updated   int64 `json:"updated"`
+}
+
+// QueryOperationStatus is used to query the state of an operation sent in
+// asynchronous invoking mode. id is the first transaction id of the
+// response of the call.
+//
+func (w *WalletClient) QueryOperationStatus(header http.Header, id string) (result *Operation, err error) {
+	if id == "" {
+		err = fmt.Errorf("operation id invalid")
+		return
+	}
+
+	err = w.doJSON(header, "GET", "/v2/operations", url.Values{"id": {id}}, nil, &result)
+
+	return
+}
+
+// ListPendingOperations is used to list the operations of a wallet which
+// are not committed or rejected yet, from the oldest to the latest.
+//
+func (w *WalletClient) ListPendingOperations(header http.Header, walletID did.Identifier) (result []*Operation, err error) {
+	if walletID == "" {
+		err = fmt.Errorf("wallet id invalid")
+		return
+	}
+
+	err = w.doJSON(header, "GET", "/v2/operations/pending", url.Values{"wallet_id": {string(walletID)}}, nil, &result)
+
+	return
+}
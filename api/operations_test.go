@@ -0,0 +1,72 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestQueryOperationStatus(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/operations$").
+		MatchParam("id", "tx-001").
+		Reply(200).
+		JSON(payloadResponse(t, &Operation{Id: "tx-001", State: OperationInvalid, Reason: "insufficient balance"}))
+
+	op, err := client.QueryOperationStatus(http.Header{}, "tx-001")
+	if err != nil {
+		t.Fatalf("query operation status fail: %v", err)
+	}
+	if op.State != OperationInvalid || !op.State.Done() || op.Reason != "insufficient balance" {
+		t.Fatalf("invalid operation should be returned, got %+v", op)
+	}
+
+	if _, err = client.QueryOperationStatus(http.Header{}, ""); err == nil {
+		t.Fatalf("err should not be nil without id")
+	}
+}
+
+func TestListPendingOperations(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/operations/pending").
+		MatchParam("wallet_id", "did:axn:wallet-001").
+		Reply(200).
+		JSON(payloadResponse(t, []*Operation{
+			{Id: "tx-001", Type: "transfer", State: OperationPending, TransactionIds: []string{"tx-001", "tx-002"}},
+		}))
+
+	ops, err := client.ListPendingOperations(http.Header{}, "did:axn:wallet-001")
+	if err != nil {
+		t.Fatalf("list pending operations fail: %v", err)
+	}
+	if len(ops) != 1 || ops[0].State.Done() || len(ops[0].TransactionIds) != 2 {
+		t.Fatalf("pending operations should be returned, got %+v", ops)
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Http headers understood by the wallet gateway.
+//
+const (
+	// AuthTokenHeader carries the user access token
+	AuthTokenHeader = "X-Auth-Token"
+	// InvokeModeHeader selects the blockchain invoking mode
+	InvokeModeHeader = "Bc-Invoke-Mode"
+	// InvokeTimeoutHeader bounds the wait in synchronous invoking mode, in seconds
+	InvokeTimeoutHeader = "Bc-Invoke-Timeout"
+	// CallbackURLHeader is the URL receiving blockchain transaction events
+	CallbackURLHeader = "Callback-Url"
+	// IdempotencyKeyHeader carries the idempotency key of a write request,
+	// the gateway uses it to drop duplicated requests
+	IdempotencyKeyHeader = "Idempotency-Key"
+)
+
+// Blockchain invoking modes.
+//
+const (
+	InvokeModeSync  = "sync"
+	InvokeModeAsync = "async"
+)
+
+// CallOption sets the http header of a call, so that callers do not have to
+// know the header names and values expected by the gateway.
+//
+type CallOption func(header http.Header)
+
+// NewHeader returns a http header with the options applied, to be passed to
+// the client methods:
+//
+//	header := api.NewHeader(api.WithAuthToken(token), api.WithSyncInvoke())
+//	resp, err := walletClient.IssueCToken(header, body, signParams)
+//
+func NewHeader(opts ...CallOption) http.Header {
+	return ApplyOptions(nil, opts...)
+}
+
+// ApplyOptions returns a copy of header with the options applied, header
+// is not modified.
+//
+func ApplyOptions(header http.Header, opts ...CallOption) http.Header {
+	h := make(http.Header, len(header)+len(opts))
+	for k, v := range header {
+		h[k] = append([]string(nil), v...)
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(h)
+		}
+	}
+	return h
+}
+
+// WithAuthToken sets the user access token.
+//
+func WithAuthToken(token string) CallOption {
+	return WithHeader(AuthTokenHeader, token)
+}
+
+// WithSyncInvoke selects the synchronous invoking mode, the call does not
+// return until the blockchain transaction is confirmed.
+//
+func WithSyncInvoke() CallOption {
+	return WithHeader(InvokeModeHeader, InvokeModeSync)
+}
+
+// WithAsyncInvoke selects the asynchronous invoking mode, the default one,
+// the call returns without waiting for blockchain transaction confirmation.
+//
+func WithAsyncInvoke() CallOption {
+	return WithHeader(InvokeModeHeader, InvokeModeAsync)
+}
+
+// WithInvokeTimeout bounds the wait for the blockchain transaction
+// confirmation in synchronous invoking mode, rounded up to the second.
+//
+func WithInvokeTimeout(timeout time.Duration) CallOption {
+	seconds := int64((timeout + time.Second - 1) / time.Second)
+	return WithHeader(InvokeTimeoutHeader, strconv.FormatInt(seconds, 10))
+}
+
+// WithCallbackURL sets the URL receiving the blockchain transaction events
+// of the call in asynchronous invoking mode, so that the calls of different
+// flows report to different receivers:
+//
+//	header := api.NewHeader(api.WithAsyncInvoke(), api.WithCallbackURL("https://settlement.example.com/events"))
+//	resp, err := walletClient.TransferAsset(header, body, signParams)
+//
+// It overrides the callback URL registered for the wallet with
+// RegisterCallback. The calls fail before being sent if url is not an
+// absolute http or https URL.
+//
+func WithCallbackURL(url string) CallOption {
+	return WithHeader(CallbackURLHeader, url)
+}
+
+// WithHeader sets a http header.
+//
+func WithHeader(key, value string) CallOption {
+	return func(header http.Header) {
+		header.Set(key, value)
+	}
+}
+
+// SetDefaultHeader sets the http header merged into the header of every call
+// of the client, e.g. its user access token. The header of a call overrides
+// the default values of the same keys. nil removes the default header.
+//
+func (w *WalletClient) SetDefaultHeader(header http.Header) {
+	if len(header) == 0 {
+		w.headers = nil
+		return
+	}
+	w.headers = ApplyOptions(header)
+}
+
+// withDefaultHeader returns the header of a call merged with the default
+// header of the client.
+func (w *WalletClient) withDefaultHeader(header http.Header) http.Header {
+	if w.headers == nil {
+		return header
+	}
+	h := ApplyOptions(w.headers)
+	for k, v := range header {
+		h[k] = append([]string(nil), v...)
+	}
+	return h
+}
@@ -0,0 +1,116 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestNewHeader(t *testing.T) {
+	header := NewHeader(
+		WithAuthToken("user-token-001"),
+		WithSyncInvoke(),
+		WithInvokeTimeout(1500*time.Millisecond),
+		WithCallbackURL("http://callback-url"),
+		nil,
+	)
+	expected := map[string]string{
+		"X-Auth-Token":      "user-token-001",
+		"Bc-Invoke-Mode":    "sync",
+		"Bc-Invoke-Timeout": "2",
+		"Callback-Url":      "http://callback-url",
+	}
+	for k, v := range expected {
+		if header.Get(k) != v {
+			t.Fatalf("header %s should be %s, got %s", k, v, header.Get(k))
+		}
+	}
+}
+
+func TestApplyOptions(t *testing.T) {
+	header := http.Header{}
+	header.Set(AuthTokenHeader, "user-token-001")
+	header.Set(InvokeModeHeader, InvokeModeSync)
+
+	h := ApplyOptions(header, WithAsyncInvoke())
+	if h.Get(InvokeModeHeader) != InvokeModeAsync || h.Get(AuthTokenHeader) != "user-token-001" {
+		t.Fatalf("options should be applied to a copy of the header, got %v", h)
+	}
+	if header.Get(InvokeModeHeader) != InvokeModeSync {
+		t.Fatalf("original header should not be modified")
+	}
+}
+
+func TestDefaultHeader(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	defaults := NewHeader(WithAuthToken("default-token"), WithHeader("X-Tenant", "tenant-001"))
+	client.SetDefaultHeader(defaults)
+	defaults.Set("X-Tenant", "changed")
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/balance").
+		MatchHeader("X-Auth-Token", "call-token").
+		MatchHeader("X-Tenant", "tenant-001").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletBalance{}))
+
+	header := NewHeader(WithAuthToken("call-token"))
+	if _, err := client.GetWalletBalance(header, "did:axn:001"); err != nil {
+		t.Fatalf("get wallet balance fail: %v", err)
+	}
+	if len(header) != 1 {
+		t.Fatalf("call header should not be modified")
+	}
+
+	client.SetDefaultHeader(nil)
+	if client.withDefaultHeader(header).Get("X-Tenant") != "" {
+		t.Fatalf("default header should be removed")
+	}
+}
+
+func TestCallbackURLOption(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/balance").
+		MatchHeader(CallbackURLHeader, "https://flow-a.example.com/events").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletBalance{}))
+
+	header := NewHeader(WithAsyncInvoke(), WithCallbackURL("https://flow-a.example.com/events"))
+	if _, err := client.GetWalletBalance(header, "did:axn:001"); err != nil {
+		t.Fatalf("get wallet balance fail: %v", err)
+	}
+
+	// rejected before being sent
+	header = NewHeader(WithCallbackURL("flow-b/events"))
+	if _, err := client.GetWalletBalance(header, "did:axn:001"); err == nil || !gock.IsDone() {
+		t.Fatalf("err should not be nil with a relative callback url, got %v", err)
+	}
+}
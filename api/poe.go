@@ -17,22 +17,17 @@ limitations under the License.
 package api
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
-	"reflect"
 
 	"strconv"
 
-	"github.com/arxanchain/sdk-go-common/errors"
-	"github.com/arxanchain/sdk-go-common/rest"
-	restapi "github.com/arxanchain/sdk-go-common/rest/api"
-	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
 	"github.com/arxanchain/sdk-go-common/structs/did"
 	"github.com/arxanchain/sdk-go-common/structs/pki"
 	"github.com/arxanchain/sdk-go-common/structs/wallet"
@@ -52,59 +47,13 @@ func (w *WalletClient) CreatePOE(header http.Header, body *wallet.POEBody, signP
 		return
 	}
 
-	if w.s != nil {
-		signParams, err = w.queryPrivateKey(header, signParams)
-		if err != nil {
-			return
-		}
-	}
-
-	// Build request signature
-	reqPayload, err := json.Marshal(body)
-	if err != nil {
-		return
-	}
-	sign, err := buildSignatureBody(signParams, reqPayload)
-	if err != nil {
-		return nil, err
-	}
-
-	// Build http request
-	r := w.c.NewRequest("POST", "/v1/poe/create")
-	r.SetHeaders(header)
-
 	// Build request body
-	reqBody := &wallet.WalletRequest{
-		Payload:   string(reqPayload),
-		Signature: sign,
-	}
-	r.SetBody(reqBody)
-
-	// Do http request
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
+	reqBody, err := w.buildWalletRequest(header, body, signParams)
 	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-
-	// Parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
-		return
-	}
-
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
-		return
-	}
-
-	payload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
-		return
+		return nil, err
 	}
 
-	err = json.Unmarshal([]byte(payload), &result)
+	err = w.doJSON(header, "POST", "/v1/poe/create", nil, reqBody, &result)
 
 	return
 }
@@ -123,208 +72,350 @@ func (w *WalletClient) UpdatePOE(header http.Header, body *wallet.POEBody, signP
 		return
 	}
 
-	if w.s != nil {
-		signParams, err = w.queryPrivateKey(header, signParams)
-		if err != nil {
-			return
-		}
-	}
-
-	// Build request signature
-	reqPayload, err := json.Marshal(body)
-	if err != nil {
-		return
-	}
-	sign, err := buildSignatureBody(signParams, reqPayload)
+	// Build request body
+	reqBody, err := w.buildWalletRequest(header, body, signParams)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build http request
-	r := w.c.NewRequest("PUT", "/v1/poe/update")
-	r.SetHeaders(header)
+	err = w.doJSON(header, "PUT", "/v1/poe/update", nil, reqBody, &result)
 
-	// Build request body
-	reqBody := &wallet.WalletRequest{
-		Payload:   string(reqPayload),
-		Signature: sign,
-	}
-	r.SetBody(reqBody)
+	return
+}
 
-	// Do http request
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
+// POEStatusRevoked is the status of the POE digital assets revoked with
+// RevokePOE, in the Status field of wallet.POEPayload.
+//
+const POEStatusRevoked pw.Status = 2
 
-	// Parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
-		return
-	}
+// RevokePOEBody is the request body of RevokePOE.
+//
+type RevokePOEBody struct {
+	// Id is the POE digital asset to revoke
+	Id did.Identifier `json:"id"`
+	// Reason is recorded with the revocation, e.g. "certificate withdrawn"
+	Reason string `json:"reason,omitempty"`
+}
 
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
+// RevokePOE is used to mark POE digital asset revoked, e.g. when the
+// certificate it proves is withdrawn. The POE is kept and can still be
+// queried, with the POEStatusRevoked status, but can no longer be updated.
+// The request must be signed by the owner of the POE.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) RevokePOE(header http.Header, body *RevokePOEBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.Id == "" {
+		err = fmt.Errorf("request payload invalid")
 		return
 	}
 
-	payload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
-		return
+	// Build request body
+	reqBody, err := w.buildWalletRequest(header, body, signParams)
+	if err != nil {
+		return nil, err
 	}
 
-	err = json.Unmarshal([]byte(payload), &result)
+	err = w.doJSON(header, "POST", "/v1/poe/revoke", nil, reqBody, &result)
 
 	return
 }
 
+// IsPOERevoked reports whether the POE digital asset has been revoked.
+//
+func IsPOERevoked(poe *wallet.POEPayload) bool {
+	return poe != nil && poe.Status == POEStatusRevoked
+}
+
 // QueryPOE is used to query POE digital asset.
 //
 func (w *WalletClient) QueryPOE(header http.Header, id did.Identifier) (result *wallet.POEPayload, err error) {
-	r := w.c.NewRequest("GET", "/v1/poe")
-	r.SetHeaders(header)
-	r.SetParam("id", string(id))
+	err = w.doJSON(header, "GET", "/v1/poe", url.Values{"id": {string(id)}}, nil, &result)
 
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
-	if err != nil {
+	return
+}
+
+// UploadPOEFile is used to upload file for specified POE digital asset
+//
+// poeID parameter is the POE digital asset ID pre-created using CreatePOE API.
+//
+// poeFile parameter is the path to file to be uploaded.
+//
+func (w *WalletClient) UploadPOEFile(header http.Header, poeID string, poeFile string, readOnly bool) (result *wallet.UploadResponse, err error) {
+	w.logger().Debugf("Call UploadPOEFile...")
+
+	if poeID == "" {
+		err = fmt.Errorf("poe id must be set when uploading poe file")
 		return
 	}
-	defer resp.Body.Close()
-
-	// parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
+	if poeFile == "" {
+		err = fmt.Errorf("poe file must be set when uploading poe file")
 		return
 	}
 
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
+	// Open the file to upload
+	srcFile, err := os.Open(poeFile)
+	if err != nil {
+		if w.privacy {
+			// the path error carries the file name
+			err = fmt.Errorf("open %s file fail", w.sensitive(poeFile))
+		}
+		w.logger().Errorf("Open %s file fail: %v", w.sensitive(poeFile), err)
 		return
 	}
 
-	respPayload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
+	defer srcFile.Close()
+
+	info, err := srcFile.Stat()
+	if err != nil {
 		return
 	}
 
-	err = json.Unmarshal([]byte(respPayload), &result)
+	w.logger().Debugf("Open %s file succ", w.sensitive(poeFile))
 
-	return
+	return w.uploadPOE(header, poeID, poeFile, srcFile, info.Size(), readOnly)
 }
 
-// UploadPOEFile is used to upload file for specified POE digital asset
+// UploadPOEFileFromReader is used to upload file contents read from r for
+// specified POE digital asset, e.g. data generated in memory or streamed
+// from another service.
 //
 // poeID parameter is the POE digital asset ID pre-created using CreatePOE API.
 //
-// poeFile parameter is the path to file to be uploaded.
+// fileName parameter is the name of the uploaded file. size parameter is the
+// number of bytes to upload, or -1 if unknown. If size is set, the upload
+// fails when r does not provide exactly size bytes.
 //
-func (w *WalletClient) UploadPOEFile(header http.Header, poeID string, poeFile string, readOnly bool) (result *wallet.UploadResponse, err error) {
-	log.Println("Call UploadPOEFile...")
-
+// r is not read anymore once the method returns, unless the client context
+// is done.
+//
+func (w *WalletClient) UploadPOEFileFromReader(header http.Header, poeID string, fileName string, size int64, r io.Reader, readOnly bool) (result *wallet.UploadResponse, err error) {
 	if poeID == "" {
 		err = fmt.Errorf("poe id must be set when uploading poe file")
 		return
 	}
-	if poeFile == "" {
-		err = fmt.Errorf("poe file must be set when uploading poe file")
+	if fileName == "" {
+		err = fmt.Errorf("file name must be set when uploading poe file")
+		return
+	}
+	if r == nil {
+		err = fmt.Errorf("reader must be set when uploading poe file")
 		return
 	}
 
-	buf := new(bytes.Buffer)
-	writer := multipart.NewWriter(buf)
+	return w.uploadPOE(header, poeID, fileName, r, size, readOnly)
+}
 
-	// Create poeID form field
-	err = writer.WriteField(wallet.OffchainPOEID, poeID)
+// uploadPOE uploads the contents of src as the POE file.
+func (w *WalletClient) uploadPOE(header http.Header, poeID string, poeFile string, src io.Reader, size int64, readOnly bool) (result *wallet.UploadResponse, err error) {
+	err = w.uploadPOEForm(header, "/v1/poe/upload", &poeForm{poeID: poeID, fileName: poeFile, readOnly: readOnly}, src, size, &result)
+	return
+}
+
+// poeForm holds the fields of a POE file upload form.
+type poeForm struct {
+	poeID    string
+	fileName string
+	readOnly bool
+	// attachment is the attachment name of the attachment uploads
+	attachment string
+	// digest hashes the file, set by writePOEForm
+	digest *uploadDigest
+}
+
+// uploadPOEForm uploads the contents of src in the POE file form to path,
+// and decodes the response payload into result.
+//
+// The file is sent with its digest, and the hash recorded by the gateway
+// in the response payload is checked against it.
+//
+// The form is streamed to the request body through a pipe, so the memory
+// used does not depend on the file size.
+func (w *WalletClient) uploadPOEForm(header http.Header, path string, form *poeForm, src io.Reader, size int64, result interface{}) (err error) {
+	poeFile := form.fileName
+	bodyReader, bodyWriter := io.Pipe()
+	writer := multipart.NewWriter(bodyWriter)
+	contentType := writer.FormDataContentType()
+	w.logger().Debugf("Content-Type: %s", contentType)
+
+	// New request
+	header = w.withDefaultHeader(header)
+	endpoint := w.endpoint(path)
+	r := w.c.NewRequest("POST", endpoint)
+	r.SetHeaders(header)
+	r.SetHeader("Content-Type", contentType)
+	r.SetBody(bodyReader)
+	trace := w.startCall(r, "POST", path, nil, header)
+	defer func() { trace.finish(err) }()
+	binding, err := w.bindResponse(r, "POST", endpoint)
 	if err != nil {
-		log.Printf("Write %s field to form fail: %v", wallet.OffchainPOEID, err)
+		bodyReader.Close()
 		return
 	}
 
-	log.Printf("Write %s field to form succ", wallet.OffchainPOEID)
+	counter := &countingReader{r: src, progress: w.upProgress, total: size}
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		bodyWriter.CloseWithError(w.writePOEForm(writer, form, counter, size))
+	}()
+	defer func() {
+		// src is no longer read once returned, unless the context is done
+		bodyReader.Close()
+		if w.ctx == nil || w.ctx.Err() == nil {
+			<-written
+			trace.uploaded(counter.n)
+		}
+	}()
 
-	// Create readOnly form field
-	err = writer.WriteField(wallet.OffchainReadOnly, strconv.FormatBool(readOnly))
+	// Do upload
+	_, resp, err := w.requireOK(w.do(r))
 	if err != nil {
-		log.Printf("Write %s field to form fail: %v", wallet.OffchainReadOnly, err)
+		w.logger().Errorf("Request to upload file fail: %v", err)
 		return
 	}
 
-	log.Printf("Write %s field to form succ", wallet.OffchainReadOnly)
+	w.logger().Debugf("Request to upload file succ")
 
-	// Create poeFile form field
-	formFile, err := writer.CreateFormFile(wallet.OffchainPOEFile, poeFile)
+	// Parse http response
+	var payload json.RawMessage
+	if err = w.decodeVerified(resp, binding, &payload); err == nil {
+		err = json.Unmarshal(payload, result)
+	}
 	if err != nil {
-		log.Printf("Create form file handler for %s fail: %v", poeFile, err)
+		w.logger().Errorf("Upload file(%s) fail: %v", w.sensitive(poeFile), err)
 		return
 	}
 
-	log.Printf("Create form file handler for %s succ", poeFile)
+	w.logger().Debugf("Parse the http response succ")
 
-	// Read data from file and Write to form
-	srcFile, err := os.Open(poeFile)
+	// Check the hash recorded by the gateway
+	var recorded struct {
+		Hash string `json:"hash"`
+	}
+	if err = json.Unmarshal(payload, &recorded); err == nil {
+		err = w.checkUploadDigest(form, written, recorded.Hash, bodyReader)
+	}
 	if err != nil {
-		log.Printf("Open %s file fail: %v", poeFile, err)
-		return
+		w.logger().Errorf("Upload file(%s) fail: %v", w.sensitive(poeFile), err)
 	}
-	defer srcFile.Close()
 
-	log.Printf("Open %s file succ", poeFile)
+	return
+}
 
-	_, err = io.Copy(formFile, srcFile)
-	if err != nil {
-		log.Printf("Write file contents to form fail: %v", err)
-		return
+// checkUploadDigest waits for the form to be written and checks its digest
+// against the hash recorded by the gateway. When the context is done, the
+// form may still be written as src is not read anymore, the upload fails
+// unless the digest is already known. The upload also fails when the
+// gateway replied before reading the whole form.
+func (w *WalletClient) checkUploadDigest(form *poeForm, written <-chan struct{}, recorded string, body io.Closer) error {
+	body.Close()
+	if w.ctx != nil && w.ctx.Err() != nil {
+		select {
+		case <-written:
+		default:
+			return w.ctx.Err()
+		}
 	}
+	<-written
+	if form.digest == nil {
+		// the form is not written when the gateway replies before reading it
+		return ErrUploadTruncated
+	}
+	return form.digest.check(recorded)
+}
 
-	log.Printf("Write file contents to form succ")
+// writePOEForm writes the upload form fields and the file contents, then
+// closes the writer to write the end of the form. If size is not negative,
+// src must provide exactly size bytes.
+func (w *WalletClient) writePOEForm(writer *multipart.Writer, form *poeForm, src io.Reader, size int64) (err error) {
+	poeFile := form.fileName
 
-	// Send form
-	contentType := writer.FormDataContentType()
-	log.Printf("Content-Type: %s", contentType)
-	// Must call Close() before http post to write EOF flag.
-	writer.Close()
+	// Create poeID form field
+	err = writer.WriteField(wallet.OffchainPOEID, form.poeID)
+	if err != nil {
+		w.logger().Errorf("Write %s field to form fail: %v", wallet.OffchainPOEID, err)
+		return
+	}
 
-	// New request
-	r := w.c.NewRequest("POST", "/v1/poe/upload")
-	r.SetHeaders(header)
-	r.SetHeader("Content-Type", contentType)
-	r.SetBody(buf.Bytes())
+	w.logger().Debugf("Write %s field to form succ", wallet.OffchainPOEID)
 
-	// Do upload
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
+	// Create readOnly form field
+	err = writer.WriteField(wallet.OffchainReadOnly, strconv.FormatBool(form.readOnly))
 	if err != nil {
-		log.Printf("Request to upload file fail: %v", err)
+		w.logger().Errorf("Write %s field to form fail: %v", wallet.OffchainReadOnly, err)
 		return
 	}
-	defer resp.Body.Close()
 
-	log.Printf("Request to upload file succ")
+	w.logger().Debugf("Write %s field to form succ", wallet.OffchainReadOnly)
 
-	// Parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
-		log.Printf("Parse the http response fail: %v", err)
+	// Create attachment name form field
+	if form.attachment != "" {
+		err = writer.WriteField(attachmentNameField, form.attachment)
+		if err != nil {
+			w.logger().Errorf("Write %s field to form fail: %v", attachmentNameField, err)
+			return
+		}
+	}
+
+	// Create poeFile form field
+	formFile, err := writer.CreateFormFile(wallet.OffchainPOEFile, poeFile)
+	if err != nil {
+		w.logger().Errorf("Create form file handler for %s fail: %v", w.sensitive(poeFile), err)
 		return
 	}
 
-	log.Printf("Parse the http response succ")
+	w.logger().Debugf("Create form file handler for %s succ", w.sensitive(poeFile))
 
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
-		log.Printf("Upload file(%s) fail: %v", poeFile, err)
+	// Read data from file and Write to form
+	if size >= 0 {
+		// read one more byte to detect a longer source
+		src = io.LimitReader(src, size+1)
+	}
+	digest := w.newUploadDigest()
+	copyBuf := getCopyBuffer()
+	n, err := io.CopyBuffer(io.MultiWriter(formFile, digest.writer()), src, *copyBuf)
+	putCopyBuffer(copyBuf)
+	if err == nil && size >= 0 && n != size {
+		err = fmt.Errorf("file size mismatch: %d bytes read, %d expected", n, size)
+	}
+	if err != nil {
+		w.logger().Errorf("Write file contents to form fail: %v", err)
 		return
 	}
 
-	respPayload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
+	w.logger().Debugf("Write file contents to form succ")
+
+	// Create digest form field, once the file is hashed
+	err = writer.WriteField(digestField, digest.value())
+	if err != nil {
+		w.logger().Errorf("Write %s field to form fail: %v", digestField, err)
 		return
 	}
+	form.digest = digest
 
-	err = json.Unmarshal([]byte(respPayload), &result)
+	// Must call Close() to write EOF flag.
+	return writer.Close()
+}
 
-	return
+// countingReader counts the bytes read from r, and reports them to
+// progress if not nil.
+type countingReader struct {
+	r        io.Reader
+	n        int64
+	progress ProgressFunc
+	total    int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	if n > 0 && c.progress != nil {
+		c.progress(c.n, c.total)
+	}
+	return n, err
 }
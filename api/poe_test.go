@@ -17,8 +17,14 @@ limitations under the License.
 package api
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"io"
 	"io/ioutil"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"os"
 	"reflect"
@@ -27,6 +33,7 @@ import (
 
 	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
 	"github.com/arxanchain/sdk-go-common/rest"
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
 	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
 	"github.com/arxanchain/sdk-go-common/structs/did"
 	"github.com/arxanchain/sdk-go-common/structs/pki"
@@ -731,3 +738,209 @@ func createFile() (string, error) {
 	}
 	return tmpfile.Name(), nil
 }
+
+// formTransport reads the uploaded multipart form and replies with resp
+type formTransport struct {
+	fields map[string]string
+	resp   []byte
+	// raw replies resp as is, without recording the file hash
+	raw bool
+}
+
+// recordedResponse returns the response, with the hash of the uploaded
+// file recorded in its payload like the gateway, unless already set.
+func (f *formTransport) recordedResponse() ([]byte, error) {
+	if f.raw {
+		return f.resp, nil
+	}
+	var respBody rtstructs.Response
+	if err := json.Unmarshal(f.resp, &respBody); err != nil {
+		return nil, err
+	}
+	payload, ok := respBody.Payload.(string)
+	if !ok {
+		return f.resp, nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(payload), &fields); err != nil {
+		return f.resp, nil
+	}
+	if hash, _ := fields["hash"].(string); hash != "" {
+		return f.resp, nil
+	}
+	sum := sha256.Sum256([]byte(f.fields[wallet.OffchainPOEFile]))
+	fields["hash"] = hex.EncodeToString(sum[:])
+	byPayload, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	respBody.Payload = string(byPayload)
+	return json.Marshal(&respBody)
+}
+
+func (f *formTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	defer req.Body.Close()
+	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	reader := multipart.NewReader(req.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := ioutil.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+		f.fields[part.FormName()] = string(data)
+	}
+	resp, err := f.recordedResponse()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(resp)),
+		Request:    req,
+	}, nil
+}
+
+func TestUploadPOEFileStream(t *testing.T) {
+	const poeID = "did:axn:poe-id-001"
+
+	// a file larger than the copy buffer
+	content := bytes.Repeat([]byte("poe file content "), 1<<16)
+	tmpfile, err := ioutil.TempFile("", "test")
+	if err != nil {
+		t.Fatalf("create tmp file fail: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err = tmpfile.Write(content); err != nil {
+		t.Fatalf("write tmp file fail: %v", err)
+	}
+	tmpfile.Close()
+
+	byPayload, err := json.Marshal(&wallet.UploadResponse{Id: poeID})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody, err := json.Marshal(&rtstructs.Response{Payload: string(byPayload)})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	transport := &formTransport{fields: map[string]string{}, resp: respBody}
+	client, err := NewWalletClient(&restapi.Config{
+		Address:    "http://127.0.0.1:8006",
+		HttpClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+
+	result, err := client.UploadPOEFile(http.Header{}, poeID, tmpfile.Name(), true)
+	if err != nil {
+		t.Fatalf("upload poe file fail: %v", err)
+	}
+	if result == nil || result.Id != poeID {
+		t.Fatalf("response POE asset id should be %v", poeID)
+	}
+	if transport.fields[wallet.OffchainPOEID] != poeID {
+		t.Fatalf("poe id field should be %v, got %v", poeID, transport.fields[wallet.OffchainPOEID])
+	}
+	if transport.fields[wallet.OffchainReadOnly] != "true" {
+		t.Fatalf("read only field should be true, got %v", transport.fields[wallet.OffchainReadOnly])
+	}
+	if transport.fields[wallet.OffchainPOEFile] != string(content) {
+		t.Fatalf("uploaded file contents mismatch, got %d bytes", len(transport.fields[wallet.OffchainPOEFile]))
+	}
+}
+
+func TestUploadPOEFileFromReader(t *testing.T) {
+	const (
+		poeID    = "did:axn:poe-id-001"
+		fileName = "contract.pdf"
+		content  = "generated poe file content"
+	)
+
+	byPayload, err := json.Marshal(&wallet.UploadResponse{Id: poeID})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody, err := json.Marshal(&rtstructs.Response{Payload: string(byPayload)})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	transport := &formTransport{fields: map[string]string{}, resp: respBody}
+	client, err := NewWalletClient(&restapi.Config{
+		Address:    "http://127.0.0.1:8006",
+		HttpClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+
+	result, err := client.UploadPOEFileFromReader(http.Header{}, poeID, fileName, int64(len(content)), strings.NewReader(content), false)
+	if err != nil {
+		t.Fatalf("upload poe file fail: %v", err)
+	}
+	if result == nil || result.Id != poeID {
+		t.Fatalf("response POE asset id should be %v", poeID)
+	}
+	if transport.fields[wallet.OffchainPOEFile] != content {
+		t.Fatalf("uploaded file contents should be %q, got %q", content, transport.fields[wallet.OffchainPOEFile])
+	}
+
+	// the reader provides more bytes than announced
+	_, err = client.UploadPOEFileFromReader(http.Header{}, poeID, fileName, 4, strings.NewReader(content), false)
+	if err == nil || !strings.Contains(err.Error(), "size mismatch") {
+		t.Fatalf("err should report the size mismatch, got %v", err)
+	}
+
+	_, err = client.UploadPOEFileFromReader(http.Header{}, poeID, fileName, -1, nil, false)
+	if err == nil {
+		t.Fatalf("err should not be nil when reader is nil")
+	}
+}
+
+func TestRevokePOE(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Post("/v1/poe/revoke").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletResponse{Id: "did:axn:poe-id-001", TransactionIds: []string{"trans-id-001"}}))
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/poe").
+		MatchParam("id", "did:axn:poe-id-001").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.POEPayload{Id: "did:axn:poe-id-001", Status: POEStatusRevoked}))
+
+	body := &RevokePOEBody{Id: "did:axn:poe-id-001", Reason: "certificate withdrawn"}
+	resp, err := client.RevokePOE(http.Header{}, body, verifySignParams)
+	if err != nil {
+		t.Fatalf("revoke poe fail: %v", err)
+	}
+	if len(resp.TransactionIds) != 1 {
+		t.Fatalf("revoke poe should return the transaction id")
+	}
+	poe, err := client.QueryPOE(http.Header{}, "did:axn:poe-id-001")
+	if err != nil {
+		t.Fatalf("query poe fail: %v", err)
+	}
+	if !IsPOERevoked(poe) || IsPOERevoked(&wallet.POEPayload{Status: pw.Status_VALID}) || IsPOERevoked(nil) {
+		t.Fatalf("revoked status should be reported")
+	}
+
+	if _, err = client.RevokePOE(http.Header{}, &RevokePOEBody{}, verifySignParams); err == nil {
+		t.Fatalf("err should not be nil without poe id")
+	}
+}
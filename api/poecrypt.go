@@ -0,0 +1,188 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/wallet-sdk-go/crypto/envelope"
+)
+
+// POEFileKey is the key encrypting POE files, see UploadPOEFileEncrypted.
+//
+type POEFileKey struct {
+	// ID identifies the key, it is recorded in clear with the file
+	ID string
+	// Key is the AES-256 key, 32 bytes
+	Key []byte
+}
+
+func (k *POEFileKey) valid() error {
+	if k == nil || len(k.Key) != envelope.KeySize {
+		return fmt.Errorf("poe file key must be %d bytes", envelope.KeySize)
+	}
+	return nil
+}
+
+// POEEncryption is the encryption metadata of a POE file.
+//
+type POEEncryption struct {
+	// Algorithm is envelope.Algorithm
+	Algorithm string `json:"algorithm"`
+	KeyID     string `json:"key_id,omitempty"`
+	// SegmentSize is the size of the segments encrypted separately
+	SegmentSize int `json:"segment_size"`
+}
+
+// EncryptedUploadResponse is the response of UploadPOEFileEncrypted.
+//
+type EncryptedUploadResponse struct {
+	*wallet.UploadResponse
+	Encryption *POEEncryption `json:"encryption"`
+}
+
+// UploadPOEFileEncrypted is used to upload file for specified POE digital
+// asset encrypted with key, so the offchain store never holds the plaintext.
+//
+// The file is encrypted while it is streamed, with AES-256-GCM under a new
+// data key sealed with key. The encryption metadata are recorded in the
+// header of the stored file and returned with the upload response. The
+// file is downloaded and decrypted with DownloadPOEFileDecrypted.
+//
+// The hash of the POE digital asset must be the sha256 hash of the
+// encrypted file, the plaintext hash is not revealed.
+//
+func (w *WalletClient) UploadPOEFileEncrypted(header http.Header, poeID string, poeFile string, readOnly bool, key *POEFileKey) (result *EncryptedUploadResponse, err error) {
+	if poeID == "" {
+		err = fmt.Errorf("poe id must be set when uploading poe file")
+		return
+	}
+	if poeFile == "" {
+		err = fmt.Errorf("poe file must be set when uploading poe file")
+		return
+	}
+	if err = key.valid(); err != nil {
+		return
+	}
+
+	srcFile, err := os.Open(poeFile)
+	if err != nil {
+		if w.privacy {
+			// the path error carries the file name
+			err = fmt.Errorf("open %s file fail", w.sensitive(poeFile))
+		}
+		w.logger().Errorf("Open %s file fail: %v", w.sensitive(poeFile), err)
+		return
+	}
+	defer srcFile.Close()
+
+	// Encrypt the file while it is uploaded
+	pr, pw := io.Pipe()
+	enc, err := envelope.NewWriter(pw, key.Key, key.ID)
+	if err != nil {
+		return
+	}
+	go func() {
+		_, err := io.Copy(enc, srcFile)
+		if err == nil {
+			err = enc.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	// the encryption stops once the upload returns
+	defer pr.Close()
+
+	resp, err := w.uploadPOE(header, poeID, poeFile, pr, -1, readOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	h := enc.Header()
+	result = &EncryptedUploadResponse{
+		UploadResponse: resp,
+		Encryption: &POEEncryption{
+			Algorithm:   h.Algorithm,
+			KeyID:       h.KeyID,
+			SegmentSize: h.SegmentSize,
+		},
+	}
+	return
+}
+
+// DownloadPOEFileDecrypted is used to download the offchain file of
+// specified POE digital asset uploaded with UploadPOEFileEncrypted, the
+// decrypted contents are streamed to out.
+//
+// The hash of the encrypted file is verified as with DownloadPOEFile, and
+// every segment of the file is authenticated while it is decrypted. The
+// POE digital asset and the encryption metadata are returned on success,
+// on error the data written to out must be discarded.
+//
+func (w *WalletClient) DownloadPOEFileDecrypted(header http.Header, poeID did.Identifier, out io.Writer, key *POEFileKey) (poe *wallet.POEPayload, enc *POEEncryption, err error) {
+	if out == nil {
+		err = fmt.Errorf("writer must be set when downloading poe file")
+		return
+	}
+	if err = key.valid(); err != nil {
+		return
+	}
+
+	// Decrypt the file while it is downloaded
+	pr, pw := io.Pipe()
+	type decrypted struct {
+		enc *POEEncryption
+		err error
+	}
+	done := make(chan decrypted, 1)
+	go func() {
+		var d decrypted
+		defer func() {
+			// a decryption error stops the download
+			pr.CloseWithError(d.err)
+			done <- d
+		}()
+		r, err := envelope.NewReader(pr, key.Key)
+		if err != nil {
+			d.err = err
+			return
+		}
+		h := r.Header()
+		if key.ID != "" && h.KeyID != "" && h.KeyID != key.ID {
+			d.err = fmt.Errorf("poe file encrypted with key %s", h.KeyID)
+			return
+		}
+		d.enc = &POEEncryption{Algorithm: h.Algorithm, KeyID: h.KeyID, SegmentSize: h.SegmentSize}
+		_, d.err = io.Copy(out, r)
+	}()
+
+	poe, err = w.DownloadPOEFile(header, poeID, pw)
+	pw.CloseWithError(err)
+	d := <-done
+	if err != nil {
+		// the decryption errors are returned by the download
+		return nil, nil, err
+	}
+	if d.err != nil {
+		return nil, nil, d.err
+	}
+	return poe, d.enc, nil
+}
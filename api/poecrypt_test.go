@@ -0,0 +1,121 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/wallet-sdk-go/crypto/envelope"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestPOEFileEncryptedRoundTrip(t *testing.T) {
+	const poeID = did.Identifier("did:axn:poe-id-001")
+	key := &POEFileKey{ID: "key-2024", Key: bytes.Repeat([]byte{1}, envelope.KeySize)}
+
+	content := bytes.Repeat([]byte("confidential contract "), 1<<13)
+	tmpfile, err := ioutil.TempFile("", "test")
+	if err != nil {
+		t.Fatalf("create tmp file fail: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err = tmpfile.Write(content); err != nil {
+		t.Fatalf("write tmp file fail: %v", err)
+	}
+	tmpfile.Close()
+
+	byPayload, err := json.Marshal(&wallet.UploadResponse{Id: poeID})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody, err := json.Marshal(&rtstructs.Response{Payload: string(byPayload)})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	transport := &formTransport{fields: map[string]string{}, resp: respBody}
+	uploader, err := NewWalletClient(&restapi.Config{
+		Address:    "http://127.0.0.1:8006",
+		HttpClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+
+	result, err := uploader.UploadPOEFileEncrypted(http.Header{}, string(poeID), tmpfile.Name(), false, key)
+	if err != nil {
+		t.Fatalf("upload encrypted poe file fail: %v", err)
+	}
+	if result.Id != poeID {
+		t.Fatalf("response POE asset id should be %v", poeID)
+	}
+	if result.Encryption == nil || result.Encryption.Algorithm != envelope.Algorithm || result.Encryption.KeyID != key.ID {
+		t.Fatalf("encryption metadata should be returned, got %+v", result.Encryption)
+	}
+	stored := transport.fields[wallet.OffchainPOEFile]
+	if strings.Contains(stored, "confidential contract") {
+		t.Fatalf("uploaded file should be encrypted")
+	}
+
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	sum := sha256.Sum256([]byte(stored))
+	mockDownload(t, poeID, hex.EncodeToString(sum[:]), stored)
+
+	var out bytes.Buffer
+	poe, enc, err := client.DownloadPOEFileDecrypted(http.Header{}, poeID, &out, key)
+	if err != nil {
+		t.Fatalf("download decrypted poe file fail: %v", err)
+	}
+	if poe == nil || poe.Id != poeID || enc == nil || enc.KeyID != key.ID {
+		t.Fatalf("POE digital asset and encryption metadata should be returned")
+	}
+	if !bytes.Equal(out.Bytes(), content) {
+		t.Fatalf("decrypted content should match the uploaded file")
+	}
+
+	// the wrong key fails
+	mockDownload(t, poeID, hex.EncodeToString(sum[:]), stored)
+	wrong := &POEFileKey{ID: key.ID, Key: bytes.Repeat([]byte{2}, envelope.KeySize)}
+	if _, _, err = client.DownloadPOEFileDecrypted(http.Header{}, poeID, ioutil.Discard, wrong); err == nil {
+		t.Fatalf("download with the wrong key should fail")
+	}
+}
+
+func TestPOEFileKeyInvalid(t *testing.T) {
+	client := &WalletClient{}
+	if _, err := client.UploadPOEFileEncrypted(http.Header{}, "did:axn:poe-id-001", "file", false, &POEFileKey{Key: []byte("short")}); err == nil {
+		t.Fatalf("short key should fail")
+	}
+	if _, _, err := client.DownloadPOEFileDecrypted(http.Header{}, "did:axn:poe-id-001", ioutil.Discard, nil); err == nil {
+		t.Fatalf("nil key should fail")
+	}
+}
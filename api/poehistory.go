@@ -0,0 +1,59 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+)
+
+// POEVersion is a version of a POE digital asset, recorded by the creation
+// or by an update of the POE.
+//
+type POEVersion struct {
+	// Version is the number of the version, 1 for the creation
+	Version  int            `json:"version"`
+	Id       did.Identifier `json:"id"`
+	Name     string         `json:"name"`
+	ParentId did.Identifier `json:"parent_id"`
+	Owner    did.Identifier `json:"owner"`
+	Hash     string         `json:"hash"`
+	Metadata []byte         `json:"metadata"`
+	Status   pw.Status      `json:"status"`
+	// Timestamp is the time the version was recorded, in unix seconds
+	Timestamp int64 `json:"timestamp"`
+	// TransactionIds are the transactions which recorded the version
+	TransactionIds []string `json:"transaction_ids"`
+}
+
+// QueryPOEHistory is used to query every version of POE digital asset, the
+// audit trail of its updates, from the oldest to the latest.
+//
+func (w *WalletClient) QueryPOEHistory(header http.Header, id did.Identifier) (result []*POEVersion, err error) {
+	if id == "" {
+		err = fmt.Errorf("request id invalid")
+		return
+	}
+
+	err = w.doJSON(header, "GET", "/v2/poe/history", url.Values{"id": {string(id)}}, nil, &result)
+
+	return
+}
@@ -0,0 +1,52 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestQueryPOEHistory(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/poe/history").
+		MatchParam("id", "did:axn:poe-001").
+		Reply(200).
+		JSON(payloadResponse(t, []*POEVersion{
+			{Version: 1, Id: "did:axn:poe-001", Metadata: []byte("v1"), Timestamp: 1500000000, TransactionIds: []string{"trans-001"}},
+			{Version: 2, Id: "did:axn:poe-001", Metadata: []byte("v2"), Timestamp: 1500000100, TransactionIds: []string{"trans-002"}},
+		}))
+
+	versions, err := client.QueryPOEHistory(http.Header{}, "did:axn:poe-001")
+	if err != nil {
+		t.Fatalf("query poe history fail: %v", err)
+	}
+	if len(versions) != 2 || string(versions[1].Metadata) != "v2" || versions[1].TransactionIds[0] != "trans-002" {
+		t.Fatalf("poe versions should be returned, got %+v", versions)
+	}
+
+	if _, err = client.QueryPOEHistory(http.Header{}, ""); err == nil {
+		t.Fatalf("err should not be nil without id")
+	}
+}
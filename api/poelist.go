@@ -0,0 +1,136 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// DefaultPOEListPageSize is the page size of the POE list queries without
+// page size.
+//
+const DefaultPOEListPageSize = 100
+
+// POEFilter selects the POE digital assets of a list query, zero values do
+// not filter.
+//
+type POEFilter struct {
+	// Name selects the POEs whose name contains it
+	Name string
+	// Hash selects the POEs of the file with that hash
+	Hash string
+	// StartTime and EndTime bound the creation time of the POEs, in unix
+	// seconds, both inclusive
+	StartTime int64
+	EndTime   int64
+}
+
+// PageRequest selects a page of a list query.
+//
+type PageRequest struct {
+	// Size is the maximum number of items of the page, a default size if
+	// zero
+	Size int32
+	// Cursor is the position to read the page from, the NextCursor of the
+	// previous page, empty for the first page
+	Cursor string
+}
+
+// setParams sets the size and cursor query parameters of the page, the
+// default size for a nil page or a page without size.
+//
+func (page *PageRequest) setParams(params url.Values, defaultSize int32) {
+	size := defaultSize
+	if page != nil {
+		if page.Size > 0 {
+			size = page.Size
+		}
+		if page.Cursor != "" {
+			params.Set("cursor", page.Cursor)
+		}
+	}
+	params.Set("size", strconv.Itoa(int(size)))
+}
+
+// POEListPage is a page of POE digital assets.
+//
+type POEListPage struct {
+	POEs []*wallet.POEPayload `json:"poes"`
+	// NextCursor is the cursor of the next page, empty on the last page
+	NextCursor string `json:"next_cursor"`
+}
+
+// QueryPOEList is used to query a page of the POE digital assets owned by a
+// wallet account, all of them are enumerated page by page following the
+// returned cursors:
+//
+//	page := &api.PageRequest{}
+//	for {
+//		poes, err := client.QueryPOEList(header, owner, filter, page)
+//		if err != nil {
+//			return err
+//		}
+//		...
+//		if poes.NextCursor == "" {
+//			break
+//		}
+//		page.Cursor = poes.NextCursor
+//	}
+//
+// filter and page can be nil for the first page of all the POEs.
+//
+func (w *WalletClient) QueryPOEList(header http.Header, owner did.Identifier, filter *POEFilter, page *PageRequest) (result *POEListPage, err error) {
+	if owner == "" {
+		err = fmt.Errorf("request owner invalid")
+		return
+	}
+
+	params := url.Values{}
+	params.Set("owner", string(owner))
+	page.setParams(params, DefaultPOEListPageSize)
+	if filter != nil {
+		if filter.StartTime < 0 || filter.EndTime < 0 || (filter.EndTime > 0 && filter.StartTime > filter.EndTime) {
+			err = fmt.Errorf("time range invalid: %d to %d", filter.StartTime, filter.EndTime)
+			return
+		}
+		if filter.Name != "" {
+			params.Set("name", filter.Name)
+		}
+		if filter.Hash != "" {
+			params.Set("hash", filter.Hash)
+		}
+		if filter.StartTime > 0 {
+			params.Set("start_time", strconv.FormatInt(filter.StartTime, 10))
+		}
+		if filter.EndTime > 0 {
+			params.Set("end_time", strconv.FormatInt(filter.EndTime, 10))
+		}
+	}
+
+	err = w.doJSON(header, "GET", "/v2/poe/list", params, nil, &result)
+	if err == nil && result == nil {
+		result = &POEListPage{}
+	}
+
+	return
+}
@@ -0,0 +1,75 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestQueryPOEList(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/poe/list").
+		MatchParam("owner", "did:axn:001").
+		MatchParam("size", "100").
+		MatchParam("name", "certificate").
+		MatchParam("start_time", "1500000000").
+		Reply(200).
+		JSON(payloadResponse(t, &POEListPage{
+			POEs:       []*wallet.POEPayload{{Id: "did:axn:poe-001", Name: "certificate-001"}},
+			NextCursor: "cursor-2",
+		}))
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/poe/list").
+		MatchParam("size", "10").
+		MatchParam("cursor", "cursor-2").
+		Reply(200).
+		JSON(payloadResponse(t, &POEListPage{
+			POEs: []*wallet.POEPayload{{Id: "did:axn:poe-002", Name: "certificate-002"}},
+		}))
+
+	filter := &POEFilter{Name: "certificate", StartTime: 1500000000}
+	page, err := client.QueryPOEList(http.Header{}, "did:axn:001", filter, nil)
+	if err != nil {
+		t.Fatalf("query poe list fail: %v", err)
+	}
+	if len(page.POEs) != 1 || page.POEs[0].Id != "did:axn:poe-001" || page.NextCursor != "cursor-2" {
+		t.Fatalf("first page should be returned, got %+v", page)
+	}
+	page, err = client.QueryPOEList(http.Header{}, "did:axn:001", nil, &PageRequest{Size: 10, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("query poe list fail: %v", err)
+	}
+	if len(page.POEs) != 1 || page.POEs[0].Id != "did:axn:poe-002" || page.NextCursor != "" {
+		t.Fatalf("last page should be returned, got %+v", page)
+	}
+
+	if _, err = client.QueryPOEList(http.Header{}, "", nil, nil); err == nil {
+		t.Fatalf("err should not be nil without owner")
+	}
+	if _, err = client.QueryPOEList(http.Header{}, "did:axn:001", &POEFilter{StartTime: 2, EndTime: 1}, nil); err == nil {
+		t.Fatalf("err should not be nil with an empty time range")
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+)
+
+// POEDownloadURL is a temporary link to the offchain file of POE digital
+// asset, generated with GeneratePOEDownloadURL.
+//
+type POEDownloadURL struct {
+	// URL downloads the file with a GET request, without credentials
+	URL string `json:"url"`
+	// Token is the access token embedded in URL, for the clients building
+	// the download request themselves
+	Token string `json:"token"`
+	// ExpiresAt is the time the link expires, in unix seconds
+	ExpiresAt int64 `json:"expires_at"`
+}
+
+// Expired reports whether the link has expired.
+//
+func (u *POEDownloadURL) Expired() bool {
+	return time.Now().Unix() >= u.ExpiresAt
+}
+
+type poeDownloadURLBody struct {
+	Id  did.Identifier `json:"id"`
+	TTL int64          `json:"ttl"`
+}
+
+// GeneratePOEDownloadURL is used to generate a pre-signed URL to download
+// the offchain file of POE digital asset, e.g. to share it with a third
+// party without giving them API credentials. The URL expires after ttl,
+// rounded up to the second, the gateway may cap it.
+//
+func (w *WalletClient) GeneratePOEDownloadURL(header http.Header, poeID did.Identifier, ttl time.Duration) (result *POEDownloadURL, err error) {
+	if poeID == "" {
+		err = fmt.Errorf("poe id invalid")
+		return
+	}
+	if ttl <= 0 {
+		err = fmt.Errorf("download url ttl must be positive")
+		return
+	}
+
+	body := &poeDownloadURLBody{
+		Id:  poeID,
+		TTL: int64((ttl + time.Second - 1) / time.Second),
+	}
+	err = w.doJSON(header, "POST", "/v2/poe/download/url", nil, body, &result)
+
+	return
+}
@@ -0,0 +1,58 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestGeneratePOEDownloadURL(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	expiresAt := time.Now().Add(15 * time.Minute).Unix()
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/poe/download/url").
+		JSON(&poeDownloadURLBody{Id: "did:axn:poe-001", TTL: 900}).
+		Reply(200).
+		JSON(payloadResponse(t, &POEDownloadURL{
+			URL:       "http://127.0.0.1:8006/v2/poe/shared?token=token-001",
+			Token:     "token-001",
+			ExpiresAt: expiresAt,
+		}))
+
+	link, err := client.GeneratePOEDownloadURL(http.Header{}, "did:axn:poe-001", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("generate poe download url fail: %v", err)
+	}
+	if link.Token != "token-001" || link.ExpiresAt != expiresAt || link.Expired() {
+		t.Fatalf("download url should be returned, got %+v", link)
+	}
+
+	if _, err = client.GeneratePOEDownloadURL(http.Header{}, "did:axn:poe-001", 0); err == nil {
+		t.Fatalf("err should not be nil without ttl")
+	}
+	if _, err = client.GeneratePOEDownloadURL(http.Header{}, "", time.Minute); err == nil {
+		t.Fatalf("err should not be nil without poe id")
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// POEVerification is the result of the verification of a file against a
+// POE digital asset, see VerifyPOE.
+//
+type POEVerification struct {
+	// POE is the POE digital asset recorded on chain
+	POE *wallet.POEPayload
+	// FileHash is the sha256 hash of the verified file, hex encoded
+	FileHash string
+	// HashMatch reports whether the file is the current file of the POE
+	HashMatch bool
+	Owner     did.Identifier
+	// Created is the creation time of the POE, in unix seconds
+	Created int64
+	Revoked bool
+	// Version is the first version of the POE recording the file hash, zero
+	// if none did, e.g. when the file does not match. RecordedAt is its time
+	// in unix seconds and TransactionIds the transactions recording it, the
+	// block reference of the proof of existence.
+	Version        int
+	RecordedAt     int64
+	TransactionIds []string
+}
+
+// Valid reports whether the file is the current file of the POE and the POE
+// is not revoked.
+//
+func (v *POEVerification) Valid() bool {
+	return v.HashMatch && !v.Revoked
+}
+
+// VerifyPOE is used to verify a file against POE digital asset: the sha256
+// hash of the file is computed locally and compared with the hash recorded
+// on chain, and the version of the POE which first recorded it is looked up
+// in its history.
+//
+// A file which does not match is not an error, the result reports it.
+//
+func (w *WalletClient) VerifyPOE(header http.Header, id did.Identifier, file io.Reader) (result *POEVerification, err error) {
+	if id == "" {
+		err = fmt.Errorf("poe id invalid")
+		return
+	}
+	if file == nil {
+		err = fmt.Errorf("file must be set when verifying poe")
+		return
+	}
+
+	h := sha256.New()
+	copyBuf := getCopyBuffer()
+	_, err = io.CopyBuffer(h, file, *copyBuf)
+	putCopyBuffer(copyBuf)
+	if err != nil {
+		return nil, err
+	}
+	fileHash := hex.EncodeToString(h.Sum(nil))
+
+	poe, err := w.QueryPOE(header, id)
+	if err != nil {
+		return nil, err
+	}
+	if poe == nil {
+		return nil, fmt.Errorf("poe %s not found", w.sensitive(string(id)))
+	}
+	result = &POEVerification{
+		POE:       poe,
+		FileHash:  fileHash,
+		HashMatch: strings.EqualFold(poe.Hash, fileHash),
+		Owner:     poe.Owner,
+		Created:   poe.Created,
+		Revoked:   IsPOERevoked(poe),
+	}
+
+	versions, err := w.QueryPOEHistory(header, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		if v != nil && strings.EqualFold(v.Hash, fileHash) {
+			result.Version = v.Version
+			result.RecordedAt = v.Timestamp
+			result.TransactionIds = v.TransactionIds
+			break
+		}
+	}
+
+	return result, nil
+}
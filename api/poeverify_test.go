@@ -0,0 +1,81 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestVerifyPOE(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	hash := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+	mock := func(current string) {
+		gock.New("http://127.0.0.1:8006").
+			Get("/v1/poe").
+			MatchParam("id", "did:axn:poe-001").
+			Reply(200).
+			JSON(payloadResponse(t, &wallet.POEPayload{Id: "did:axn:poe-001", Owner: "did:axn:001", Hash: current, Created: 1500000000}))
+		gock.New("http://127.0.0.1:8006").
+			Get("/v2/poe/history").
+			MatchParam("id", "did:axn:poe-001").
+			Reply(200).
+			JSON(payloadResponse(t, []*POEVersion{
+				{Version: 1, Hash: hash("contract v1"), Timestamp: 1500000000, TransactionIds: []string{"trans-001"}},
+				{Version: 2, Hash: hash("contract v2"), Timestamp: 1500000100, TransactionIds: []string{"trans-002"}},
+			}))
+	}
+
+	mock(strings.ToUpper(hash("contract v2")))
+	result, err := client.VerifyPOE(http.Header{}, "did:axn:poe-001", strings.NewReader("contract v2"))
+	if err != nil {
+		t.Fatalf("verify poe fail: %v", err)
+	}
+	if !result.Valid() || result.Owner != "did:axn:001" || result.Created != 1500000000 {
+		t.Fatalf("current file should be valid, got %+v", result)
+	}
+	if result.Version != 2 || result.RecordedAt != 1500000100 || result.TransactionIds[0] != "trans-002" {
+		t.Fatalf("version recording the file should be found, got %+v", result)
+	}
+
+	// a previous version of the file
+	mock(hash("contract v2"))
+	result, err = client.VerifyPOE(http.Header{}, "did:axn:poe-001", strings.NewReader("contract v1"))
+	if err != nil {
+		t.Fatalf("verify poe fail: %v", err)
+	}
+	if result.HashMatch || result.Valid() || result.Version != 1 {
+		t.Fatalf("previous file should not match but be found in history, got %+v", result)
+	}
+
+	if _, err = client.VerifyPOE(http.Header{}, "did:axn:poe-001", nil); err == nil {
+		t.Fatalf("err should not be nil without file")
+	}
+}
@@ -0,0 +1,287 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// Kinds of the prepared requests.
+const (
+	// PreparedCreatePOE is the kind of the requests of PrepareCreatePOE
+	PreparedCreatePOE = "create_poe"
+	// PreparedUpdatePOE is the kind of the requests of PrepareUpdatePOE
+	PreparedUpdatePOE = "update_poe"
+	// PreparedTxs is the kind of the signed transactions of the issue and
+	// transfer Prepare* APIs
+	PreparedTxs = "process_txs"
+)
+
+// preparedEndpoints are the method and path of the prepared request kinds,
+// SubmitPrepared only sends the prepared requests of these kinds.
+var preparedEndpoints = map[string]struct{ method, path string }{
+	PreparedCreatePOE: {"POST", "/v1/poe/create"},
+	PreparedUpdatePOE: {"PUT", "/v1/poe/update"},
+	PreparedTxs:       {"POST", "/v2/transaction/process"},
+}
+
+// PreparedRequest is a fully signed write request which has not been sent yet.
+//
+// It is produced by the Prepare* APIs and can be handed to a separate
+// broadcaster process, which submits it later through SubmitPrepared. The
+// endpoint is selected by the client from the kind of the request, it is
+// not carried by the prepared request.
+//
+// TravelRuleEnvelope is the travel-rule envelope of a transfer reaching the
+// threshold, SubmitPrepared sends it in the TravelRuleEnvelopeHeader.
+//
+type PreparedRequest struct {
+	Kind               string          `json:"kind"`
+	Body               json.RawMessage `json:"body"`
+	TokenId            string          `json:"token_id,omitempty"`
+	TravelRuleEnvelope string          `json:"travel_rule_envelope,omitempty"`
+}
+
+func newPreparedRequest(kind string, body interface{}) (*PreparedRequest, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return &PreparedRequest{
+		Kind: kind,
+		Body: data,
+	}, nil
+}
+
+func newPreparedTxs(txs []*pw.TX) (*PreparedRequest, error) {
+	return newPreparedRequest(PreparedTxs, &wallet.ProcessTxBody{Txs: txs})
+}
+
+// PrepareCreatePOE is used to build the signed request of CreatePOE without sending it.
+//
+func (w *WalletClient) PrepareCreatePOE(header http.Header, body *wallet.POEBody, signParams *pki.SignatureParam) ([]byte, error) {
+	if body == nil {
+		return nil, fmt.Errorf("request payload invalid")
+	}
+	reqBody, err := w.buildWalletRequest(header, body, signParams)
+	if err != nil {
+		return nil, err
+	}
+	prepared, err := newPreparedRequest(PreparedCreatePOE, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(prepared)
+}
+
+// PrepareUpdatePOE is used to build the signed request of UpdatePOE without sending it.
+//
+func (w *WalletClient) PrepareUpdatePOE(header http.Header, body *wallet.POEBody, signParams *pki.SignatureParam) ([]byte, error) {
+	if body == nil {
+		return nil, fmt.Errorf("request payload invalid")
+	}
+	reqBody, err := w.buildWalletRequest(header, body, signParams)
+	if err != nil {
+		return nil, err
+	}
+	prepared, err := newPreparedRequest(PreparedUpdatePOE, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(prepared)
+}
+
+// PrepareIssueCToken is used to build the signed transactions of IssueCToken without sending them.
+//
+// The issue proposal is still sent to the wallet service to get the transactions to be signed.
+//
+func (w *WalletClient) PrepareIssueCToken(header http.Header, body *wallet.IssueBody, signParams *pki.SignatureParam) ([]byte, error) {
+	if body == nil {
+		return nil, fmt.Errorf("request payload invalid")
+	}
+	signParams, err := w.prepareSignParams(header, signParams)
+	if err != nil {
+		return nil, err
+	}
+	issuePreRsp, err := w.SendIssueCTokenProposal(header, body)
+	if err != nil {
+		return nil, err
+	}
+	if err = w.SignTxs(issuePreRsp.Txs, signParams); err != nil {
+		return nil, fmt.Errorf("sign Txs error: %v", err)
+	}
+	prepared, err := newPreparedTxs(issuePreRsp.Txs)
+	if err != nil {
+		return nil, err
+	}
+	prepared.TokenId = issuePreRsp.TokenId
+	return json.Marshal(prepared)
+}
+
+// PrepareIssueAsset is used to build the signed transactions of IssueAsset without sending them.
+//
+// The issue proposal is still sent to the wallet service to get the transactions to be signed.
+//
+func (w *WalletClient) PrepareIssueAsset(header http.Header, body *wallet.IssueAssetBody, signParams *pki.SignatureParam) ([]byte, error) {
+	if body == nil {
+		return nil, fmt.Errorf("request payload invalid")
+	}
+	signParams, err := w.prepareSignParams(header, signParams)
+	if err != nil {
+		return nil, err
+	}
+	txs, err := w.SendIssueAssetProposal(header, body)
+	if err != nil {
+		return nil, err
+	}
+	return w.prepareTxs(txs, signParams)
+}
+
+// PrepareTransferCToken is used to build the signed transactions of TransferCToken without sending them.
+//
+// The transfer proposal is still sent to the wallet service to get the transactions to be signed.
+// The transfer goes through the same counterparty checks as TransferCToken before it is returned.
+//
+func (w *WalletClient) PrepareTransferCToken(header http.Header, body *wallet.TransferCTokenBody, signParams *pki.SignatureParam) ([]byte, error) {
+	if body == nil {
+		return nil, fmt.Errorf("request payload invalid")
+	}
+	if err := w.checkCounterpartyKYC(header, body.To); err != nil {
+		return nil, err
+	}
+	screened := w.screenAsync(screeningTypeCToken, body.From, body.To, body)
+	submitHeader, err := w.travelRuleHeader(header, body)
+	if err != nil {
+		return nil, err
+	}
+	signParams, err = w.prepareSignParams(header, signParams)
+	if err != nil {
+		return nil, err
+	}
+	txs, err := w.SendTransferCTokenProposal(header, body)
+	if err != nil {
+		return nil, err
+	}
+	return w.prepareScreenedTxs(submitHeader, txs, signParams, screened)
+}
+
+// PrepareTransferAsset is used to build the signed transactions of TransferAsset without sending them.
+//
+// The transfer proposal is still sent to the wallet service to get the transactions to be signed.
+// The transfer goes through the same counterparty checks as TransferAsset before it is returned.
+//
+func (w *WalletClient) PrepareTransferAsset(header http.Header, body *wallet.TransferAssetBody, signParams *pki.SignatureParam) ([]byte, error) {
+	if body == nil {
+		return nil, fmt.Errorf("request payload invalid")
+	}
+	if err := w.checkCounterpartyKYC(header, body.To); err != nil {
+		return nil, err
+	}
+	screened := w.screenAsync(screeningTypeAsset, body.From, body.To, body)
+	signParams, err := w.prepareSignParams(header, signParams)
+	if err != nil {
+		return nil, err
+	}
+	txs, err := w.SendTransferAssetProposal(header, body)
+	if err != nil {
+		return nil, err
+	}
+	return w.prepareScreenedTxs(nil, txs, signParams, screened)
+}
+
+func (w *WalletClient) prepareSignParams(header http.Header, signParams *pki.SignatureParam) (*pki.SignatureParam, error) {
+	if w.s == nil {
+		return signParams, nil
+	}
+	return w.queryPrivateKey(header, signParams)
+}
+
+func (w *WalletClient) prepareTxs(txs []*pw.TX, signParams *pki.SignatureParam) ([]byte, error) {
+	return w.prepareScreenedTxs(nil, txs, signParams, nil)
+}
+
+// prepareScreenedTxs is prepareTxs waiting for screened, if not nil, before
+// returning the signed transactions, with the travel-rule envelope of the
+// submit header.
+func (w *WalletClient) prepareScreenedTxs(submitHeader http.Header, txs []*pw.TX, signParams *pki.SignatureParam, screened func() error) ([]byte, error) {
+	if err := w.SignTxs(txs, signParams); err != nil {
+		return nil, fmt.Errorf("sign Txs error: %v", err)
+	}
+	// wait for the screening decision before handing the transactions out
+	if screened != nil {
+		if err := screened(); err != nil {
+			return nil, err
+		}
+	}
+	prepared, err := newPreparedTxs(txs)
+	if err != nil {
+		return nil, err
+	}
+	prepared.TravelRuleEnvelope = submitHeader.Get(TravelRuleEnvelopeHeader)
+	return json.Marshal(prepared)
+}
+
+// SubmitPrepared is used to submit a request built by one of the Prepare* APIs.
+// The prepared requests of an unknown kind are rejected.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+func (w *WalletClient) SubmitPrepared(header http.Header, data []byte) (result *wallet.WalletResponse, err error) {
+	var prepared PreparedRequest
+	if err = json.Unmarshal(data, &prepared); err != nil {
+		err = fmt.Errorf("prepared request invalid: %v", err)
+		return
+	}
+	endpoint, ok := preparedEndpoints[prepared.Kind]
+	if !ok {
+		err = fmt.Errorf("prepared request kind invalid: %q", prepared.Kind)
+		return
+	}
+	if len(prepared.Body) == 0 {
+		err = fmt.Errorf("prepared request invalid")
+		return
+	}
+
+	if prepared.TravelRuleEnvelope != "" {
+		h := http.Header{}
+		for k, v := range header {
+			h[k] = v
+		}
+		h.Set(TravelRuleEnvelopeHeader, prepared.TravelRuleEnvelope)
+		header = h
+	}
+
+	if err = w.doJSON(header, endpoint.method, endpoint.path, nil, prepared.Body, &result); err != nil {
+		return
+	}
+	if result != nil && prepared.TokenId != "" {
+		result.TokenId = prepared.TokenId
+	}
+
+	return
+}
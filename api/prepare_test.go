@@ -0,0 +1,218 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestPrepareCreatePOEAndSubmitSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		token   = "user-token-001"
+		poeID   = "did:axn:poe-id-001"
+		transID = "trans-id-001"
+	)
+
+	//request body & response body
+	reqBody := &wallet.POEBody{
+		Name:     "piaoju001",
+		Owner:    "did:axn:001",
+		Metadata: []byte("this is metadata"),
+	}
+	sign := &pki.SignatureParam{
+		Creator:    "did:axn:arxan-provider",
+		Nonce:      "helloalice",
+		PrivateKey: "WBZNmTTf34Kg+pQOTSIRL+JeQYDfj7InWc0A/9kvNvQSI8Ue8iRD8gn9CNmGO2EjJILF/3RELmEcbuS5G0d+Mg==",
+	}
+	payload := &wallet.WalletResponse{
+		Id:             poeID,
+		TransactionIds: []string{transID},
+	}
+	byPayload, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody := &rtstructs.Response{
+		ErrCode: 0,
+		Payload: string(byPayload),
+	}
+
+	//set http header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	//prepare without sending
+	prepared, err := client.PrepareCreatePOE(header, reqBody, sign)
+	if err != nil {
+		t.Fatalf("prepare create poe fail: %v", err)
+	}
+	var req PreparedRequest
+	if err = json.Unmarshal(prepared, &req); err != nil {
+		t.Fatalf("prepared request should be valid json: %v", err)
+	}
+	if req.Kind != PreparedCreatePOE {
+		t.Fatalf("prepared request should be of kind %s, not %s", PreparedCreatePOE, req.Kind)
+	}
+	var walletReq wallet.WalletRequest
+	if err = json.Unmarshal(req.Body, &walletReq); err != nil {
+		t.Fatalf("prepared body should be a wallet request: %v", err)
+	}
+	if walletReq.Signature == nil {
+		t.Fatalf("prepared request should be signed")
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Post("/v1/poe/create").
+		MatchHeader("X-Auth-Token", token).
+		Reply(200).
+		JSON(respBody)
+
+	//submit later
+	resp, err := client.SubmitPrepared(header, prepared)
+	if err != nil {
+		t.Fatalf("submit prepared request fail: %v", err)
+	}
+	if resp == nil || resp.Id != poeID {
+		t.Fatalf("response POE asset id should be %v", poeID)
+	}
+}
+
+func TestSubmitPreparedFail(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	resp, err := client.SubmitPrepared(http.Header{}, []byte("{}"))
+	if err == nil {
+		t.Fatalf("err should not be nil when prepared request is invalid")
+	}
+	if resp != nil {
+		t.Fatalf("response object should be nil when prepared request is invalid")
+	}
+}
+
+func TestSubmitPreparedEndpointNotTrusted(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Post("/v1/wallet/key/rotate").
+		Reply(200).
+		JSON(&rtstructs.Response{})
+
+	for _, data := range []string{
+		`{"method":"POST","path":"/v1/wallet/key/rotate","body":{}}`,
+		`{"kind":"rotate_key","method":"POST","path":"/v1/wallet/key/rotate","body":{}}`,
+	} {
+		if _, err := client.SubmitPrepared(http.Header{}, []byte(data)); err == nil {
+			t.Fatalf("prepared request %s should be rejected", data)
+		}
+	}
+	if gock.IsDone() {
+		t.Fatalf("no request should be sent to the endpoint of the prepared request")
+	}
+}
+
+func TestPrepareTransferCTokenScreened(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const owner = did.Identifier("did:axn:001")
+	client.SetSigner(owner, &fakeSigner{creator: owner})
+	screener := &fakeScreener{}
+	client.SetScreener(screener, time.Minute, time.Second)
+
+	script, err := json.Marshal(&pw.UTXOSignature{PublicKey: []byte("public-key")})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	preRsp := []map[string]interface{}{{
+		"founder": string(owner),
+		"txout":   []map[string]interface{}{{"script": script}},
+	}}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/tokens/transfer/prepare").
+		Reply(200).
+		JSON(payloadResponse(t, preRsp))
+
+	body := &wallet.TransferCTokenBody{
+		From: string(owner),
+		To:   "did:axn:002",
+		Tokens: []*wallet.TokenAmount{
+			{TokenId: "ctoken-001", Amount: 100},
+		},
+	}
+	prepared, err := client.PrepareTransferCToken(http.Header{}, body, &pki.SignatureParam{Creator: owner})
+	if _, ok := err.(*ComplianceError); !ok {
+		t.Fatalf("err should be a *ComplianceError, got %v", err)
+	}
+	if prepared != nil {
+		t.Fatalf("denied transfer should not be prepared")
+	}
+	if screener.calls != 1 {
+		t.Fatalf("transfer should be screened once, got %d", screener.calls)
+	}
+}
+
+func TestPrepareTransferAssetKYCInsufficient(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+	client.SetTransferKYCRequirement(KYCVerified)
+
+	const to = "did:axn:002"
+
+	//mock http request, the transfer proposal is not sent
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/kyc").
+		MatchParam("id", to).
+		Reply(200).
+		JSON(payloadResponse(t, &KYCStatus{Id: to, Level: KYCBasic}))
+
+	body := &wallet.TransferAssetBody{From: "did:axn:001", To: to, Assets: []string{"asset-001"}}
+	prepared, err := client.PrepareTransferAsset(http.Header{}, body, &pki.SignatureParam{})
+	if _, ok := err.(*KYCError); !ok {
+		t.Fatalf("err should be a *KYCError, got %v", err)
+	}
+	if prepared != nil {
+		t.Fatalf("transfer to an unverified wallet should not be prepared")
+	}
+}
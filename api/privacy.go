@@ -0,0 +1,74 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+)
+
+// sensitiveParams are the query params holding wallet IDs or amounts.
+var sensitiveParams = []string{"id", "owner", "spender", "counterparty", "min_amount", "max_amount"}
+
+// SetPrivacyMode makes the client keep wallet IDs, amounts and file names
+// out of its logs and error strings, they are replaced by Redact hashes.
+//
+// Request and response payloads are not affected, the typed errors keep
+// the original values in their fields for programmatic use.
+//
+func (w *WalletClient) SetPrivacyMode(enabled bool) {
+	w.privacy = enabled
+}
+
+// Redact replaces a sensitive value by a short hash of it, so occurrences of
+// the same value can still be correlated.
+//
+func Redact(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// sensitive returns the value to be logged or put in an error string.
+func (w *WalletClient) sensitive(value string) string {
+	if !w.privacy {
+		return value
+	}
+	return Redact(value)
+}
+
+// sensitiveParams returns the query params to be traced, a copy with the
+// sensitive params redacted in privacy mode.
+func (w *WalletClient) sensitiveParams(params url.Values) url.Values {
+	if !w.privacy || len(params) == 0 {
+		return params
+	}
+	redacted := make(url.Values, len(params))
+	for k, v := range params {
+		redacted[k] = v
+	}
+	for _, k := range sensitiveParams {
+		if v, ok := params[k]; ok {
+			values := make([]string, len(v))
+			for i := range v {
+				values[i] = Redact(v[i])
+			}
+			redacted[k] = values
+		}
+	}
+	return redacted
+}
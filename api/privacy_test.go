@@ -0,0 +1,226 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+// messageLogger records the formatted messages
+type messageLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *messageLogger) record(format string, args []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+
+func (l *messageLogger) Debugf(format string, args ...interface{}) { l.record(format, args) }
+func (l *messageLogger) Infof(format string, args ...interface{})  { l.record(format, args) }
+func (l *messageLogger) Warnf(format string, args ...interface{})  { l.record(format, args) }
+func (l *messageLogger) Errorf(format string, args ...interface{}) { l.record(format, args) }
+
+// leaks returns the messages containing one of the values.
+func (l *messageLogger) leaks(values ...string) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var leaks []string
+	for _, m := range l.messages {
+		for _, v := range values {
+			if strings.Contains(m, v) {
+				leaks = append(leaks, m)
+				break
+			}
+		}
+	}
+	return leaks
+}
+
+func TestPrivacyModeRedactsErrors(t *testing.T) {
+	client := &WalletClient{}
+	client.SetScreener(&fakeScreener{}, time.Minute, time.Second)
+	client.SetPrivacyMode(true)
+
+	body := &wallet.TransferCTokenBody{
+		From: "did:axn:001",
+		To:   "did:axn:002",
+		Tokens: []*wallet.TokenAmount{
+			{TokenId: "ctoken-001", Amount: 100},
+		},
+	}
+	err := client.screenAsync(screeningTypeCToken, body.From, body.To, body)()
+	if err == nil {
+		t.Fatalf("err should not be nil when transfer is denied")
+	}
+	ce, ok := err.(*ComplianceError)
+	if !ok {
+		t.Fatalf("error type should be *ComplianceError, not %T", err)
+	}
+	if strings.Contains(err.Error(), body.From) || !strings.Contains(err.Error(), Redact(body.From)) {
+		t.Fatalf("error string should only carry the redacted wallet id: %s", err)
+	}
+	if ce.Request.From != body.From {
+		t.Fatalf("error fields should keep the original wallet id")
+	}
+
+	kycErr := &KYCError{Id: "did:axn:002", Required: KYCVerified, redacted: true}
+	if strings.Contains(kycErr.Error(), "did:axn:002") {
+		t.Fatalf("error string should not carry the wallet id: %s", kycErr)
+	}
+
+	if _, err = client.UploadPOEFile(nil, "did:axn:poe-id-001", "/nonexistent/secret-contract.pdf", false); err == nil {
+		t.Fatalf("err should not be nil when poe file does not exist")
+	}
+	if strings.Contains(err.Error(), "secret-contract") {
+		t.Fatalf("error string should not carry the file name: %s", err)
+	}
+}
+
+func TestPrivacyModeEventLogs(t *testing.T) {
+	const walletID = "did:axn:wallet-001"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "id: 1\nevent: transaction.confirmed\ndata: {invalid\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client, err := NewWalletClient(&restapi.Config{Address: server.URL, HttpClient: &http.Client{}})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+	logger := &messageLogger{}
+	client.SetLogger(logger)
+	client.SetPrivacyMode(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err = client.SubscribeTransactionEvents(ctx, http.Header{}, walletID, nil); err != nil {
+		t.Fatalf("subscribe transaction events fail: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(logger.leaks(Redact(walletID))) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("the invalid event should be logged with the redacted wallet id, got %v", logger.messages)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if leaks := logger.leaks(walletID); len(leaks) != 0 {
+		t.Fatalf("the logs should not carry the wallet id: %v", leaks)
+	}
+}
+
+func TestPrivacyModeTransactionLogs(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+	logger := &messageLogger{}
+	client.SetLogger(logger)
+	client.SetPrivacyMode(true)
+
+	const id = "did:axn:001"
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/transaction/logs").
+		Reply(200).
+		JSON(&rtstructs.Response{ErrCode: 8000, ErrMessage: "wallet not found"})
+
+	if _, err := client.QueryTransactionLogs(http.Header{}, id, "in", 0, 0); err == nil {
+		t.Fatalf("err should not be nil when wallet is not found")
+	}
+	if len(logger.leaks(Redact(id))) == 0 {
+		t.Fatalf("the query should be logged with the redacted wallet id, got %v", logger.messages)
+	}
+	if leaks := logger.leaks(id); len(leaks) != 0 {
+		t.Fatalf("the logs should not carry the wallet id: %v", leaks)
+	}
+}
+
+func TestPrivacyModeOutputs(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+	logger := &messageLogger{}
+	tracer := &fakeTracer{}
+	client.SetLogger(logger)
+	client.SetTracer(tracer)
+	client.SetPrivacyMode(true)
+
+	const (
+		id    = "did:axn:001"
+		poeID = "did:axn:poe-001"
+	)
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/transaction/logs").
+		MatchParam("id", id).
+		Reply(http.StatusNotFound).
+		BodyString("wallet " + id + " not found")
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/poe").
+		MatchParam("id", poeID).
+		Reply(200).
+		JSON(payloadResponse(t, nil))
+
+	var errs []error
+	_, err := client.QueryTransactionLogs(http.Header{}, id, "in", 0, 0)
+	errs = append(errs, err)
+	_, err = client.VerifyPOE(http.Header{}, poeID, strings.NewReader("contents"))
+	errs = append(errs, err)
+	_, err = client.RotateWalletKey(http.Header{}, id, "public-key", &KeySigner{}, &pki.SignatureParam{Creator: "did:axn:002"})
+	errs = append(errs, err)
+	for i, err := range errs {
+		if err == nil {
+			t.Fatalf("call %d should fail", i)
+		}
+		if strings.Contains(err.Error(), id) || strings.Contains(err.Error(), poeID) {
+			t.Fatalf("error string should not carry the ids: %s", err)
+		}
+	}
+
+	if leaks := logger.leaks(id, poeID); len(leaks) != 0 {
+		t.Fatalf("the logs should not carry the ids: %v", leaks)
+	}
+	if len(tracer.calls) != 2 {
+		t.Fatalf("two calls should be traced, got %d", len(tracer.calls))
+	}
+	for _, call := range tracer.calls {
+		if call.WalletID() == id || call.WalletID() == poeID || call.Params.Get("id") == id || call.Params.Get("id") == poeID {
+			t.Fatalf("the traced call should not carry the ids: %#v", call)
+		}
+	}
+	if tracer.calls[0].WalletID() != Redact(id) {
+		t.Fatalf("the traced wallet id should be redacted, got %q", tracer.calls[0].WalletID())
+	}
+}
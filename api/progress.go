@@ -0,0 +1,66 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"io"
+)
+
+// ProgressFunc receives the progress of a file transfer: the number of
+// bytes transferred so far and the total number of bytes, -1 if unknown.
+//
+// It is called from the goroutine streaming the file each time data is
+// transferred, never concurrently for a transfer, and must return quickly.
+// A transfer is stalled when it is no longer called.
+//
+type ProgressFunc func(transferred, total int64)
+
+// WithUploadProgress returns a shallow copy of the client reporting the
+// progress of the POE file uploads to fn, as WithContext the configuration
+// set on the client is shared with the copy.
+//
+func (w *WalletClient) WithUploadProgress(fn ProgressFunc) *WalletClient {
+	w2 := *w
+	w2.upProgress = fn
+	return &w2
+}
+
+// WithDownloadProgress returns a shallow copy of the client reporting the
+// progress of the POE file downloads to fn.
+//
+func (w *WalletClient) WithDownloadProgress(fn ProgressFunc) *WalletClient {
+	w2 := *w
+	w2.downProgress = fn
+	return &w2
+}
+
+// progressWriter reports the bytes written to w.
+type progressWriter struct {
+	w        io.Writer
+	progress ProgressFunc
+	total    int64
+	n        int64
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	if n > 0 {
+		p.n += int64(n)
+		p.progress(p.n, p.total)
+	}
+	return n, err
+}
@@ -0,0 +1,120 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+// progressRecorder records the progress reports.
+type progressRecorder struct {
+	calls       int
+	transferred int64
+	total       int64
+}
+
+func (p *progressRecorder) report(transferred, total int64) {
+	if transferred < p.transferred {
+		panic("progress should not go backwards")
+	}
+	p.calls++
+	p.transferred, p.total = transferred, total
+}
+
+func TestUploadProgress(t *testing.T) {
+	const poeID = "did:axn:poe-id-001"
+
+	content := bytes.Repeat([]byte("poe file content "), 1<<14)
+	tmpfile, err := ioutil.TempFile("", "test")
+	if err != nil {
+		t.Fatalf("create tmp file fail: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err = tmpfile.Write(content); err != nil {
+		t.Fatalf("write tmp file fail: %v", err)
+	}
+	tmpfile.Close()
+
+	byPayload, err := json.Marshal(&wallet.UploadResponse{Id: poeID})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody, err := json.Marshal(&rtstructs.Response{Payload: string(byPayload)})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	client, err := NewWalletClient(&restapi.Config{
+		Address:    "http://127.0.0.1:8006",
+		HttpClient: &http.Client{Transport: &formTransport{fields: map[string]string{}, resp: respBody}},
+	})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+
+	progress := &progressRecorder{}
+	if _, err = client.WithUploadProgress(progress.report).UploadPOEFile(http.Header{}, poeID, tmpfile.Name(), false); err != nil {
+		t.Fatalf("upload poe file fail: %v", err)
+	}
+	if progress.calls < 2 {
+		t.Fatalf("progress should be reported while uploading, got %d calls", progress.calls)
+	}
+	if progress.transferred != int64(len(content)) || progress.total != int64(len(content)) {
+		t.Fatalf("progress should end at %d/%d, got %d/%d", len(content), len(content), progress.transferred, progress.total)
+	}
+
+	// the client itself does not report
+	progress.calls = 0
+	if _, err = client.UploadPOEFile(http.Header{}, poeID, tmpfile.Name(), false); err != nil {
+		t.Fatalf("upload poe file fail: %v", err)
+	}
+	if progress.calls != 0 {
+		t.Fatalf("progress should only be reported by the copy")
+	}
+}
+
+func TestDownloadProgress(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const poeID = did.Identifier("did:axn:poe-id-001")
+	content := string(bytes.Repeat([]byte("poe file content "), 1<<12))
+	sum := sha256.Sum256([]byte(content))
+	mockDownload(t, poeID, hex.EncodeToString(sum[:]), content)
+
+	progress := &progressRecorder{}
+	if _, err := client.WithDownloadProgress(progress.report).DownloadPOEFile(http.Header{}, poeID, ioutil.Discard); err != nil {
+		t.Fatalf("download poe file fail: %v", err)
+	}
+	if progress.calls == 0 || progress.transferred != int64(len(content)) {
+		t.Fatalf("progress should end at %d bytes, got %d", len(content), progress.transferred)
+	}
+}
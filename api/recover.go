@@ -0,0 +1,48 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned instead of crashing the process when decoding a
+// response or calling a user-supplied hook panics.
+//
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("recovered panic: %v\n%s", e.Value, e.Stack)
+}
+
+// RecoverPanic converts a panic into a *PanicError stored in err, it must be
+// called deferred by the function which err is the named result of.
+//
+//	func decode(data []byte) (err error) {
+//		defer RecoverPanic(&err)
+//		...
+//	}
+//
+func RecoverPanic(err *error) {
+	if r := recover(); r != nil {
+		*err = &PanicError{Value: r, Stack: debug.Stack()}
+	}
+}
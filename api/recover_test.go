@@ -0,0 +1,51 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+type panicScreener struct{}
+
+func (panicScreener) Screen(ctx context.Context, req *ScreeningRequest) (*ScreeningDecision, error) {
+	var decision *ScreeningDecision
+	return decision, fmt.Errorf("%v", decision.Approved)
+}
+
+func TestScreenerPanicRecovered(t *testing.T) {
+	client := &WalletClient{}
+	client.SetScreener(panicScreener{}, time.Minute, time.Second)
+
+	body := &wallet.TransferCTokenBody{From: "did:axn:001", To: "did:axn:002"}
+	err := client.screenAsync(screeningTypeCToken, body.From, body.To, body)()
+	if err == nil {
+		t.Fatalf("err should not be nil when screener panics")
+	}
+	pe, ok := err.(*PanicError)
+	if !ok {
+		t.Fatalf("error type should be *PanicError, not %T", err)
+	}
+	if len(pe.Stack) == 0 {
+		t.Fatalf("panic error should carry the stack trace")
+	}
+}
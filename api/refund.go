@@ -0,0 +1,90 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// RefundBody is the request body of Refund.
+//
+// The refund reverses (part of) the original transfer: the tokens are sent
+// back from the original payee to the original payer. A zero Amount refunds
+// the whole remaining amount of the original transaction.
+//
+type RefundBody struct {
+	OriginalTxId string `json:"original_tx_id"`
+	Amount       int64  `json:"amount,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+// Refund is a refund linked to its original transaction.
+//
+type Refund struct {
+	RefundTxIds  []string `json:"refund_tx_ids"`
+	OriginalTxId string   `json:"original_tx_id"`
+	TokenId      string   `json:"token_id"`
+	Amount       int64    `json:"amount"`
+	Reason       string   `json:"reason,omitempty"`
+	Created      int64    `json:"created"`
+}
+
+// Refund is used to refund an original colored token transfer, fully or partially.
+//
+// The reverse transfer is linked to the original transaction in the transaction
+// logs, so refunds can be queried and reconciled with QueryRefunds.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) Refund(header http.Header, body *RefundBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.OriginalTxId == "" {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	if body.Amount < 0 {
+		err = fmt.Errorf("refund amount must not be negative")
+		return
+	}
+	return w.processProposal(header, "/v2/transaction/tokens/refund/prepare", body, signParams)
+}
+
+// QueryRefunds is used to query the refunds linked to an original transaction.
+//
+func (w *WalletClient) QueryRefunds(header http.Header, originalTxID string) (result []*Refund, err error) {
+	if originalTxID == "" {
+		err = fmt.Errorf("original transaction id invalid")
+		return
+	}
+
+	if err = w.doJSON(header, "GET", "/v2/transaction/refunds", url.Values{"original_tx_id": {originalTxID}}, nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/rest"
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestRefundFailErrCode(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		token   = "user-token-001"
+		errCode = 5017
+		errMsg  = "RefundExceedsOriginalAmount"
+	)
+
+	//request body & response body
+	reqBody := &RefundBody{
+		OriginalTxId: "trans-id-001",
+		Amount:       1000,
+		Reason:       "returned goods",
+	}
+	sign := &pki.SignatureParam{
+		Creator:    "did:axn:002",
+		Nonce:      "helloalice",
+		PrivateKey: "WBZNmTTf34Kg+pQOTSIRL+JeQYDfj7InWc0A/9kvNvQSI8Ue8iRD8gn9CNmGO2EjJILF/3RELmEcbuS5G0d+Mg==",
+	}
+	respBody := &rtstructs.Response{
+		ErrCode:    errCode,
+		ErrMessage: errMsg,
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/tokens/refund/prepare").
+		MatchHeader("X-Auth-Token", token).
+		Reply(200).
+		JSON(respBody)
+
+	//set http header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	//do refund
+	resp, err := client.Refund(header, reqBody, sign)
+	if err == nil {
+		t.Fatalf("err should not be nil when refund fail")
+	}
+	errWitherrCode, ok := err.(rest.HTTPCodedError)
+	if !ok {
+		t.Fatalf("error type should be HTTPCodedError not %v", reflect.TypeOf(err))
+	}
+	if errWitherrCode.Code() != errCode {
+		t.Fatalf("Error code should be %d", errCode)
+	}
+	if resp != nil {
+		t.Fatalf("response object should be nil when refund fail")
+	}
+}
+
+func TestQueryRefundsSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		token      = "user-token-001"
+		originalID = "trans-id-001"
+		refundID   = "trans-id-002"
+	)
+
+	//response body
+	payload := []*Refund{
+		{
+			RefundTxIds:  []string{refundID},
+			OriginalTxId: originalID,
+			Amount:       100,
+		},
+	}
+	byPayload, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody := &rtstructs.Response{
+		ErrCode: 0,
+		Payload: string(byPayload),
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/transaction/refunds").
+		MatchParam("original_tx_id", originalID).
+		MatchHeader("X-Auth-Token", token).
+		Reply(200).
+		JSON(respBody)
+
+	//set http header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	//do query refunds
+	refunds, err := client.QueryRefunds(header, originalID)
+	if err != nil {
+		t.Fatalf("query refunds fail: %v", err)
+	}
+	if len(refunds) != 1 || refunds[0].RefundTxIds[0] != refundID {
+		t.Fatalf("refund transaction id should be %v", refundID)
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+)
+
+// ResponseSignatureHeader carries the signature of a gateway response,
+// base64 encoded, see ResponseSignedData.
+//
+const ResponseSignatureHeader = "Response-Signature"
+
+// RequestNonceHeader carries a random nonce of each request when the
+// response signatures are verified, the gateway signs it with the response
+// so that a signed response can not be replayed for another request.
+//
+const RequestNonceHeader = "Request-Nonce"
+
+// maxVerifiedBody is the most of a response body read to verify its signature.
+const maxVerifiedBody = 8 << 20
+
+// ErrResponseSignatureInvalid is returned when the signature of a gateway
+// response does not verify against the gateway key, the response may come
+// from a spoofed or compromised gateway.
+//
+var ErrResponseSignatureInvalid = fmt.Errorf("gateway response signature invalid")
+
+// ErrResponseSignatureMissing is returned when a gateway response is not
+// signed while the signatures are required.
+//
+var ErrResponseSignatureMissing = fmt.Errorf("gateway response signature missing")
+
+// ResponseSignedData returns the data signed by the gateway for a response:
+// the method, the path and the nonce of the request, each followed by a new
+// line, then the response body.
+//
+func ResponseSignedData(method, path, nonce string, body []byte) []byte {
+	data := make([]byte, 0, len(method)+len(path)+len(nonce)+3+len(body))
+	data = append(data, method+"\n"+path+"\n"+nonce+"\n"...)
+	return append(data, body...)
+}
+
+// ResponseVerifier verifies the signature of the signed data of a gateway
+// response, see ResponseSignedData.
+//
+type ResponseVerifier interface {
+	VerifyResponse(data, signature []byte) error
+}
+
+// ECDSAResponseVerifier verifies ASN.1 encoded ECDSA signatures of the
+// SHA-256 hash of the signed data of the responses.
+//
+type ECDSAResponseVerifier struct {
+	Key *ecdsa.PublicKey
+}
+
+// VerifyResponse implements ResponseVerifier.
+//
+func (v *ECDSAResponseVerifier) VerifyResponse(data, signature []byte) error {
+	var sig struct {
+		R, S *big.Int
+	}
+	if rest, err := asn1.Unmarshal(signature, &sig); err != nil || len(rest) > 0 {
+		return ErrResponseSignatureInvalid
+	}
+	hash := sha256.Sum256(data)
+	if v.Key == nil || sig.R == nil || sig.S == nil || !ecdsa.Verify(v.Key, hash[:], sig.R, sig.S) {
+		return ErrResponseSignatureInvalid
+	}
+	return nil
+}
+
+type responseVerification struct {
+	verifier ResponseVerifier
+	required bool
+}
+
+// SetResponseVerifier makes the client verify the signature of the gateway
+// responses, in the ResponseSignatureHeader header, with verifier, nil
+// disables it. The calls fail with ErrResponseSignatureInvalid when the
+// signature does not verify. The unsigned responses are accepted, unless
+// required is set, then they fail with ErrResponseSignatureMissing.
+//
+// The json responses are verified, the downloaded POE files are checked
+// against their hash recorded on chain instead.
+//
+func (w *WalletClient) SetResponseVerifier(verifier ResponseVerifier, required bool) {
+	if verifier == nil {
+		w.respVerify = nil
+		return
+	}
+	w.respVerify = &responseVerification{verifier: verifier, required: required}
+}
+
+// SetGatewayKey makes the client verify the ECDSA signatures of the gateway
+// responses with the gateway public key, see SetResponseVerifier.
+//
+func (w *WalletClient) SetGatewayKey(key *ecdsa.PublicKey, required bool) {
+	if key == nil {
+		w.SetResponseVerifier(nil, false)
+		return
+	}
+	w.SetResponseVerifier(&ECDSAResponseVerifier{Key: key}, required)
+}
+
+// responseBinding identifies the request a response answers.
+type responseBinding struct {
+	method string
+	path   string
+	nonce  string
+}
+
+// bindResponse sets a new nonce in the request when the responses are
+// verified, and returns the binding of its response, nil otherwise. path is
+// the path the request is sent to.
+func (w *WalletClient) bindResponse(r *restapi.Request, method, path string) (*responseBinding, error) {
+	if w.respVerify == nil {
+		return nil, nil
+	}
+	nonce := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	b := &responseBinding{method: method, path: path, nonce: hex.EncodeToString(nonce)}
+	r.SetHeader(RequestNonceHeader, b.nonce)
+	return b, nil
+}
+
+// decodeVerified verifies the signature of a successful response to the
+// request of binding, see verifyResponse, and decodes its payload into result.
+func (w *WalletClient) decodeVerified(resp *http.Response, binding *responseBinding, result interface{}) error {
+	if err := w.verifyResponse(resp, binding); err != nil {
+		resp.Body.Close()
+		return err
+	}
+	return decodeResponse(resp, result)
+}
+
+// verifyResponse verifies the signature of a successful response when the
+// verification is enabled, the body is read and replaced so that it can be
+// decoded afterwards. At most maxVerifiedBody bytes of the body are read.
+func (w *WalletClient) verifyResponse(resp *http.Response, binding *responseBinding) error {
+	rv := w.respVerify
+	if rv == nil {
+		return nil
+	}
+	if binding == nil {
+		// the verification was enabled while the request was sent
+		return ErrResponseSignatureInvalid
+	}
+	encoded := resp.Header.Get(ResponseSignatureHeader)
+	if encoded == "" {
+		if rv.required {
+			return ErrResponseSignatureMissing
+		}
+		return nil
+	}
+	signature, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrResponseSignatureInvalid
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxVerifiedBody+1))
+	if err != nil {
+		return err
+	}
+	if len(body) > maxVerifiedBody {
+		return fmt.Errorf("gateway response larger than %d bytes, can not verify its signature", maxVerifiedBody)
+	}
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return rv.verifier.VerifyResponse(ResponseSignedData(binding.method, binding.path, binding.nonce, body), signature)
+}
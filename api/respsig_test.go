@@ -0,0 +1,114 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+func signResponse(t *testing.T, key *ecdsa.PrivateKey, req *http.Request, body []byte) string {
+	hash := sha256.Sum256(ResponseSignedData(req.Method, req.URL.Path, req.Header.Get(RequestNonceHeader), body))
+	r, s, err := ecdsa.Sign(rand.Reader, key, hash[:])
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	sig, err := asn1.Marshal(struct{ R, S interface{} }{r, s})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func TestResponseSignature(t *testing.T) {
+	gatewayKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	spoofKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	var (
+		signer *ecdsa.PrivateKey
+		replay bool
+		// replayed is the signature of the previous response
+		replayed string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := json.Marshal(payloadResponse(t, &wallet.WalletInfo{Id: "did:axn:001"}))
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+		if r.Header.Get(RequestNonceHeader) == "" {
+			t.Errorf("request should carry a nonce")
+		}
+		if replay {
+			w.Header().Set(ResponseSignatureHeader, replayed)
+		} else if signer != nil {
+			replayed = signResponse(t, signer, r, body)
+			w.Header().Set(ResponseSignatureHeader, replayed)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client, err := NewWalletClient(&restapi.Config{Address: server.URL, HttpClient: &http.Client{}})
+	if err != nil {
+		t.Fatalf("New wallet client fail: %v", err)
+	}
+	client.SetGatewayKey(&gatewayKey.PublicKey, false)
+
+	signer = gatewayKey
+	info, err := client.GetWalletInfo(http.Header{}, "did:axn:001")
+	if err != nil || info.Id != "did:axn:001" {
+		t.Fatalf("signed response should be accepted, got %+v, %v", info, err)
+	}
+
+	replay = true
+	if _, err = client.GetWalletInfo(http.Header{}, "did:axn:001"); err != ErrResponseSignatureInvalid {
+		t.Fatalf("replayed response should be rejected, got %v", err)
+	}
+	replay = false
+
+	signer = spoofKey
+	if _, err = client.GetWalletInfo(http.Header{}, "did:axn:001"); err != ErrResponseSignatureInvalid {
+		t.Fatalf("err should be ErrResponseSignatureInvalid, got %v", err)
+	}
+
+	signer = nil
+	if _, err = client.GetWalletInfo(http.Header{}, "did:axn:001"); err != nil {
+		t.Fatalf("unsigned response should be accepted when not required: %v", err)
+	}
+	client.SetGatewayKey(&gatewayKey.PublicKey, true)
+	if _, err = client.GetWalletInfo(http.Header{}, "did:axn:001"); err != ErrResponseSignatureMissing {
+		t.Fatalf("err should be ErrResponseSignatureMissing, got %v", err)
+	}
+}
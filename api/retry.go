@@ -0,0 +1,236 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/errors"
+	"github.com/arxanchain/sdk-go-common/rest"
+)
+
+// ErrorClass tells how an error returned by the wallet client should be handled.
+//
+type ErrorClass int
+
+const (
+	// ErrorRetryable means the request may succeed if it is sent again later,
+	// e.g. the gateway could not be reached
+	ErrorRetryable ErrorClass = iota
+	// ErrorNonRetryable means the gateway rejected the request, sending it
+	// again will fail the same way
+	ErrorNonRetryable
+	// ErrorFatal means the client itself is misconfigured, e.g. its keys are
+	// invalid, no request can succeed until it is fixed
+	ErrorFatal
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorRetryable:
+		return "retryable"
+	case ErrorNonRetryable:
+		return "non-retryable"
+	case ErrorFatal:
+		return "fatal"
+	}
+	return "unknown"
+}
+
+var (
+	errorClassesMu sync.RWMutex
+	errorClasses   = map[int]ErrorClass{
+		int(errors.SDKInvalidBase64Data): ErrorFatal,
+	}
+)
+
+// DefaultErrorClasses returns a copy of the default classification of the
+// gateway error codes. Codes not listed are non-retryable.
+//
+func DefaultErrorClasses() map[int]ErrorClass {
+	errorClassesMu.RLock()
+	defer errorClassesMu.RUnlock()
+	classes := make(map[int]ErrorClass, len(errorClasses))
+	for code, class := range errorClasses {
+		classes[code] = class
+	}
+	return classes
+}
+
+// SetDefaultErrorClass changes the default class of a gateway error code,
+// for all clients.
+//
+func SetDefaultErrorClass(code int, class ErrorClass) {
+	errorClassesMu.Lock()
+	defer errorClassesMu.Unlock()
+	errorClasses[code] = class
+}
+
+// ClassifyError returns the class of an error with the default classification.
+//
+// Errors without a gateway error code are retryable, they are returned when
+// the gateway could not be reached or its response could not be read. So are
+// the timeout, throttling and server side http statuses.
+//
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ErrorNonRetryable
+	}
+	if e, ok := err.(*Error); ok && retryableStatus(e.StatusCode) {
+		return ErrorRetryable
+	}
+	coded, ok := err.(rest.HTTPCodedError)
+	if !ok {
+		return ErrorRetryable
+	}
+	errorClassesMu.RLock()
+	defer errorClassesMu.RUnlock()
+	if class, ok := errorClasses[int(coded.Code())]; ok {
+		return class
+	}
+	return ErrorNonRetryable
+}
+
+func retryableStatus(status int) bool {
+	return status == http.StatusRequestTimeout || status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// errorClassTable holds the per-client error class overrides, shared by the
+// copies made with WithContext
+type errorClassTable struct {
+	mu      sync.RWMutex
+	classes map[int]ErrorClass
+}
+
+// SetErrorClass overrides the class of a gateway error code for this client,
+// e.g. for custom codes returned by a deployment.
+//
+func (w *WalletClient) SetErrorClass(code int, class ErrorClass) {
+	if w.errorClasses == nil {
+		w.errorClasses = &errorClassTable{}
+	}
+	w.errorClasses.mu.Lock()
+	defer w.errorClasses.mu.Unlock()
+	if w.errorClasses.classes == nil {
+		w.errorClasses.classes = make(map[int]ErrorClass)
+	}
+	w.errorClasses.classes[code] = class
+}
+
+// ClassifyError returns the class of an error returned by this client,
+// applying the overrides set with SetErrorClass.
+//
+func (w *WalletClient) ClassifyError(err error) ErrorClass {
+	if coded, ok := err.(rest.HTTPCodedError); ok && w.errorClasses != nil {
+		w.errorClasses.mu.RLock()
+		class, ok := w.errorClasses.classes[int(coded.Code())]
+		w.errorClasses.mu.RUnlock()
+		if ok {
+			return class
+		}
+	}
+	return ClassifyError(err)
+}
+
+// Defaults of the retry policy settings.
+//
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBackoff     = 200 * time.Millisecond
+	DefaultRetryMaxBackoff  = 5 * time.Second
+)
+
+// RetryPolicy sets how the client sends again the requests failing with a
+// retryable error, zero values are replaced with the defaults.
+//
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a request is sent, including the
+	// first one
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled for each retry
+	// up to MaxBackoff, with jitter
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// SetRetry sets the retry policy of the client, nil disables the retries,
+// which is the default.
+//
+// The requests failing with an error classified retryable by ClassifyError
+// are sent again, except the POST requests without idempotency key, see
+// SetAutoIdempotencyKey: the gateway may have processed them already. The
+// retries stop when the client context is done. The requests streaming files
+// are not retried.
+//
+func (w *WalletClient) SetRetry(policy *RetryPolicy) {
+	if policy == nil {
+		w.retry = nil
+		return
+	}
+	p := *policy
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = DefaultRetryBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultRetryMaxBackoff
+	}
+	if p.MaxBackoff < p.Backoff {
+		p.MaxBackoff = p.Backoff
+	}
+	w.retry = &p
+}
+
+// shouldRetry reports whether a request which failed with err after attempts
+// can be sent again.
+func (w *WalletClient) shouldRetry(method string, header http.Header, attempts int, err error) bool {
+	if w.retry == nil || attempts >= w.retry.MaxAttempts || w.Context().Err() != nil {
+		return false
+	}
+	if method == "POST" && header.Get(IdempotencyKeyHeader) == "" {
+		return false
+	}
+	return w.ClassifyError(err) == ErrorRetryable
+}
+
+// retryWait waits before sending again a request failed after attempts,
+// giving up when the client context is done.
+func (w *WalletClient) retryWait(attempts int) error {
+	d := w.retry.Backoff
+	for i := 1; i < attempts && d < w.retry.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > w.retry.MaxBackoff {
+		d = w.retry.MaxBackoff
+	}
+	// wait between half and the whole backoff
+	d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-w.Context().Done():
+		return w.Context().Err()
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/rest"
+)
+
+func TestClassifyError(t *testing.T) {
+	const customCode = 7001
+	client := &WalletClient{}
+
+	if class := client.ClassifyError(fmt.Errorf("dial tcp: connection refused")); class != ErrorRetryable {
+		t.Fatalf("error without code should be retryable, not %v", class)
+	}
+	if class := client.ClassifyError(rest.CodedError(customCode, "GatewayBusy")); class != ErrorNonRetryable {
+		t.Fatalf("unknown error code should be non-retryable, not %v", class)
+	}
+
+	client.SetErrorClass(customCode, ErrorRetryable)
+	if class := client.ClassifyError(rest.CodedError(customCode, "GatewayBusy")); class != ErrorRetryable {
+		t.Fatalf("overridden error code should be retryable, not %v", class)
+	}
+	if class := ClassifyError(rest.CodedError(customCode, "GatewayBusy")); class != ErrorNonRetryable {
+		t.Fatalf("client override should not change the default classification, got %v", class)
+	}
+	if _, ok := DefaultErrorClasses()[customCode]; ok {
+		t.Fatalf("client override should not be listed in the default classes")
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// RotateKeyBody is the request body of RotateWalletKey.
+//
+// NewKeySignature is the signature of NewPublicKey made with the new key,
+// proving the possession of its private key, the request itself is signed
+// with the current key of the wallet.
+//
+type RotateKeyBody struct {
+	Id              did.Identifier     `json:"id"`
+	NewPublicKey    string             `json:"new_public_key"`
+	NewKeySignature *pki.SignatureBody `json:"new_key_signature"`
+}
+
+// RotateWalletKey is used to replace the key pair of a wallet. The new
+// public key (base64) is registered on chain, the rotation is signed with
+// the current key of the wallet through signParams and newSigner signs the
+// new public key to prove the possession of the new private key.
+//
+// When a signer is set for the wallet with SetSigner, it is replaced by
+// newSigner once the rotation succeeds. Keys stored in a keystore are
+// rotated with keystore.KeyStore.Rotate, which calls RotateWalletKey before
+// replacing the stored key.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) RotateWalletKey(header http.Header, id did.Identifier, newPublicKey string, newSigner Signer, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if id == "" || newPublicKey == "" || newSigner == nil {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	if signParams == nil || signParams.Creator != id {
+		err = fmt.Errorf("rotation of %s must be signed with its current key", w.sensitive(string(id)))
+		return
+	}
+
+	proof, err := newSigner.Sign([]byte(newPublicKey))
+	if err != nil {
+		return nil, err
+	}
+	if proof == nil {
+		return nil, fmt.Errorf("signer returned no signature")
+	}
+	body := &RotateKeyBody{
+		Id:              id,
+		NewPublicKey:    newPublicKey,
+		NewKeySignature: proof,
+	}
+
+	// Build request body
+	reqBody, err := w.buildWalletRequest(header, body, signParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = w.doJSON(header, "POST", "/v2/wallet/key/rotate", nil, reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	w.InvalidateKey(id)
+	if w.signerFor(&pki.SignatureParam{Creator: id}) != nil {
+		w.SetSigner(id, newSigner)
+	}
+	return result, nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestRotateWalletKeySucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const id = did.Identifier("did:axn:rotate-001")
+	oldSigner := &fakeSigner{creator: id}
+	newSigner := &fakeSigner{creator: id}
+	client.SetSigner(id, oldSigner)
+	defer client.SetSigner(id, nil)
+
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/wallet/key/rotate").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletResponse{Id: id}))
+
+	resp, err := client.RotateWalletKey(http.Header{}, id, "bmV3LXB1YmxpYy1rZXk=", newSigner, &pki.SignatureParam{Creator: id})
+	if err != nil {
+		t.Fatalf("rotate wallet key fail: %v", err)
+	}
+	if resp.Id != id {
+		t.Fatalf("response id should be %s, got %s", id, resp.Id)
+	}
+	if len(newSigner.signed) != 1 || string(newSigner.signed[0]) != "bmV3LXB1YmxpYy1rZXk=" {
+		t.Fatalf("new signer should sign the new public key, signed %q", newSigner.signed)
+	}
+	if len(oldSigner.signed) != 1 {
+		t.Fatalf("old signer should sign the request, signed %d payloads", len(oldSigner.signed))
+	}
+	var body RotateKeyBody
+	if err = json.Unmarshal(oldSigner.signed[0], &body); err != nil {
+		t.Fatalf("signed payload invalid: %v", err)
+	}
+	if body.Id != id || body.NewKeySignature == nil || body.NewKeySignature.Creator != id {
+		t.Fatalf("rotation body invalid: %+v", body)
+	}
+	if signer := client.signerFor(&pki.SignatureParam{Creator: id}); signer != newSigner {
+		t.Fatalf("signer of %s should be replaced by the new signer", id)
+	}
+}
+
+func TestRotateWalletKeyFail(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const id = did.Identifier("did:axn:rotate-002")
+	oldSigner := &fakeSigner{creator: id}
+	newSigner := &fakeSigner{creator: id}
+	client.SetSigner(id, oldSigner)
+	defer client.SetSigner(id, nil)
+
+	if _, err := client.RotateWalletKey(http.Header{}, id, "", newSigner, &pki.SignatureParam{Creator: id}); err == nil {
+		t.Fatalf("empty new public key should fail")
+	}
+	if _, err := client.RotateWalletKey(http.Header{}, id, "a2V5", newSigner, &pki.SignatureParam{Creator: "did:axn:other"}); err == nil {
+		t.Fatalf("rotation signed by another DID should fail")
+	}
+
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/wallet/key/rotate").
+		Reply(500)
+
+	if _, err := client.RotateWalletKey(http.Header{}, id, "a2V5", newSigner, &pki.SignatureParam{Creator: id}); err == nil {
+		t.Fatalf("rotate wallet key should fail")
+	}
+	if signer := client.signerFor(&pki.SignatureParam{Creator: id}); signer != oldSigner {
+		t.Fatalf("signer of %s should be kept when the rotation fails", id)
+	}
+}
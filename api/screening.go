@@ -0,0 +1,213 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// ScreeningRequest describes a transfer to be screened before submission.
+//
+type ScreeningRequest struct {
+	Type string      `json:"type"`
+	From string      `json:"from"`
+	To   string      `json:"to"`
+	Body interface{} `json:"body"`
+}
+
+// ScreeningDecision is the decision of a screening service.
+//
+// TTL is how long the decision may be reused for identical transfers,
+// zero means the client default.
+//
+type ScreeningDecision struct {
+	Approved bool
+	Reason   string
+	TTL      time.Duration
+}
+
+// Screener is implemented by external AML screening services.
+//
+// Screen is called asynchronously while the transfer is being prepared and
+// signed, the transfer is only submitted once it has been approved.
+//
+type Screener interface {
+	Screen(ctx context.Context, req *ScreeningRequest) (*ScreeningDecision, error)
+}
+
+// ComplianceError is returned when a transfer is denied by the screening service.
+//
+type ComplianceError struct {
+	Request *ScreeningRequest
+	Reason  string
+
+	redacted bool
+}
+
+func (e *ComplianceError) Error() string {
+	from, to := e.Request.From, e.Request.To
+	if e.redacted {
+		from, to = Redact(from), Redact(to)
+	}
+	return fmt.Sprintf("%s transfer from %s to %s denied by screening: %s", e.Request.Type, from, to, e.Reason)
+}
+
+const (
+	screeningTypeCToken = "ctoken"
+	screeningTypeAsset  = "asset"
+	screeningTypeHold   = "hold"
+
+	defaultScreeningTTL     = 10 * time.Minute
+	defaultScreeningTimeout = 30 * time.Second
+)
+
+type screeningEntry struct {
+	decision *ScreeningDecision
+	expires  time.Time
+}
+
+type screening struct {
+	screener Screener
+	timeout  time.Duration
+	ttl      time.Duration
+
+	mu    sync.Mutex
+	cache map[string]*screeningEntry
+}
+
+// SetScreener sets the screening service consulted before submitting
+// transfers, nil disables screening.
+//
+// ttl is the default time a decision is cached for identical transfers,
+// timeout bounds how long a screening may take.
+//
+func (w *WalletClient) SetScreener(screener Screener, ttl, timeout time.Duration) {
+	if screener == nil {
+		w.screening = nil
+		return
+	}
+	if ttl <= 0 {
+		ttl = defaultScreeningTTL
+	}
+	if timeout <= 0 {
+		timeout = defaultScreeningTimeout
+	}
+	w.screening = &screening{
+		screener: screener,
+		timeout:  timeout,
+		ttl:      ttl,
+		cache:    make(map[string]*screeningEntry),
+	}
+}
+
+// screenAsync starts screening the transfer and returns a function waiting for the result.
+func (w *WalletClient) screenAsync(typ, from, to string, body interface{}) func() error {
+	s := w.screening
+	if s == nil {
+		return func() error { return nil }
+	}
+	req := &ScreeningRequest{Type: typ, From: from, To: to, Body: body}
+	ctx := w.Context()
+	done := make(chan error, 1)
+	go func() {
+		var err error
+		defer func() {
+			done <- err
+		}()
+		// a panicking screener must not crash the process
+		defer RecoverPanic(&err)
+		err = s.screen(ctx, req)
+	}()
+	return func() error {
+		err := <-done
+		if ce, ok := err.(*ComplianceError); ok && w.privacy {
+			redacted := *ce
+			redacted.redacted = true
+			return &redacted
+		}
+		return err
+	}
+}
+
+func (s *screening) screen(ctx context.Context, req *ScreeningRequest) error {
+	key, err := screeningKey(req)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	entry, ok := s.cache[key]
+	s.mu.Unlock()
+	if !ok || now.After(entry.expires) {
+		ctx, cancel := context.WithTimeout(ctx, s.timeout)
+		decision, err := s.screener.Screen(ctx, req)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("screen transfer fail: %v", err)
+		}
+		if decision == nil {
+			return fmt.Errorf("screen transfer fail: no decision")
+		}
+		ttl := decision.TTL
+		if ttl <= 0 {
+			ttl = s.ttl
+		}
+		entry = &screeningEntry{decision: decision, expires: now.Add(ttl)}
+		s.mu.Lock()
+		s.cache[key] = entry
+		s.mu.Unlock()
+	}
+
+	if !entry.decision.Approved {
+		return &ComplianceError{Request: req, Reason: entry.decision.Reason}
+	}
+	return nil
+}
+
+// screeningKey identifies the transfers sharing a screening decision by their
+// type, parties and the tokens or assets they move, the rest of the body,
+// e.g. an expiry or an encrypted memo, changes from one transfer to the next.
+func screeningKey(req *ScreeningRequest) (string, error) {
+	var moved interface{}
+	switch body := req.Body.(type) {
+	case *wallet.TransferCTokenBody:
+		moved = body.Tokens
+	case *HoldCTokenBody:
+		moved = body.Tokens
+	case *wallet.TransferAssetBody:
+		moved = body.Assets
+	case *TransferNFTBody:
+		moved = []interface{}{body.AssetId, body.ItemIds}
+	default:
+		moved = req.Body
+	}
+	data, err := json.Marshal([]interface{}{req.Type, req.From, req.To, moved})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+type fakeScreener struct {
+	calls    int
+	approved bool
+}
+
+func (f *fakeScreener) Screen(ctx context.Context, req *ScreeningRequest) (*ScreeningDecision, error) {
+	f.calls++
+	return &ScreeningDecision{Approved: f.approved, Reason: "sanctioned counterparty"}, nil
+}
+
+func TestScreeningDeniedAndCached(t *testing.T) {
+	client := &WalletClient{}
+	screener := &fakeScreener{}
+	client.SetScreener(screener, time.Minute, time.Second)
+
+	body := &wallet.TransferCTokenBody{
+		From: "did:axn:001",
+		To:   "did:axn:002",
+		Tokens: []*wallet.TokenAmount{
+			{TokenId: "ctoken-001", Amount: 100},
+		},
+	}
+	for i := 0; i < 2; i++ {
+		err := client.screenAsync(screeningTypeCToken, body.From, body.To, body)()
+		if err == nil {
+			t.Fatalf("err should not be nil when transfer is denied")
+		}
+		if _, ok := err.(*ComplianceError); !ok {
+			t.Fatalf("error type should be *ComplianceError, not %T", err)
+		}
+	}
+	if screener.calls != 1 {
+		t.Fatalf("screening decision should be cached, screener called %d times", screener.calls)
+	}
+
+	// another transfer is screened again
+	body.Tokens[0].Amount = 200
+	screener.approved = true
+	if err := client.screenAsync(screeningTypeCToken, body.From, body.To, body)(); err != nil {
+		t.Fatalf("approved transfer should pass screening: %v", err)
+	}
+	if screener.calls != 2 {
+		t.Fatalf("different transfer should be screened, screener called %d times", screener.calls)
+	}
+}
+
+func TestScreeningCachedAcrossMemos(t *testing.T) {
+	client := &WalletClient{}
+	screener := &fakeScreener{approved: true}
+	client.SetScreener(screener, time.Minute, time.Second)
+
+	tokens := []*wallet.TokenAmount{{TokenId: "ctoken-001", Amount: 100}}
+	for i, memo := range []string{"c2VhbGVkLTE=", "c2VhbGVkLTI="} {
+		body := &HoldCTokenBody{
+			From:    "did:axn:001",
+			To:      "did:axn:002",
+			Tokens:  tokens,
+			Memo:    memo,
+			Expires: time.Now().Add(time.Duration(i+1) * time.Hour).Unix(),
+		}
+		if err := client.screenAsync(screeningTypeHold, body.From, body.To, body)(); err != nil {
+			t.Fatalf("approved transfer should pass screening: %v", err)
+		}
+	}
+	if screener.calls != 1 {
+		t.Fatalf("holds differing by memo and expiry should share a decision, screener called %d times", screener.calls)
+	}
+
+	// another receiver is screened again
+	body := &HoldCTokenBody{From: "did:axn:001", To: "did:axn:003", Tokens: tokens}
+	if err := client.screenAsync(screeningTypeHold, body.From, body.To, body)(); err != nil {
+		t.Fatalf("approved transfer should pass screening: %v", err)
+	}
+	if screener.calls != 2 {
+		t.Fatalf("transfer to another receiver should be screened, screener called %d times", screener.calls)
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/arxanchain/sdk-go-common/errors"
+	"github.com/arxanchain/sdk-go-common/utils"
+)
+
+// ErrMlockUnsupported is returned by SecretBytes.Lock on the platforms
+// without mlock.
+//
+var ErrMlockUnsupported = fmt.Errorf("locking memory not supported on this platform")
+
+// SecretBytes holds key material, so that it can be wiped from memory once
+// it is no longer needed instead of lingering until the garbage collector
+// reuses it, and optionally be locked in memory so that it is never
+// swapped to disk.
+//
+// The private keys of the signature params are decoded into SecretBytes
+// for the time of a signature only, KeySigner keeps its key in one.
+//
+type SecretBytes struct {
+	mu     sync.Mutex
+	b      []byte
+	locked bool
+}
+
+// NewSecretBytes returns SecretBytes holding b, without copying it: b is
+// zeroed along with the SecretBytes.
+//
+func NewSecretBytes(b []byte) *SecretBytes {
+	return &SecretBytes{b: b}
+}
+
+// SecretFromBase64 decodes a base64 key, such as the private key of
+// signature params, into SecretBytes.
+//
+func SecretFromBase64(s string) (*SecretBytes, error) {
+	b, err := utils.DecodeBase64(s)
+	if err != nil {
+		return nil, &Error{ErrCode: errors.SDKInvalidBase64Data, Message: err.Error()}
+	}
+	return NewSecretBytes(b), nil
+}
+
+// Bytes returns the secret, without copying it, nil once zeroed. The
+// returned slice must not be kept after the SecretBytes is zeroed.
+//
+func (s *SecretBytes) Bytes() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b
+}
+
+// Len returns the length of the secret, 0 once zeroed.
+//
+func (s *SecretBytes) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.b)
+}
+
+// Lock locks the memory of the secret, so that it is never swapped to
+// disk, until it is zeroed. ErrMlockUnsupported is returned on the
+// platforms without mlock, locking may also fail over the memory lock
+// limit of the process (RLIMIT_MEMLOCK).
+//
+func (s *SecretBytes) Lock() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locked || len(s.b) == 0 {
+		return nil
+	}
+	if err := mlock(s.b); err != nil {
+		return err
+	}
+	s.locked = true
+	return nil
+}
+
+// Zero overwrites the secret with zeros and releases it, it is safe to call
+// more than once.
+//
+func (s *SecretBytes) Zero() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	zeroBytes(s.b)
+	if s.locked {
+		munlock(s.b)
+		s.locked = false
+	}
+	s.b = nil
+}
+
+// String hides the secret when it is printed.
+//
+func (s *SecretBytes) String() string {
+	return "[secret]"
+}
+
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSecretBytes(t *testing.T) {
+	b := []byte("private-key")
+	s := NewSecretBytes(b)
+	if string(s.Bytes()) != "private-key" || s.Len() != len(b) {
+		t.Fatalf("secret should hold the key")
+	}
+	if fmt.Sprintf("%v %s", s, s) != "[secret] [secret]" {
+		t.Fatalf("secret should not be printed")
+	}
+	if err := s.Lock(); err != nil {
+		// locking may be refused over RLIMIT_MEMLOCK
+		t.Logf("lock secret: %v", err)
+	}
+
+	s.Zero()
+	for _, c := range b {
+		if c != 0 {
+			t.Fatalf("secret should be zeroed in place: %q", b)
+		}
+	}
+	if s.Bytes() != nil || s.Len() != 0 {
+		t.Fatalf("zeroed secret should be released")
+	}
+	s.Zero()
+
+	if _, err := SecretFromBase64("!!"); err == nil {
+		t.Fatalf("invalid base64 should fail")
+	}
+}
+
+func TestKeySignerClose(t *testing.T) {
+	signer, err := NewKeySigner(verifySignParams)
+	if err != nil {
+		t.Fatalf("new key signer fail: %v", err)
+	}
+	if signer.params.PrivateKey != "" {
+		t.Fatalf("signer should not keep the encoded private key")
+	}
+	expected, err := buildSignatureBody(verifySignParams, []byte("payload"))
+	if err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+	body, err := signer.Sign([]byte("payload"))
+	if err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+	if body.SignatureValue != expected.SignatureValue {
+		t.Fatalf("signature should match the signature params one")
+	}
+
+	signer.Close()
+	if _, err = signer.Sign([]byte("payload")); err == nil {
+		t.Fatalf("closed signer should not sign")
+	}
+}
+
+func TestNewSecretKeySigner(t *testing.T) {
+	priv, pub, cleanup := registerP256(t)
+	defer cleanup()
+	if _, err := NewSecretKeySigner("did:axn:alice", "rsa", NewSecretBytes(priv)); err == nil {
+		t.Fatalf("unknown algorithm should fail")
+	}
+	if _, err := NewSecretKeySigner("did:axn:alice", "p256", NewSecretBytes(nil)); err == nil {
+		t.Fatalf("empty key should fail")
+	}
+
+	signer, err := NewSecretKeySigner("did:axn:alice", "p256", NewSecretBytes(priv))
+	if err != nil {
+		t.Fatalf("new secret key signer fail: %v", err)
+	}
+	body, err := signer.Sign([]byte("payload"))
+	if err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+	if body.Creator != "did:axn:alice" || body.Nonce == "" || body.Created == 0 {
+		t.Fatalf("signature body invalid: %+v", body)
+	}
+	if err = VerifySignature([]byte("payload"), body, pub); err != nil {
+		t.Fatalf("verify fail: %v", err)
+	}
+
+	signer.Close()
+	for _, c := range priv {
+		if c != 0 {
+			t.Fatalf("closed signer should zero the key")
+		}
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/utils"
+)
+
+// Signer signs payloads on behalf of an identity, so that keys held in an
+// HSM, a KMS or a remote signing service can be used without exposing the
+// key material to the client.
+//
+// Sign returns the signature body of the payload, with the creator, nonce
+// and created time of the signature and the base64 signature value, as
+// computed from signature params by the client.
+//
+type Signer interface {
+	Sign(payload []byte) (*pki.SignatureBody, error)
+}
+
+// KeySigner is a Signer using an in process private key, the same way the
+// client signs with signature params.
+//
+// The private key is decoded once and kept in SecretBytes, Close zeroes it
+// when the signer is no longer needed.
+//
+type KeySigner struct {
+	params pki.SignatureParam
+	alg    SignatureAlgorithm
+	key    *SecretBytes
+	// stamp sets the created time of every signature
+	stamp bool
+}
+
+// NewKeySigner returns a KeySigner signing with the signature params.
+//
+func NewKeySigner(signParams *pki.SignatureParam) (*KeySigner, error) {
+	if err := checkSignParams(signParams); err != nil {
+		return nil, err
+	}
+	alg, encoded, err := keyAlgorithm(signParams.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	key, err := SecretFromBase64(encoded)
+	if err != nil {
+		return nil, err
+	}
+	params := *signParams
+	params.PrivateKey = ""
+	return &KeySigner{params: params, alg: alg, key: key}, nil
+}
+
+// NewSecretKeySigner returns a KeySigner of the creator signing with the
+// private key of the algorithm, named as in AlgorithmKey. The signer takes
+// ownership of the key, which is zeroed by Close. Every signature gets a
+// new nonce and the current time.
+//
+func NewSecretKeySigner(creator did.Identifier, algorithm string, key *SecretBytes) (*KeySigner, error) {
+	if creator == "" {
+		return nil, fmt.Errorf("request signature creator must be set")
+	}
+	if key == nil || key.Len() == 0 {
+		return nil, fmt.Errorf("request signature private key must be set")
+	}
+	var alg SignatureAlgorithm
+	if algorithm != "" && algorithm != AlgorithmEd25519 {
+		var err error
+		if alg, err = LookupSignatureAlgorithm(algorithm); err != nil {
+			return nil, err
+		}
+	}
+	return &KeySigner{params: pki.SignatureParam{Creator: creator}, alg: alg, key: key, stamp: true}, nil
+}
+
+// Sign signs the payload with the private key. Every signature gets a new
+// nonce when the signature params have none.
+//
+func (s *KeySigner) Sign(payload []byte) (*pki.SignatureBody, error) {
+	if s.key.Len() == 0 {
+		return nil, fmt.Errorf("key signer closed")
+	}
+	params := s.params
+	if params.Nonce == "" {
+		nonce, err := defaultNonces.Nonce(params.Creator)
+		if err != nil {
+			return nil, err
+		}
+		params.Nonce = nonce
+	}
+	if s.stamp {
+		params.Created = time.Now().Unix()
+	}
+	signData, alg, err := signWithKey(s.alg, s.key, &params, payload)
+	if err != nil {
+		return nil, err
+	}
+	return &pki.SignatureBody{
+		Creator:        params.Creator,
+		Created:        params.Created,
+		Nonce:          params.Nonce,
+		SignatureValue: signatureValue(alg, utils.EncodeBase64(signData.Sign)),
+	}, nil
+}
+
+// Close zeroes the private key, the signer can not sign afterwards.
+//
+func (s *KeySigner) Close() error {
+	s.key.Zero()
+	return nil
+}
+
+// signerTable holds the signers of the client, shared by the copies made
+// with WithContext
+type signerTable struct {
+	mu      sync.RWMutex
+	signers map[did.Identifier]Signer
+}
+
+// SetSigner makes the client sign with signer for the creator. It is used
+// for the signature params of the creator without private key, so the
+// methods taking signature params only need the creator:
+//
+//	client.SetSigner("did:axn:issuer", hsmSigner)
+//	resp, err := client.IssueCToken(header, body, &pki.SignatureParam{Creator: "did:axn:issuer"})
+//
+// nil signer removes the signer of the creator.
+//
+func (w *WalletClient) SetSigner(creator did.Identifier, signer Signer) {
+	if w.signers == nil {
+		w.signers = &signerTable{}
+	}
+	w.signers.mu.Lock()
+	defer w.signers.mu.Unlock()
+	if signer == nil {
+		delete(w.signers.signers, creator)
+		return
+	}
+	if w.signers.signers == nil {
+		w.signers.signers = make(map[did.Identifier]Signer)
+	}
+	w.signers.signers[creator] = signer
+}
+
+// signerFor returns the signer of signature params without private key,
+// nil if none is set.
+func (w *WalletClient) signerFor(signParams *pki.SignatureParam) Signer {
+	if w.signers == nil || signParams == nil || signParams.PrivateKey != "" {
+		return nil
+	}
+	w.signers.mu.RLock()
+	defer w.signers.mu.RUnlock()
+	return w.signers.signers[signParams.Creator]
+}
+
+// signPayload returns the signature body of data, signed by the signer of
+// the creator if any, or with the private key of the signature params,
+// copied from the key cache if queryPrivateKey left it there.
+func (w *WalletClient) signPayload(signParams *pki.SignatureParam, data []byte) (*pki.SignatureBody, error) {
+	signer := w.signerFor(signParams)
+	if signer == nil {
+		signer = w.cachedSigner(signParams)
+	}
+	if signer == nil {
+		return buildSignatureBody(signParams, data)
+	}
+	sign, err := signer.Sign(data)
+	if err != nil {
+		return nil, err
+	}
+	if sign == nil {
+		return nil, fmt.Errorf("signer returned no signature")
+	}
+	return sign, nil
+}
+
+// signRaw is like signPayload, the signature value is the raw signature
+// instead of its base64.
+func (w *WalletClient) signRaw(signParams *pki.SignatureParam, data []byte) (*pki.SignatureBody, error) {
+	if w.signerFor(signParams) == nil && w.cachedSigner(signParams) == nil {
+		return buildSignatureBodyBase(signParams, data)
+	}
+	sign, err := w.signPayload(signParams, data)
+	if err != nil {
+		return nil, err
+	}
+	alg, value := SplitSignatureValue(sign.SignatureValue)
+	raw, err := utils.DecodeBase64(value)
+	if err != nil {
+		return nil, fmt.Errorf("signer returned invalid signature: %v", err)
+	}
+	rawSign := *sign
+	rawSign.SignatureValue = signatureValue(alg, string(raw))
+	return &rawSign, nil
+}
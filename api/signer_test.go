@@ -0,0 +1,169 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/sdk-go-common/utils"
+)
+
+// fakeSigner signs like a remote signing service
+type fakeSigner struct {
+	creator did.Identifier
+	signed  [][]byte
+}
+
+func (f *fakeSigner) Sign(payload []byte) (*pki.SignatureBody, error) {
+	f.signed = append(f.signed, payload)
+	return &pki.SignatureBody{
+		Creator:        f.creator,
+		Nonce:          "remote-nonce",
+		SignatureValue: utils.EncodeBase64([]byte("remote-sig")),
+	}, nil
+}
+
+func TestSetSignerRequest(t *testing.T) {
+	initWalletClient(t)
+	client := walletClient.(*WalletClient)
+
+	const creator = did.Identifier("did:axn:hsm-issuer")
+	signer := &fakeSigner{creator: creator}
+	client.SetSigner(creator, signer)
+
+	body := &wallet.POEBody{Name: "MyCar", Owner: "did:axn:001"}
+	reqBody, err := client.buildWalletRequest(nil, body, &pki.SignatureParam{Creator: creator})
+	if err != nil {
+		t.Fatalf("build wallet request fail: %v", err)
+	}
+	if len(signer.signed) != 1 || string(signer.signed[0]) != reqBody.Payload {
+		t.Fatalf("request payload should be signed by the signer")
+	}
+	if reqBody.Signature.SignatureValue != utils.EncodeBase64([]byte("remote-sig")) || reqBody.Signature.Nonce != "remote-nonce" {
+		t.Fatalf("signer signature should be used, got %#v", reqBody.Signature)
+	}
+
+	// a private key takes precedence over the signer
+	reqBody, err = client.buildWalletRequest(nil, body, &pki.SignatureParam{Creator: creator, Nonce: "nonce", PrivateKey: verifySignParams.PrivateKey})
+	if err != nil {
+		t.Fatalf("build wallet request fail: %v", err)
+	}
+	if len(signer.signed) != 1 || reqBody.Signature.Nonce != "nonce" {
+		t.Fatalf("private key should be used when set")
+	}
+
+	client.SetSigner(creator, nil)
+	if _, err = client.buildWalletRequest(nil, body, &pki.SignatureParam{Creator: creator}); err == nil {
+		t.Fatalf("err should not be nil without signer nor private key")
+	}
+}
+
+func TestSetSignerUTXO(t *testing.T) {
+	initWalletClient(t)
+	client := walletClient.(*WalletClient)
+
+	const creator = did.Identifier("did:axn:hsm-issuer")
+	client.SetSigner(creator, &fakeSigner{creator: creator})
+
+	script, err := json.Marshal(&pw.UTXOSignature{PublicKey: []byte("public-key")})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	// the output type is not named, so it is built from json
+	txJSON, err := json.Marshal(map[string]interface{}{
+		"txout": []map[string]interface{}{{"script": script}},
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	tx := &pw.TX{}
+	if err = json.Unmarshal(txJSON, tx); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err = client.SignTx(tx, &pki.SignatureParam{Creator: creator}); err != nil {
+		t.Fatalf("sign tx fail: %v", err)
+	}
+
+	var signed pw.UTXOSignature
+	if err = json.Unmarshal(tx.Txout[0].Script, &signed); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if string(signed.Signature) != "remote-sig" || signed.Nonce != "remote-nonce" || signed.Creator != string(creator) {
+		t.Fatalf("utxo should carry the raw signer signature, got %#v", signed)
+	}
+}
+
+// failingSigner fails like an unreachable signing service
+type failingSigner struct{}
+
+func (failingSigner) Sign(payload []byte) (*pki.SignatureBody, error) {
+	return nil, fmt.Errorf("hsm unreachable")
+}
+
+func TestSignTxsSignerFail(t *testing.T) {
+	initWalletClient(t)
+	client := walletClient.(*WalletClient)
+
+	const creator = did.Identifier("did:axn:hsm-issuer")
+	client.SetSigner(creator, failingSigner{})
+
+	script, err := json.Marshal(&pw.UTXOSignature{PublicKey: []byte("public-key")})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	txJSON, err := json.Marshal(map[string]interface{}{
+		"founder": string(creator),
+		"txout":   []map[string]interface{}{{"script": script}},
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	tx := &pw.TX{}
+	if err = json.Unmarshal(txJSON, tx); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err = client.SignTxs([]*pw.TX{tx}, &pki.SignatureParam{Creator: creator}); err == nil {
+		t.Fatalf("err should not be nil when the signer fails")
+	}
+}
+
+func TestKeySigner(t *testing.T) {
+	if _, err := NewKeySigner(&pki.SignatureParam{Creator: "did:axn:001"}); err == nil {
+		t.Fatalf("err should not be nil without private key")
+	}
+	signer, err := NewKeySigner(verifySignParams)
+	if err != nil {
+		t.Fatalf("new key signer fail: %v", err)
+	}
+	sign, err := signer.Sign([]byte("payload"))
+	if err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+	expected, err := buildSignatureBody(verifySignParams, []byte("payload"))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if *sign != *expected {
+		t.Fatalf("key signer should sign like signature params")
+	}
+}
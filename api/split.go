@@ -0,0 +1,125 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// UTXORef identifies a colored token UTXO, by the SourceTxDataHash and Ix
+// fields of the pw.UTXO returned by QueryTransactionUTXO.
+//
+type UTXORef struct {
+	SourceTxDataHash string `json:"source_tx_data_hash"`
+	Ix               string `json:"ix"`
+}
+
+// SplitCTokenBody is the request body of SplitCToken.
+//
+// The Source UTXO of the Owner is split into new UTXOs of the given Amounts,
+// the remainder, if any, is kept in one more UTXO.
+//
+type SplitCTokenBody struct {
+	Owner   string      `json:"owner"`
+	TokenId string      `json:"token_id"`
+	Source  *UTXORef    `json:"source"`
+	Amounts []int64     `json:"amounts"`
+	Fee     *wallet.Fee `json:"fee,omitempty"`
+}
+
+// MergeCTokenBody is the request body of MergeCToken.
+//
+// The Sources UTXOs of the Owner are merged into one UTXO, every UTXO of the
+// token held by the Owner if Sources is empty.
+//
+type MergeCTokenBody struct {
+	Owner   string      `json:"owner"`
+	TokenId string      `json:"token_id"`
+	Sources []*UTXORef  `json:"sources,omitempty"`
+	Fee     *wallet.Fee `json:"fee,omitempty"`
+}
+
+// SplitCToken is used to split a colored token UTXO into smaller ones, e.g.
+// so that partial amounts can be transferred or held without change.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) SplitCToken(header http.Header, body *SplitCTokenBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.Owner == "" || body.TokenId == "" || body.Source == nil || body.Source.SourceTxDataHash == "" {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	if len(body.Amounts) == 0 {
+		err = fmt.Errorf("split amounts invalid")
+		return
+	}
+	for _, amount := range body.Amounts {
+		if amount <= 0 {
+			err = fmt.Errorf("split amount must be positive")
+			return
+		}
+	}
+	return w.processProposal(header, "/v2/transaction/tokens/split/prepare", body, signParams)
+}
+
+// MergeCToken is used to merge colored token UTXOs into one.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) MergeCToken(header http.Header, body *MergeCTokenBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.Owner == "" || body.TokenId == "" {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	if len(body.Sources) == 1 {
+		err = fmt.Errorf("at least two utxos must be merged")
+		return
+	}
+	seen := make(map[UTXORef]bool, len(body.Sources))
+	for _, source := range body.Sources {
+		if source == nil || source.SourceTxDataHash == "" {
+			err = fmt.Errorf("merge source invalid")
+			return
+		}
+		if seen[*source] {
+			err = fmt.Errorf("utxo %s:%s duplicated", source.SourceTxDataHash, source.Ix)
+			return
+		}
+		seen[*source] = true
+	}
+	return w.processProposal(header, "/v2/transaction/tokens/merge/prepare", body, signParams)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestSplitCTokenSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const owner = did.Identifier("did:axn:001")
+	signer := &fakeSigner{creator: owner}
+	client.SetSigner(owner, signer)
+
+	script, err := json.Marshal(&pw.UTXOSignature{PublicKey: []byte("public-key")})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	preRsp := []map[string]interface{}{{
+		"founder": string(owner),
+		"txout":   []map[string]interface{}{{"script": script}},
+	}}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/tokens/split/prepare").
+		Reply(200).
+		JSON(payloadResponse(t, preRsp))
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/process").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletResponse{Id: "split-tx-001"}))
+
+	body := &SplitCTokenBody{
+		Owner:   string(owner),
+		TokenId: "token-001",
+		Source:  &UTXORef{SourceTxDataHash: "tx-hash-001", Ix: "0"},
+		Amounts: []int64{10, 10, 10},
+	}
+	resp, err := client.SplitCToken(http.Header{}, body, &pki.SignatureParam{Creator: owner})
+	if err != nil {
+		t.Fatalf("split ctoken fail: %v", err)
+	}
+	if resp.Id != "split-tx-001" {
+		t.Fatalf("response should carry the transaction id")
+	}
+	if len(signer.signed) != 1 {
+		t.Fatalf("split should be signed by the owner")
+	}
+}
+
+func TestSplitCTokenInvalidBody(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	source := &UTXORef{SourceTxDataHash: "tx-hash-001", Ix: "0"}
+	bodies := map[string]*SplitCTokenBody{
+		"no source":       {Owner: "did:axn:001", TokenId: "token-001", Amounts: []int64{10}},
+		"no amounts":      {Owner: "did:axn:001", TokenId: "token-001", Source: source},
+		"negative amount": {Owner: "did:axn:001", TokenId: "token-001", Source: source, Amounts: []int64{10, -1}},
+	}
+	for name, body := range bodies {
+		if _, err := client.SplitCToken(http.Header{}, body, &pki.SignatureParam{}); err == nil {
+			t.Errorf("%s: err should not be nil", name)
+		}
+	}
+}
+
+func TestMergeCTokenInvalidBody(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	source := &UTXORef{SourceTxDataHash: "tx-hash-001", Ix: "0"}
+	bodies := map[string]*MergeCTokenBody{
+		"no token":   {Owner: "did:axn:001"},
+		"one source": {Owner: "did:axn:001", TokenId: "token-001", Sources: []*UTXORef{source}},
+		"duplicated": {Owner: "did:axn:001", TokenId: "token-001", Sources: []*UTXORef{source, {SourceTxDataHash: "tx-hash-001", Ix: "0"}}},
+	}
+	for name, body := range bodies {
+		if _, err := client.MergeCToken(http.Header{}, body, &pki.SignatureParam{}); err == nil {
+			t.Errorf("%s: err should not be nil", name)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// ErrSupplyCapExceeded is returned by ReissueCToken when the reissued amount
+// would raise the total supply of the token above its supply cap.
+//
+var ErrSupplyCapExceeded = fmt.Errorf("ctoken supply cap exceeded")
+
+// ReissueCTokenBody is the request body of ReissueCToken.
+//
+// The tokens are minted under the existing TokenId, e.g. to top up the
+// points of a loyalty program, and sent to the Owner wallet.
+//
+type ReissueCTokenBody struct {
+	Issuer  string      `json:"issuer"`
+	TokenId string      `json:"token_id"`
+	Owner   string      `json:"owner"`
+	Amount  int64       `json:"amount"`
+	Fee     *wallet.Fee `json:"fee,omitempty"`
+}
+
+// SupplyCapBody is the request body of SetSupplyCap.
+//
+type SupplyCapBody struct {
+	Issuer  string `json:"issuer"`
+	TokenId string `json:"token_id"`
+	// Cap is the maximum total supply of the token, zero removes the cap
+	Cap int64 `json:"cap"`
+}
+
+// ReissueCToken is used to issue more colored tokens of an existing token.
+// The request must be signed by the issuer of the token.
+//
+// The supply cap of the token is checked with QueryCTokenInfo before the
+// proposal is sent, ErrSupplyCapExceeded is returned when the reissue would
+// exceed it.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) ReissueCToken(header http.Header, body *ReissueCTokenBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.Issuer == "" || body.TokenId == "" || body.Owner == "" {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	if body.Amount <= 0 {
+		err = fmt.Errorf("reissue amount must be positive")
+		return
+	}
+
+	info, err := w.QueryCTokenInfo(header, body.TokenId)
+	if err != nil {
+		return nil, fmt.Errorf("query ctoken info error: %v", err)
+	}
+	if info.SupplyCap > 0 && body.Amount > info.SupplyCap-info.TotalSupply {
+		return nil, ErrSupplyCapExceeded
+	}
+
+	return w.processProposal(header, "/v2/transaction/tokens/reissue/prepare", body, signParams)
+}
+
+// SetSupplyCap is used to set the maximum total supply of a colored token,
+// later reissues can not raise the supply above it. The request must be
+// signed by the issuer of the token.
+//
+// The cap can not be lower than the current total supply.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) SetSupplyCap(header http.Header, body *SupplyCapBody, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if body == nil || body.Issuer == "" || body.TokenId == "" {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+	if body.Cap < 0 {
+		err = fmt.Errorf("supply cap must not be negative")
+		return
+	}
+
+	if body.Cap > 0 {
+		info, err := w.QueryCTokenInfo(header, body.TokenId)
+		if err != nil {
+			return nil, fmt.Errorf("query ctoken info error: %v", err)
+		}
+		if body.Cap < info.TotalSupply {
+			return nil, fmt.Errorf("supply cap %d below total supply %d", body.Cap, info.TotalSupply)
+		}
+	}
+
+	return w.processProposal(header, "/v2/transaction/tokens/cap/prepare", body, signParams)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestReissueCTokenSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const issuer = did.Identifier("did:axn:001")
+	signer := &fakeSigner{creator: issuer}
+	client.SetSigner(issuer, signer)
+
+	script, err := json.Marshal(&pw.UTXOSignature{PublicKey: []byte("public-key")})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	preRsp := []map[string]interface{}{{
+		"founder": string(issuer),
+		"txout":   []map[string]interface{}{{"script": script}},
+	}}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/ctoken/info").
+		MatchParam("id", "token-001").
+		Reply(200).
+		JSON(payloadResponse(t, &CTokenInfo{Id: "token-001", TotalSupply: 900, SupplyCap: 1000}))
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/tokens/reissue/prepare").
+		Reply(200).
+		JSON(payloadResponse(t, preRsp))
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/process").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletResponse{Id: "reissue-tx-001"}))
+
+	body := &ReissueCTokenBody{
+		Issuer:  string(issuer),
+		TokenId: "token-001",
+		Owner:   "did:axn:002",
+		Amount:  100,
+	}
+	resp, err := client.ReissueCToken(http.Header{}, body, &pki.SignatureParam{Creator: issuer})
+	if err != nil {
+		t.Fatalf("reissue ctoken fail: %v", err)
+	}
+	if resp.Id != "reissue-tx-001" {
+		t.Fatalf("response should carry the transaction id")
+	}
+	if len(signer.signed) != 1 {
+		t.Fatalf("reissue should be signed by the issuer")
+	}
+}
+
+func TestReissueCTokenCapExceeded(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/ctoken/info").
+		MatchParam("id", "token-001").
+		Reply(200).
+		JSON(payloadResponse(t, &CTokenInfo{Id: "token-001", TotalSupply: 950, SupplyCap: 1000}))
+
+	body := &ReissueCTokenBody{
+		Issuer:  "did:axn:001",
+		TokenId: "token-001",
+		Owner:   "did:axn:002",
+		Amount:  100,
+	}
+	resp, err := client.ReissueCToken(http.Header{}, body, &pki.SignatureParam{})
+	if err != ErrSupplyCapExceeded {
+		t.Fatalf("err should be ErrSupplyCapExceeded, got %v", err)
+	}
+	if resp != nil {
+		t.Fatalf("response object should be nil when the cap is exceeded")
+	}
+
+	body.Amount = 0
+	if _, err = client.ReissueCToken(http.Header{}, body, &pki.SignatureParam{}); err == nil {
+		t.Fatalf("err should not be nil without amount")
+	}
+}
+
+func TestSetSupplyCapBelowSupply(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v2/ctoken/info").
+		MatchParam("id", "token-001").
+		Reply(200).
+		JSON(payloadResponse(t, &CTokenInfo{Id: "token-001", TotalSupply: 950}))
+
+	body := &SupplyCapBody{Issuer: "did:axn:001", TokenId: "token-001", Cap: 900}
+	if _, err := client.SetSupplyCap(http.Header{}, body, &pki.SignatureParam{}); err == nil {
+		t.Fatalf("err should not be nil when the cap is below the supply")
+	}
+
+	body.Cap = -1
+	if _, err := client.SetSupplyCap(http.Header{}, body, &pki.SignatureParam{}); err == nil {
+		t.Fatalf("err should not be nil with negative cap")
+	}
+}
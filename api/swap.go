@@ -0,0 +1,172 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+)
+
+// SwapBody is the request body of ExchangeAsset.
+//
+// Payment is the colored token leg, from the buyer to the seller, and Asset
+// the digital asset leg, from the seller to the buyer. Both legs are
+// settled in a single blockchain transaction, either both or none of them
+// take place.
+//
+type SwapBody struct {
+	Payment *wallet.TransferCTokenBody `json:"payment"`
+	Asset   *wallet.TransferAssetBody  `json:"asset"`
+}
+
+// ExchangeAsset is used to swap colored tokens against digital assets
+// atomically, e.g. to settle a marketplace order.
+//
+// The transactions of the payment leg are signed by the buyer with
+// buyerSignParams, the ones of the asset leg by the seller with
+// sellerSignParams, and all of them are submitted at once. Both legs go
+// through the same counterparty checks as TransferCToken and TransferAsset.
+//
+// The default invoking mode is asynchronous, it will return
+// without waiting for blockchain transaction confirmation.
+//
+// If you want to switch to synchronous invoking mode, set
+// 'BC-Invoke-Mode' header to 'sync' value. In synchronous mode,
+// it will not return until the blockchain transaction is confirmed.
+//
+// The default key pair trust mode does not trust, it will required key pair.
+// If you had trust the key pair, it will required security code.
+//
+func (w *WalletClient) ExchangeAsset(header http.Header, body *SwapBody, buyerSignParams, sellerSignParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if err = checkSwapBody(body); err != nil {
+		return
+	}
+	if buyerSignParams == nil || sellerSignParams == nil {
+		err = fmt.Errorf("signature params invalid")
+		return
+	}
+	buyer, seller := body.Payment.From, body.Payment.To
+	if string(buyerSignParams.Creator) != buyer || string(sellerSignParams.Creator) != seller {
+		err = fmt.Errorf("signature params do not match swap parties")
+		return
+	}
+
+	if err = w.checkCounterpartyKYC(header, seller); err != nil {
+		return
+	}
+	if err = w.checkCounterpartyKYC(header, buyer); err != nil {
+		return
+	}
+	paymentScreened := w.screenAsync(screeningTypeCToken, buyer, seller, body.Payment)
+	assetScreened := w.screenAsync(screeningTypeAsset, seller, buyer, body.Asset)
+	submitHeader, err := w.travelRuleHeader(header, body.Payment)
+	if err != nil {
+		return
+	}
+
+	if w.s != nil {
+		buyerSignParams, err = w.queryPrivateKey(header, buyerSignParams)
+		if err != nil {
+			return
+		}
+		sellerSignParams, err = w.queryPrivateKey(header, sellerSignParams)
+		if err != nil {
+			return
+		}
+	}
+
+	// 1 send swap proposal to get wallet.Tx of both legs
+	txs, err := w.SendSwapProposal(header, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2 sign each leg by its owner
+	err = w.signSwapTxs(txs, buyerSignParams, sellerSignParams)
+	if err != nil {
+		err = fmt.Errorf("sign Txs error: %v", err)
+		return nil, err
+	}
+
+	// wait for the screening decisions before submission
+	paymentErr, assetErr := paymentScreened(), assetScreened()
+	if paymentErr != nil {
+		return nil, paymentErr
+	}
+	if assetErr != nil {
+		return nil, assetErr
+	}
+
+	// 3 call ProcessTx to submit both legs in one transaction
+	return w.ProcessTx(submitHeader, txs)
+}
+
+// SendSwapProposal is used to send swap proposal to get wallet.Tx of both
+// legs to be signed.
+//
+func (w *WalletClient) SendSwapProposal(header http.Header, body *SwapBody) (result []*pw.TX, err error) {
+	if err = checkSwapBody(body); err != nil {
+		return nil, err
+	}
+
+	err = w.doJSON(header, "POST", "/v2/transaction/swap/prepare", nil, body, &result)
+
+	return
+}
+
+// checkSwapBody checks the legs of a swap are between the same two parties.
+func checkSwapBody(body *SwapBody) error {
+	if body == nil || body.Payment == nil || body.Asset == nil ||
+		len(body.Payment.Tokens) == 0 || len(body.Asset.Assets) == 0 {
+		return fmt.Errorf("request payload invalid")
+	}
+	if body.Payment.From == "" || body.Payment.To == "" || body.Payment.From == body.Payment.To {
+		return fmt.Errorf("swap parties invalid")
+	}
+	if body.Asset.From != body.Payment.To || body.Asset.To != body.Payment.From {
+		return fmt.Errorf("swap legs do not match")
+	}
+	return nil
+}
+
+// signSwapTxs signs the transactions founded by the buyer and the seller
+// with their keys, and the other ones, e.g. fees, by the platform.
+func (w *WalletClient) signSwapTxs(txs []*pw.TX, buyerSignParams, sellerSignParams *pki.SignatureParam) error {
+	for _, tx := range txs {
+		var err error
+		switch tx.Founder {
+		case string(buyerSignParams.Creator):
+			err = w.SignTx(tx, buyerSignParams)
+		case string(sellerSignParams.Creator):
+			err = w.SignTx(tx, sellerSignParams)
+		default:
+			var platformSignParams *pki.SignatureParam
+			platformSignParams, err = w.c.GetEnterpriseSignParam()
+			if err == nil {
+				err = w.SignTx(tx, platformSignParams)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+func TestExchangeAssetSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const buyer, seller = did.Identifier("did:axn:buyer"), did.Identifier("did:axn:seller")
+	buyerSigner := &fakeSigner{creator: buyer}
+	sellerSigner := &fakeSigner{creator: seller}
+	client.SetSigner(buyer, buyerSigner)
+	client.SetSigner(seller, sellerSigner)
+
+	script, err := json.Marshal(&pw.UTXOSignature{PublicKey: []byte("public-key")})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	txs := []map[string]interface{}{
+		{"founder": string(buyer), "txout": []map[string]interface{}{{"script": script}}},
+		{"founder": string(seller), "txout": []map[string]interface{}{{"script": script}}},
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/swap/prepare").
+		Reply(200).
+		JSON(payloadResponse(t, txs))
+	gock.New("http://127.0.0.1:8006").
+		Post("/v2/transaction/process").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletResponse{Id: "swap-tx-001"}))
+
+	body := &SwapBody{
+		Payment: &wallet.TransferCTokenBody{
+			From:   string(buyer),
+			To:     string(seller),
+			Tokens: []*wallet.TokenAmount{{TokenId: "ctoken-001", Amount: 10}},
+		},
+		Asset: &wallet.TransferAssetBody{
+			From:   string(seller),
+			To:     string(buyer),
+			Assets: []string{"asset-001"},
+		},
+	}
+	resp, err := client.ExchangeAsset(http.Header{}, body, &pki.SignatureParam{Creator: buyer}, &pki.SignatureParam{Creator: seller})
+	if err != nil {
+		t.Fatalf("exchange asset fail: %v", err)
+	}
+	if resp == nil || resp.Id != "swap-tx-001" {
+		t.Fatalf("response id should be swap-tx-001")
+	}
+	if len(buyerSigner.signed) != 1 || len(sellerSigner.signed) != 1 {
+		t.Fatalf("each leg should be signed by its owner")
+	}
+}
+
+func TestExchangeAssetFail(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	buyerSign := &pki.SignatureParam{Creator: "did:axn:buyer"}
+	sellerSign := &pki.SignatureParam{Creator: "did:axn:seller"}
+	body := &SwapBody{
+		Payment: &wallet.TransferCTokenBody{
+			From:   "did:axn:buyer",
+			To:     "did:axn:seller",
+			Tokens: []*wallet.TokenAmount{{TokenId: "ctoken-001", Amount: 10}},
+		},
+		Asset: &wallet.TransferAssetBody{
+			From:   "did:axn:other",
+			To:     "did:axn:buyer",
+			Assets: []string{"asset-001"},
+		},
+	}
+	if _, err := client.ExchangeAsset(http.Header{}, body, buyerSign, sellerSign); err == nil {
+		t.Fatalf("err should not be nil when the legs do not match")
+	}
+
+	body.Asset.From = "did:axn:seller"
+	if _, err := client.ExchangeAsset(http.Header{}, body, sellerSign, buyerSign); err == nil {
+		t.Fatalf("err should not be nil when the signature params do not match the parties")
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AuthorizationHeader carries the access token of a TokenSource.
+//
+const AuthorizationHeader = "Authorization"
+
+// tokenExpiryDelta is how long before its expiry a token is refreshed, so
+// that it does not expire on the way to the gateway.
+const tokenExpiryDelta = 10 * time.Second
+
+// ErrTokenInvalid is returned when a TokenSource returns no access token.
+//
+var ErrTokenInvalid = fmt.Errorf("token source returned no access token")
+
+// Token is an access token of the gateway, like oauth2.Token.
+//
+type Token struct {
+	// AccessToken is the token sent in the Authorization header
+	AccessToken string
+	// TokenType is the type of the token, "Bearer" if empty
+	TokenType string
+	// Expiry is when the token expires, the token never expires if zero
+	Expiry time.Time
+}
+
+// Valid tells whether the token is set and not about to expire.
+//
+func (t *Token) Valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Now().Add(tokenExpiryDelta).Before(t.Expiry)
+}
+
+// Type returns the type of the token, "Bearer" by default.
+//
+func (t *Token) Type() string {
+	if t.TokenType == "" {
+		return "Bearer"
+	}
+	return t.TokenType
+}
+
+// TokenSource returns the access tokens of the gateway, like
+// oauth2.TokenSource, which it adapts with:
+//
+//	api.TokenSourceFunc(func() (*api.Token, error) {
+//		t, err := oauthSource.Token()
+//		if err != nil {
+//			return nil, err
+//		}
+//		return &api.Token{AccessToken: t.AccessToken, TokenType: t.Type(), Expiry: t.Expiry}, nil
+//	})
+//
+// The client caches the token until it expires or the gateway rejects it,
+// so Token is called to get a new token.
+//
+type TokenSource interface {
+	Token() (*Token, error)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource.
+//
+type TokenSourceFunc func() (*Token, error)
+
+// Token implements TokenSource.
+//
+func (f TokenSourceFunc) Token() (*Token, error) {
+	return f()
+}
+
+// WithTokenSource sets the token source of the client, see SetTokenSource.
+//
+func WithTokenSource(src TokenSource) ClientOption {
+	return func(o *clientOptions) {
+		o.tokens = src
+	}
+}
+
+// SetTokenSource makes the client send the token of src in the
+// Authorization header of the requests. The token is refreshed when it
+// expires, and when the gateway rejects it with 401 Unauthorized the
+// request is sent once more with a new token.
+//
+// The Authorization header set by the caller takes precedence, nil src
+// disables the tokens.
+//
+func (w *WalletClient) SetTokenSource(src TokenSource) {
+	if w.middleware == nil {
+		w.middleware = &middlewareChain{}
+	}
+	var auth *tokenAuth
+	if src != nil {
+		auth = &tokenAuth{src: src}
+	}
+	w.middleware.mu.Lock()
+	defer w.middleware.mu.Unlock()
+	w.middleware.tokens = auth
+}
+
+// tokenAuth caches the token of a token source.
+type tokenAuth struct {
+	src TokenSource
+
+	mu  sync.Mutex
+	tok *Token
+}
+
+// token returns the cached token, a new one if it is not valid or is
+// stale, the token the gateway rejected.
+func (a *tokenAuth) token(stale *Token) (*Token, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.tok != stale && a.tok.Valid() {
+		return a.tok, nil
+	}
+	tok, err := a.src.Token()
+	if err != nil {
+		return nil, fmt.Errorf("token source: %v", err)
+	}
+	if tok == nil || tok.AccessToken == "" {
+		return nil, ErrTokenInvalid
+	}
+	a.tok = tok
+	return tok, nil
+}
+
+// roundTrip sends the request with rt and the token, once more with a new
+// token if the gateway rejects it and the request body can be sent again.
+func (a *tokenAuth) roundTrip(req *http.Request, rt RoundTripFunc) (*http.Response, error) {
+	tok, err := a.token(nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := rt(withToken(req, tok))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	retry := new(http.Request)
+	*retry = *req
+	if req.Body != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	if tok, err = a.token(tok); err != nil {
+		if retry.Body != nil {
+			retry.Body.Close()
+		}
+		return resp, nil
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	return rt(withToken(retry, tok))
+}
+
+// withToken returns a copy of the request with the token in its
+// Authorization header.
+func withToken(req *http.Request, tok *Token) *http.Request {
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header)+1)
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	r.Header.Set(AuthorizationHeader, tok.Type()+" "+tok.AccessToken)
+	return r
+}
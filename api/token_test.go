@@ -0,0 +1,175 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+type countingTokens struct {
+	tokens []*Token
+	calls  int
+}
+
+func (c *countingTokens) Token() (*Token, error) {
+	c.calls++
+	if c.calls > len(c.tokens) {
+		return nil, fmt.Errorf("no more tokens")
+	}
+	return c.tokens[c.calls-1], nil
+}
+
+func TestTokenSourceRefreshOnUnauthorized(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	src := &countingTokens{tokens: []*Token{{AccessToken: "token-1"}, {AccessToken: "token-2", TokenType: "MAC"}}}
+	client.SetTokenSource(src)
+	defer client.SetTokenSource(nil)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/balance").
+		MatchHeader(AuthorizationHeader, "Bearer token-1").
+		Reply(401)
+	for i := 0; i < 2; i++ {
+		gock.New("http://127.0.0.1:8006").
+			Get("/v1/wallet/balance").
+			MatchHeader(AuthorizationHeader, "MAC token-2").
+			Reply(200).
+			JSON(payloadResponse(t, &wallet.WalletBalance{}))
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetWalletBalance(http.Header{}, "did:axn:001"); err != nil {
+			t.Fatalf("get wallet balance %d fail: %v", i, err)
+		}
+	}
+	if src.calls != 2 {
+		t.Fatalf("the token should be refreshed once, %d calls", src.calls)
+	}
+	if !gock.IsDone() {
+		t.Fatalf("the request should be sent again with the new token")
+	}
+}
+
+func TestTokenSourceExpiry(t *testing.T) {
+	src := &countingTokens{tokens: []*Token{
+		{AccessToken: "token-1", Expiry: time.Now().Add(time.Second)},
+		{AccessToken: "token-2", Expiry: time.Now().Add(time.Hour)},
+	}}
+	auth := &tokenAuth{src: src}
+	var sent []string
+	rt := func(req *http.Request) (*http.Response, error) {
+		sent = append(sent, req.Header.Get(AuthorizationHeader))
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+	}
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest("GET", "http://wallet.test/v1/wallet/balance", nil)
+		if _, err := auth.roundTrip(req, rt); err != nil {
+			t.Fatalf("round trip fail: %v", err)
+		}
+	}
+	if len(sent) != 2 || sent[0] != "Bearer token-1" || sent[1] != "Bearer token-2" {
+		t.Fatalf("the token about to expire should be refreshed, sent %v", sent)
+	}
+}
+
+func TestTokenSourceRetryBody(t *testing.T) {
+	src := &countingTokens{tokens: []*Token{{AccessToken: "token-1"}, {AccessToken: "token-2"}}}
+	auth := &tokenAuth{src: src}
+	var bodies []string
+	rt := func(req *http.Request) (*http.Response, error) {
+		data, _ := ioutil.ReadAll(req.Body)
+		bodies = append(bodies, req.Header.Get(AuthorizationHeader)+" "+string(data))
+		status := http.StatusUnauthorized
+		if req.Header.Get(AuthorizationHeader) == "Bearer token-2" {
+			status = http.StatusOK
+		}
+		return &http.Response{StatusCode: status, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+	}
+
+	req, _ := http.NewRequest("POST", "http://wallet.test/v1/wallet/register", strings.NewReader("payload"))
+	resp, err := auth.roundTrip(req, rt)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("round trip fail: %v", err)
+	}
+	if len(bodies) != 2 || bodies[0] != "Bearer token-1 payload" || bodies[1] != "Bearer token-2 payload" {
+		t.Fatalf("the body should be sent again, got %v", bodies)
+	}
+
+	// a second rejection is returned, the request is sent once more only
+	bodies = nil
+	src.tokens = append(src.tokens, &Token{AccessToken: "token-3"})
+	auth.tok = &Token{AccessToken: "token-0"}
+	req, _ = http.NewRequest("POST", "http://wallet.test/v1/wallet/register", strings.NewReader("payload"))
+	if resp, err = auth.roundTrip(req, rt); err != nil || resp.StatusCode != http.StatusUnauthorized || len(bodies) != 2 {
+		t.Fatalf("the second rejection should be returned, got %v %v", bodies, err)
+	}
+}
+
+func TestTokenSourceHeaderAndError(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	client.SetTokenSource(TokenSourceFunc(func() (*Token, error) {
+		return nil, fmt.Errorf("identity provider down")
+	}))
+	defer client.SetTokenSource(nil)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/balance").
+		MatchHeader(AuthorizationHeader, "Bearer caller-token").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletBalance{}))
+	header := http.Header{}
+	header.Set(AuthorizationHeader, "Bearer caller-token")
+	if _, err := client.GetWalletBalance(header, "did:axn:001"); err != nil {
+		t.Fatalf("the Authorization header of the caller should take precedence: %v", err)
+	}
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/balance").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletBalance{}))
+	if _, err := client.GetWalletBalance(http.Header{}, "did:axn:001"); err == nil || !strings.Contains(err.Error(), "identity provider down") {
+		t.Fatalf("the token source error should be returned, got %v", err)
+	}
+}
+
+func TestWithTokenSource(t *testing.T) {
+	client, err := New(WithBaseURL("http://127.0.0.1:8006"), WithTokenSource(TokenSourceFunc(func() (*Token, error) {
+		return &Token{AccessToken: "token"}, nil
+	})))
+	if err != nil {
+		t.Fatalf("new client fail: %v", err)
+	}
+	if client.middleware.tokens == nil {
+		t.Fatalf("the token source should be set")
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+)
+
+// Call describes a call to the wallet gateway, as passed to the Tracer.
+//
+type Call struct {
+	// Method and Endpoint are the http method and path of the request
+	Method   string
+	Endpoint string
+	// Params are the query params of the request, the wallet IDs and
+	// amounts are redacted in privacy mode, see SetPrivacyMode
+	Params url.Values
+	// Header is the request header, the tracer adds the trace context
+	// headers to it to propagate the trace to the gateway
+	Header http.Header
+}
+
+// WalletID returns the wallet DID of the queries about a wallet, empty for
+// the other calls. It is the Redact hash of the DID in privacy mode.
+//
+func (c *Call) WalletID() string {
+	return c.Params.Get("id")
+}
+
+// TxType returns the transaction type of the transaction calls, e.g.
+// "tokens/transfer" for the colored token transfer proposal, empty for the
+// other calls.
+//
+func (c *Call) TxType() string {
+	i := strings.Index(c.Endpoint, "/transaction/")
+	if i < 0 {
+		return ""
+	}
+	return strings.TrimSuffix(c.Endpoint[i+len("/transaction/"):], "/prepare")
+}
+
+// InvokeMode returns the blockchain invoking mode of the call.
+//
+func (c *Call) InvokeMode() string {
+	if mode := c.Header.Get(InvokeModeHeader); mode != "" {
+		return mode
+	}
+	return InvokeModeAsync
+}
+
+// Tracer traces the calls to the wallet gateway, e.g. with OpenTelemetry
+// spans, see the tracing/otel package.
+//
+type Tracer interface {
+	// StartCall starts tracing the call made in ctx, the context of the
+	// client, and returns the function ending it with the call error.
+	StartCall(ctx context.Context, call *Call) (end func(err error))
+}
+
+// SetTracer sets the tracer of the calls to the wallet gateway, nil
+// disables tracing, which is the default.
+//
+func (w *WalletClient) SetTracer(tracer Tracer) {
+	w.tracer = tracer
+}
+
+// callTrace traces and measures a call.
+type callTrace struct {
+	w     *WalletClient
+	call  *Call
+	start time.Time
+	end   func(err error)
+}
+
+// startCall starts tracing and measuring the call of r, the headers added
+// by the tracer are set on r. It returns nil without tracer nor metrics.
+func (w *WalletClient) startCall(r *restapi.Request, method, path string, params url.Values, header http.Header) *callTrace {
+	if w.tracer == nil && w.metrics == nil {
+		return nil
+	}
+	t := &callTrace{
+		w: w,
+		call: &Call{
+			Method:   method,
+			Endpoint: path,
+			Params:   w.sensitiveParams(params),
+			Header:   ApplyOptions(header),
+		},
+		start: time.Now(),
+	}
+	if w.tracer != nil {
+		t.end = w.tracer.StartCall(w.Context(), t.call)
+		r.SetHeaders(t.call.Header)
+	}
+	return t
+}
+
+// uploaded reports the file bytes sent by the call.
+func (t *callTrace) uploaded(n int64) {
+	if t != nil && t.w.metrics != nil {
+		t.w.metrics.ObserveUpload(t.call, n)
+	}
+}
+
+// finish ends the call with its error.
+func (t *callTrace) finish(err error) {
+	if t == nil {
+		return
+	}
+	if t.end != nil {
+		t.end(err)
+	}
+	if t.w.metrics != nil {
+		t.w.metrics.ObserveCall(t.call, time.Since(t.start), err)
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+// fakeTracer records the calls and propagates a fixed trace context
+type fakeTracer struct {
+	calls []*Call
+	errs  []error
+}
+
+func (f *fakeTracer) StartCall(ctx context.Context, call *Call) func(err error) {
+	call.Header.Set("Traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	f.calls = append(f.calls, call)
+	return func(err error) {
+		f.errs = append(f.errs, err)
+	}
+}
+
+func TestCallInfo(t *testing.T) {
+	call := &Call{
+		Endpoint: "/v2/transaction/tokens/transfer/prepare",
+		Header:   NewHeader(WithSyncInvoke()),
+	}
+	if call.TxType() != "tokens/transfer" || call.InvokeMode() != InvokeModeSync || call.WalletID() != "" {
+		t.Fatalf("call info should be taken from the endpoint and the header")
+	}
+	call = &Call{Endpoint: "/v1/wallet/balance", Params: map[string][]string{"id": {"did:axn:001"}}, Header: http.Header{}}
+	if call.TxType() != "" || call.InvokeMode() != InvokeModeAsync || call.WalletID() != "did:axn:001" {
+		t.Fatalf("call info should be taken from the params")
+	}
+}
+
+func TestSetTracer(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	tracer := &fakeTracer{}
+	client.SetTracer(tracer)
+	defer client.SetTracer(nil)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/balance").
+		MatchHeader("Traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletBalance{}))
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/info").
+		Reply(200).
+		JSON(&rtstructs.Response{ErrCode: 8000, ErrMessage: "wallet not found"})
+
+	header := http.Header{}
+	if _, err := client.GetWalletBalance(header, "did:axn:001"); err != nil {
+		t.Fatalf("get wallet balance fail: %v", err)
+	}
+	if len(header) != 0 {
+		t.Fatalf("caller header should not be modified")
+	}
+	if _, err := client.GetWalletInfo(header, "did:axn:001"); err == nil {
+		t.Fatalf("err should not be nil when the gateway returns an error code")
+	}
+
+	if len(tracer.calls) != 2 || tracer.calls[0].Endpoint != "/v1/wallet/balance" || tracer.calls[0].WalletID() != "did:axn:001" {
+		t.Fatalf("calls should be traced, got %v", tracer.calls)
+	}
+	if tracer.errs[0] != nil {
+		t.Fatalf("first call should end without error")
+	}
+	if e, ok := tracer.errs[1].(*Error); !ok || e.ErrCode != 8000 {
+		t.Fatalf("second call should end with the gateway error, got %v", tracer.errs[1])
+	}
+}
+
+func TestTracerPrivacyMode(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	tracer := &fakeTracer{}
+	client.SetTracer(tracer)
+	client.SetPrivacyMode(true)
+
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/balance").
+		MatchParam("id", "did:axn:001").
+		Reply(200).
+		JSON(payloadResponse(t, &wallet.WalletBalance{}))
+	if _, err := client.GetWalletBalance(http.Header{}, "did:axn:001"); err != nil {
+		t.Fatalf("get wallet balance fail: %v", err)
+	}
+	if len(tracer.calls) != 1 || tracer.calls[0].WalletID() != Redact("did:axn:001") {
+		t.Fatalf("the wallet id should be redacted from the traces, got %v", tracer.calls)
+	}
+}
@@ -20,17 +20,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"reflect"
+	"net/url"
 	"strconv"
 
-	"github.com/arxanchain/sdk-go-common/errors"
 	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
-	"github.com/arxanchain/sdk-go-common/rest"
-	restapi "github.com/arxanchain/sdk-go-common/rest/api"
-	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
 	"github.com/arxanchain/sdk-go-common/structs/did"
 	"github.com/arxanchain/sdk-go-common/structs/pki"
 	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/sdk-go-common/utils"
 )
 
 /////////////////////////////////////////////////////////////////////////////////////////////////
@@ -53,6 +50,12 @@ func (w *WalletClient) IssueCToken(header http.Header, body *wallet.IssueBody, s
 		err = fmt.Errorf("request payload invalid")
 		return
 	}
+	// the fee is estimated in a copy, the body of the caller is left as is
+	issue := *body
+	body = &issue
+	if err = w.fillFee(header, TxKindIssueCToken, body, &body.Fee); err != nil {
+		return
+	}
 
 	if w.s != nil {
 		signParams, err = w.queryPrivateKey(header, signParams)
@@ -102,37 +105,8 @@ func (w *WalletClient) SendIssueCTokenProposal(header http.Header, body *wallet.
 		return nil, err
 	}
 
-	// Build http request
-	r := w.c.NewRequest("POST", "/v2/transaction/tokens/issue/prepare")
-	r.SetHeaders(header)
-	r.SetBody(body)
-
-	// Do http request
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	// Parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
-		return nil, err
-	}
-
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
-		return nil, err
-	}
-
-	respPayload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
-		return nil, err
-	}
-
 	issueRsp = &wallet.IssueCTokenPrepareResponse{}
-	if err = json.Unmarshal([]byte(respPayload), issueRsp); err != nil {
+	if err = w.doJSON(header, "POST", "/v2/transaction/tokens/issue/prepare", nil, body, issueRsp); err != nil {
 		return nil, err
 	}
 	return issueRsp, nil
@@ -158,6 +132,12 @@ func (w *WalletClient) IssueAsset(header http.Header, body *wallet.IssueAssetBod
 		err = fmt.Errorf("request payload invalid")
 		return
 	}
+	// the fee is estimated in a copy, the body of the caller is left as is
+	issue := *body
+	body = &issue
+	if err = w.fillFee(header, TxKindIssueAsset, body, &body.Fee); err != nil {
+		return
+	}
 
 	if w.s != nil {
 		signParams, err = w.queryPrivateKey(header, signParams)
@@ -201,35 +181,7 @@ func (w *WalletClient) SendIssueAssetProposal(header http.Header, body *wallet.I
 		return nil, err
 	}
 
-	// Build http request
-	r := w.c.NewRequest("POST", "/v2/transaction/assets/issue/prepare")
-	r.SetHeaders(header)
-	r.SetBody(body)
-
-	// Do http request
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	// Parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
-		return nil, err
-	}
-
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
-		return nil, err
-	}
-
-	respPayload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
-		return nil, err
-	}
-	err = json.Unmarshal([]byte(respPayload), &result)
+	err = w.doJSON(header, "POST", "/v2/transaction/assets/issue/prepare", nil, body, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -256,6 +208,26 @@ func (w *WalletClient) TransferCToken(header http.Header, body *wallet.TransferC
 		err = fmt.Errorf("request payload invalid")
 		return
 	}
+	// the fee is estimated in a copy, the body of the caller is left as is
+	transfer := *body
+	body = &transfer
+	if err = w.fillFee(header, TxKindTransferCToken, body, &body.Fee); err != nil {
+		return
+	}
+	return w.transferCToken(header, body, body, signParams)
+}
+
+// transferCToken runs the counterparty checks of the transfer, sends the
+// proposal body to get wallet.Tx, signs and submits them.
+func (w *WalletClient) transferCToken(header http.Header, body *wallet.TransferCTokenBody, proposal interface{}, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	if err = w.checkCounterpartyKYC(header, body.To); err != nil {
+		return
+	}
+	screened := w.screenAsync(screeningTypeCToken, body.From, body.To, body)
+	submitHeader, err := w.travelRuleHeader(header, body)
+	if err != nil {
+		return
+	}
 
 	if w.s != nil {
 		signParams, err = w.queryPrivateKey(header, signParams)
@@ -265,8 +237,8 @@ func (w *WalletClient) TransferCToken(header http.Header, body *wallet.TransferC
 	}
 
 	// 1 send transfer proposal to get wallet.Tx
-	txs, err := w.SendTransferCTokenProposal(header, body)
-	if err != nil {
+	var txs []*pw.TX
+	if err = w.doJSON(header, "POST", "/v2/transaction/tokens/transfer/prepare", nil, proposal, &txs); err != nil {
 		return nil, err
 	}
 
@@ -277,8 +249,13 @@ func (w *WalletClient) TransferCToken(header http.Header, body *wallet.TransferC
 		return nil, err
 	}
 
+	// wait for the screening decision before submission
+	if err = screened(); err != nil {
+		return nil, err
+	}
+
 	// 3 call ProcessTx to transfer formally
-	return w.ProcessTx(header, txs)
+	return w.ProcessTx(submitHeader, txs)
 }
 
 // SendTransferCTokenProposal is used to send transfer colored tokens proposal to get wallet.Tx to be signed.
@@ -299,35 +276,7 @@ func (w *WalletClient) SendTransferCTokenProposal(header http.Header, body *wall
 		return nil, err
 	}
 
-	// Build http request
-	r := w.c.NewRequest("POST", "/v2/transaction/tokens/transfer/prepare")
-	r.SetHeaders(header)
-	r.SetBody(body)
-
-	// Do http request
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	// Parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
-		return nil, err
-	}
-
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
-		return nil, err
-	}
-
-	respPayload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
-		return nil, err
-	}
-	err = json.Unmarshal([]byte(respPayload), &result)
+	err = w.doJSON(header, "POST", "/v2/transaction/tokens/transfer/prepare", nil, body, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -354,6 +303,17 @@ func (w *WalletClient) TransferAsset(header http.Header, body *wallet.TransferAs
 		err = fmt.Errorf("request payload invalid")
 		return
 	}
+	// the fee is estimated in a copy, the body of the caller is left as is
+	transfer := *body
+	body = &transfer
+	if err = w.fillFee(header, TxKindTransferAsset, body, &body.Fee); err != nil {
+		return
+	}
+
+	if err = w.checkCounterpartyKYC(header, body.To); err != nil {
+		return
+	}
+	screened := w.screenAsync(screeningTypeAsset, body.From, body.To, body)
 
 	if w.s != nil {
 		signParams, err = w.queryPrivateKey(header, signParams)
@@ -375,6 +335,11 @@ func (w *WalletClient) TransferAsset(header http.Header, body *wallet.TransferAs
 		return nil, err
 	}
 
+	// wait for the screening decision before submission
+	if err = screened(); err != nil {
+		return nil, err
+	}
+
 	// 3 call ProcessTx to transfer formally
 	return w.ProcessTx(header, txs)
 }
@@ -397,35 +362,7 @@ func (w *WalletClient) SendTransferAssetProposal(header http.Header, body *walle
 		return nil, err
 	}
 
-	// Build http request
-	r := w.c.NewRequest("POST", "/v2/transaction/assets/transfer/prepare")
-	r.SetHeaders(header)
-	r.SetBody(body)
-
-	// Do http request
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	// Parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
-		return nil, err
-	}
-
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
-		return nil, err
-	}
-
-	respPayload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
-		return nil, err
-	}
-	err = json.Unmarshal([]byte(respPayload), &result)
+	err = w.doJSON(header, "POST", "/v2/transaction/assets/transfer/prepare", nil, body, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -444,9 +381,11 @@ func (w *WalletClient) SignTxs(txs []*pw.TX, signParams *pki.SignatureParam) (er
 				return err
 			}
 
-			w.SignTx(tx, platformSignParams)
-		} else {
-			w.SignTx(tx, signParams)
+			if err = w.SignTx(tx, platformSignParams); err != nil {
+				return err
+			}
+		} else if err = w.SignTx(tx, signParams); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -469,14 +408,19 @@ func (w *WalletClient) SignTx(tx *pw.TX, signParams *pki.SignatureParam) (err er
 		if utxoSignature.PublicKey == nil {
 			continue
 		}
-		signatureBody, err := buildSignatureBodyBase(signParams, utxoSignature.PublicKey)
+		params, err := w.withNonce(signParams)
+		if err != nil {
+			return err
+		}
+		signatureBody, err := w.signRaw(params, utxoSignature.PublicKey)
 		if err != nil {
 			err = fmt.Errorf("sign error: %v", err)
 			return err
 		}
+		w.dumpSignature(SignKindUTXO, params, utxoSignature.PublicKey, utils.EncodeBase64([]byte(signatureBody.SignatureValue)))
 		utxoSignature.Signature = []byte(signatureBody.SignatureValue)
-		utxoSignature.Nonce = signParams.Nonce
-		utxoSignature.Creator = string(signParams.Creator)
+		utxoSignature.Nonce = signatureBody.Nonce
+		utxoSignature.Creator = string(signatureBody.Creator)
 		signData, err := json.Marshal(utxoSignature)
 		if err != nil {
 			return err
@@ -494,44 +438,55 @@ func (w *WalletClient) ProcessTx(header http.Header, txs []*pw.TX) (result *wall
 		return nil, err
 	}
 
-	// Build http request
-	r := w.c.NewRequest("POST", "/v2/transaction/process")
-	r.SetHeaders(header)
-
 	// Build request payload
 	txBody := &wallet.ProcessTxBody{
 		Txs: txs,
 	}
-	r.SetBody(txBody)
 
-	// Do http request
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
+	err = w.doJSON(header, "POST", "/v2/transaction/process", nil, txBody, &result)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	return result, nil
+}
 
-	// Parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
-		return nil, err
-	}
+// processProposal sends a transaction proposal, signs the returned UTXOs and processes them.
+func (w *WalletClient) processProposal(header http.Header, path string, body interface{}, signParams *pki.SignatureParam) (result *wallet.WalletResponse, err error) {
+	return w.processScreenedProposal(header, path, body, signParams, nil)
+}
 
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
-		return nil, err
+// processScreenedProposal is processProposal waiting for screened, if not
+// nil, before processing the signed UTXOs.
+func (w *WalletClient) processScreenedProposal(header http.Header, path string, body interface{}, signParams *pki.SignatureParam, screened func() error) (result *wallet.WalletResponse, err error) {
+	if w.s != nil {
+		signParams, err = w.queryPrivateKey(header, signParams)
+		if err != nil {
+			return
+		}
 	}
 
-	respPayload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
+	// 1 send proposal to get wallet.Tx
+	var txs []*pw.TX
+	if err = w.doJSON(header, "POST", path, nil, body, &txs); err != nil {
 		return nil, err
 	}
-	err = json.Unmarshal([]byte(respPayload), &result)
+
+	// 2 sign public key as signature
+	err = w.SignTxs(txs, signParams)
 	if err != nil {
+		err = fmt.Errorf("sign Txs error: %v", err)
 		return nil, err
 	}
-	return result, nil
+
+	// wait for the screening decision before submission
+	if screened != nil {
+		if err = screened(); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3 call ProcessTx to process formally
+	return w.ProcessTx(header, txs)
 }
 
 // QueryTransactionLogs is used to query transaction logs.
@@ -542,8 +497,11 @@ func (w *WalletClient) ProcessTx(header http.Header, txs []*pw.TX) (result *wall
 // other: in && out
 // num, page: count and page to be returned
 //
+// Use QueryTransactionLogsPage or TransactionLogs to page through the logs
+// of busy wallets or to filter them.
+//
 func (w *WalletClient) QueryTransactionLogs(header http.Header, id did.Identifier, txType string, num, page int32) (result []*pw.UTXO, err error) {
-	fmt.Printf("*****in wallet-sdk-go id: %v, txType: %v, num: %v, page: %v\n", id, txType, num, page)
+	w.logger().Debugf("Query transaction logs id: %v, txType: %v, num: %v, page: %v", w.sensitive(string(id)), txType, num, page)
 	if id == "" {
 		err = fmt.Errorf("request id invalid")
 		return
@@ -557,39 +515,13 @@ func (w *WalletClient) QueryTransactionLogs(header http.Header, id did.Identifie
 
 	numStr := strconv.Itoa(int(num))
 	pageStr := strconv.Itoa(int(page))
-	// Build http request
-	r := w.c.NewRequest("GET", "/v2/transaction/logs")
-	r.SetHeaders(header)
-	r.SetParam("id", string(id))
-	r.SetParam("type", txType)
-	r.SetParam("num", numStr)
-	r.SetParam("page", pageStr)
-
-	// Do http request
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-
-	// Parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
-		return
-	}
-
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
-		return
-	}
+	params := url.Values{}
+	params.Set("id", string(id))
+	params.Set("type", txType)
+	params.Set("num", numStr)
+	params.Set("page", pageStr)
 
-	respPayload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
-		return
-	}
-
-	err = json.Unmarshal([]byte(respPayload), &result)
+	err = w.doJSON(header, "GET", "/v2/transaction/logs", params, nil, &result)
 
 	return
 }
@@ -612,38 +544,12 @@ func (w *WalletClient) QueryTransactionUTXO(header http.Header, id did.Identifie
 
 	numStr := strconv.Itoa(int(num))
 	pageStr := strconv.Itoa(int(page))
-	// Build http request
-	r := w.c.NewRequest("GET", "/v2/transaction/utxo")
-	r.SetHeaders(header)
-	r.SetParam("id", string(id))
-	r.SetParam("num", numStr)
-	r.SetParam("page", pageStr)
-
-	// Do http request
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-
-	// Parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
-		return
-	}
+	params := url.Values{}
+	params.Set("id", string(id))
+	params.Set("num", numStr)
+	params.Set("page", pageStr)
 
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
-		return
-	}
-
-	respPayload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
-		return
-	}
-
-	err = json.Unmarshal([]byte(respPayload), &result)
+	err = w.doJSON(header, "GET", "/v2/transaction/utxo", params, nil, &result)
 
 	return
 }
@@ -666,38 +572,12 @@ func (w *WalletClient) QueryTransactionSTXO(header http.Header, id did.Identifie
 
 	numStr := strconv.Itoa(int(num))
 	pageStr := strconv.Itoa(int(page))
-	// Build http request
-	r := w.c.NewRequest("GET", "/v2/transaction/stxo")
-	r.SetHeaders(header)
-	r.SetParam("id", string(id))
-	r.SetParam("num", numStr)
-	r.SetParam("page", pageStr)
-
-	// Do http request
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-
-	// Parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
-		return
-	}
-
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
-		return
-	}
-
-	respPayload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
-		return
-	}
+	params := url.Values{}
+	params.Set("id", string(id))
+	params.Set("num", numStr)
+	params.Set("page", pageStr)
 
-	err = json.Unmarshal([]byte(respPayload), &result)
+	err = w.doJSON(header, "GET", "/v2/transaction/stxo", params, nil, &result)
 
 	return
 }
@@ -0,0 +1,241 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+)
+
+// Defaults of the transport settings, the ones of http.DefaultTransport.
+//
+const (
+	DefaultDialTimeout         = 30 * time.Second
+	DefaultKeepAlive           = 30 * time.Second
+	DefaultTLSHandshakeTimeout = 10 * time.Second
+	DefaultIdleConnTimeout     = 90 * time.Second
+	DefaultMaxIdleConns        = 100
+)
+
+// clientOptions are the settings of a wallet client.
+type clientOptions struct {
+	// configuration overrides, see New
+	address    string
+	apiKey     string
+	httpClient *http.Client
+	// client settings
+	logger    Logger
+	signers   map[did.Identifier]Signer
+	retry     *RetryPolicy
+	endpoints map[string]string
+	tokens    TokenSource
+
+	// http settings
+	transport       http.RoundTripper
+	timeout         time.Duration
+	proxy           *url.URL
+	noProxy         bool
+	dialTimeout     time.Duration
+	keepAlive       time.Duration
+	tlsTimeout      time.Duration
+	idleConnTimeout time.Duration
+	maxIdleConns    int
+	noKeepAlives    bool
+	// tuned is set by the options changing the transport settings
+	tuned bool
+	err   error
+}
+
+// ClientOption sets the wallet client, see New and NewWalletClient.
+//
+type ClientOption func(o *clientOptions)
+
+// WithTransport sends the requests with rt, e.g. a transport shared with
+// other clients, instead of the transport of the configuration http client.
+// The other transport options cannot be used with it.
+//
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(o *clientOptions) {
+		o.transport = rt
+	}
+}
+
+// WithTimeout bounds the duration of the requests, including reading the
+// response body, zero means no limit.
+//
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithProxy sends the requests through the proxy at proxyURL, e.g.
+// "http://proxy.corp:3128", instead of the proxy of the environment
+// variables. An empty proxyURL disables the proxy.
+//
+func WithProxy(proxyURL string) ClientOption {
+	return func(o *clientOptions) {
+		o.tuned = true
+		if proxyURL == "" {
+			o.proxy, o.noProxy = nil, true
+			return
+		}
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Host == "" {
+			o.err = fmt.Errorf("proxy url %q invalid", proxyURL)
+			return
+		}
+		o.proxy, o.noProxy = u, false
+	}
+}
+
+// WithDialTimeout bounds the time to establish the connections.
+//
+func WithDialTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.tuned = true
+		o.dialTimeout = timeout
+	}
+}
+
+// WithTLSHandshakeTimeout bounds the time of the TLS handshakes.
+//
+func WithTLSHandshakeTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.tuned = true
+		o.tlsTimeout = timeout
+	}
+}
+
+// WithKeepAlive sets the keep-alive period of the connections, and how
+// long idle connections are kept open and how many of them, zero values
+// keep the defaults.
+//
+func WithKeepAlive(period, idleTimeout time.Duration, maxIdleConns int) ClientOption {
+	return func(o *clientOptions) {
+		o.tuned = true
+		o.keepAlive = period
+		o.idleConnTimeout = idleTimeout
+		o.maxIdleConns = maxIdleConns
+	}
+}
+
+// WithoutKeepAlives opens a connection per request.
+//
+func WithoutKeepAlives() ClientOption {
+	return func(o *clientOptions) {
+		o.tuned = true
+		o.noKeepAlives = true
+	}
+}
+
+// apply returns a copy of the http client with the options applied.
+func (o *clientOptions) apply(client *http.Client) (*http.Client, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	c := *client
+	if o.timeout > 0 {
+		c.Timeout = o.timeout
+	}
+	if o.transport != nil {
+		if o.tuned {
+			return nil, fmt.Errorf("transport settings cannot be used with a custom transport")
+		}
+		c.Transport = o.transport
+		return &c, nil
+	}
+	if !o.tuned {
+		return &c, nil
+	}
+
+	var base *http.Transport
+	switch t := c.Transport.(type) {
+	case nil:
+	case *http.Transport:
+		base = t
+	default:
+		return nil, fmt.Errorf("transport settings need a *http.Transport, not %T", c.Transport)
+	}
+	c.Transport = o.newTransport(base)
+	return &c, nil
+}
+
+// newTransport returns a transport with the settings of base, nil for the
+// defaults, and of the options.
+func (o *clientOptions) newTransport(base *http.Transport) *http.Transport {
+	t := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		TLSHandshakeTimeout:   DefaultTLSHandshakeTimeout,
+		IdleConnTimeout:       DefaultIdleConnTimeout,
+		MaxIdleConns:          DefaultMaxIdleConns,
+		ExpectContinueTimeout: time.Second,
+	}
+	var tlsConfig *tls.Config
+	if base != nil {
+		t.Proxy = base.Proxy
+		t.DialContext = base.DialContext
+		t.TLSHandshakeTimeout = base.TLSHandshakeTimeout
+		t.DisableKeepAlives = base.DisableKeepAlives
+		t.DisableCompression = base.DisableCompression
+		t.MaxIdleConns = base.MaxIdleConns
+		t.MaxIdleConnsPerHost = base.MaxIdleConnsPerHost
+		t.IdleConnTimeout = base.IdleConnTimeout
+		t.ResponseHeaderTimeout = base.ResponseHeaderTimeout
+		t.ExpectContinueTimeout = base.ExpectContinueTimeout
+		tlsConfig = base.TLSClientConfig
+	}
+	if tlsConfig != nil {
+		t.TLSClientConfig = tlsConfig.Clone()
+	}
+
+	if o.proxy != nil {
+		t.Proxy = http.ProxyURL(o.proxy)
+	} else if o.noProxy {
+		t.Proxy = nil
+	}
+	if o.dialTimeout > 0 || o.keepAlive > 0 || t.DialContext == nil {
+		dialer := &net.Dialer{Timeout: DefaultDialTimeout, KeepAlive: DefaultKeepAlive}
+		if o.dialTimeout > 0 {
+			dialer.Timeout = o.dialTimeout
+		}
+		if o.keepAlive > 0 {
+			dialer.KeepAlive = o.keepAlive
+		}
+		t.DialContext = dialer.DialContext
+	}
+	if o.tlsTimeout > 0 {
+		t.TLSHandshakeTimeout = o.tlsTimeout
+	}
+	if o.idleConnTimeout > 0 {
+		t.IdleConnTimeout = o.idleConnTimeout
+	}
+	if o.maxIdleConns > 0 {
+		t.MaxIdleConns = o.maxIdleConns
+		t.MaxIdleConnsPerHost = o.maxIdleConns
+	}
+	if o.noKeepAlives {
+		t.DisableKeepAlives = true
+	}
+	return t
+}
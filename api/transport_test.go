@@ -0,0 +1,100 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+)
+
+func TestClientOptionsProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		proxied = append(proxied, req.URL.String())
+		fmt.Fprint(rw, `{"ErrCode":0,"Payload":"{\"id\":\"did:axn:001\"}"}`)
+	}))
+	defer proxy.Close()
+
+	client, err := NewWalletClient(&restapi.Config{Address: "http://wallet.test:8006", HttpClient: &http.Client{}},
+		WithProxy(proxy.URL),
+		WithDialTimeout(time.Second),
+		WithTLSHandshakeTimeout(time.Second),
+		WithKeepAlive(time.Second, time.Minute, 4),
+		WithTimeout(5*time.Second),
+	)
+	if err != nil {
+		t.Fatalf("new wallet client fail: %v", err)
+	}
+	if _, err = client.GetWalletInfo(http.Header{}, "did:axn:001"); err != nil {
+		t.Fatalf("get wallet info fail: %v", err)
+	}
+	if len(proxied) != 1 || !strings.HasPrefix(proxied[0], "http://wallet.test:8006/") {
+		t.Fatalf("request should go through the proxy, got %v", proxied)
+	}
+}
+
+func TestClientOptionsTransport(t *testing.T) {
+	var o clientOptions
+	for _, opt := range []ClientOption{WithProxy(""), WithDialTimeout(time.Second), WithKeepAlive(0, time.Minute, 4), WithoutKeepAlives(), WithTimeout(time.Minute)} {
+		opt(&o)
+	}
+	base := &http.Client{Transport: &http.Transport{MaxIdleConns: 8, ResponseHeaderTimeout: time.Second}}
+	c, err := o.apply(base)
+	if err != nil {
+		t.Fatalf("apply options fail: %v", err)
+	}
+	if c == base || c.Timeout != time.Minute {
+		t.Fatalf("timeout should be set on a copy of the http client")
+	}
+	tr, ok := c.Transport.(*http.Transport)
+	if !ok || tr == base.Transport {
+		t.Fatalf("transport should be a new *http.Transport, got %T", c.Transport)
+	}
+	if tr.Proxy != nil || !tr.DisableKeepAlives || tr.IdleConnTimeout != time.Minute || tr.MaxIdleConns != 4 || tr.ResponseHeaderTimeout != time.Second {
+		t.Fatalf("transport settings should be applied over the base transport")
+	}
+	if base.Transport.(*http.Transport).MaxIdleConns != 8 {
+		t.Fatalf("base transport should not be modified")
+	}
+
+	// custom round tripper
+	rt := &middlewareTransport{}
+	o = clientOptions{}
+	WithTransport(rt)(&o)
+	if c, err = o.apply(&http.Client{}); err != nil || c.Transport != rt {
+		t.Fatalf("custom transport should be used, got %v", err)
+	}
+	WithDialTimeout(time.Second)(&o)
+	if _, err = o.apply(&http.Client{}); err == nil {
+		t.Fatalf("transport settings should fail with a custom transport")
+	}
+	o = clientOptions{}
+	WithDialTimeout(time.Second)(&o)
+	if _, err = o.apply(&http.Client{Transport: rt}); err == nil {
+		t.Fatalf("transport settings should fail without a *http.Transport")
+	}
+
+	if _, err = NewWalletClient(&restapi.Config{Address: "http://wallet.test:8006"}, WithProxy("::bad")); err == nil {
+		t.Fatalf("invalid proxy url should fail")
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/ecdsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/wallet-sdk-go/crypto/ecies"
+)
+
+// TravelRuleEnvelopeHeader is the http header carrying the travel-rule
+// envelope of a transfer when it is submitted.
+const TravelRuleEnvelopeHeader = "Travel-Rule-Envelope"
+
+const travelRuleEnvelopeVersion = "1"
+
+// TravelRuleParty is the IVMS-style identity information of a transfer party.
+//
+type TravelRuleParty struct {
+	Name          string `json:"name"`
+	AccountId     string `json:"account_id"`
+	Address       string `json:"address,omitempty"`
+	NationalId    string `json:"national_id,omitempty"`
+	DateOfBirth   string `json:"date_of_birth,omitempty"`
+	PlaceOfBirth  string `json:"place_of_birth,omitempty"`
+	CustomerId    string `json:"customer_id,omitempty"`
+	VASP          string `json:"vasp"`
+	VASPCountry   string `json:"vasp_country,omitempty"`
+	VASPLegalName string `json:"vasp_legal_name,omitempty"`
+}
+
+// TravelRuleInfo is the originator and beneficiary information of a transfer.
+//
+type TravelRuleInfo struct {
+	Originator  *TravelRuleParty `json:"originator"`
+	Beneficiary *TravelRuleParty `json:"beneficiary"`
+}
+
+// TravelRuleEnvelope is the travel-rule information encrypted to the
+// beneficiary VASP, only the beneficiary VASP can open it.
+//
+type TravelRuleEnvelope struct {
+	Version         string `json:"version"`
+	Algorithm       string `json:"algorithm"`
+	BeneficiaryVASP string `json:"beneficiary_vasp"`
+	Ciphertext      []byte `json:"ciphertext"`
+}
+
+// TravelRuleProvider supplies the travel-rule information of a transfer, and
+// the public key of the beneficiary VASP to encrypt it to.
+//
+type TravelRuleProvider interface {
+	TravelRuleInfo(body *wallet.TransferCTokenBody) (info *TravelRuleInfo, vaspKey *ecdsa.PublicKey, err error)
+}
+
+type travelRule struct {
+	threshold int64
+	provider  TravelRuleProvider
+}
+
+// SetTravelRule makes TransferCToken attach a travel-rule envelope to every
+// transfer whose total amount reaches threshold, nil provider disables it.
+//
+func (w *WalletClient) SetTravelRule(threshold int64, provider TravelRuleProvider) {
+	if provider == nil {
+		w.travelRule = nil
+		return
+	}
+	w.travelRule = &travelRule{threshold: threshold, provider: provider}
+}
+
+// travelRuleInfo calls the provider, recovering its panics.
+func (tr *travelRule) travelRuleInfo(body *wallet.TransferCTokenBody) (info *TravelRuleInfo, vaspKey *ecdsa.PublicKey, err error) {
+	defer RecoverPanic(&err)
+	return tr.provider.TravelRuleInfo(body)
+}
+
+// SealTravelRuleEnvelope encrypts the travel-rule information to the beneficiary VASP public key.
+//
+func SealTravelRuleEnvelope(info *TravelRuleInfo, vaspKey *ecdsa.PublicKey) (*TravelRuleEnvelope, error) {
+	if info == nil || info.Originator == nil || info.Beneficiary == nil {
+		return nil, fmt.Errorf("travel rule originator and beneficiary must be set")
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := ecies.Encrypt(vaspKey, data)
+	if err != nil {
+		return nil, err
+	}
+	return &TravelRuleEnvelope{
+		Version:         travelRuleEnvelopeVersion,
+		Algorithm:       ecies.AlgorithmOf(vaspKey.Curve),
+		BeneficiaryVASP: info.Beneficiary.VASP,
+		Ciphertext:      ciphertext,
+	}, nil
+}
+
+// OpenTravelRuleEnvelope decrypts a travel-rule envelope with the beneficiary VASP private key.
+//
+func OpenTravelRuleEnvelope(env *TravelRuleEnvelope, vaspKey *ecdsa.PrivateKey) (*TravelRuleInfo, error) {
+	if env == nil {
+		return nil, fmt.Errorf("travel rule envelope must be set")
+	}
+	if vaspKey == nil {
+		return nil, fmt.Errorf("travel rule vasp key must be set")
+	}
+	if env.Algorithm != ecies.AlgorithmOf(vaspKey.Curve) {
+		return nil, fmt.Errorf("travel rule envelope algorithm %s not supported", env.Algorithm)
+	}
+	data, err := ecies.Decrypt(vaspKey, env.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	var info TravelRuleInfo
+	if err = json.Unmarshal(data, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// travelRuleApplies reports whether the transfer needs a travel-rule
+// envelope, its total amount reaching the threshold.
+func (w *WalletClient) travelRuleApplies(body *wallet.TransferCTokenBody) bool {
+	tr := w.travelRule
+	if tr == nil {
+		return false
+	}
+	var total int64
+	for _, token := range body.Tokens {
+		if token != nil {
+			total += token.Amount
+		}
+	}
+	return total >= tr.threshold
+}
+
+// travelRuleHeader returns the header to submit the transfer with, carrying
+// the travel-rule envelope when the transfer reaches the threshold.
+func (w *WalletClient) travelRuleHeader(header http.Header, body *wallet.TransferCTokenBody) (http.Header, error) {
+	if !w.travelRuleApplies(body) {
+		return header, nil
+	}
+
+	info, vaspKey, err := w.travelRule.travelRuleInfo(body)
+	if err != nil {
+		return nil, fmt.Errorf("get travel rule info fail: %v", err)
+	}
+	env, err := SealTravelRuleEnvelope(info, vaspKey)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		return nil, err
+	}
+
+	h := http.Header{}
+	for k, v := range header {
+		h[k] = v
+	}
+	h.Set(TravelRuleEnvelopeHeader, base64.StdEncoding.EncodeToString(data))
+	return h, nil
+}
+
+// QueryTravelRuleEnvelope is used to query the travel-rule envelope attached to a transaction.
+//
+func (w *WalletClient) QueryTravelRuleEnvelope(header http.Header, txID string) (result *TravelRuleEnvelope, err error) {
+	if txID == "" {
+		err = fmt.Errorf("transaction id invalid")
+		return
+	}
+
+	if err = w.doJSON(header, "GET", "/v2/transaction/travelrule", url.Values{"tx_id": {txID}}, nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/ecdsa"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/wallet-sdk-go/crypto/ecies"
+)
+
+type fakeTravelRuleProvider struct {
+	key *ecdsa.PublicKey
+}
+
+func (f *fakeTravelRuleProvider) TravelRuleInfo(body *wallet.TransferCTokenBody) (*TravelRuleInfo, *ecdsa.PublicKey, error) {
+	info := &TravelRuleInfo{
+		Originator:  &TravelRuleParty{Name: "Alice", AccountId: body.From, VASP: "vasp-a"},
+		Beneficiary: &TravelRuleParty{Name: "Bob", AccountId: body.To, VASP: "vasp-b"},
+	}
+	return info, f.key, nil
+}
+
+func TestTravelRuleHeader(t *testing.T) {
+	priv, err := ecies.GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	client := &WalletClient{}
+	client.SetTravelRule(1000, &fakeTravelRuleProvider{key: &priv.PublicKey})
+
+	header := http.Header{}
+	body := &wallet.TransferCTokenBody{
+		From: "did:axn:001",
+		To:   "did:axn:002",
+		Tokens: []*wallet.TokenAmount{
+			{TokenId: "ctoken-001", Amount: 600},
+		},
+	}
+
+	// below threshold, no envelope attached
+	h, err := client.travelRuleHeader(header, body)
+	if err != nil {
+		t.Fatalf("travel rule header fail: %v", err)
+	}
+	if h.Get(TravelRuleEnvelopeHeader) != "" {
+		t.Fatalf("envelope should not be attached below threshold")
+	}
+
+	body.Tokens = append(body.Tokens, &wallet.TokenAmount{TokenId: "ctoken-002", Amount: 400})
+	h, err = client.travelRuleHeader(header, body)
+	if err != nil {
+		t.Fatalf("travel rule header fail: %v", err)
+	}
+	if header.Get(TravelRuleEnvelopeHeader) != "" {
+		t.Fatalf("caller header should not be modified")
+	}
+	data, err := base64.StdEncoding.DecodeString(h.Get(TravelRuleEnvelopeHeader))
+	if err != nil {
+		t.Fatalf("decode envelope header fail: %v", err)
+	}
+	var env TravelRuleEnvelope
+	if err = json.Unmarshal(data, &env); err != nil {
+		t.Fatalf("unmarshal envelope fail: %v", err)
+	}
+	if env.BeneficiaryVASP != "vasp-b" {
+		t.Fatalf("beneficiary vasp should be vasp-b, not %s", env.BeneficiaryVASP)
+	}
+
+	info, err := OpenTravelRuleEnvelope(&env, priv)
+	if err != nil {
+		t.Fatalf("open envelope fail: %v", err)
+	}
+	if info.Originator.Name != "Alice" || info.Beneficiary.AccountId != "did:axn:002" {
+		t.Fatalf("envelope content mismatch: %+v %+v", info.Originator, info.Beneficiary)
+	}
+
+	other, _ := ecies.GenerateKey()
+	if _, err = OpenTravelRuleEnvelope(&env, other); err == nil {
+		t.Fatalf("err should not be nil when opening with another key")
+	}
+}
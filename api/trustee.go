@@ -0,0 +1,182 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/arxanchain/sdk-go-common/structs"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/safebox"
+)
+
+// ErrTrusteeDisabled is returned by the key pair trust methods when the
+// client is not configured with TrusteeKeyPairEnable.
+//
+var ErrTrusteeDisabled = fmt.Errorf("trustee key pair not enabled")
+
+// ErrSecurityCodeRequired is returned when signature params have neither a
+// private key nor the security code of a trusted key pair.
+//
+var ErrSecurityCodeRequired = fmt.Errorf("security code required to sign with trusted key pair")
+
+// ErrUntrustUnsupported is returned by UntrustKeyPair when the safebox
+// client can not delete key pairs.
+//
+var ErrUntrustUnsupported = fmt.Errorf("safebox client does not support deleting key pairs")
+
+// TrustKeyPairBody is the request body of TrustKeyPair.
+//
+type TrustKeyPairBody struct {
+	Id         did.Identifier
+	PrivateKey string
+	PublicKey  string
+}
+
+// KeyPair is a key pair returned by UntrustKeyPair.
+//
+type KeyPair struct {
+	PrivateKey string
+	PublicKey  string
+}
+
+// keyPairDeleter is implemented by the safebox clients able to delete the
+// trusted key pairs.
+type keyPairDeleter interface {
+	DeleteKeyPair(header http.Header, body *safebox.OperateKeyInfo) error
+}
+
+// safeboxHeader returns the header of a safebox request, a copy with the
+// api key of the client.
+func (w *WalletClient) safeboxHeader(header http.Header) (http.Header, error) {
+	key, err := w.apiKey()
+	if err != nil || key == "" {
+		return header, err
+	}
+	return ApplyOptions(header, WithHeader(structs.APIKeyHeader, key)), nil
+}
+
+// TrustKeyPair is used to save the key pair of a wallet in the safebox, it
+// returns the security code which replaces the private key in the signature
+// params of the wallet afterwards.
+//
+// The wallets registered by a client configured with TrusteeKeyPairEnable
+// are trusted on registration.
+//
+func (w *WalletClient) TrustKeyPair(header http.Header, body *TrustKeyPairBody) (securityCode string, err error) {
+	if w.s == nil {
+		err = ErrTrusteeDisabled
+		return
+	}
+	if body == nil || body.Id == "" || body.PrivateKey == "" || body.PublicKey == "" {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+
+	if header, err = w.safeboxHeader(header); err != nil {
+		return
+	}
+	response, err := w.s.TrusteeKeyPair(header, &safebox.SaveKeyPairRequetBody{
+		UserDid:    string(body.Id),
+		PrivateKey: body.PrivateKey,
+		PublicKey:  body.PublicKey,
+	})
+	if err != nil {
+		return
+	}
+	w.InvalidateKey(body.Id)
+	return response.Code, nil
+}
+
+// UntrustKeyPair is used to remove the key pair of a wallet from the
+// safebox, it returns the key pair, which the signature params of the
+// wallet must carry afterwards.
+//
+// ErrUntrustUnsupported is returned when the safebox client can not delete
+// key pairs.
+//
+func (w *WalletClient) UntrustKeyPair(header http.Header, id did.Identifier, securityCode string) (result *KeyPair, err error) {
+	if w.s == nil {
+		err = ErrTrusteeDisabled
+		return
+	}
+	if id == "" {
+		err = fmt.Errorf("request id invalid")
+		return
+	}
+	if securityCode == "" {
+		err = ErrSecurityCodeRequired
+		return
+	}
+	deleter, ok := w.s.(keyPairDeleter)
+	if !ok {
+		err = ErrUntrustUnsupported
+		return
+	}
+
+	if header, err = w.safeboxHeader(header); err != nil {
+		return
+	}
+	info := &safebox.OperateKeyInfo{UserDid: string(id), Code: securityCode}
+	response, err := w.s.QueryPrivateKey(header, info)
+	if err != nil {
+		return
+	}
+	if err = deleter.DeleteKeyPair(header, info); err != nil {
+		return
+	}
+	w.InvalidateKey(id)
+	return &KeyPair{PrivateKey: response.PrivateKey, PublicKey: response.PublicKey}, nil
+}
+
+// RotateSecurityCode is used to replace the security code of a trusted key
+// pair, e.g. when it may have leaked. The key pair is trusted again and the
+// new security code is returned.
+//
+func (w *WalletClient) RotateSecurityCode(header http.Header, id did.Identifier, securityCode string) (newCode string, err error) {
+	if w.s == nil {
+		err = ErrTrusteeDisabled
+		return
+	}
+	if id == "" {
+		err = fmt.Errorf("request id invalid")
+		return
+	}
+	if securityCode == "" {
+		err = ErrSecurityCodeRequired
+		return
+	}
+
+	if header, err = w.safeboxHeader(header); err != nil {
+		return
+	}
+	response, err := w.s.QueryPrivateKey(header, &safebox.OperateKeyInfo{UserDid: string(id), Code: securityCode})
+	if err != nil {
+		return
+	}
+	response, err = w.s.TrusteeKeyPair(header, &safebox.SaveKeyPairRequetBody{
+		UserDid:    string(id),
+		PrivateKey: response.PrivateKey,
+		PublicKey:  response.PublicKey,
+	})
+	if err != nil {
+		return
+	}
+	w.InvalidateKey(id)
+	return response.Code, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/safebox"
+)
+
+// fakeSafebox keeps the key pairs in memory, the security code of a key
+// pair changes every time it is saved.
+type fakeSafebox struct {
+	saved int
+	keys  map[string]*safebox.KeyPairResponse
+}
+
+func (s *fakeSafebox) TrusteeKeyPair(header http.Header, body *safebox.SaveKeyPairRequetBody) (*safebox.KeyPairResponse, error) {
+	s.saved++
+	key := &safebox.KeyPairResponse{Code: fmt.Sprintf("code-%d", s.saved), PrivateKey: body.PrivateKey, PublicKey: body.PublicKey}
+	s.keys[body.UserDid] = key
+	return key, nil
+}
+
+func (s *fakeSafebox) QueryPrivateKey(header http.Header, body *safebox.OperateKeyInfo) (*safebox.KeyPairResponse, error) {
+	key := s.keys[body.UserDid]
+	if key == nil || key.Code != body.Code {
+		return nil, fmt.Errorf("security code invalid")
+	}
+	return key, nil
+}
+
+func (s *fakeSafebox) DeleteKeyPair(header http.Header, body *safebox.OperateKeyInfo) error {
+	delete(s.keys, body.UserDid)
+	return nil
+}
+
+func TestTrustKeyPairLifecycle(t *testing.T) {
+	box := &fakeSafebox{keys: make(map[string]*safebox.KeyPairResponse)}
+	client := &WalletClient{cfg: &restapi.Config{}, s: box}
+
+	code, err := client.TrustKeyPair(http.Header{}, &TrustKeyPairBody{Id: "did:axn:001", PrivateKey: "private-key", PublicKey: "public-key"})
+	if err != nil || code != "code-1" {
+		t.Fatalf("trust key pair fail: %q, %v", code, err)
+	}
+
+	newCode, err := client.RotateSecurityCode(http.Header{}, "did:axn:001", code)
+	if err != nil || newCode == code {
+		t.Fatalf("rotate security code fail: %q, %v", newCode, err)
+	}
+	if _, err = client.RotateSecurityCode(http.Header{}, "did:axn:001", code); err == nil {
+		t.Fatalf("err should not be nil with the old security code")
+	}
+
+	params, err := client.queryPrivateKey(http.Header{}, &pki.SignatureParam{Creator: "did:axn:001", SecurityCode: newCode})
+	if err != nil || params.PrivateKey != "private-key" {
+		t.Fatalf("new security code should give the private key, got %+v, %v", params, err)
+	}
+	if _, err = client.queryPrivateKey(http.Header{}, &pki.SignatureParam{Creator: "did:axn:001"}); err != ErrSecurityCodeRequired {
+		t.Fatalf("err should be ErrSecurityCodeRequired, got %v", err)
+	}
+
+	keyPair, err := client.UntrustKeyPair(http.Header{}, "did:axn:001", newCode)
+	if err != nil || keyPair.PrivateKey != "private-key" || keyPair.PublicKey != "public-key" {
+		t.Fatalf("untrust key pair fail: %+v, %v", keyPair, err)
+	}
+	if len(box.keys) != 0 {
+		t.Fatalf("key pair should be deleted from the safebox")
+	}
+}
+
+func TestTrustKeyPairDisabled(t *testing.T) {
+	client := &WalletClient{cfg: &restapi.Config{}}
+
+	if _, err := client.TrustKeyPair(http.Header{}, &TrustKeyPairBody{Id: "did:axn:001", PrivateKey: "a", PublicKey: "b"}); err != ErrTrusteeDisabled {
+		t.Fatalf("err should be ErrTrusteeDisabled, got %v", err)
+	}
+	if _, err := client.RotateSecurityCode(http.Header{}, "did:axn:001", "code"); err != ErrTrusteeDisabled {
+		t.Fatalf("err should be ErrTrusteeDisabled, got %v", err)
+	}
+	if _, err := client.UntrustKeyPair(http.Header{}, "did:axn:001", "code"); err != ErrTrusteeDisabled {
+		t.Fatalf("err should be ErrTrusteeDisabled, got %v", err)
+	}
+}
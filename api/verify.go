@@ -0,0 +1,155 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/sdk-go-common/utils"
+)
+
+// ErrRemoteVerifyUnsupported is returned by VerifySignatureRemote when the
+// gateway does not provide the signature verification endpoint.
+//
+var ErrRemoteVerifyUnsupported = fmt.Errorf("signature verification not supported by the gateway")
+
+// VerifySignatureBody is the request body of VerifySignatureRemote.
+//
+type VerifySignatureBody struct {
+	Payload   string             `json:"payload"`
+	Signature *pki.SignatureBody `json:"signature"`
+}
+
+// VerifySignatureResult is the verification result returned by the gateway.
+//
+type VerifySignatureResult struct {
+	Valid  bool   `json:"valid"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// VerifySignatureRemote is used to let the gateway verify a signature of the
+// payload, without executing anything.
+//
+// It is meant to be called once when setting up new signing keys or a new
+// signing setup, to confirm the gateway accepts the signatures before real
+// transactions are sent. The payload is signed with signParams, see
+// DumpSignature to inspect the signed bytes.
+//
+// ErrRemoteVerifyUnsupported is returned when the gateway has no
+// verification endpoint.
+//
+func (w *WalletClient) VerifySignatureRemote(header http.Header, payload []byte, signParams *pki.SignatureParam) (result *VerifySignatureResult, err error) {
+	if len(payload) == 0 {
+		err = fmt.Errorf("request payload invalid")
+		return
+	}
+
+	if signParams, err = w.withNonce(signParams); err != nil {
+		return nil, err
+	}
+	sign, err := w.signPayload(signParams, payload)
+	if err != nil {
+		return nil, err
+	}
+	w.dumpSignature(SignKindRequest, signParams, payload, sign.SignatureValue)
+
+	// Build http request
+	header = w.withDefaultHeader(header)
+	endpoint := w.endpoint("/v1/signature/verify")
+	r := w.c.NewRequest("POST", endpoint)
+	r.SetHeaders(header)
+	binding, err := w.bindResponse(r, "POST", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	r.SetBody(&VerifySignatureBody{
+		Payload:   string(payload),
+		Signature: sign,
+	})
+
+	trace := w.startCall(r, "POST", "/v1/signature/verify", nil, header)
+	defer func() { trace.finish(err) }()
+
+	// Do http request
+	d, resp, err := w.do(r)
+	if err == nil && resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrRemoteVerifyUnsupported
+	}
+	_, resp, err = w.requireOK(d, resp, err)
+	if err != nil {
+		return nil, err
+	}
+	if err = w.decodeVerified(resp, binding, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// VerifySignature verifies the signature body of a payload with the raw
+// public key of its creator, e.g. to validate the signed payloads submitted
+// by partners. The algorithm is the one advertised in the signature value,
+// which is the base64 signature as in the signature bodies of the wallet
+// requests.
+//
+// Only the exact bytes of the payload are verified, see
+// VerifyCanonicalSignature for the JSON payloads re-encoded in transit.
+//
+func VerifySignature(payload []byte, sign *pki.SignatureBody, publicKey []byte) error {
+	if sign == nil || sign.SignatureValue == "" {
+		return fmt.Errorf("signature must be set")
+	}
+	name, value := SplitSignatureValue(sign.SignatureValue)
+	alg, err := LookupSignatureAlgorithm(name)
+	if err != nil {
+		return err
+	}
+	sig, err := utils.DecodeBase64(value)
+	if err != nil {
+		return fmt.Errorf("signature value invalid: %v", err)
+	}
+	return alg.Verify(publicKey, payload, sig)
+}
+
+// VerifyCanonicalSignature verifies the signature body of a JSON payload
+// in its canonical form, see CanonicalJSON, as the client signs the request
+// bodies, e.g. for the payloads re-encoded in transit. It returns the
+// canonical bytes which were signed: the caller must act on them, not on
+// the payload, which may parse differently, e.g. with duplicate keys.
+//
+func VerifyCanonicalSignature(payload []byte, sign *pki.SignatureBody, publicKey []byte) (signed []byte, err error) {
+	if signed, err = Canonicalize(payload); err != nil {
+		return nil, fmt.Errorf("payload invalid: %v", err)
+	}
+	if err = VerifySignature(signed, sign, publicKey); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// VerifyWalletRequest verifies the signature of a signed wallet request,
+// as built by the client, with the raw public key of its creator.
+//
+func VerifyWalletRequest(req *wallet.WalletRequest, publicKey []byte) error {
+	if req == nil {
+		return fmt.Errorf("wallet request must be set")
+	}
+	return VerifySignature([]byte(req.Payload), req.Signature, publicKey)
+}
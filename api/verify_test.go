@@ -0,0 +1,192 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/sdk-go-common/utils"
+	"golang.org/x/crypto/ed25519"
+	gock "gopkg.in/h2non/gock.v1"
+)
+
+var verifySignParams = &pki.SignatureParam{
+	Creator:    "did:axn:001",
+	Nonce:      "helloalice",
+	PrivateKey: "WBZNmTTf34Kg+pQOTSIRL+JeQYDfj7InWc0A/9kvNvQSI8Ue8iRD8gn9CNmGO2EjJILF/3RELmEcbuS5G0d+Mg==",
+}
+
+func TestVerifySignatureRemoteSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const token = "user-token-001"
+
+	//response body
+	byPayload, err := json.Marshal(&VerifySignatureResult{Valid: true})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody := &rtstructs.Response{
+		ErrCode: 0,
+		Payload: string(byPayload),
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Post("/v1/signature/verify").
+		MatchHeader("X-Auth-Token", token).
+		Reply(200).
+		JSON(respBody)
+
+	//set http header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	//do verify signature
+	result, err := client.VerifySignatureRemote(header, []byte(`{"id":"did:axn:002"}`), verifySignParams)
+	if err != nil {
+		t.Fatalf("verify signature fail: %v", err)
+	}
+	if !result.Valid {
+		t.Fatalf("signature should be valid")
+	}
+}
+
+func TestVerifySignatureRemoteUnsupported(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Post("/v1/signature/verify").
+		Reply(404)
+
+	//do verify signature
+	result, err := client.VerifySignatureRemote(http.Header{}, []byte(`{"id":"did:axn:002"}`), verifySignParams)
+	if err != ErrRemoteVerifyUnsupported {
+		t.Fatalf("err should be ErrRemoteVerifyUnsupported when endpoint is missing, not %v", err)
+	}
+	if result != nil {
+		t.Fatalf("result should be nil when endpoint is missing")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	priv, pub, cleanup := registerP256(t)
+	defer cleanup()
+	body := map[string]interface{}{"from": "did:axn:alice", "to": "did:axn:bob", "amount": 100}
+	payload, err := CanonicalJSON(body)
+	if err != nil {
+		t.Fatalf("canonical json fail: %v", err)
+	}
+	sign, err := buildSignatureBody(&pki.SignatureParam{
+		Creator:    "did:axn:alice",
+		Nonce:      "nonce",
+		PrivateKey: AlgorithmKey("p256", priv),
+	}, payload)
+	if err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+
+	if err = VerifySignature(payload, sign, pub); err != nil {
+		t.Fatalf("verify fail: %v", err)
+	}
+	// re-encoded by the partner
+	indented, err := json.MarshalIndent(body, "", "  ")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err = VerifySignature(indented, sign, pub); err == nil {
+		t.Fatalf("verify should fail on the bytes which were not signed")
+	}
+	signed, err := VerifyCanonicalSignature(indented, sign, pub)
+	if err != nil {
+		t.Fatalf("verify re-encoded payload fail: %v", err)
+	}
+	if string(signed) != string(payload) {
+		t.Fatalf("the signed bytes should be returned, got %s", signed)
+	}
+	duplicated := []byte(`{"amount":1000,"from":"did:axn:alice","to":"did:axn:bob","amount":100}`)
+	if signed, err = VerifyCanonicalSignature(duplicated, sign, pub); err != nil || string(signed) != string(payload) {
+		t.Fatalf("the canonical bytes should be returned for a payload with duplicate keys, got %s, %v", signed, err)
+	}
+	if _, err = VerifyCanonicalSignature([]byte("not json"), sign, pub); err == nil {
+		t.Fatalf("verify should fail on a payload which is not json")
+	}
+	body["amount"] = 1000
+	tampered, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err = VerifySignature(tampered, sign, pub); err == nil {
+		t.Fatalf("verify should fail on a tampered payload")
+	}
+
+	bad := *sign
+	bad.SignatureValue = "rsa:AAAA"
+	if err = VerifySignature(payload, &bad, pub); err == nil {
+		t.Fatalf("verify should fail on an unknown algorithm")
+	}
+	bad.SignatureValue = "p256:!!"
+	if err = VerifySignature(payload, &bad, pub); err == nil {
+		t.Fatalf("verify should fail on an invalid signature value")
+	}
+	if err = VerifySignature(payload, nil, pub); err == nil {
+		t.Fatalf("verify should fail without signature")
+	}
+}
+
+func TestVerifyWalletRequest(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	payload := []byte(`{"id":"did:axn:bob","name":"transfer"}`)
+	req := &wallet.WalletRequest{
+		Payload: string(payload),
+		Signature: &pki.SignatureBody{
+			Creator:        "did:axn:bob",
+			Nonce:          "nonce",
+			SignatureValue: utils.EncodeBase64(ed25519.Sign(priv, payload)),
+		},
+	}
+	if err = VerifyWalletRequest(req, pub); err != nil {
+		t.Fatalf("verify wallet request fail: %v", err)
+	}
+
+	other, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	if err = VerifyWalletRequest(req, other); err == nil {
+		t.Fatalf("verify should fail with another key")
+	}
+	if err = VerifyWalletRequest(nil, pub); err == nil {
+		t.Fatalf("verify should fail without request")
+	}
+}
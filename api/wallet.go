@@ -17,16 +17,14 @@ limitations under the License.
 package api
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"net/http"
-	"reflect"
+	"net/url"
+	"time"
 
 	safeboxapi "github.com/arxanchain/safebox-sdk-go/api"
-	"github.com/arxanchain/sdk-go-common/errors"
-	"github.com/arxanchain/sdk-go-common/rest"
 	restapi "github.com/arxanchain/sdk-go-common/rest/api"
-	rtstructs "github.com/arxanchain/sdk-go-common/rest/structs"
 	"github.com/arxanchain/sdk-go-common/structs"
 	"github.com/arxanchain/sdk-go-common/structs/did"
 	"github.com/arxanchain/sdk-go-common/structs/pki"
@@ -40,14 +38,56 @@ type WalletClient struct {
 	c   *restapi.Client
 	s   safebox.ISafeboxClient
 	cfg *restapi.Config
+
+	holdTTL      time.Duration
+	autoFee      bool
+	batchSize    int
+	chunkWorkers int
+	transferKYC  KYCLevel
+	screening    *screening
+	travelRule   *travelRule
+	memoKeys     MemoKeyFunc
+	respVerify   *responseVerification
+	nonces       NonceFunc
+	keys         *keyCache
+	privacy      bool
+	diagnostics  *signDiagnostics
+	errorClasses *errorClassTable
+	signers      *signerTable
+	log          Logger
+	tracer       Tracer
+	metrics      Metrics
+	middleware   *middlewareChain
+	breaker      *circuitBreaker
+	noIdemKey    bool
+	retry        *RetryPolicy
+	endpoints    map[string]string
+	headers      http.Header
+	upProgress   ProgressFunc
+	downProgress ProgressFunc
+	uploadMD5    bool
+	ctx          context.Context
 }
 
 // NewWalletClient returns a WalletClient instance.
 //
-func NewWalletClient(config *restapi.Config) (*WalletClient, error) {
+// The options set the http transport of the client, e.g. its proxy and
+// timeouts, on a copy of the http client of the configuration.
+//
+func NewWalletClient(config *restapi.Config, opts ...ClientOption) (*WalletClient, error) {
 	if config == nil {
 		return nil, fmt.Errorf("config must be set")
 	}
+	o := newClientOptions(opts)
+	if o.err != nil {
+		return nil, o.err
+	}
+	return newWalletClient(config, o)
+}
+
+// newWalletClient returns a WalletClient instance set with the options.
+func newWalletClient(config *restapi.Config, o *clientOptions) (*WalletClient, error) {
+	config = o.clientConfig(config)
 
 	var s safebox.ISafeboxClient
 	var err error
@@ -66,12 +106,15 @@ func NewWalletClient(config *restapi.Config) (*WalletClient, error) {
 		config.RouteTag = "wallet-ng"
 	}
 
-	c, err := restapi.NewClient(config)
+	middleware := &middlewareChain{}
+	c, err := newRestClient(config, middleware, o)
 	if err != nil {
 		return nil, err
 	}
 
-	return &WalletClient{c: c, s: s, cfg: config}, nil
+	w := &WalletClient{c: c, s: s, cfg: config, errorClasses: &errorClassTable{}, signers: &signerTable{}, middleware: middleware}
+	o.setup(w)
+	return w, nil
 }
 
 // Register is used to register user wallet.
@@ -92,37 +135,10 @@ func (w *WalletClient) Register(header http.Header, body *wallet.RegisterWalletB
 		return
 	}
 
-	// Build http request
-	r := w.c.NewRequest("POST", "/v1/wallet/register")
-	r.SetHeaders(header)
-	r.SetBody(body)
-
-	// Do http request
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-
-	// Parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
+	if err = w.doJSON(header, "POST", "/v1/wallet/register", nil, body, &result); err != nil {
 		return
 	}
 
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
-		return
-	}
-
-	payload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
-		return
-	}
-
-	err = json.Unmarshal([]byte(payload), &result)
-
 	result, err = w.trusteeKeyPair(header, result)
 	return
 }
@@ -133,8 +149,13 @@ func (w *WalletClient) trusteeKeyPair(header http.Header, req *wallet.WalletResp
 		return
 	}
 
-	if w.cfg.ApiKey != "" {
-		header.Set(structs.APIKeyHeader, w.cfg.ApiKey)
+	key, err := w.apiKey()
+	if err != nil {
+		result = nil
+		return
+	}
+	if key != "" {
+		header.Set(structs.APIKeyHeader, key)
 	}
 	response, err := w.s.TrusteeKeyPair(header, &safebox.SaveKeyPairRequetBody{
 		UserDid:    string(req.Id),
@@ -151,17 +172,44 @@ func (w *WalletClient) trusteeKeyPair(header http.Header, req *wallet.WalletResp
 	return
 }
 
+// queryPrivateKey resolves the private key of signature params with their
+// security code. With the key cache, the key is kept in the cache and the
+// params keep their security code, the signatures copy the cached key.
+// The params are resolved in a copy, the params of the caller are left as is.
 func (w *WalletClient) queryPrivateKey(header http.Header, signParams *pki.SignatureParam) (result *pki.SignatureParam, err error) {
 	result = signParams
-	if w.s == nil {
+	if w.s == nil || w.signerFor(signParams) != nil {
 		return
 	}
 	if result.PrivateKey != "" && result.SecurityCode == "" {
 		return
 	}
+	params := *signParams
+	result = &params
+	if result.SecurityCode == "" {
+		if result.SecurityCode, err = w.securityCode(result.Creator); err != nil {
+			result = nil
+			return
+		}
+	}
+	if result.SecurityCode == "" {
+		result = nil
+		err = ErrSecurityCodeRequired
+		return
+	}
 
-	if w.cfg.ApiKey != "" {
-		header.Set(structs.APIKeyHeader, w.cfg.ApiKey)
+	if w.keys != nil && w.keys.has(result.Creator, result.SecurityCode) {
+		// the key is copied from the cache by the signature
+		return
+	}
+
+	key, err := w.apiKey()
+	if err != nil {
+		result = nil
+		return
+	}
+	if key != "" {
+		header.Set(structs.APIKeyHeader, key)
 	}
 	response, err := w.s.QueryPrivateKey(header, &safebox.OperateKeyInfo{
 		UserDid: string(result.Creator),
@@ -171,6 +219,9 @@ func (w *WalletClient) queryPrivateKey(header http.Header, signParams *pki.Signa
 		result = nil
 		return
 	}
+	if w.keys != nil && w.keys.put(result.Creator, result.SecurityCode, response.PrivateKey) {
+		return
+	}
 	result.SecurityCode = ""
 	result.PrivateKey = response.PrivateKey
 
@@ -195,37 +246,10 @@ func (w *WalletClient) RegisterSubWallet(header http.Header, body *wallet.Regist
 		return
 	}
 
-	// Build http request
-	r := w.c.NewRequest("POST", "/v1/wallet/register/subwallet")
-	r.SetHeaders(header)
-	r.SetBody(body)
-
-	// Do http request
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-
-	// Parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
-		return
-	}
-
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
+	if err = w.doJSON(header, "POST", "/v1/wallet/register/subwallet", nil, body, &result); err != nil {
 		return
 	}
 
-	payload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
-		return
-	}
-
-	err = json.Unmarshal([]byte(payload), &result)
-
 	result, err = w.trusteeKeyPair(header, result)
 
 	return
@@ -234,34 +258,7 @@ func (w *WalletClient) RegisterSubWallet(header http.Header, body *wallet.Regist
 // GetWalletBalance is used to get wallet balances.
 //
 func (w *WalletClient) GetWalletBalance(header http.Header, id did.Identifier) (result *wallet.WalletBalance, err error) {
-	r := w.c.NewRequest("GET", "/v1/wallet/balance")
-	r.SetHeaders(header)
-	r.SetParam("id", string(id))
-
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-
-	// parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
-		return
-	}
-
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
-		return
-	}
-
-	respPayload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
-		return
-	}
-
-	err = json.Unmarshal([]byte(respPayload), &result)
+	err = w.doJSON(header, "GET", "/v1/wallet/balance", url.Values{"id": {string(id)}}, nil, &result)
 
 	return
 }
@@ -269,34 +266,13 @@ func (w *WalletClient) GetWalletBalance(header http.Header, id did.Identifier) (
 // GetWalletInfo is used to get wallet base information.
 //
 func (w *WalletClient) GetWalletInfo(header http.Header, id did.Identifier) (result *wallet.WalletInfo, err error) {
-	r := w.c.NewRequest("GET", "/v1/wallet/info")
-	r.SetHeaders(header)
-	r.SetParam("id", string(id))
-
-	_, resp, err := restapi.RequireOK(w.c.DoRequest(r))
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-
-	// parse http response
-	var respBody rtstructs.Response
-	if err = restapi.DecodeBody(resp, &respBody); err != nil {
-		return
-	}
-
-	if respBody.ErrCode != errors.SuccCode {
-		err = rest.CodedError(respBody.ErrCode, respBody.ErrMessage)
-		return
-	}
-
-	respPayload, ok := respBody.Payload.(string)
-	if !ok {
-		err = fmt.Errorf("response payload type invalid: %v", reflect.TypeOf(respBody.Payload))
-		return
-	}
-
-	err = json.Unmarshal([]byte(respPayload), &result)
+	err = w.doJSON(header, "GET", "/v1/wallet/info", url.Values{"id": {string(id)}}, nil, &result)
 
 	return
 }
+
+// QueryWalletInfo is used to query wallet base information,
+// it is the same as GetWalletInfo and named after the other query APIs.
+func (w *WalletClient) QueryWalletInfo(header http.Header, id did.Identifier) (result *wallet.WalletInfo, err error) {
+	return w.GetWalletInfo(header, id)
+}
@@ -732,3 +732,58 @@ func TestGetWalletInfoFailErrCode(t *testing.T) {
 		t.Fatalf("WalletInfo object should be nil when query fail")
 	}
 }
+
+func TestQueryWalletInfoSucc(t *testing.T) {
+	//init gock & walletclient
+	initWalletClient(t)
+	defer gock.Off()
+	client := walletClient.(*WalletClient)
+
+	const (
+		token    = "user-token-001"
+		id       = did.Identifier("did:axn:001")
+		endpoint = did.DidEndpoint("endpoint-001")
+	)
+
+	//build response body
+	payload := &wallet.WalletInfo{
+		Id:       id,
+		Endpoint: endpoint,
+		Status:   pw.Status_VALID,
+	}
+	byPayload, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	respBody := &rtstructs.Response{
+		ErrCode: 0,
+		Payload: string(byPayload),
+	}
+
+	//mock http request
+	gock.New("http://127.0.0.1:8006").
+		Get("/v1/wallet/info").
+		MatchParam("id", string(id)).
+		MatchHeader("X-Auth-Token", token).
+		Reply(200).
+		JSON(respBody)
+
+	//set header
+	header := http.Header{}
+	header.Set("X-Auth-Token", token)
+
+	//do query wallet info
+	result, err := client.QueryWalletInfo(header, id)
+	if err != nil {
+		t.Fatalf("query wallet info fail: %v", err)
+	}
+	if result == nil {
+		t.Fatalf("WalletInfo object should not be nil")
+	}
+	if result.Id != id {
+		t.Fatalf("wallet id should be %v", id)
+	}
+	if result.Endpoint != endpoint {
+		t.Fatalf("wallet endpoint should be %v", endpoint)
+	}
+}
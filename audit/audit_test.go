@@ -0,0 +1,99 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+)
+
+type fakeClient struct {
+	logs map[string][]*pw.UTXO
+}
+
+func (f *fakeClient) QueryTransactionLogs(header http.Header, id did.Identifier, txType string, num, page int32) ([]*pw.UTXO, error) {
+	if page > 1 {
+		return nil, nil
+	}
+	return f.logs[txType], nil
+}
+
+func TestExportSignVerify(t *testing.T) {
+	now := time.Now()
+	log := NewMemoryLog()
+	log.Append(&Record{Time: now.Add(-2 * time.Hour), Operation: "TransferCToken", WalletId: "did:axn:001", TxIds: []string{"tx-old"}})
+	log.Append(&Record{Time: now.Add(-time.Minute), Operation: "TransferCToken", WalletId: "did:axn:001", TxIds: []string{"tx-002"}})
+	log.Append(&Record{Time: now.Add(-2 * time.Minute), Operation: "TransferCToken", WalletId: "did:axn:001", TxIds: []string{"tx-001"}})
+
+	client := &fakeClient{logs: map[string][]*pw.UTXO{
+		"out": {{SourceTxDataHash: "tx-001", CTokenId: "ctoken-001", Value: 100, Addr: "did:axn:002"}},
+	}}
+	report, err := NewExporter(log, client, nil).Export(now.Add(-time.Hour), now)
+	if err != nil {
+		t.Fatalf("export fail: %v", err)
+	}
+	if len(report.Operations) != 2 {
+		t.Fatalf("report should have 2 operations, not %d", len(report.Operations))
+	}
+	first, second := report.Operations[0], report.Operations[1]
+	if first.TxIds[0] != "tx-001" || !first.Confirmed || len(first.Chain) != 1 {
+		t.Fatalf("first operation should be the confirmed tx-001: %+v", first)
+	}
+	if second.Confirmed {
+		t.Fatalf("operation without chain transaction should not be confirmed")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	signed, err := Sign(report, key)
+	if err != nil {
+		t.Fatalf("sign report fail: %v", err)
+	}
+
+	// round trip through the written form
+	var buf bytes.Buffer
+	if err = Write(&buf, signed); err != nil {
+		t.Fatalf("write report fail: %v", err)
+	}
+	var read SignedReport
+	if err = json.Unmarshal(buf.Bytes(), &read); err != nil {
+		t.Fatalf("read report fail: %v", err)
+	}
+	verified, err := Verify(&read)
+	if err != nil {
+		t.Fatalf("verify report fail: %v", err)
+	}
+	if verified.SchemaVersion != SchemaVersion || len(verified.Operations) != 2 {
+		t.Fatalf("verified report mismatch: %+v", verified)
+	}
+
+	read.Report = bytes.Replace(read.Report, []byte("tx-001"), []byte("tx-009"), -1)
+	if _, err = Verify(&read); err == nil {
+		t.Fatalf("err should not be nil when report is altered")
+	}
+}
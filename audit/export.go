@@ -0,0 +1,277 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+)
+
+const (
+	// SchemaVersion is the version of the report schema
+	SchemaVersion = "1.0"
+	// SignatureAlgorithm is the algorithm signed reports use
+	SignatureAlgorithm = "ECDSA-P256-SHA256"
+
+	defaultPageSize int32 = 100
+)
+
+// Client is the subset of the wallet client used to look up the chain
+// transactions of the audited operations.
+//
+type Client interface {
+	QueryTransactionLogs(header http.Header, id did.Identifier, txType string, num, page int32) ([]*pw.UTXO, error)
+}
+
+// ChainTx is a chain transaction log entry belonging to an operation.
+//
+type ChainTx struct {
+	TxHash       string `json:"tx_hash"`
+	Direction    string `json:"direction"`
+	TokenId      string `json:"token_id"`
+	Amount       int64  `json:"amount"`
+	Counterparty string `json:"counterparty"`
+}
+
+// Operation is an audited operation and the chain transactions it produced.
+//
+type Operation struct {
+	*Record
+	Confirmed bool       `json:"confirmed"`
+	Chain     []*ChainTx `json:"chain,omitempty"`
+}
+
+// Report is the audit report of the operations performed in a period.
+//
+type Report struct {
+	SchemaVersion string       `json:"schema_version"`
+	From          time.Time    `json:"from"`
+	To            time.Time    `json:"to"`
+	Generated     time.Time    `json:"generated"`
+	Operations    []*Operation `json:"operations"`
+}
+
+// SignedReport is a report together with its signature and the public key
+// to verify it with, so it can be handed to auditors as is.
+//
+type SignedReport struct {
+	Algorithm string          `json:"algorithm"`
+	PublicKey []byte          `json:"public_key"`
+	Report    json.RawMessage `json:"report"`
+	Signature []byte          `json:"signature"`
+}
+
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// Exporter builds audit reports from the audit log and the chain logs.
+//
+type Exporter struct {
+	log      Log
+	client   Client
+	header   http.Header
+	pageSize int32
+}
+
+// NewExporter returns an Exporter instance.
+//
+// The client may be nil, then the chain transactions are not looked up and
+// no operation is reported as confirmed.
+//
+func NewExporter(log Log, client Client, header http.Header) *Exporter {
+	return &Exporter{log: log, client: client, header: header, pageSize: defaultPageSize}
+}
+
+// Export builds the report of the operations performed in the period [from, to).
+//
+func (e *Exporter) Export(from, to time.Time) (*Report, error) {
+	if e.log == nil {
+		return nil, fmt.Errorf("audit log must be set")
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("audit period invalid: %v - %v", from, to)
+	}
+
+	records, err := e.log.Records(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// chain transactions of every wallet involved, keyed by tx hash
+	chain := make(map[did.Identifier]map[string][]*ChainTx)
+	report := &Report{
+		SchemaVersion: SchemaVersion,
+		From:          from,
+		To:            to,
+		Generated:     time.Now(),
+		Operations:    []*Operation{},
+	}
+	for _, r := range records {
+		op := &Operation{Record: r}
+		if e.client != nil && r.WalletId != "" && len(r.TxIds) > 0 {
+			txs, ok := chain[r.WalletId]
+			if !ok {
+				txs, err = e.queryChain(r.WalletId)
+				if err != nil {
+					return nil, err
+				}
+				chain[r.WalletId] = txs
+			}
+			op.Confirmed = true
+			for _, id := range r.TxIds {
+				found, ok := txs[id]
+				if !ok {
+					op.Confirmed = false
+					continue
+				}
+				op.Chain = append(op.Chain, found...)
+			}
+		}
+		report.Operations = append(report.Operations, op)
+	}
+	return report, nil
+}
+
+func (e *Exporter) queryChain(id did.Identifier) (map[string][]*ChainTx, error) {
+	txs := make(map[string][]*ChainTx)
+	for _, direction := range []string{"in", "out"} {
+		for page := int32(1); ; page++ {
+			logs, err := e.client.QueryTransactionLogs(e.header, id, direction, e.pageSize, page)
+			if err != nil {
+				return nil, err
+			}
+			for _, utxo := range logs {
+				tx := &ChainTx{
+					TxHash:    utxo.SourceTxDataHash,
+					Direction: direction,
+					TokenId:   utxo.CTokenId,
+					Amount:    utxo.Value,
+				}
+				if direction == "in" {
+					tx.Counterparty = utxo.Founder
+				} else {
+					tx.Counterparty = utxo.Addr
+				}
+				txs[tx.TxHash] = append(txs[tx.TxHash], tx)
+			}
+			if int32(len(logs)) < e.pageSize {
+				break
+			}
+		}
+	}
+	return txs, nil
+}
+
+// Sign signs the report with the client private key.
+//
+func Sign(report *Report, key *ecdsa.PrivateKey) (*SignedReport, error) {
+	if report == nil || key == nil {
+		return nil, fmt.Errorf("report and key must be set")
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(data)
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	sig, err := asn1.Marshal(ecdsaSignature{R: r, S: s})
+	if err != nil {
+		return nil, err
+	}
+	return &SignedReport{
+		Algorithm: SignatureAlgorithm,
+		PublicKey: pub,
+		Report:    data,
+		Signature: sig,
+	}, nil
+}
+
+// Verify checks the signature of a signed report and returns the report.
+//
+// Verify only proves the report was not altered after signing, the auditor
+// must check the embedded public key belongs to the audited client.
+//
+func Verify(signed *SignedReport) (*Report, error) {
+	if signed == nil {
+		return nil, fmt.Errorf("signed report must be set")
+	}
+	if signed.Algorithm != SignatureAlgorithm {
+		return nil, fmt.Errorf("signature algorithm %s not supported", signed.Algorithm)
+	}
+	key, err := x509.ParsePKIXPublicKey(signed.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key type %T not supported", key)
+	}
+	var sig ecdsaSignature
+	if _, err = asn1.Unmarshal(signed.Signature, &sig); err != nil {
+		return nil, err
+	}
+	// the report is signed in compact form, writing it indented must not
+	// break the signature
+	var data bytes.Buffer
+	if err = json.Compact(&data, signed.Report); err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(data.Bytes())
+	if !ecdsa.Verify(pub, digest[:], sig.R, sig.S) {
+		return nil, fmt.Errorf("report signature invalid")
+	}
+	var report Report
+	if err = json.Unmarshal(data.Bytes(), &report); err != nil {
+		return nil, err
+	}
+	if report.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("report schema version %s not supported", report.SchemaVersion)
+	}
+	return &report, nil
+}
+
+// Write writes the signed report as indented json.
+//
+func Write(w io.Writer, signed *SignedReport) error {
+	data, err := json.MarshalIndent(signed, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
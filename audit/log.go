@@ -0,0 +1,86 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit keeps a log of the operations a client performed and exports
+// signed, schema-versioned reports of them for regulatory audits.
+package audit
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+)
+
+// Record is one operation performed by the client.
+//
+type Record struct {
+	Time      time.Time      `json:"time"`
+	Operation string         `json:"operation"`
+	WalletId  did.Identifier `json:"wallet_id"`
+	TxIds     []string       `json:"tx_ids,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// Log is the audit log the operations are recorded to.
+//
+type Log interface {
+	// Append records an operation
+	Append(r *Record) error
+	// Records returns the operations performed in the period [from, to),
+	// ordered by time
+	Records(from, to time.Time) ([]*Record, error)
+}
+
+// MemoryLog is a Log keeping the records in memory.
+//
+type MemoryLog struct {
+	mu      sync.Mutex
+	records []*Record
+}
+
+// NewMemoryLog returns a MemoryLog instance.
+//
+func NewMemoryLog() *MemoryLog {
+	return &MemoryLog{}
+}
+
+// Append records an operation.
+//
+func (l *MemoryLog) Append(r *Record) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, r)
+	return nil
+}
+
+// Records returns the operations performed in the period [from, to).
+//
+func (l *MemoryLog) Records(from, to time.Time) ([]*Record, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var result []*Record
+	for _, r := range l.records {
+		if !r.Time.Before(from) && r.Time.Before(to) {
+			result = append(result, r)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return result, nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command sign-debug prints the exact bytes being signed, the nonce and the
+// resulting signature for a request body, to troubleshoot "signature verify
+// failed" errors returned by the gateway.
+//
+// The body is read from the -body file, or stdin, and signed as is without
+// its trailing newline. To reproduce a signature of the client, take the
+// body from the data_text field of the client diagnostics output (see
+// WalletClient.SetSignDiagnostics), or pass -canonical to sign the canonical
+// JSON of the body as the client does.
+//
+// Usage:
+//
+//	sign-debug -creator did:axn:001 -nonce nonce -key <base64 private key> -body body.json
+//
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+func main() {
+	var (
+		creator = flag.String("creator", "", "did of the signer")
+		nonce   = flag.String("nonce", "", "signature nonce")
+		created = flag.Int64("created", 0, "signature creation time in unix seconds, defaults to now")
+		key     = flag.String("key", "", "base64 ed25519 private key of the signer")
+		keyFile = flag.String("key-file", "", "file holding the base64 ed25519 private key")
+		body    = flag.String("body", "", "file holding the body to sign, defaults to stdin")
+		compact = flag.Bool("compact", false, "compact the json body before signing")
+		canon   = flag.Bool("canonical", false, "sign the canonical json of the body, as the client does for request payloads")
+	)
+	flag.Parse()
+
+	if err := run(*creator, *nonce, *created, *key, *keyFile, *body, *compact, *canon); err != nil {
+		fmt.Fprintf(os.Stderr, "sign-debug: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(creator, nonce string, created int64, key, keyFile, body string, compact, canon bool) error {
+	if keyFile != "" {
+		data, err := ioutil.ReadFile(keyFile)
+		if err != nil {
+			return err
+		}
+		key = string(bytes.TrimSpace(data))
+	}
+	if created == 0 {
+		created = time.Now().Unix()
+	}
+
+	var data []byte
+	var err error
+	if body != "" {
+		data, err = ioutil.ReadFile(body)
+	} else {
+		data, err = ioutil.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		return err
+	}
+	data = bytes.TrimRight(data, "\r\n")
+	if canon {
+		if data, err = api.Canonicalize(data); err != nil {
+			return err
+		}
+	} else if compact {
+		var buf bytes.Buffer
+		if err = json.Compact(&buf, data); err != nil {
+			return fmt.Errorf("body is not valid json: %v", err)
+		}
+		data = buf.Bytes()
+	}
+
+	dump, err := api.DumpSignature(&pki.SignatureParam{
+		Creator:    did.Identifier(creator),
+		Created:    created,
+		Nonce:      nonce,
+		PrivateKey: key,
+	}, data)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
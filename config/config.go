@@ -0,0 +1,318 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config loads the wallet client configuration from the environment
+// or from a JSON or YAML file, so that services can be configured without
+// code changes across environments:
+//
+//	cfg, err := config.LoadConfigFromFile("/etc/wallet/client.yaml")
+//	if err != nil {
+//		return err
+//	}
+//	walletClient, err := cfg.NewWalletClient()
+//
+// YAML files need the yaml build tag, which pulls gopkg.in/yaml.v3.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	restapi "github.com/arxanchain/sdk-go-common/rest/api"
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+// EnvPrefix prefixes the names of the environment variables read by
+// LoadConfigFromEnv.
+//
+const EnvPrefix = "WALLET_"
+
+// Config is the wallet client configuration of a file or the environment.
+//
+type Config struct {
+	// Address is the address of the wallet gateway
+	Address     string `json:"address"`
+	APIKey      string `json:"api_key"`
+	RouteTag    string `json:"route_tag,omitempty"`
+	CallbackURL string `json:"callback_url,omitempty"`
+	// TLS holds the certificate paths
+	TLS                 *TLSConfig           `json:"tls,omitempty"`
+	EnterpriseSignParam *EnterpriseSignParam `json:"enterprise_sign_param,omitempty"`
+	TrusteeKeyPair      bool                 `json:"trustee_key_pair,omitempty"`
+	// Retry is the retry policy, none if nil
+	Retry    *RetryConfig   `json:"retry,omitempty"`
+	Timeouts TimeoutsConfig `json:"timeouts"`
+	// Proxy is the URL of the http proxy, the one of the environment
+	// variables of net/http if empty
+	Proxy string `json:"proxy,omitempty"`
+}
+
+// TLSConfig holds the paths of the TLS certificates.
+//
+type TLSConfig struct {
+	CAFile   string `json:"ca_file"`
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+}
+
+// EnterpriseSignParam is the enterprise signature parameter, used to sign
+// the UTXO records of the fees.
+//
+type EnterpriseSignParam struct {
+	Creator    string `json:"creator"`
+	Nonce      string `json:"nonce"`
+	PrivateKey string `json:"private_key"`
+}
+
+// RetryConfig is the retry policy, see api.RetryPolicy.
+//
+type RetryConfig struct {
+	MaxAttempts int      `json:"max_attempts"`
+	Backoff     Duration `json:"backoff"`
+	MaxBackoff  Duration `json:"max_backoff"`
+}
+
+// TimeoutsConfig bounds the requests, zero values keep the defaults.
+//
+type TimeoutsConfig struct {
+	// Request bounds the whole requests
+	Request      Duration `json:"request"`
+	Dial         Duration `json:"dial"`
+	TLSHandshake Duration `json:"tls_handshake"`
+}
+
+// Duration is a time.Duration written as a string, e.g. "1.5s", or as a
+// number of seconds.
+//
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+//
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var seconds float64
+		if json.Unmarshal(data, &seconds) != nil {
+			return fmt.Errorf("duration invalid: %s", data)
+		}
+		*d = Duration(seconds * float64(time.Second))
+		return nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("duration invalid: %s", s)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+//
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// yamlUnmarshal decodes YAML documents, it is set with the yaml build tag.
+var yamlUnmarshal func(data []byte, v interface{}) error
+
+// LoadConfigFromFile loads the configuration of a JSON file, or of a YAML
+// file with the .yaml or .yml extension.
+//
+func LoadConfigFromFile(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if yamlUnmarshal == nil {
+			return nil, fmt.Errorf("yaml config %s needs the yaml build tag", path)
+		}
+		err = yamlUnmarshal(data, c)
+	default:
+		err = json.Unmarshal(data, c)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config %s invalid: %v", path, err)
+	}
+	return c, nil
+}
+
+// LoadConfigFromEnv loads the configuration of the environment variables,
+// named after the JSON keys of the file configuration with EnvPrefix:
+//
+//	WALLET_ADDRESS, WALLET_API_KEY, WALLET_ROUTE_TAG, WALLET_CALLBACK_URL
+//	WALLET_TLS_CA_FILE, WALLET_TLS_CERT_FILE, WALLET_TLS_KEY_FILE
+//	WALLET_ENTERPRISE_CREATOR, WALLET_ENTERPRISE_NONCE, WALLET_ENTERPRISE_PRIVATE_KEY
+//	WALLET_TRUSTEE_KEY_PAIR
+//	WALLET_RETRY_MAX_ATTEMPTS, WALLET_RETRY_BACKOFF, WALLET_RETRY_MAX_BACKOFF
+//	WALLET_TIMEOUT, WALLET_DIAL_TIMEOUT, WALLET_TLS_HANDSHAKE_TIMEOUT
+//	WALLET_PROXY
+//
+func LoadConfigFromEnv() (*Config, error) {
+	e := &envReader{}
+	c := &Config{
+		Address:     e.get("ADDRESS"),
+		APIKey:      e.get("API_KEY"),
+		RouteTag:    e.get("ROUTE_TAG"),
+		CallbackURL: e.get("CALLBACK_URL"),
+		Proxy:       e.get("PROXY"),
+	}
+	if tls := (TLSConfig{CAFile: e.get("TLS_CA_FILE"), CertFile: e.get("TLS_CERT_FILE"), KeyFile: e.get("TLS_KEY_FILE")}); tls != (TLSConfig{}) {
+		c.TLS = &tls
+	}
+	if param := (EnterpriseSignParam{Creator: e.get("ENTERPRISE_CREATOR"), Nonce: e.get("ENTERPRISE_NONCE"), PrivateKey: e.get("ENTERPRISE_PRIVATE_KEY")}); param != (EnterpriseSignParam{}) {
+		c.EnterpriseSignParam = &param
+	}
+	c.TrusteeKeyPair = e.bool("TRUSTEE_KEY_PAIR")
+	if retry := (RetryConfig{MaxAttempts: e.int("RETRY_MAX_ATTEMPTS"), Backoff: e.duration("RETRY_BACKOFF"), MaxBackoff: e.duration("RETRY_MAX_BACKOFF")}); retry != (RetryConfig{}) {
+		c.Retry = &retry
+	}
+	c.Timeouts = TimeoutsConfig{
+		Request:      e.duration("TIMEOUT"),
+		Dial:         e.duration("DIAL_TIMEOUT"),
+		TLSHandshake: e.duration("TLS_HANDSHAKE_TIMEOUT"),
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+	return c, nil
+}
+
+// envReader reads the environment variables, keeping the first error.
+type envReader struct {
+	err error
+}
+
+func (e *envReader) get(name string) string {
+	return os.Getenv(EnvPrefix + name)
+}
+
+func (e *envReader) fail(name, value string) {
+	if e.err == nil {
+		e.err = fmt.Errorf("environment variable %s%s invalid: %q", EnvPrefix, name, value)
+	}
+}
+
+func (e *envReader) bool(name string) bool {
+	s := e.get(name)
+	if s == "" {
+		return false
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		e.fail(name, s)
+	}
+	return v
+}
+
+func (e *envReader) int(name string) int {
+	s := e.get(name)
+	if s == "" {
+		return 0
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		e.fail(name, s)
+	}
+	return v
+}
+
+func (e *envReader) duration(name string) Duration {
+	s := e.get(name)
+	if s == "" {
+		return 0
+	}
+	var d Duration
+	if err := d.UnmarshalJSON([]byte(strconv.Quote(s))); err != nil {
+		if seconds, err := strconv.ParseFloat(s, 64); err == nil {
+			return Duration(seconds * float64(time.Second))
+		}
+		e.fail(name, s)
+	}
+	return d
+}
+
+// RestConfig returns the rest client configuration.
+//
+func (c *Config) RestConfig() *restapi.Config {
+	config := &restapi.Config{
+		Address:              c.Address,
+		ApiKey:               c.APIKey,
+		RouteTag:             c.RouteTag,
+		CallbackUrl:          c.CallbackURL,
+		TrusteeKeyPairEnable: c.TrusteeKeyPair,
+	}
+	if c.TLS != nil {
+		config.TLSConfig = &restapi.TLSConfig{
+			CAFile:   c.TLS.CAFile,
+			CertFile: c.TLS.CertFile,
+			KeyFile:  c.TLS.KeyFile,
+		}
+	}
+	if c.EnterpriseSignParam != nil {
+		config.EnterpriseSignParam = &restapi.EnterpriseSignParam{
+			Creator:    c.EnterpriseSignParam.Creator,
+			Nonce:      c.EnterpriseSignParam.Nonce,
+			PrivateKey: c.EnterpriseSignParam.PrivateKey,
+		}
+	}
+	return config
+}
+
+// Options returns the client options of the retry policy, timeouts and
+// proxy.
+//
+func (c *Config) Options() []api.ClientOption {
+	var opts []api.ClientOption
+	if c.Retry != nil {
+		opts = append(opts, api.WithRetry(&api.RetryPolicy{
+			MaxAttempts: c.Retry.MaxAttempts,
+			Backoff:     time.Duration(c.Retry.Backoff),
+			MaxBackoff:  time.Duration(c.Retry.MaxBackoff),
+		}))
+	}
+	if c.Timeouts.Request > 0 {
+		opts = append(opts, api.WithTimeout(time.Duration(c.Timeouts.Request)))
+	}
+	if c.Timeouts.Dial > 0 {
+		opts = append(opts, api.WithDialTimeout(time.Duration(c.Timeouts.Dial)))
+	}
+	if c.Timeouts.TLSHandshake > 0 {
+		opts = append(opts, api.WithTLSHandshakeTimeout(time.Duration(c.Timeouts.TLSHandshake)))
+	}
+	if c.Proxy != "" {
+		opts = append(opts, api.WithProxy(c.Proxy))
+	}
+	return opts
+}
+
+// NewWalletClient returns a wallet client of the configuration, opts are
+// applied after the ones of the configuration.
+//
+func (c *Config) NewWalletClient(opts ...api.ClientOption) (*api.WalletClient, error) {
+	if c.Address == "" {
+		return nil, fmt.Errorf("config address must be set")
+	}
+	return api.NewWalletClient(c.RestConfig(), append(c.Options(), opts...)...)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, name, data string) string {
+	dir, err := ioutil.TempDir("", "wallet-config")
+	if err != nil {
+		t.Fatalf("create temp dir fail: %v", err)
+	}
+	path := filepath.Join(dir, name)
+	if err = ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatalf("write config fail: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfigFromFile(t *testing.T) {
+	path := writeConfig(t, "client.json", `{
+		"address": "https://wallet.example.com:9143",
+		"api_key": "api-key",
+		"tls": {"ca_file": "ca.pem", "cert_file": "cert.pem", "key_file": "key.pem"},
+		"enterprise_sign_param": {"creator": "did:axn:enterprise", "nonce": "nonce", "private_key": "key"},
+		"retry": {"max_attempts": 4, "backoff": "100ms", "max_backoff": 2},
+		"timeouts": {"request": "30s", "dial": "3s"},
+		"proxy": "http://proxy.corp:3128"
+	}`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	c, err := LoadConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("load config fail: %v", err)
+	}
+	if c.Address != "https://wallet.example.com:9143" || c.APIKey != "api-key" || c.Proxy != "http://proxy.corp:3128" {
+		t.Fatalf("config fields should be loaded, got %+v", c)
+	}
+	if c.Retry == nil || c.Retry.MaxAttempts != 4 || c.Retry.Backoff != Duration(100*time.Millisecond) || c.Retry.MaxBackoff != Duration(2*time.Second) {
+		t.Fatalf("retry should be loaded, got %+v", c.Retry)
+	}
+	if c.Timeouts.Request != Duration(30*time.Second) || c.Timeouts.Dial != Duration(3*time.Second) {
+		t.Fatalf("timeouts should be loaded, got %+v", c.Timeouts)
+	}
+
+	rest := c.RestConfig()
+	if rest.TLSConfig == nil || rest.TLSConfig.CertFile != "cert.pem" || rest.EnterpriseSignParam == nil || rest.EnterpriseSignParam.Creator != "did:axn:enterprise" {
+		t.Fatalf("rest config should carry the certificates and enterprise sign param")
+	}
+	if len(c.Options()) != 4 {
+		t.Fatalf("config should have 4 client options, got %d", len(c.Options()))
+	}
+
+	bad := writeConfig(t, "bad.json", `{"timeouts": {"dial": "3 seconds"}}`)
+	defer os.RemoveAll(filepath.Dir(bad))
+	if _, err = LoadConfigFromFile(bad); err == nil {
+		t.Fatalf("invalid duration should fail")
+	}
+	if yamlUnmarshal == nil {
+		yml := writeConfig(t, "client.yaml", "address: http://127.0.0.1:8006\n")
+		defer os.RemoveAll(filepath.Dir(yml))
+		if _, err = LoadConfigFromFile(yml); err == nil {
+			t.Fatalf("yaml config should fail without the yaml build tag")
+		}
+	}
+}
+
+func TestLoadConfigFromEnv(t *testing.T) {
+	env := map[string]string{
+		"WALLET_ADDRESS":            "http://127.0.0.1:8006",
+		"WALLET_API_KEY":            "api-key",
+		"WALLET_TRUSTEE_KEY_PAIR":   "true",
+		"WALLET_RETRY_MAX_ATTEMPTS": "3",
+		"WALLET_TIMEOUT":            "10",
+		"WALLET_DIAL_TIMEOUT":       "500ms",
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+
+	c, err := LoadConfigFromEnv()
+	if err != nil {
+		t.Fatalf("load config fail: %v", err)
+	}
+	if c.Address != "http://127.0.0.1:8006" || c.APIKey != "api-key" || !c.TrusteeKeyPair || c.TLS != nil {
+		t.Fatalf("config fields should be loaded, got %+v", c)
+	}
+	if c.Retry == nil || c.Retry.MaxAttempts != 3 {
+		t.Fatalf("retry should be loaded, got %+v", c.Retry)
+	}
+	if c.Timeouts.Request != Duration(10*time.Second) || c.Timeouts.Dial != Duration(500*time.Millisecond) {
+		t.Fatalf("timeouts should be loaded, got %+v", c.Timeouts)
+	}
+
+	os.Setenv("WALLET_RETRY_MAX_ATTEMPTS", "three")
+	if _, err = LoadConfigFromEnv(); err == nil {
+		t.Fatalf("invalid environment variable should fail")
+	}
+
+	if _, err = (&Config{}).NewWalletClient(); err == nil {
+		t.Fatalf("config without address should fail")
+	}
+}
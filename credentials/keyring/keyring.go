@@ -0,0 +1,91 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package keyring provides an api.CredentialStore reading the credentials
+// from the keychain of the OS, with the security tool on macOS and the
+// secret-tool of libsecret on Linux.
+package keyring
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+// ErrUnsupported is returned on the platforms without a supported keychain.
+//
+var ErrUnsupported = fmt.Errorf("keyring unsupported on " + runtime.GOOS)
+
+// Store reads the credentials of a service from the keychain, the
+// credential names are the account names of the items.
+//
+type Store struct {
+	service string
+	goos    string
+	run     func(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// New returns the keychain store of service.
+//
+func New(service string) (*Store, error) {
+	if service == "" {
+		return nil, fmt.Errorf("keyring service invalid")
+	}
+	return &Store{service: service, goos: runtime.GOOS, run: run}, nil
+}
+
+// Credential reads the credential from the keychain,
+// api.ErrCredentialNotFound is returned when there is no such item.
+//
+func (s *Store) Credential(ctx context.Context, name string) (string, error) {
+	var (
+		tool string
+		args []string
+	)
+	switch s.goos {
+	case "darwin":
+		tool, args = "security", []string{"find-generic-password", "-s", s.service, "-a", name, "-w"}
+	case "linux":
+		tool, args = "secret-tool", []string{"lookup", "service", s.service, "account", name}
+	default:
+		return "", ErrUnsupported
+	}
+	out, err := s.run(ctx, tool, args...)
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return "", api.ErrCredentialNotFound
+		}
+		return "", fmt.Errorf("keyring %s fail: %v", tool, err)
+	}
+	value := strings.TrimSuffix(string(out), "\n")
+	if value == "" {
+		return "", api.ErrCredentialNotFound
+	}
+	return value, nil
+}
+
+func run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	err := cmd.Run()
+	return stdout.Bytes(), err
+}
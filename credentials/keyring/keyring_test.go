@@ -0,0 +1,74 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyring
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+func TestCredential(t *testing.T) {
+	s, err := New("wallet")
+	if err != nil {
+		t.Fatalf("new keyring store fail: %v", err)
+	}
+	var command string
+	s.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		command = name + " " + strings.Join(args, " ")
+		switch args[len(args)-1] {
+		case "api-key", "-w":
+			return []byte("secret\n"), nil
+		}
+		return nil, &exec.ExitError{}
+	}
+
+	s.goos = "darwin"
+	if value, err := s.Credential(context.Background(), "api-key"); err != nil || value != "secret" {
+		t.Fatalf("credential mismatch: %q, %v", value, err)
+	}
+	if command != "security find-generic-password -s wallet -a api-key -w" {
+		t.Fatalf("command mismatch: %s", command)
+	}
+
+	s.goos = "linux"
+	if value, err := s.Credential(context.Background(), "api-key"); err != nil || value != "secret" {
+		t.Fatalf("credential mismatch: %q, %v", value, err)
+	}
+	if command != "secret-tool lookup service wallet account api-key" {
+		t.Fatalf("command mismatch: %s", command)
+	}
+	if _, err = s.Credential(context.Background(), "missing"); err != api.ErrCredentialNotFound {
+		t.Fatalf("missing item should not be found, got %v", err)
+	}
+
+	s.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		return nil, fmt.Errorf("executable file not found")
+	}
+	if _, err = s.Credential(context.Background(), "api-key"); err == nil || err == api.ErrCredentialNotFound {
+		t.Fatalf("the tool error should be returned, got %v", err)
+	}
+
+	s.goos = "plan9"
+	if _, err = s.Credential(context.Background(), "api-key"); err != ErrUnsupported {
+		t.Fatalf("err should be ErrUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vault provides an api.CredentialStore reading the credentials
+// from the KV version 2 secrets engine of HashiCorp Vault.
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+const (
+	defaultMount   = "secret"
+	defaultField   = "value"
+	defaultTimeout = 10 * time.Second
+)
+
+// Config is the configuration of a Vault credential store.
+//
+type Config struct {
+	// Address is the Vault address, e.g. https://vault:8200
+	Address string
+	// Token is the Vault token, allowed to read the secrets
+	Token string
+	// Mount is the mount path of the KV engine, "secret" if empty
+	Mount string
+	// HTTPClient sends the requests, a client with a 10 seconds timeout if nil
+	HTTPClient *http.Client
+}
+
+// Store reads the credentials from Vault, the credential names are the
+// secret paths followed by the field, e.g. "wallet/issuer#api_key". The
+// field is "value" when the name has none.
+//
+type Store struct {
+	cfg  Config
+	base string
+}
+
+type readResponse struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+}
+
+// New returns a Vault credential store.
+//
+func New(cfg *Config) (*Store, error) {
+	if cfg == nil || cfg.Address == "" || cfg.Token == "" {
+		return nil, fmt.Errorf("vault config invalid")
+	}
+	s := &Store{cfg: *cfg}
+	if s.cfg.Mount == "" {
+		s.cfg.Mount = defaultMount
+	}
+	if s.cfg.HTTPClient == nil {
+		s.cfg.HTTPClient = &http.Client{Timeout: defaultTimeout}
+	}
+	s.base = fmt.Sprintf("%s/v1/%s/data/", strings.TrimRight(s.cfg.Address, "/"), strings.Trim(s.cfg.Mount, "/"))
+	return s, nil
+}
+
+// Credential reads the credential from Vault, api.ErrCredentialNotFound is
+// returned when the secret or the field does not exist.
+//
+func (s *Store) Credential(ctx context.Context, name string) (string, error) {
+	path, field := splitName(name)
+	if path == "" {
+		return "", fmt.Errorf("vault credential name invalid: %q", name)
+	}
+	req, err := http.NewRequest("GET", s.base+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("X-Vault-Token", s.cfg.Token)
+
+	resp, err := s.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault read fail: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return "", api.ErrCredentialNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault read fail: unexpected response code: %d (%s)", resp.StatusCode, body)
+	}
+	var out readResponse
+	if err = json.Unmarshal(body, &out); err != nil {
+		return "", err
+	}
+	value, ok := out.Data.Data[field]
+	if !ok {
+		return "", api.ErrCredentialNotFound
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("vault credential %s is not a string", name)
+	}
+	return str, nil
+}
+
+// splitName splits a credential name into the secret path and the field.
+func splitName(name string) (path, field string) {
+	path, field = name, defaultField
+	if i := strings.LastIndex(name, "#"); i >= 0 {
+		path, field = name[:i], name[i+1:]
+	}
+	return strings.Trim(path, "/"), field
+}
@@ -0,0 +1,73 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+func TestCredential(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/kv/data/wallet/issuer" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"data":{"data":{"value":"api-key","token":"access-token","retries":3}}}`))
+	}))
+	defer server.Close()
+
+	s, err := New(&Config{Address: server.URL, Token: "vault-token", Mount: "kv"})
+	if err != nil {
+		t.Fatalf("new vault store fail: %v", err)
+	}
+	ctx := context.Background()
+	if value, err := s.Credential(ctx, "wallet/issuer"); err != nil || value != "api-key" {
+		t.Fatalf("credential mismatch: %q, %v", value, err)
+	}
+	if value, err := s.Credential(ctx, "/wallet/issuer#token"); err != nil || value != "access-token" {
+		t.Fatalf("credential field mismatch: %q, %v", value, err)
+	}
+	if _, err = s.Credential(ctx, "wallet/issuer#missing"); err != api.ErrCredentialNotFound {
+		t.Fatalf("missing field should not be found, got %v", err)
+	}
+	if _, err = s.Credential(ctx, "wallet/other"); err != api.ErrCredentialNotFound {
+		t.Fatalf("missing secret should not be found, got %v", err)
+	}
+	if _, err = s.Credential(ctx, "wallet/issuer#retries"); err == nil {
+		t.Fatalf("err should not be nil for a non string field")
+	}
+
+	s.cfg.Token = "other-token"
+	if _, err = s.Credential(ctx, "wallet/issuer"); err == nil || err == api.ErrCredentialNotFound {
+		t.Fatalf("err should be returned when vault denies the read, got %v", err)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	if _, err := New(&Config{Address: "http://vault:8200"}); err == nil {
+		t.Fatalf("err should not be nil without token")
+	}
+}
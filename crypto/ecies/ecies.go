@@ -0,0 +1,166 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ecies implements public key encryption on the NIST curves: an
+// ephemeral ECDH key agreement on the curve of the recipient key, a SHA-256
+// derived key and AES-256-GCM.
+//
+// The ciphertext layout is the uncompressed ephemeral public key, the GCM
+// nonce (12 bytes), then the sealed data.
+package ecies
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Algorithm is the name of the scheme implemented by this package on the
+// P-256 curve, see AlgorithmOf.
+const Algorithm = "ECIES-P256-SHA256-AES256GCM"
+
+const nonceLen = 12
+
+// AlgorithmOf returns the name of the scheme on the curve of the keys, e.g.
+// Algorithm for P-256 keys.
+//
+func AlgorithmOf(curve elliptic.Curve) string {
+	return "ECIES-" + strings.Replace(curve.Params().Name, "-", "", -1) + "-SHA256-AES256GCM"
+}
+
+// GenerateKey generates a P-256 key pair.
+//
+func GenerateKey() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+// MarshalPublicKey encodes a public key in uncompressed form.
+//
+func MarshalPublicKey(pub *ecdsa.PublicKey) []byte {
+	return elliptic.Marshal(pub.Curve, pub.X, pub.Y)
+}
+
+// UnmarshalPublicKey decodes a P-256 public key encoded by MarshalPublicKey.
+//
+func UnmarshalPublicKey(data []byte) (*ecdsa.PublicKey, error) {
+	return unmarshalPublicKey(elliptic.P256(), data)
+}
+
+func unmarshalPublicKey(curve elliptic.Curve, data []byte) (*ecdsa.PublicKey, error) {
+	x, y := elliptic.Unmarshal(curve, data)
+	if x == nil {
+		return nil, fmt.Errorf("ecies public key invalid")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// byteLen is the size of the field elements of the curve.
+func byteLen(curve elliptic.Curve) int {
+	return (curve.Params().BitSize + 7) / 8
+}
+
+// pubKeyLen is the size of the uncompressed public keys of the curve.
+func pubKeyLen(curve elliptic.Curve) int {
+	return 1 + 2*byteLen(curve)
+}
+
+// supported reports whether the curve is one of the NIST curves of the
+// standard library.
+func supported(curve elliptic.Curve) bool {
+	switch curve {
+	case elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521():
+		return true
+	}
+	return false
+}
+
+func sharedKey(priv []byte, pub *ecdsa.PublicKey) []byte {
+	x, _ := pub.Curve.ScalarMult(pub.X, pub.Y, priv)
+	// left pad the shared x coordinate to the field size
+	secret := make([]byte, byteLen(pub.Curve))
+	xb := x.Bytes()
+	copy(secret[len(secret)-len(xb):], xb)
+	key := sha256.Sum256(secret)
+	return key[:]
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt encrypts plaintext so that only the owner of the private key of pub can decrypt it.
+//
+func Encrypt(pub *ecdsa.PublicKey, plaintext []byte) ([]byte, error) {
+	if pub == nil || !supported(pub.Curve) {
+		return nil, fmt.Errorf("ecies public key must be a P-224, P-256, P-384 or P-521 key")
+	}
+	if pub.X == nil || pub.Y == nil || !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, fmt.Errorf("ecies public key invalid: point not on curve")
+	}
+	eph, err := ecdsa.GenerateKey(pub.Curve, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(sharedKey(eph.D.Bytes(), pub))
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, nonceLen)
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, pubKeyLen(pub.Curve)+nonceLen+len(plaintext)+gcm.Overhead())
+	out = append(out, MarshalPublicKey(&eph.PublicKey)...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, plaintext, nil), nil
+}
+
+// Decrypt decrypts a ciphertext produced by Encrypt.
+//
+func Decrypt(priv *ecdsa.PrivateKey, ciphertext []byte) ([]byte, error) {
+	if priv == nil || !supported(priv.Curve) {
+		return nil, fmt.Errorf("ecies private key must be a P-224, P-256, P-384 or P-521 key")
+	}
+	keyLen := pubKeyLen(priv.Curve)
+	if len(ciphertext) < keyLen+nonceLen {
+		return nil, fmt.Errorf("ecies ciphertext too short")
+	}
+	eph, err := unmarshalPublicKey(priv.Curve, ciphertext[:keyLen])
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(sharedKey(priv.D.Bytes(), eph))
+	if err != nil {
+		return nil, err
+	}
+	nonce := ciphertext[keyLen : keyLen+nonceLen]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext[keyLen+nonceLen:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("ecies decrypt fail: %v", err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ecies
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	priv, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	pub, err := UnmarshalPublicKey(MarshalPublicKey(&priv.PublicKey))
+	if err != nil {
+		t.Fatalf("unmarshal public key fail: %v", err)
+	}
+
+	plaintext := []byte("originator: alice, beneficiary: bob")
+	ciphertext, err := Encrypt(pub, plaintext)
+	if err != nil {
+		t.Fatalf("encrypt fail: %v", err)
+	}
+	decrypted, err := Decrypt(priv, ciphertext)
+	if err != nil {
+		t.Fatalf("decrypt fail: %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Fatalf("decrypted data should be %s", plaintext)
+	}
+
+	other, _ := GenerateKey()
+	if _, err = Decrypt(other, ciphertext); err == nil {
+		t.Fatalf("err should not be nil when decrypting with another key")
+	}
+}
+
+func TestEncryptDecryptCurves(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P224(), elliptic.P384(), elliptic.P521()} {
+		priv, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			t.Fatalf("generate key fail: %v", err)
+		}
+		ciphertext, err := Encrypt(&priv.PublicKey, []byte("memo"))
+		if err != nil {
+			t.Fatalf("encrypt with %s fail: %v", curve.Params().Name, err)
+		}
+		decrypted, err := Decrypt(priv, ciphertext)
+		if err != nil || string(decrypted) != "memo" {
+			t.Fatalf("decrypt with %s fail: %q, %v", curve.Params().Name, decrypted, err)
+		}
+	}
+	if AlgorithmOf(elliptic.P256()) != Algorithm || AlgorithmOf(elliptic.P384()) != "ECIES-P384-SHA256-AES256GCM" {
+		t.Fatalf("algorithm names should follow the curves")
+	}
+}
+
+func TestEncryptInvalidKey(t *testing.T) {
+	priv, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	keys := map[string]*ecdsa.PublicKey{
+		"no point":     {Curve: priv.Curve},
+		"not on curve": {Curve: priv.Curve, X: priv.X, Y: new(big.Int).Add(priv.Y, big.NewInt(1))},
+	}
+	for name, pub := range keys {
+		if _, err = Encrypt(pub, []byte("memo")); err == nil {
+			t.Errorf("%s: err should not be nil", name)
+		}
+	}
+}
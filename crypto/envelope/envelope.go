@@ -0,0 +1,371 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package envelope encrypts streams with AES-256-GCM under a data key, which
+// is itself sealed with a key encryption key of the caller.
+//
+// The stream starts with "WSE1", the big-endian length of the JSON header
+// and the header, holding the sealed data key and the encryption parameters.
+// The data follows in segments sealed separately, so streams of any size
+// are encrypted and decrypted with a bounded memory. The last segment is
+// sealed as such, so a truncated stream fails to decrypt, and every segment
+// is sealed with the header as additional data, so a tampered header fails
+// to decrypt too.
+package envelope
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Algorithm is the name of the scheme implemented by this package.
+const Algorithm = "AES256GCM-SEGMENTED"
+
+// DefaultSegmentSize is the size of the plaintext segments.
+const DefaultSegmentSize = 64 << 10
+
+// MaxSegmentSize is the largest segment size accepted in a header, so that
+// a corrupted stream does not make the reader allocate an unbounded buffer.
+const MaxSegmentSize = 16 << 20
+
+// KeySize is the size of the key encryption keys.
+const KeySize = 32
+
+const (
+	magic       = "WSE1"
+	prefixLen   = 8
+	maxHeader   = 4 << 10
+	lastSegment = 1
+)
+
+// Header holds the encryption parameters of a stream.
+//
+type Header struct {
+	Algorithm string `json:"algorithm"`
+	// KeyID identifies the key encryption key, it is not secret
+	KeyID string `json:"key_id,omitempty"`
+	// WrappedKey is the data key sealed with the key encryption key
+	WrappedKey  []byte `json:"wrapped_key"`
+	NoncePrefix []byte `json:"nonce_prefix"`
+	SegmentSize int    `json:"segment_size"`
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("envelope key must be %d bytes", KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func random(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// segmentNonce returns the nonce of the segment of index i.
+func segmentNonce(prefix []byte, i uint32) []byte {
+	nonce := make([]byte, prefixLen+4)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[prefixLen:], i)
+	return nonce
+}
+
+// segmentAAD returns the additional data of a segment, the raw header
+// followed by whether the segment is the last one.
+func segmentAAD(head []byte, last bool) []byte {
+	aad := make([]byte, len(head)+1)
+	copy(aad, head)
+	if last {
+		aad[len(head)] = lastSegment
+	}
+	return aad
+}
+
+// Writer encrypts the data written to it, Close must be called to seal the
+// last segment.
+//
+type Writer struct {
+	dst    io.Writer
+	header *Header
+	gcm    cipher.AEAD
+	head   []byte
+	raw    []byte
+	buf    []byte
+	out    []byte
+	index  uint32
+	closed bool
+	err    error
+}
+
+// NewWriter returns a Writer encrypting to dst with a new data key sealed
+// with kek, a KeySize bytes key, identified by keyID. The header is written
+// to dst with the first segment.
+//
+func NewWriter(dst io.Writer, kek []byte, keyID string) (*Writer, error) {
+	kekGCM, err := newGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := random(KeySize)
+	if err != nil {
+		return nil, err
+	}
+	wrapNonce, err := random(kekGCM.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := random(prefixLen)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Header{
+		Algorithm:   Algorithm,
+		KeyID:       keyID,
+		WrappedKey:  kekGCM.Seal(wrapNonce, wrapNonce, dataKey, []byte(Algorithm)),
+		NoncePrefix: prefix,
+		SegmentSize: DefaultSegmentSize,
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	head := make([]byte, len(magic)+4, len(magic)+4+len(data))
+	copy(head, magic)
+	binary.BigEndian.PutUint32(head[len(magic):], uint32(len(data)))
+	head = append(head, data...)
+
+	return &Writer{
+		dst:    dst,
+		header: h,
+		gcm:    gcm,
+		head:   head,
+		raw:    head,
+		buf:    make([]byte, 0, h.SegmentSize),
+	}, nil
+}
+
+// Header returns the header of the stream.
+//
+func (w *Writer) Header() *Header {
+	return w.header
+}
+
+// Write implements io.Writer.
+//
+func (w *Writer) Write(p []byte) (n int, err error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.closed {
+		return 0, fmt.Errorf("envelope writer closed")
+	}
+	for len(p) > 0 {
+		if len(w.buf) == cap(w.buf) {
+			// more data follows, the buffered segment is not the last one
+			if err = w.seal(false); err != nil {
+				return n, err
+			}
+		}
+		k := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+k]
+		p = p[k:]
+		n += k
+	}
+	return n, nil
+}
+
+// Close seals the last segment, it does not close the destination.
+//
+func (w *Writer) Close() error {
+	if w.closed {
+		return w.err
+	}
+	w.closed = true
+	if w.err != nil {
+		return w.err
+	}
+	return w.seal(true)
+}
+
+func (w *Writer) seal(last bool) error {
+	if w.head != nil {
+		if _, err := w.dst.Write(w.head); err != nil {
+			w.err = err
+			return err
+		}
+		w.head = nil
+	}
+	w.out = w.gcm.Seal(w.out[:0], segmentNonce(w.header.NoncePrefix, w.index), w.buf, segmentAAD(w.raw, last))
+	w.index++
+	w.buf = w.buf[:0]
+	if _, err := w.dst.Write(w.out); err != nil {
+		w.err = err
+		return err
+	}
+	return nil
+}
+
+// ReadHeader reads the header of an encrypted stream. The header is not
+// authenticated until the first segment is decrypted.
+//
+func ReadHeader(src io.Reader) (*Header, error) {
+	h, _, err := readHeader(src)
+	return h, err
+}
+
+// readHeader reads the header of an encrypted stream, and returns it with
+// its raw bytes.
+func readHeader(src io.Reader) (*Header, []byte, error) {
+	head := make([]byte, len(magic)+4)
+	if _, err := io.ReadFull(src, head); err != nil {
+		return nil, nil, fmt.Errorf("envelope header invalid: %v", err)
+	}
+	if string(head[:len(magic)]) != magic {
+		return nil, nil, fmt.Errorf("envelope header invalid: not an encrypted stream")
+	}
+	n := binary.BigEndian.Uint32(head[len(magic):])
+	if n > maxHeader {
+		return nil, nil, fmt.Errorf("envelope header invalid: %d bytes", n)
+	}
+	head = append(head, make([]byte, n)...)
+	data := head[len(magic)+4:]
+	if _, err := io.ReadFull(src, data); err != nil {
+		return nil, nil, fmt.Errorf("envelope header invalid: %v", err)
+	}
+	h := &Header{}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, nil, fmt.Errorf("envelope header invalid: %v", err)
+	}
+	if h.Algorithm != Algorithm {
+		return nil, nil, fmt.Errorf("envelope algorithm not supported: %s", h.Algorithm)
+	}
+	if h.SegmentSize <= 0 || h.SegmentSize > MaxSegmentSize || len(h.NoncePrefix) != prefixLen {
+		return nil, nil, fmt.Errorf("envelope header invalid: segment parameters")
+	}
+	return h, head, nil
+}
+
+// Reader decrypts an encrypted stream.
+//
+type Reader struct {
+	src    *bufio.Reader
+	header *Header
+	raw    []byte
+	gcm    cipher.AEAD
+	in     []byte
+	plain  []byte
+	index  uint32
+	done   bool
+	err    error
+}
+
+// NewReader reads the header of the encrypted stream src and returns a
+// Reader decrypting it with kek, the key encryption key of the header.
+//
+func NewReader(src io.Reader, kek []byte) (*Reader, error) {
+	h, raw, err := readHeader(src)
+	if err != nil {
+		return nil, err
+	}
+	kekGCM, err := newGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(h.WrappedKey) < kekGCM.NonceSize() {
+		return nil, fmt.Errorf("envelope wrapped key invalid")
+	}
+	nonce := h.WrappedKey[:kekGCM.NonceSize()]
+	dataKey, err := kekGCM.Open(nil, nonce, h.WrappedKey[kekGCM.NonceSize():], []byte(Algorithm))
+	if err != nil {
+		return nil, fmt.Errorf("envelope key invalid: %v", err)
+	}
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{
+		src:    bufio.NewReader(src),
+		header: h,
+		raw:    raw,
+		gcm:    gcm,
+		in:     make([]byte, h.SegmentSize+gcm.Overhead()),
+	}, nil
+}
+
+// Header returns the header of the stream.
+//
+func (r *Reader) Header() *Header {
+	return r.header
+}
+
+// Read implements io.Reader.
+//
+func (r *Reader) Read(p []byte) (int, error) {
+	for len(r.plain) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		r.err = r.open()
+	}
+	n := copy(p, r.plain)
+	r.plain = r.plain[n:]
+	return n, nil
+}
+
+// open decrypts the next segment.
+func (r *Reader) open() error {
+	n, err := io.ReadFull(r.src, r.in)
+	last := false
+	switch err {
+	case nil:
+		if _, perr := r.src.Peek(1); perr == io.EOF {
+			last = true
+		}
+	case io.ErrUnexpectedEOF, io.EOF:
+		last = true
+	default:
+		return err
+	}
+	plain, err := r.gcm.Open(r.in[:0], segmentNonce(r.header.NoncePrefix, r.index), r.in[:n], segmentAAD(r.raw, last))
+	if err != nil {
+		return fmt.Errorf("envelope decrypt fail: %v", err)
+	}
+	r.index++
+	r.plain = plain
+	r.done = last
+	return nil
+}
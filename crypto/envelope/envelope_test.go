@@ -0,0 +1,124 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+)
+
+func encrypt(t *testing.T, kek, plaintext []byte) []byte {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, kek, "key-1")
+	if err != nil {
+		t.Fatalf("new writer fail: %v", err)
+	}
+	if _, err = w.Write(plaintext); err != nil {
+		t.Fatalf("write fail: %v", err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatalf("close fail: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	kek := bytes.Repeat([]byte{7}, KeySize)
+	for _, size := range []int{0, 1, DefaultSegmentSize, DefaultSegmentSize + 1, 3*DefaultSegmentSize - 5} {
+		plaintext := bytes.Repeat([]byte("x"), size)
+		ciphertext := encrypt(t, kek, plaintext)
+
+		r, err := NewReader(bytes.NewReader(ciphertext), kek)
+		if err != nil {
+			t.Fatalf("new reader fail: %v", err)
+		}
+		if r.Header().KeyID != "key-1" {
+			t.Fatalf("key id should be in the header")
+		}
+		got, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatalf("decrypt %d bytes fail: %v", size, err)
+		}
+		if !bytes.Equal(got, plaintext) {
+			t.Fatalf("decrypted %d bytes should match", size)
+		}
+	}
+}
+
+func TestTamper(t *testing.T) {
+	kek := bytes.Repeat([]byte{7}, KeySize)
+	plaintext := bytes.Repeat([]byte("x"), 2*DefaultSegmentSize+10)
+	ciphertext := encrypt(t, kek, plaintext)
+
+	if _, err := NewReader(bytes.NewReader(ciphertext), bytes.Repeat([]byte{8}, KeySize)); err == nil {
+		t.Fatalf("wrong key should fail")
+	}
+
+	// truncated at a segment boundary
+	segment := DefaultSegmentSize + 16
+	truncated := ciphertext[:len(ciphertext)-26]
+	if len(ciphertext)-len(truncated) != 10+16 {
+		t.Fatalf("bad test setup")
+	}
+	r, err := NewReader(bytes.NewReader(truncated[:len(truncated)-segment]), kek)
+	if err != nil {
+		t.Fatalf("new reader fail: %v", err)
+	}
+	if _, err = ioutil.ReadAll(r); err == nil {
+		t.Fatalf("truncated stream should fail")
+	}
+
+	tampered := append([]byte(nil), ciphertext...)
+	tampered[len(tampered)-1] ^= 1
+	r, err = NewReader(bytes.NewReader(tampered), kek)
+	if err != nil {
+		t.Fatalf("new reader fail: %v", err)
+	}
+	if _, err = ioutil.ReadAll(r); err == nil {
+		t.Fatalf("tampered stream should fail")
+	}
+
+	tampered = bytes.Replace(ciphertext, []byte(`"key-1"`), []byte(`"key-2"`), 1)
+	r, err = NewReader(bytes.NewReader(tampered), kek)
+	if err != nil {
+		t.Fatalf("new reader fail: %v", err)
+	}
+	if _, err = ioutil.ReadAll(r); err == nil {
+		t.Fatalf("tampered header should fail")
+	}
+}
+
+func TestSegmentSizeLimit(t *testing.T) {
+	data, err := json.Marshal(&Header{
+		Algorithm:   Algorithm,
+		NoncePrefix: make([]byte, prefixLen),
+		SegmentSize: MaxSegmentSize + 1,
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	head := make([]byte, len(magic)+4)
+	copy(head, magic)
+	binary.BigEndian.PutUint32(head[len(magic):], uint32(len(data)))
+
+	if _, err = NewReader(bytes.NewReader(append(head, data...)), bytes.Repeat([]byte{7}, KeySize)); err == nil {
+		t.Fatalf("segment size above the limit should fail")
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package headers builds the http headers of the wallet client calls, so
+// that callers do not have to know the header names and values expected by
+// the gateway:
+//
+//	header := headers.NewHeader().
+//		WithAPIKey(apiKey).
+//		WithToken(token).
+//		WithSyncInvoke().
+//		Build()
+//	resp, err := walletClient.IssueCToken(header, body, signParams)
+//
+// The builder chains the call options of the api package, Build applies
+// them with api.ApplyOptions. The headers common to all the calls of a
+// client can be set once with api.WalletClient.SetDefaultHeader.
+package headers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs"
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+// Builder builds a http header from api call options.
+//
+type Builder struct {
+	header http.Header
+	opts   []api.CallOption
+}
+
+// NewHeader returns an empty Builder.
+//
+func NewHeader() *Builder {
+	return &Builder{}
+}
+
+// From returns a Builder starting with a copy of header.
+//
+func From(header http.Header) *Builder {
+	return &Builder{header: api.ApplyOptions(header)}
+}
+
+// Build returns the header with the options applied, the builder can be
+// used again.
+//
+func (b *Builder) Build() http.Header {
+	return api.ApplyOptions(b.header, b.opts...)
+}
+
+// With adds call options of the api package.
+//
+func (b *Builder) With(opts ...api.CallOption) *Builder {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+// Set sets a http header, see api.WithHeader.
+//
+func (b *Builder) Set(key, value string) *Builder {
+	return b.With(api.WithHeader(key, value))
+}
+
+// WithAPIKey sets the API access key of the call, api.WithAPIKey sets the
+// one of all the calls of a client.
+//
+func (b *Builder) WithAPIKey(apiKey string) *Builder {
+	return b.Set(structs.APIKeyHeader, apiKey)
+}
+
+// WithToken sets the user access token, see api.WithAuthToken.
+//
+func (b *Builder) WithToken(token string) *Builder {
+	return b.With(api.WithAuthToken(token))
+}
+
+// WithSyncInvoke selects the synchronous invoking mode, see
+// api.WithSyncInvoke.
+//
+func (b *Builder) WithSyncInvoke() *Builder {
+	return b.With(api.WithSyncInvoke())
+}
+
+// WithAsyncInvoke selects the asynchronous invoking mode, see
+// api.WithAsyncInvoke.
+//
+func (b *Builder) WithAsyncInvoke() *Builder {
+	return b.With(api.WithAsyncInvoke())
+}
+
+// WithInvokeTimeout bounds the wait in synchronous invoking mode, see
+// api.WithInvokeTimeout.
+//
+func (b *Builder) WithInvokeTimeout(timeout time.Duration) *Builder {
+	return b.With(api.WithInvokeTimeout(timeout))
+}
+
+// WithCallbackURL sets the URL receiving the blockchain transaction events,
+// see api.WithCallbackURL.
+//
+func (b *Builder) WithCallbackURL(url string) *Builder {
+	return b.With(api.WithCallbackURL(url))
+}
+
+// WithIdempotencyKey sets the idempotency key of the call, see
+// api.WithIdempotencyKey.
+//
+func (b *Builder) WithIdempotencyKey(key string) *Builder {
+	return b.With(api.WithIdempotencyKey(key))
+}
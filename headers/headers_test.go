@@ -0,0 +1,71 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package headers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs"
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+func TestBuilder(t *testing.T) {
+	b := NewHeader().
+		WithAPIKey("key").
+		WithToken("token").
+		WithSyncInvoke().
+		WithInvokeTimeout(1500*time.Millisecond).
+		WithCallbackURL("http://callback").
+		WithIdempotencyKey("idem").
+		Set("X-Custom", "custom")
+	header := b.Build()
+
+	expected := map[string]string{
+		structs.APIKeyHeader:     "key",
+		api.AuthTokenHeader:      "token",
+		api.InvokeModeHeader:     api.InvokeModeSync,
+		api.InvokeTimeoutHeader:  "2",
+		api.CallbackURLHeader:    "http://callback",
+		api.IdempotencyKeyHeader: "idem",
+		"X-Custom":               "custom",
+	}
+	for k, v := range expected {
+		if header.Get(k) != v {
+			t.Fatalf("header %s should be %q, got %q", k, v, header.Get(k))
+		}
+	}
+
+	// the built header is a copy
+	header.Set("X-Custom", "changed")
+	if b.WithAsyncInvoke().Build().Get("X-Custom") != "custom" {
+		t.Fatalf("built header should not share the builder header")
+	}
+	if b.Build().Get(api.InvokeModeHeader) != api.InvokeModeAsync {
+		t.Fatalf("invoke mode should be async")
+	}
+
+	if !reflect.DeepEqual(NewHeader().WithToken("token").WithSyncInvoke().Build(), api.NewHeader(api.WithAuthToken("token"), api.WithSyncInvoke())) {
+		t.Fatalf("the builder should build the header of the api call options")
+	}
+
+	from := From(header).WithToken("other").Build()
+	if from.Get(api.AuthTokenHeader) != "other" || header.Get(api.AuthTokenHeader) != "token" {
+		t.Fatalf("From should start with a copy of the header")
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keystore
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/sdk-go-common/utils"
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+const backupVersion = 1
+
+// WalletBackup is a wallet restored by ImportWallet.
+//
+type WalletBackup struct {
+	// Key is the key pair of the wallet
+	Key *Key
+	// Wallet is the wallet info at the time of the export, nil if it was
+	// exported without a client
+	Wallet *wallet.WalletInfo
+	// Created is the export time, in unix seconds
+	Created int64
+}
+
+// backupJSON is the layout of the exported wallet bundle, only the DID and
+// the export time are kept in clear.
+type backupJSON struct {
+	Version int            `json:"version"`
+	DID     did.Identifier `json:"did"`
+	Created int64          `json:"created"`
+	Crypto  cryptoJSON     `json:"crypto"`
+}
+
+// backupContentJSON is the encrypted content of the wallet bundle.
+type backupContentJSON struct {
+	PrivateKey string             `json:"private_key"`
+	Wallet     *wallet.WalletInfo `json:"wallet,omitempty"`
+}
+
+// ExportWallet returns an encrypted backup of the wallet DID, e.g. for the
+// disaster recovery of issuer wallets: the key stored with passphrase and
+// the wallet info queried with client, encrypted with backupPassphrase. The
+// wallet info is left out when client is nil.
+//
+// The backup is restored with ImportWallet on another machine.
+//
+func (ks *KeyStore) ExportWallet(client api.Client, header http.Header, id did.Identifier, passphrase, backupPassphrase string) ([]byte, error) {
+	if backupPassphrase == "" {
+		return nil, fmt.Errorf("backup passphrase must be set")
+	}
+	key, err := ks.Key(id, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	content := &backupContentJSON{PrivateKey: utils.EncodeBase64(key.PrivateKey)}
+	if client != nil {
+		if content.Wallet, err = client.QueryWalletInfo(header, id); err != nil {
+			return nil, fmt.Errorf("query wallet info fail: %v", err)
+		}
+	}
+	plain, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	c, err := encryptData(plain, backupPassphrase, ks.scryptN, ks.scryptP)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&backupJSON{
+		Version: backupVersion,
+		DID:     id,
+		Created: time.Now().Unix(),
+		Crypto:  *c,
+	})
+}
+
+// ImportWallet decrypts the wallet backup with backupPassphrase and stores
+// its key encrypted with newPassphrase, ErrKeyExists is returned if the
+// keystore already has a key of the DID.
+//
+func (ks *KeyStore) ImportWallet(data []byte, backupPassphrase, newPassphrase string) (*WalletBackup, error) {
+	var b backupJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("wallet backup invalid: %v", err)
+	}
+	if b.Version != backupVersion {
+		return nil, fmt.Errorf("wallet backup version not supported: %d", b.Version)
+	}
+	if b.DID == "" {
+		return nil, fmt.Errorf("wallet backup did must be set")
+	}
+	plain, err := decryptData(&b.Crypto, backupPassphrase)
+	if err != nil {
+		return nil, err
+	}
+	var content backupContentJSON
+	if err = json.Unmarshal(plain, &content); err != nil {
+		return nil, fmt.Errorf("wallet backup content invalid: %v", err)
+	}
+	if content.Wallet != nil && content.Wallet.Id != b.DID {
+		return nil, fmt.Errorf("wallet backup of %s holds the wallet info of %s", b.DID, content.Wallet.Id)
+	}
+	key, err := NewKey(b.DID, content.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if err = ks.Store(key, newPassphrase); err != nil {
+		return nil, err
+	}
+	return &WalletBackup{Key: key, Wallet: content.Wallet, Created: b.Created}, nil
+}
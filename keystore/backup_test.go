@@ -0,0 +1,114 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keystore
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/wallet-sdk-go/mocks"
+)
+
+func TestKeyStoreExportImportWallet(t *testing.T) {
+	src, cleanupSrc := newTestKeyStore(t)
+	defer cleanupSrc()
+	dst, cleanupDst := newTestKeyStore(t)
+	defer cleanupDst()
+
+	const id = did.Identifier("did:axn:issuer")
+	key, err := src.Generate(id, "issuer")
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	client := &mocks.Client{
+		QueryWalletInfoFunc: func(header http.Header, id did.Identifier) (*wallet.WalletInfo, error) {
+			return &wallet.WalletInfo{Id: id, Email: "issuer@example.com"}, nil
+		},
+	}
+
+	if _, err = src.ExportWallet(client, nil, id, "wrong", "backup"); err != ErrDecrypt {
+		t.Fatalf("err should be ErrDecrypt, not %v", err)
+	}
+	data, err := src.ExportWallet(client, nil, id, "issuer", "backup")
+	if err != nil {
+		t.Fatalf("export wallet fail: %v", err)
+	}
+	if bytes.Contains(data, []byte("issuer@example.com")) {
+		t.Fatalf("wallet info should be encrypted in the backup")
+	}
+
+	if _, err = dst.ImportWallet(data, "issuer", "new"); err != ErrDecrypt {
+		t.Fatalf("err should be ErrDecrypt, not %v", err)
+	}
+	backup, err := dst.ImportWallet(data, "backup", "new")
+	if err != nil {
+		t.Fatalf("import wallet fail: %v", err)
+	}
+	if backup.Wallet == nil || backup.Wallet.Id != id || backup.Wallet.Email != "issuer@example.com" {
+		t.Fatalf("wallet info mismatch: %#v", backup.Wallet)
+	}
+	if backup.Created == 0 {
+		t.Fatalf("backup created time should be set")
+	}
+	imported, err := dst.Key(id, "new")
+	if err != nil {
+		t.Fatalf("get imported key fail: %v", err)
+	}
+	if !bytes.Equal(imported.PrivateKey, key.PrivateKey) {
+		t.Fatalf("imported key should be the exported key")
+	}
+	if _, err = dst.ImportWallet(data, "backup", "new"); err != ErrKeyExists {
+		t.Fatalf("err should be ErrKeyExists, not %v", err)
+	}
+}
+
+func TestKeyStoreExportWalletFail(t *testing.T) {
+	ks, cleanup := newTestKeyStore(t)
+	defer cleanup()
+
+	const id = did.Identifier("did:axn:issuer")
+	if _, err := ks.Generate(id, "issuer"); err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	client := &mocks.Client{
+		QueryWalletInfoFunc: func(header http.Header, id did.Identifier) (*wallet.WalletInfo, error) {
+			return nil, fmt.Errorf("gateway down")
+		},
+	}
+	if _, err := ks.ExportWallet(client, nil, id, "issuer", "backup"); err == nil {
+		t.Fatalf("export should fail when the wallet info can not be queried")
+	}
+
+	// without client only the key is exported
+	data, err := ks.ExportWallet(nil, nil, id, "issuer", "backup")
+	if err != nil {
+		t.Fatalf("export wallet fail: %v", err)
+	}
+	ks2, cleanup2 := newTestKeyStore(t)
+	defer cleanup2()
+	backup, err := ks2.ImportWallet(data, "backup", "issuer")
+	if err != nil {
+		t.Fatalf("import wallet fail: %v", err)
+	}
+	if backup.Wallet != nil {
+		t.Fatalf("wallet info should be nil, got %#v", backup.Wallet)
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keystore
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/crypto/ed25519"
+)
+
+const (
+	// DefaultBasePath is the derivation path of the wallet accounts, the
+	// key of account i is derived at DefaultBasePath/i'. It must never
+	// change, or the keys could not be restored from the mnemonics.
+	DefaultBasePath = "m/44'/9000'"
+
+	// HardenedOffset is the first hardened child index, ed25519 keys only
+	// derive hardened children.
+	HardenedOffset uint32 = 0x80000000
+
+	// DefaultMnemonicBits is the entropy size of the generated mnemonics,
+	// 256 bits for 24 words.
+	DefaultMnemonicBits = 256
+
+	ed25519Curve = "ed25519 seed"
+)
+
+// NewMnemonic returns a new random BIP-39 mnemonic with bits of entropy,
+// 128 to 256 bits in steps of 32, i.e. 12 to 24 words.
+//
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// ValidateMnemonic checks the words and the checksum of the mnemonic.
+//
+func ValidateMnemonic(mnemonic string) error {
+	if _, err := bip39.EntropyFromMnemonic(mnemonic); err != nil {
+		return fmt.Errorf("mnemonic invalid: %v", err)
+	}
+	return nil
+}
+
+// ExtendedKey is a node of the SLIP-0010 ed25519 key tree.
+//
+type ExtendedKey struct {
+	key       []byte
+	chainCode []byte
+}
+
+// NewMasterKey returns the root of the key tree of the seed.
+//
+func NewMasterKey(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("seed invalid: %d bytes", len(seed))
+	}
+	return newExtendedKey([]byte(ed25519Curve), seed), nil
+}
+
+func newExtendedKey(key, data []byte) *ExtendedKey {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	return &ExtendedKey{key: sum[:32], chainCode: sum[32:]}
+}
+
+// Child returns the hardened child at index, index is taken as the offset
+// from HardenedOffset if lower.
+//
+func (k *ExtendedKey) Child(index uint32) *ExtendedKey {
+	if index < HardenedOffset {
+		index += HardenedOffset
+	}
+	data := make([]byte, 1+32+4)
+	copy(data[1:], k.key)
+	binary.BigEndian.PutUint32(data[33:], index)
+	return newExtendedKey(k.chainCode, data)
+}
+
+// Derive returns the key at path, e.g. m/44'/9000'/0'. Every index must be
+// hardened.
+//
+func (k *ExtendedKey) Derive(path string) (*ExtendedKey, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) == 0 || parts[0] != "m" {
+		return nil, fmt.Errorf("derivation path invalid: %s", path)
+	}
+	key := k
+	for _, part := range parts[1:] {
+		if !strings.HasSuffix(part, "'") {
+			return nil, fmt.Errorf("derivation path invalid: %s, ed25519 keys only derive hardened children", path)
+		}
+		index, err := strconv.ParseUint(strings.TrimSuffix(part, "'"), 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("derivation path invalid: %s", path)
+		}
+		key = key.Child(uint32(index))
+	}
+	return key, nil
+}
+
+// PrivateKey returns the ed25519 private key of the node.
+//
+func (k *ExtendedKey) PrivateKey() ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(k.key)
+}
+
+// HDWallet derives the keys of wallet accounts from a BIP-39 mnemonic, so
+// that the signing keys of all the wallet DIDs can be restored from the one
+// seed phrase.
+//
+type HDWallet struct {
+	// BasePath is the path the accounts are derived from, DefaultBasePath
+	// by default
+	BasePath string
+
+	master *ExtendedKey
+}
+
+// NewHDWallet returns the HD wallet of the mnemonic and the optional BIP-39
+// passphrase.
+//
+func NewHDWallet(mnemonic, passphrase string) (*HDWallet, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("mnemonic invalid: %v", err)
+	}
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	return &HDWallet{BasePath: DefaultBasePath, master: master}, nil
+}
+
+// DerivationPath returns the derivation path of the account.
+//
+func (hw *HDWallet) DerivationPath(account uint32) string {
+	return fmt.Sprintf("%s/%d'", hw.BasePath, account)
+}
+
+// Key returns the key of the account, which signs for the DID.
+//
+func (hw *HDWallet) Key(id did.Identifier, account uint32) (*Key, error) {
+	if id == "" {
+		return nil, fmt.Errorf("key id must be set")
+	}
+	if account >= HardenedOffset {
+		return nil, fmt.Errorf("account index invalid: %d", account)
+	}
+	node, err := hw.master.Derive(hw.DerivationPath(account))
+	if err != nil {
+		return nil, err
+	}
+	key := &Key{ID: id, PrivateKey: node.PrivateKey()}
+	key.PublicKey = key.PrivateKey.Public().(ed25519.PublicKey)
+	return key, nil
+}
+
+// SignatureParam returns the signature params of the DID signed with the
+// key of the account.
+//
+func (hw *HDWallet) SignatureParam(id did.Identifier, account uint32) (*pki.SignatureParam, error) {
+	key, err := hw.Key(id, account)
+	if err != nil {
+		return nil, err
+	}
+	return key.SignatureParam()
+}
@@ -0,0 +1,103 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keystore
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// TestDerive checks the test vector 1 for ed25519 of SLIP-0010.
+func TestDerive(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		t.Fatalf("new master key fail: %v", err)
+	}
+	cases := []struct {
+		path, chainCode, key string
+	}{
+		{"m", "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb", "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7"},
+		{"m/0'", "8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69", "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3"},
+	}
+	for _, c := range cases {
+		k, err := master.Derive(c.path)
+		if err != nil {
+			t.Fatalf("derive %s fail: %v", c.path, err)
+		}
+		if hex.EncodeToString(k.chainCode) != c.chainCode || hex.EncodeToString(k.key) != c.key {
+			t.Fatalf("key at %s mismatch", c.path)
+		}
+	}
+	if _, err = master.Derive("m/0"); err == nil {
+		t.Fatalf("err should not be nil for a non hardened path")
+	}
+}
+
+func TestHDWallet(t *testing.T) {
+	mnemonic, err := NewMnemonic(DefaultMnemonicBits)
+	if err != nil {
+		t.Fatalf("new mnemonic fail: %v", err)
+	}
+	if err = ValidateMnemonic(mnemonic); err != nil {
+		t.Fatalf("validate mnemonic fail: %v", err)
+	}
+
+	hw, err := NewHDWallet(mnemonic, "")
+	if err != nil {
+		t.Fatalf("new hd wallet fail: %v", err)
+	}
+	alice, err := hw.Key("did:axn:alice", 0)
+	if err != nil {
+		t.Fatalf("derive key fail: %v", err)
+	}
+	bob, err := hw.Key("did:axn:bob", 1)
+	if err != nil {
+		t.Fatalf("derive key fail: %v", err)
+	}
+	if bytes.Equal(alice.PrivateKey, bob.PrivateKey) {
+		t.Fatalf("accounts should have different keys")
+	}
+
+	restored, err := NewHDWallet(mnemonic, "")
+	if err != nil {
+		t.Fatalf("restore hd wallet fail: %v", err)
+	}
+	params, err := restored.SignatureParam("did:axn:bob", 1)
+	if err != nil {
+		t.Fatalf("signature params fail: %v", err)
+	}
+	key, err := NewKey("did:axn:bob", params.PrivateKey)
+	if err != nil {
+		t.Fatalf("new key fail: %v", err)
+	}
+	if !bytes.Equal(key.PrivateKey, bob.PrivateKey) {
+		t.Fatalf("restored key should be the derived key")
+	}
+
+	other, err := NewHDWallet(mnemonic, "passphrase")
+	if err != nil {
+		t.Fatalf("new hd wallet fail: %v", err)
+	}
+	if k, _ := other.Key("did:axn:alice", 0); bytes.Equal(k.PrivateKey, alice.PrivateKey) {
+		t.Fatalf("passphrase should change the keys")
+	}
+	if _, err = NewHDWallet(mnemonic+" extra", ""); err == nil {
+		t.Fatalf("err should not be nil for an invalid mnemonic")
+	}
+}
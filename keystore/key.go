@@ -0,0 +1,340 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/utils"
+	"github.com/arxanchain/wallet-sdk-go/api"
+	"github.com/arxanchain/wallet-sdk-go/signer"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// StandardScryptN is the scrypt N parameter of the keys stored at rest
+	StandardScryptN = 1 << 18
+	// StandardScryptP is the scrypt P parameter of the keys stored at rest
+	StandardScryptP = 1
+	// LightScryptN is a cheaper scrypt N parameter, e.g. for tests or
+	// constrained devices
+	LightScryptN = 1 << 12
+	// LightScryptP is the scrypt P parameter used with LightScryptN
+	LightScryptP = 6
+
+	version      = 3
+	scryptR      = 8
+	scryptDKLen  = 32
+	cipherName   = "aes-128-ctr"
+	kdfName      = "scrypt"
+	saltSize     = 32
+	maxScryptN   = 1 << 22
+	maxScryptP   = 16
+	maxScryptDKL = 64
+)
+
+// ErrDecrypt is returned when the key file cannot be decrypted with the
+// passphrase.
+//
+var ErrDecrypt = fmt.Errorf("could not decrypt key with given passphrase")
+
+// Key is an ed25519 key pair of a wallet DID.
+//
+type Key struct {
+	// ID is the DID the key belongs to
+	ID did.Identifier
+	// PublicKey is the ed25519 public key
+	PublicKey ed25519.PublicKey
+	// PrivateKey is the ed25519 private key
+	PrivateKey ed25519.PrivateKey
+}
+
+// GenerateKey returns a new random ed25519 key pair of the DID.
+//
+func GenerateKey(id did.Identifier) (*Key, error) {
+	if id == "" {
+		return nil, fmt.Errorf("key id must be set")
+	}
+	pub, pri, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{ID: id, PublicKey: pub, PrivateKey: pri}, nil
+}
+
+// NewKey returns the key pair of the DID from the base64 ed25519 private
+// key, as used in the signature params.
+//
+func NewKey(id did.Identifier, privateKey string) (*Key, error) {
+	if id == "" {
+		return nil, fmt.Errorf("key id must be set")
+	}
+	pri, err := utils.DecodeBase64(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("private key invalid: %v", err)
+	}
+	if len(pri) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("private key invalid: %d bytes", len(pri))
+	}
+	key := &Key{ID: id, PrivateKey: ed25519.PrivateKey(pri)}
+	key.PublicKey = key.PrivateKey.Public().(ed25519.PublicKey)
+	return key, nil
+}
+
+// SignatureParam returns the signature params of the key, with a random
+// nonce and the current time, to pass to the methods of the wallet client
+// taking signature params or to api.NewKeySigner.
+//
+func (k *Key) SignatureParam() (*pki.SignatureParam, error) {
+	nonce, err := signer.NewNonce()
+	if err != nil {
+		return nil, err
+	}
+	return &pki.SignatureParam{
+		Creator:    k.ID,
+		Created:    time.Now().Unix(),
+		Nonce:      nonce,
+		PrivateKey: utils.EncodeBase64(k.PrivateKey),
+	}, nil
+}
+
+// PublicKeyBase64 returns the base64 public key, as registered for the
+// wallet DID.
+//
+func (k *Key) PublicKeyBase64() string {
+	return utils.EncodeBase64(k.PublicKey)
+}
+
+// Signer returns a signer of the key, to be set on the wallet client with
+// SetSigner. The signer holds its own copy of the private key, zeroed by
+// its Close method.
+//
+func (k *Key) Signer() (*api.KeySigner, error) {
+	pri := make([]byte, len(k.PrivateKey))
+	copy(pri, k.PrivateKey)
+	return api.NewSecretKeySigner(k.ID, api.AlgorithmEd25519, api.NewSecretBytes(pri))
+}
+
+// Zero overwrites the private key with zeros, once the key is no longer
+// needed.
+//
+func (k *Key) Zero() {
+	for i := range k.PrivateKey {
+		k.PrivateKey[i] = 0
+	}
+}
+
+type cipherParamsJSON struct {
+	IV string `json:"iv"`
+}
+
+type kdfParamsJSON struct {
+	DKLen int    `json:"dklen"`
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	Salt  string `json:"salt"`
+}
+
+type cryptoJSON struct {
+	Cipher       string           `json:"cipher"`
+	CipherText   string           `json:"ciphertext"`
+	CipherParams cipherParamsJSON `json:"cipherparams"`
+	KDF          string           `json:"kdf"`
+	KDFParams    kdfParamsJSON    `json:"kdfparams"`
+	MAC          string           `json:"mac"`
+}
+
+type encryptedKeyJSON struct {
+	Version   int            `json:"version"`
+	ID        string         `json:"id"`
+	DID       did.Identifier `json:"did"`
+	PublicKey string         `json:"public_key"`
+	Crypto    cryptoJSON     `json:"crypto"`
+}
+
+// EncryptKey encrypts the key with the passphrase and returns the key file,
+// in the Web3 Secret Storage (keystore v3) JSON layout: the key is derived
+// from the passphrase with scrypt and the private key encrypted with
+// AES-128-CTR. The MAC is the SHA-256 of the second half of the derived key
+// and the ciphertext, in place of the Keccak-256 of the Ethereum keystores.
+//
+func EncryptKey(key *Key, passphrase string, scryptN, scryptP int) ([]byte, error) {
+	if key == nil || key.ID == "" || len(key.PrivateKey) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("key invalid")
+	}
+	c, err := encryptData(key.PrivateKey, passphrase, scryptN, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	uuid := make([]byte, 16)
+	if _, err = rand.Read(uuid); err != nil {
+		return nil, err
+	}
+	uuid[6] = uuid[6]&0x0f | 0x40
+	uuid[8] = uuid[8]&0x3f | 0x80
+
+	return json.Marshal(&encryptedKeyJSON{
+		Version:   version,
+		ID:        fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:]),
+		DID:       key.ID,
+		PublicKey: utils.EncodeBase64(key.PublicKey),
+		Crypto:    *c,
+	})
+}
+
+// DecryptKey decrypts the key file with the passphrase, ErrDecrypt is
+// returned if the passphrase is wrong.
+//
+func DecryptKey(data []byte, passphrase string) (*Key, error) {
+	k, err := parseKeyFile(data)
+	if err != nil {
+		return nil, err
+	}
+	pri, err := decryptData(&k.Crypto, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if len(pri) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("key file private key invalid: %d bytes", len(pri))
+	}
+
+	key := &Key{ID: k.DID, PrivateKey: ed25519.PrivateKey(pri)}
+	key.PublicKey = key.PrivateKey.Public().(ed25519.PublicKey)
+	if k.PublicKey != "" && k.PublicKey != utils.EncodeBase64(key.PublicKey) {
+		return nil, fmt.Errorf("key file public key mismatch")
+	}
+	return key, nil
+}
+
+// parseKeyFile decodes the key file without decrypting it.
+func parseKeyFile(data []byte) (*encryptedKeyJSON, error) {
+	var k encryptedKeyJSON
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("key file invalid: %v", err)
+	}
+	if k.Version != version {
+		return nil, fmt.Errorf("key file version not supported: %d", k.Version)
+	}
+	if k.DID == "" {
+		return nil, fmt.Errorf("key file did must be set")
+	}
+	return &k, nil
+}
+
+// encryptData encrypts data with the passphrase: the key is derived from
+// the passphrase with scrypt and data encrypted with AES-128-CTR.
+func encryptData(data []byte, passphrase string, scryptN, scryptP int) (*cryptoJSON, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err = rand.Read(iv); err != nil {
+		return nil, err
+	}
+	cipherText, err := aesCTR(derivedKey[:16], iv, data)
+	if err != nil {
+		return nil, err
+	}
+	return &cryptoJSON{
+		Cipher:       cipherName,
+		CipherText:   hex.EncodeToString(cipherText),
+		CipherParams: cipherParamsJSON{IV: hex.EncodeToString(iv)},
+		KDF:          kdfName,
+		KDFParams: kdfParamsJSON{
+			DKLen: scryptDKLen,
+			N:     scryptN,
+			R:     scryptR,
+			P:     scryptP,
+			Salt:  hex.EncodeToString(salt),
+		},
+		MAC: hex.EncodeToString(keyMAC(derivedKey, cipherText)),
+	}, nil
+}
+
+// decryptData decrypts the data encrypted by encryptData, ErrDecrypt is
+// returned if the passphrase is wrong.
+func decryptData(c *cryptoJSON, passphrase string) ([]byte, error) {
+	if c.Cipher != cipherName || c.KDF != kdfName {
+		return nil, fmt.Errorf("key file cipher not supported: %s/%s", c.Cipher, c.KDF)
+	}
+	p := &c.KDFParams
+	if p.N <= 1 || p.N > maxScryptN || p.R != scryptR || p.P < 1 || p.P > maxScryptP ||
+		p.DKLen < scryptDKLen || p.DKLen > maxScryptDKL {
+		return nil, fmt.Errorf("key file kdf params invalid")
+	}
+	salt, err := hex.DecodeString(p.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("key file salt invalid: %v", err)
+	}
+	iv, err := hex.DecodeString(c.CipherParams.IV)
+	if err != nil || len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("key file iv invalid")
+	}
+	cipherText, err := hex.DecodeString(c.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("key file ciphertext invalid: %v", err)
+	}
+	mac, err := hex.DecodeString(c.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("key file mac invalid: %v", err)
+	}
+
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, p.N, p.R, p.P, p.DKLen)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(keyMAC(derivedKey, cipherText), mac) {
+		return nil, ErrDecrypt
+	}
+	return aesCTR(derivedKey[:16], iv, cipherText)
+}
+
+func keyMAC(derivedKey, cipherText []byte) []byte {
+	var b bytes.Buffer
+	b.Write(derivedKey[16:32])
+	b.Write(cipherText)
+	sum := sha256.Sum256(b.Bytes())
+	return sum[:]
+}
+
+func aesCTR(key, iv, in []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(in))
+	cipher.NewCTR(block, iv).XORKeyStream(out, in)
+	return out, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keystore
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/utils"
+)
+
+func TestEncryptKey(t *testing.T) {
+	key, err := GenerateKey("did:axn:alice")
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	data, err := EncryptKey(key, "passphrase", LightScryptN, LightScryptP)
+	if err != nil {
+		t.Fatalf("encrypt key fail: %v", err)
+	}
+	if bytes.Contains(data, []byte(utils.EncodeBase64(key.PrivateKey))) {
+		t.Fatalf("key file should not hold the private key in clear")
+	}
+
+	if _, err = DecryptKey(data, "wrong passphrase"); err != ErrDecrypt {
+		t.Fatalf("err should be ErrDecrypt, not %v", err)
+	}
+	decrypted, err := DecryptKey(data, "passphrase")
+	if err != nil {
+		t.Fatalf("decrypt key fail: %v", err)
+	}
+	if decrypted.ID != key.ID || !bytes.Equal(decrypted.PrivateKey, key.PrivateKey) || !bytes.Equal(decrypted.PublicKey, key.PublicKey) {
+		t.Fatalf("decrypted key should be the encrypted key")
+	}
+}
+
+func TestNewKey(t *testing.T) {
+	key, err := GenerateKey("did:axn:alice")
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	params, err := key.SignatureParam()
+	if err != nil {
+		t.Fatalf("signature params fail: %v", err)
+	}
+	if params.Creator != key.ID || params.Nonce == "" || params.Created == 0 {
+		t.Fatalf("signature params mismatch: %#v", params)
+	}
+
+	imported, err := NewKey("did:axn:alice", params.PrivateKey)
+	if err != nil {
+		t.Fatalf("new key fail: %v", err)
+	}
+	if !bytes.Equal(imported.PublicKey, key.PublicKey) {
+		t.Fatalf("public key should be derived from the private key")
+	}
+	if _, err = NewKey("did:axn:alice", utils.EncodeBase64([]byte("short"))); err == nil {
+		t.Fatalf("err should not be nil for an invalid private key")
+	}
+}
+
+func TestKeySignerZero(t *testing.T) {
+	key, err := GenerateKey("did:axn:alice")
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	signer, err := key.Signer()
+	if err != nil {
+		t.Fatalf("new signer fail: %v", err)
+	}
+	key.Zero()
+	for _, b := range key.PrivateKey {
+		if b != 0 {
+			t.Fatalf("private key should be zeroed")
+		}
+	}
+
+	// the signer holds its own copy of the key
+	body, err := signer.Sign([]byte("payload"))
+	if err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+	if body.Creator != key.ID || body.Nonce == "" || body.Created == 0 {
+		t.Fatalf("signature body mismatch: %#v", body)
+	}
+	signer.Close()
+	if _, err = signer.Sign([]byte("payload")); err == nil {
+		t.Fatalf("closed signer should not sign")
+	}
+}
@@ -0,0 +1,344 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package keystore stores ed25519 key pairs of wallet DIDs encrypted at rest,
+// one key file per DID in a directory, so that applications need not keep
+// raw base64 private keys around.
+//
+// The keys are used to sign the wallet requests through their signature
+// params or as an api.Signer:
+//
+//	ks, err := keystore.NewKeyStore("/var/lib/wallet/keys", keystore.StandardScryptN, keystore.StandardScryptP)
+//	key, err := ks.Generate("did:axn:issuer", passphrase)
+//	signer, err := ks.Signer("did:axn:issuer", passphrase)
+//	client.SetSigner("did:axn:issuer", signer)
+//
+// Keys can also be derived from a BIP-39 mnemonic with HDWallet, so that the
+// keys of all the wallet DIDs are restored from one seed phrase.
+package keystore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+const (
+	keyFileExt     = ".json"
+	pendingFileExt = ".pending"
+)
+
+var (
+	// ErrKeyNotFound is returned when the keystore has no key of the DID.
+	//
+	ErrKeyNotFound = fmt.Errorf("key not found")
+	// ErrKeyExists is returned when storing a key of a DID the keystore
+	// already has a key of.
+	//
+	ErrKeyExists = fmt.Errorf("key already exists")
+	// ErrRotationPending is returned by Rotate when a previous rotation of
+	// the key was interrupted, see CompleteRotation and AbortRotation.
+	//
+	ErrRotationPending = fmt.Errorf("key rotation pending")
+)
+
+// KeyStore stores encrypted keys in a directory.
+//
+type KeyStore struct {
+	dir     string
+	scryptN int
+	scryptP int
+	mu      sync.Mutex
+}
+
+// NewKeyStore returns a keystore storing the keys in dir, encrypted with
+// the scrypt parameters. The directory is created if it does not exist.
+//
+func NewKeyStore(dir string, scryptN, scryptP int) (*KeyStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("keystore dir must be set")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &KeyStore{dir: dir, scryptN: scryptN, scryptP: scryptP}, nil
+}
+
+// Generate generates a new key pair of the DID and stores it encrypted
+// with the passphrase.
+//
+func (ks *KeyStore) Generate(id did.Identifier, passphrase string) (*Key, error) {
+	key, err := GenerateKey(id)
+	if err != nil {
+		return nil, err
+	}
+	if err = ks.Store(key, passphrase); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Store stores the key encrypted with the passphrase, ErrKeyExists is
+// returned if the keystore already has a key of the DID.
+//
+func (ks *KeyStore) Store(key *Key, passphrase string) error {
+	data, err := EncryptKey(key, passphrase, ks.scryptN, ks.scryptP)
+	if err != nil {
+		return err
+	}
+	return ks.writeKeyFile(key.ID, data)
+}
+
+// Key returns the key of the DID decrypted with the passphrase.
+//
+func (ks *KeyStore) Key(id did.Identifier, passphrase string) (*Key, error) {
+	data, err := ks.readKeyFile(id)
+	if err != nil {
+		return nil, err
+	}
+	key, err := DecryptKey(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if key.ID != id {
+		return nil, fmt.Errorf("key file of %s holds the key of %s", id, key.ID)
+	}
+	return key, nil
+}
+
+// List returns the DIDs of the stored keys, sorted.
+//
+func (ks *KeyStore) List() ([]did.Identifier, error) {
+	files, err := ioutil.ReadDir(ks.dir)
+	if err != nil {
+		return nil, err
+	}
+	var ids []did.Identifier
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), keyFileExt) {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(ks.dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		k, err := parseKeyFile(data)
+		if err != nil {
+			// skip the files which are not key files
+			continue
+		}
+		ids = append(ids, k.DID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// Delete deletes the key of the DID, the passphrase is checked first.
+//
+func (ks *KeyStore) Delete(id did.Identifier, passphrase string) error {
+	if _, err := ks.Key(id, passphrase); err != nil {
+		return err
+	}
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	return os.Remove(ks.keyFilePath(id))
+}
+
+// Export returns the key file of the DID, encrypted with newPassphrase, to
+// be imported in another keystore.
+//
+func (ks *KeyStore) Export(id did.Identifier, passphrase, newPassphrase string) ([]byte, error) {
+	key, err := ks.Key(id, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return EncryptKey(key, newPassphrase, ks.scryptN, ks.scryptP)
+}
+
+// Import decrypts the key file with passphrase and stores the key encrypted
+// with newPassphrase, ErrKeyExists is returned if the keystore already has
+// a key of the DID.
+//
+func (ks *KeyStore) Import(data []byte, passphrase, newPassphrase string) (*Key, error) {
+	key, err := DecryptKey(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if err = ks.Store(key, newPassphrase); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Signer returns a signer of the key of the DID, to be set on the wallet
+// client with SetSigner.
+//
+func (ks *KeyStore) Signer(id did.Identifier, passphrase string) (*api.KeySigner, error) {
+	key, err := ks.Key(id, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return key.Signer()
+}
+
+// Rotate replaces the key of the DID by a new key, stored encrypted with
+// the same passphrase. register is called with the current and the new key
+// to register the new public key, typically with the RotateWalletKey method
+// of the wallet client:
+//
+//	key, err := ks.Rotate(id, passphrase, func(oldKey, newKey *keystore.Key) error {
+//		oldParams, err := oldKey.SignatureParam()
+//		...
+//		newSigner, err := newKey.Signer()
+//		...
+//		_, err = client.RotateWalletKey(header, id, newKey.PublicKeyBase64(), newSigner, oldParams)
+//		return err
+//	})
+//
+// The new key is written to a pending file before register is called and
+// replaces the key file atomically once register succeeds, the current key
+// is kept if it fails. If the process stops in between, the pending file is
+// kept and Rotate returns ErrRotationPending until the rotation is settled
+// with CompleteRotation or AbortRotation, depending on whether the new key
+// has been registered.
+//
+func (ks *KeyStore) Rotate(id did.Identifier, passphrase string, register func(oldKey, newKey *Key) error) (*Key, error) {
+	if register == nil {
+		return nil, fmt.Errorf("key rotation register func must be set")
+	}
+	oldKey, err := ks.Key(id, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	newKey, err := GenerateKey(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := EncryptKey(newKey, passphrase, ks.scryptN, ks.scryptP)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := ks.keyFilePath(id) + pendingFileExt
+	ks.mu.Lock()
+	if _, err = os.Stat(pending); err == nil {
+		ks.mu.Unlock()
+		return nil, ErrRotationPending
+	}
+	err = ks.writeFile(pending, data)
+	ks.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = register(oldKey, newKey); err != nil {
+		ks.mu.Lock()
+		os.Remove(pending)
+		ks.mu.Unlock()
+		return nil, err
+	}
+	if err = ks.CompleteRotation(id); err != nil {
+		return nil, err
+	}
+	return newKey, nil
+}
+
+// CompleteRotation replaces the key of the DID by the new key of an
+// interrupted rotation, once the new key is known to be registered.
+//
+func (ks *KeyStore) CompleteRotation(id did.Identifier) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	path := ks.keyFilePath(id)
+	err := os.Rename(path+pendingFileExt, path)
+	if os.IsNotExist(err) {
+		return ErrKeyNotFound
+	}
+	return err
+}
+
+// AbortRotation discards the new key of an interrupted rotation, keeping
+// the current key of the DID.
+//
+func (ks *KeyStore) AbortRotation(id did.Identifier) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	err := os.Remove(ks.keyFilePath(id) + pendingFileExt)
+	if os.IsNotExist(err) {
+		return ErrKeyNotFound
+	}
+	return err
+}
+
+// keyFilePath returns the path of the key file of the DID, named after the
+// hash of the DID as DIDs are not valid file names on every platform.
+func (ks *KeyStore) keyFilePath(id did.Identifier) string {
+	sum := sha256.Sum256([]byte(id))
+	return filepath.Join(ks.dir, hex.EncodeToString(sum[:])+keyFileExt)
+}
+
+func (ks *KeyStore) readKeyFile(id did.Identifier) ([]byte, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	data, err := ioutil.ReadFile(ks.keyFilePath(id))
+	if os.IsNotExist(err) {
+		return nil, ErrKeyNotFound
+	}
+	return data, err
+}
+
+// writeKeyFile writes the key file of the DID, ErrKeyExists is returned if
+// there is one already.
+func (ks *KeyStore) writeKeyFile(id did.Identifier, data []byte) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	path := ks.keyFilePath(id)
+	if _, err := os.Stat(path); err == nil {
+		return ErrKeyExists
+	}
+	return ks.writeFile(path, data)
+}
+
+// writeFile writes the file through a temporary file, so that a failed
+// write never leaves a partial key file.
+func (ks *KeyStore) writeFile(path string, data []byte) error {
+	f, err := ioutil.TempFile(ks.dir, ".key-")
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err == nil {
+		err = f.Chmod(0600)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
@@ -0,0 +1,218 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keystore
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+)
+
+func newTestKeyStore(t *testing.T) (*KeyStore, func()) {
+	dir, err := ioutil.TempDir("", "keystore")
+	if err != nil {
+		t.Fatalf("create temp dir fail: %v", err)
+	}
+	ks, err := NewKeyStore(dir, LightScryptN, LightScryptP)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("new keystore fail: %v", err)
+	}
+	return ks, func() { os.RemoveAll(dir) }
+}
+
+func TestKeyStore(t *testing.T) {
+	ks, cleanup := newTestKeyStore(t)
+	defer cleanup()
+
+	bob, err := ks.Generate("did:axn:bob", "bob")
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	if _, err = ks.Generate("did:axn:alice", "alice"); err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	if err = ks.Store(bob, "bob"); err != ErrKeyExists {
+		t.Fatalf("err should be ErrKeyExists, not %v", err)
+	}
+
+	ids, err := ks.List()
+	if err != nil {
+		t.Fatalf("list keys fail: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != "did:axn:alice" || ids[1] != "did:axn:bob" {
+		t.Fatalf("keys should be alice and bob, not %v", ids)
+	}
+
+	key, err := ks.Key("did:axn:bob", "bob")
+	if err != nil {
+		t.Fatalf("get key fail: %v", err)
+	}
+	if !bytes.Equal(key.PrivateKey, bob.PrivateKey) {
+		t.Fatalf("stored key should be the generated key")
+	}
+	if _, err = ks.Key("did:axn:bob", "alice"); err != ErrDecrypt {
+		t.Fatalf("err should be ErrDecrypt, not %v", err)
+	}
+	if _, err = ks.Key("did:axn:carol", "carol"); err != ErrKeyNotFound {
+		t.Fatalf("err should be ErrKeyNotFound, not %v", err)
+	}
+
+	if err = ks.Delete("did:axn:alice", "bob"); err != ErrDecrypt {
+		t.Fatalf("err should be ErrDecrypt, not %v", err)
+	}
+	if err = ks.Delete("did:axn:alice", "alice"); err != nil {
+		t.Fatalf("delete key fail: %v", err)
+	}
+	if ids, _ = ks.List(); len(ids) != 1 {
+		t.Fatalf("one key should be left, not %v", ids)
+	}
+}
+
+func TestKeyStoreExportImport(t *testing.T) {
+	src, cleanupSrc := newTestKeyStore(t)
+	defer cleanupSrc()
+	dst, cleanupDst := newTestKeyStore(t)
+	defer cleanupDst()
+
+	id := did.Identifier("did:axn:bob")
+	key, err := src.Generate(id, "bob")
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+	data, err := src.Export(id, "bob", "transfer")
+	if err != nil {
+		t.Fatalf("export key fail: %v", err)
+	}
+	if _, err = dst.Import(data, "bob", "new"); err != ErrDecrypt {
+		t.Fatalf("err should be ErrDecrypt, not %v", err)
+	}
+	if _, err = dst.Import(data, "transfer", "new"); err != nil {
+		t.Fatalf("import key fail: %v", err)
+	}
+	imported, err := dst.Key(id, "new")
+	if err != nil {
+		t.Fatalf("get imported key fail: %v", err)
+	}
+	if !bytes.Equal(imported.PrivateKey, key.PrivateKey) {
+		t.Fatalf("imported key should be the exported key")
+	}
+
+	signer, err := dst.Signer(id, "new")
+	if err != nil {
+		t.Fatalf("new signer fail: %v", err)
+	}
+	body, err := signer.Sign([]byte("payload"))
+	if err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+	if body.Creator != id || body.SignatureValue == "" {
+		t.Fatalf("signature body mismatch: %#v", body)
+	}
+}
+
+func TestKeyStoreRotate(t *testing.T) {
+	ks, cleanup := newTestKeyStore(t)
+	defer cleanup()
+
+	const id = did.Identifier("did:axn:bob")
+	old, err := ks.Generate(id, "bob")
+	if err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+
+	// failed registration keeps the current key
+	if _, err = ks.Rotate(id, "bob", func(oldKey, newKey *Key) error {
+		return fmt.Errorf("register fail")
+	}); err == nil || err.Error() != "register fail" {
+		t.Fatalf("err should be the register error, not %v", err)
+	}
+	key, err := ks.Key(id, "bob")
+	if err != nil {
+		t.Fatalf("get key fail: %v", err)
+	}
+	if !bytes.Equal(key.PrivateKey, old.PrivateKey) {
+		t.Fatalf("key should be kept when the registration fails")
+	}
+
+	var registered *Key
+	rotated, err := ks.Rotate(id, "bob", func(oldKey, newKey *Key) error {
+		if !bytes.Equal(oldKey.PrivateKey, old.PrivateKey) {
+			t.Fatalf("register should get the current key")
+		}
+		registered = newKey
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("rotate key fail: %v", err)
+	}
+	if registered == nil || !bytes.Equal(rotated.PrivateKey, registered.PrivateKey) {
+		t.Fatalf("rotated key should be the registered key")
+	}
+	key, err = ks.Key(id, "bob")
+	if err != nil {
+		t.Fatalf("get key fail: %v", err)
+	}
+	if !bytes.Equal(key.PrivateKey, rotated.PrivateKey) {
+		t.Fatalf("stored key should be the rotated key")
+	}
+	ids, err := ks.List()
+	if err != nil || len(ids) != 1 {
+		t.Fatalf("keystore should hold one key, got %v %v", ids, err)
+	}
+}
+
+func TestKeyStoreRotateInterrupted(t *testing.T) {
+	ks, cleanup := newTestKeyStore(t)
+	defer cleanup()
+
+	const id = did.Identifier("did:axn:bob")
+	if _, err := ks.Generate(id, "bob"); err != nil {
+		t.Fatalf("generate key fail: %v", err)
+	}
+
+	// a rotation which never completes, as if the process stopped
+	var pending *Key
+	func() {
+		defer func() { recover() }()
+		ks.Rotate(id, "bob", func(oldKey, newKey *Key) error {
+			pending = newKey
+			panic("interrupted")
+		})
+	}()
+
+	if _, err := ks.Rotate(id, "bob", func(oldKey, newKey *Key) error { return nil }); err != ErrRotationPending {
+		t.Fatalf("err should be ErrRotationPending, not %v", err)
+	}
+	if err := ks.CompleteRotation(id); err != nil {
+		t.Fatalf("complete rotation fail: %v", err)
+	}
+	key, err := ks.Key(id, "bob")
+	if err != nil {
+		t.Fatalf("get key fail: %v", err)
+	}
+	if !bytes.Equal(key.PrivateKey, pending.PrivateKey) {
+		t.Fatalf("stored key should be the pending key")
+	}
+	if err = ks.AbortRotation(id); err != ErrKeyNotFound {
+		t.Fatalf("err should be ErrKeyNotFound, not %v", err)
+	}
+}
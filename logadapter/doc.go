@@ -0,0 +1,21 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logadapter adapts loggers which do not implement api.Logger as is.
+//
+// *zap.SugaredLogger and the logrus loggers already implement api.Logger and
+// need no adapter. With Go 1.21 or later, Slog adapts a *slog.Logger.
+package logadapter
@@ -0,0 +1,68 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logadapter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+// slogLogger writes the messages to a *slog.Logger.
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// Slog returns an api.Logger writing the messages to l, a nil l writes to
+// slog.Default.
+//
+func Slog(l *slog.Logger) api.Logger {
+	return &slogLogger{l: l}
+}
+
+func (s *slogLogger) logf(level slog.Level, format string, args []interface{}) {
+	l := s.l
+	if l == nil {
+		l = slog.Default()
+	}
+	ctx := context.Background()
+	if !l.Enabled(ctx, level) {
+		return
+	}
+	l.Log(ctx, level, fmt.Sprintf(format, args...))
+}
+
+func (s *slogLogger) Debugf(format string, args ...interface{}) {
+	s.logf(slog.LevelDebug, format, args)
+}
+
+func (s *slogLogger) Infof(format string, args ...interface{}) {
+	s.logf(slog.LevelInfo, format, args)
+}
+
+func (s *slogLogger) Warnf(format string, args ...interface{}) {
+	s.logf(slog.LevelWarn, format, args)
+}
+
+func (s *slogLogger) Errorf(format string, args ...interface{}) {
+	s.logf(slog.LevelError, format, args)
+}
@@ -0,0 +1,42 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logadapter
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := Slog(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+	logger.Debugf("debug %d", 1)
+	logger.Warnf("upload %s fail", "poe-001")
+
+	out := buf.String()
+	if strings.Contains(out, "debug 1") {
+		t.Fatalf("debug messages should be filtered out by the handler level")
+	}
+	if !strings.Contains(out, "level=WARN") || !strings.Contains(out, `msg="upload poe-001 fail"`) {
+		t.Fatalf("warning should be logged, got %q", out)
+	}
+}
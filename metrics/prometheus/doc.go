@@ -0,0 +1,32 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package prometheus exports the metrics of the wallet client calls to
+// Prometheus.
+//
+// It is built with the prometheus build tag, so that programs not using it
+// do not need the Prometheus client module:
+//
+//	go build -tags prometheus
+//
+// The collector counts the calls, their errors by gateway error code, the
+// retryable errors and the uploaded file bytes, and records the latency of
+// the calls, per endpoint:
+//
+//	collector := prometheus.NewCollector("")
+//	registry.MustRegister(collector)
+//	walletClient.SetMetrics(collector)
+package prometheus
@@ -0,0 +1,138 @@
+//go:build prometheus
+// +build prometheus
+
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prometheus
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+// DefaultNamespace is the namespace of the metrics of the collectors created
+// without namespace.
+//
+const DefaultNamespace = "wallet_sdk"
+
+// Collector is a prometheus.Collector and an api.Metrics recording the
+// calls of the wallet clients it is set on.
+//
+type Collector struct {
+	requests  *prom.CounterVec
+	latency   *prom.HistogramVec
+	errors    *prom.CounterVec
+	retryable *prom.CounterVec
+	uploaded  *prom.CounterVec
+}
+
+// NewCollector returns a Collector with its metrics in the namespace, the
+// DefaultNamespace if empty.
+//
+func NewCollector(namespace string) *Collector {
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	return &Collector{
+		requests: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Calls to the wallet gateway by endpoint and result.",
+		}, []string{"method", "endpoint", "result"}),
+		latency: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of the calls to the wallet gateway.",
+			Buckets:   prom.DefBuckets,
+		}, []string{"method", "endpoint"}),
+		errors: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Failed calls to the wallet gateway by error code.",
+		}, []string{"method", "endpoint", "code"}),
+		retryable: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "retryable_errors_total",
+			Help:      "Calls to the wallet gateway failing with a retryable error, to be sent again.",
+		}, []string{"method", "endpoint"}),
+		uploaded: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "upload_bytes_total",
+			Help:      "File bytes uploaded to the wallet gateway.",
+		}, []string{"endpoint"}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+//
+func (c *Collector) Describe(ch chan<- *prom.Desc) {
+	c.requests.Describe(ch)
+	c.latency.Describe(ch)
+	c.errors.Describe(ch)
+	c.retryable.Describe(ch)
+	c.uploaded.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+//
+func (c *Collector) Collect(ch chan<- prom.Metric) {
+	c.requests.Collect(ch)
+	c.latency.Collect(ch)
+	c.errors.Collect(ch)
+	c.retryable.Collect(ch)
+	c.uploaded.Collect(ch)
+}
+
+// ObserveCall implements api.Metrics.
+//
+func (c *Collector) ObserveCall(call *api.Call, duration time.Duration, err error) {
+	c.latency.WithLabelValues(call.Method, call.Endpoint).Observe(duration.Seconds())
+	if err == nil {
+		c.requests.WithLabelValues(call.Method, call.Endpoint, "ok").Inc()
+		return
+	}
+	c.requests.WithLabelValues(call.Method, call.Endpoint, "error").Inc()
+	c.errors.WithLabelValues(call.Method, call.Endpoint, errorCode(err)).Inc()
+	if api.ClassifyError(err) == api.ErrorRetryable {
+		c.retryable.WithLabelValues(call.Method, call.Endpoint).Inc()
+	}
+}
+
+// ObserveUpload implements api.Metrics.
+//
+func (c *Collector) ObserveUpload(call *api.Call, bytes int64) {
+	c.uploaded.WithLabelValues(call.Endpoint).Add(float64(bytes))
+}
+
+// errorCode returns the error code label of err: the gateway error code,
+// "http_" and the status without code, or "transport" when the gateway did
+// not respond.
+func errorCode(err error) string {
+	var e *api.Error
+	if !errors.As(err, &e) {
+		return "transport"
+	}
+	if e.ErrCode != 0 {
+		return strconv.Itoa(e.ErrCode)
+	}
+	return "http_" + strconv.Itoa(e.StatusCode)
+}
@@ -0,0 +1,1283 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mocks provides a fake api.Client, so code depending on the wallet
+// client can be unit tested without a gateway.
+//
+// Responses are programmed by setting the function fields of Client, the
+// calls are recorded:
+//
+//	client := &mocks.Client{
+//		QueryPOEFunc: func(header http.Header, id did.Identifier) (*wallet.POEPayload, error) {
+//			return &wallet.POEPayload{Id: id}, nil
+//		},
+//	}
+//	poe, err := client.QueryPOE(header, "did:axn:001")
+//
+// Calling a method whose function is not set returns ErrNotImplemented.
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+// ErrNotImplemented is returned by the methods whose function is not set.
+//
+var ErrNotImplemented = fmt.Errorf("mock method not implemented")
+
+// Call is a recorded call of a Client method.
+//
+type Call struct {
+	Method string
+	Header http.Header
+	Args   []interface{}
+}
+
+// Client is a fake api.Client, each method calls the function field
+// of the same name, e.g. QueryPOE calls QueryPOEFunc.
+//
+type Client struct {
+	RegisterFunc                   func(header http.Header, body *wallet.RegisterWalletBody) (*wallet.WalletResponse, error)
+	RegisterSubWalletFunc          func(header http.Header, body *wallet.RegisterSubWalletBody) (*wallet.WalletResponse, error)
+	GetWalletBalanceFunc           func(header http.Header, id did.Identifier) (*wallet.WalletBalance, error)
+	GetWalletInfoFunc              func(header http.Header, id did.Identifier) (*wallet.WalletInfo, error)
+	QueryWalletInfoFunc            func(header http.Header, id did.Identifier) (*wallet.WalletInfo, error)
+	UploadPOEFileFromReaderFunc    func(header http.Header, poeID string, fileName string, size int64, r io.Reader, readOnly bool) (*wallet.UploadResponse, error)
+	QueryWalletBalanceFunc         func(header http.Header, id did.Identifier) (*api.WalletBalances, error)
+	CreatePOEFunc                  func(header http.Header, body *wallet.POEBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	UpdatePOEFunc                  func(header http.Header, body *wallet.POEBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	QueryPOEFunc                   func(header http.Header, id did.Identifier) (*wallet.POEPayload, error)
+	UploadPOEFileFunc              func(header http.Header, poeID string, poeFile string, readOnly bool) (*wallet.UploadResponse, error)
+	IssueCTokenFunc                func(header http.Header, body *wallet.IssueBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	IssueAssetFunc                 func(header http.Header, body *wallet.IssueAssetBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	TransferCTokenFunc             func(header http.Header, body *wallet.TransferCTokenBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	TransferAssetFunc              func(header http.Header, body *wallet.TransferAssetBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	QueryTransactionLogsFunc       func(header http.Header, id did.Identifier, txType string, num, page int32) ([]*pw.UTXO, error)
+	QueryTransactionUTXOFunc       func(header http.Header, id did.Identifier, num, page int32) ([]*pw.UTXO, error)
+	QueryTransactionSTXOFunc       func(header http.Header, id did.Identifier, num, page int32) ([]*pw.UTXO, error)
+	IndexSetFunc                   func(header http.Header, body *wallet.IndexSetPayload) ([]string, error)
+	IndexGetFunc                   func(header http.Header, body *wallet.IndexGetPayload) ([]string, error)
+	PrepareCreatePOEFunc           func(header http.Header, body *wallet.POEBody, signParams *pki.SignatureParam) ([]byte, error)
+	PrepareUpdatePOEFunc           func(header http.Header, body *wallet.POEBody, signParams *pki.SignatureParam) ([]byte, error)
+	PrepareIssueCTokenFunc         func(header http.Header, body *wallet.IssueBody, signParams *pki.SignatureParam) ([]byte, error)
+	PrepareIssueAssetFunc          func(header http.Header, body *wallet.IssueAssetBody, signParams *pki.SignatureParam) ([]byte, error)
+	PrepareTransferCTokenFunc      func(header http.Header, body *wallet.TransferCTokenBody, signParams *pki.SignatureParam) ([]byte, error)
+	PrepareTransferAssetFunc       func(header http.Header, body *wallet.TransferAssetBody, signParams *pki.SignatureParam) ([]byte, error)
+	SubmitPreparedFunc             func(header http.Header, data []byte) (*wallet.WalletResponse, error)
+	SubmitSignedTransactionFunc    func(header http.Header, tx *api.SignedTransaction) (*wallet.WalletResponse, error)
+	SubmitMultiSigTransactionFunc  func(header http.Header, tx *api.PendingTransaction) (*wallet.WalletResponse, error)
+	BurnCTokenFunc                 func(header http.Header, body *api.BurnCTokenBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	BurnCTokenSignFunc             func(header http.Header, body *api.BurnCTokenBody, signParams *pki.SignatureParam) ([]*pw.TX, error)
+	EstimateFeeFunc                func(header http.Header, txType string, body interface{}) (*wallet.Fee, error)
+	TransferCTokenHTLCFunc         func(header http.Header, body *api.HTLCTransferBody, signParams *pki.SignatureParam) (*api.HTLCResponse, error)
+	TransferCTokenMemoFunc         func(header http.Header, body *api.MemoTransferBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	ClaimHTLCFunc                  func(header http.Header, body *api.ClaimHTLCBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	RefundHTLCFunc                 func(header http.Header, body *api.RefundHTLCBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	QueryHTLCFunc                  func(header http.Header, htlcId string) (*api.HTLC, error)
+	ExchangeAssetFunc              func(header http.Header, body *api.SwapBody, buyerSignParams, sellerSignParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	ApproveFunc                    func(header http.Header, body *api.ApproveBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	TransferFromFunc               func(header http.Header, body *api.TransferFromBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	QueryAllowanceFunc             func(header http.Header, owner, spender did.Identifier, tokenId string) (*api.Allowance, error)
+	TransferCTokenBatchFunc        func(header http.Header, transfers []*wallet.TransferCTokenBody, signParams *pki.SignatureParam) (*api.BatchResult, error)
+	IssueAssetBatchFunc            func(header http.Header, assets []*wallet.IssueAssetBody, signParams *pki.SignatureParam) (*api.BatchResult, error)
+	CreatePOEBatchFunc             func(header http.Header, poes []*wallet.POEBody, signParams *pki.SignatureParam) (*api.BatchResult, error)
+	DistributeCTokenFunc           func(header http.Header, body *api.DistributeCTokenBody, opts *api.DistributionOptions, signParams *pki.SignatureParam) (*api.DistributionReport, error)
+	HoldCTokenFunc                 func(header http.Header, body *api.HoldCTokenBody, signParams *pki.SignatureParam) (*api.HoldResponse, error)
+	CaptureHoldFunc                func(header http.Header, body *api.CaptureHoldBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	VoidHoldFunc                   func(header http.Header, body *api.VoidHoldBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	VoidExpiredHoldsFunc           func(header http.Header, id did.Identifier, signParams *pki.SignatureParam) ([]string, error)
+	QueryHoldsFunc                 func(header http.Header, id did.Identifier, status api.HoldStatus) ([]*api.Hold, error)
+	QueryActiveHoldsFunc           func(header http.Header, id did.Identifier) ([]*api.Hold, error)
+	RefundFunc                     func(header http.Header, body *api.RefundBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	QueryRefundsFunc               func(header http.Header, originalTxID string) ([]*api.Refund, error)
+	OpenDisputeFunc                func(header http.Header, body *api.OpenDisputeBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	AddDisputeEvidenceFunc         func(header http.Header, body *api.DisputeEvidenceBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	ResolveDisputeFunc             func(header http.Header, body *api.ResolveDisputeBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	QueryDisputesFunc              func(header http.Header, txID string) ([]*api.Dispute, error)
+	SetWalletKYCStatusFunc         func(header http.Header, body *api.KYCStatusBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	QueryWalletKYCStatusFunc       func(header http.Header, id did.Identifier) (*api.KYCStatus, error)
+	RecordConsentFunc              func(header http.Header, receipt *api.ConsentReceipt, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	RevokeConsentFunc              func(header http.Header, id did.Identifier, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	QueryConsentFunc               func(header http.Header, id did.Identifier) (*api.ConsentReceipt, error)
+	EraseOffchainDataFunc          func(header http.Header, body *api.EraseOffchainBody, signParams *pki.SignatureParam) (*api.OffchainErasure, error)
+	QueryOffchainErasureFunc       func(header http.Header, poeID did.Identifier) (*api.OffchainErasure, error)
+	VerifySignatureRemoteFunc      func(header http.Header, payload []byte, signParams *pki.SignatureParam) (*api.VerifySignatureResult, error)
+	QueryTravelRuleEnvelopeFunc    func(header http.Header, txID string) (*api.TravelRuleEnvelope, error)
+	SubscribeTransactionEventsFunc func(ctx context.Context, header http.Header, walletID did.Identifier, filter *api.EventFilter) (<-chan *api.Event, error)
+	UploadPOEFileChunkedFunc       func(header http.Header, poeID string, poeFile string, chunkSize int64, readOnly bool) (*wallet.UploadResponse, *api.UploadSession, error)
+	StartChunkedUploadFunc         func(header http.Header, poeID string, poeFile string, chunkSize int64, readOnly bool) (*api.UploadSession, error)
+	ContinueChunkedUploadFunc      func(header http.Header, session *api.UploadSession, poeFile string, progress func(uploaded, chunks int)) (*wallet.UploadResponse, error)
+	DownloadPOEFileFunc            func(header http.Header, poeID did.Identifier, out io.Writer) (*wallet.POEPayload, error)
+	DownloadPOEFileDecryptedFunc   func(header http.Header, poeID did.Identifier, out io.Writer, key *api.POEFileKey) (*wallet.POEPayload, *api.POEEncryption, error)
+	QueryTransactionStatusFunc     func(header http.Header, txID string) (*api.TransactionReceipt, error)
+	WaitForConfirmationFunc        func(ctx context.Context, header http.Header, txID string, opts *api.PollOptions) (*api.TransactionReceipt, error)
+	QueryTransactionLogsPageFunc   func(header http.Header, query *api.TransactionLogsQuery) (*api.TransactionLogsPage, error)
+	TransactionLogsFunc            func(header http.Header, query *api.TransactionLogsQuery) *api.TransactionLogIterator
+	RevokePOEFunc                  func(header http.Header, body *api.RevokePOEBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	QueryPOEHistoryFunc            func(header http.Header, id did.Identifier) ([]*api.POEVersion, error)
+	VerifyPOEFunc                  func(header http.Header, id did.Identifier, file io.Reader) (*api.POEVerification, error)
+	AttachPOEFileFunc              func(header http.Header, poeID did.Identifier, name string, fileName string, size int64, r io.Reader, readOnly bool) (*api.POEAttachment, error)
+	ListPOEAttachmentsFunc         func(header http.Header, poeID did.Identifier) ([]*api.POEAttachment, error)
+	DeletePOEAttachmentFunc        func(header http.Header, poeID did.Identifier, name string) error
+	QueryPOEListFunc               func(header http.Header, owner did.Identifier, filter *api.POEFilter, page *api.PageRequest) (*api.POEListPage, error)
+	UploadPOEFileEncryptedFunc     func(header http.Header, poeID string, poeFile string, readOnly bool, key *api.POEFileKey) (*api.EncryptedUploadResponse, error)
+	GeneratePOEDownloadURLFunc     func(header http.Header, poeID did.Identifier, ttl time.Duration) (*api.POEDownloadURL, error)
+	RegisterCallbackFunc           func(header http.Header, body *api.CallbackBody) (*api.CallbackRegistration, error)
+	UpdateCallbackFunc             func(header http.Header, body *api.CallbackBody) (*api.CallbackRegistration, error)
+	QueryCallbackFunc              func(header http.Header, walletID did.Identifier) (*api.CallbackRegistration, error)
+	DeleteCallbackFunc             func(header http.Header, walletID did.Identifier) error
+	QueryOperationStatusFunc       func(header http.Header, id string) (*api.Operation, error)
+	ListPendingOperationsFunc      func(header http.Header, walletID did.Identifier) ([]*api.Operation, error)
+	QueryCTokenInfoFunc            func(header http.Header, tokenID string) (*api.CTokenInfo, error)
+	ListCTokensFunc                func(header http.Header, issuer did.Identifier) ([]*api.CTokenInfo, error)
+	QueryAssetFunc                 func(header http.Header, assetID string) (*api.AssetInfo, error)
+	QueryAssetHistoryFunc          func(header http.Header, assetID string) ([]*api.AssetEvent, error)
+	ListWalletAssetsFunc           func(header http.Header, id did.Identifier, page *api.PageRequest) (*api.AssetListPage, error)
+	ListWalletCTokensFunc          func(header http.Header, id did.Identifier, page *api.PageRequest) (*api.CTokenListPage, error)
+	IssueNFTFunc                   func(header http.Header, body *api.IssueNFTBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	TransferNFTFunc                func(header http.Header, body *api.TransferNFTBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	ReissueCTokenFunc              func(header http.Header, body *api.ReissueCTokenBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	SetSupplyCapFunc               func(header http.Header, body *api.SupplyCapBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	SplitCTokenFunc                func(header http.Header, body *api.SplitCTokenBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	MergeCTokenFunc                func(header http.Header, body *api.MergeCTokenBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+	TrustKeyPairFunc               func(header http.Header, body *api.TrustKeyPairBody) (string, error)
+	UntrustKeyPairFunc             func(header http.Header, id did.Identifier, securityCode string) (*api.KeyPair, error)
+	RotateSecurityCodeFunc         func(header http.Header, id did.Identifier, securityCode string) (string, error)
+	RotateWalletKeyFunc            func(header http.Header, id did.Identifier, newPublicKey string, newSigner api.Signer, signParams *pki.SignatureParam) (*wallet.WalletResponse, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ api.Client = (*Client)(nil)
+
+// Calls returns the calls made so far, in order.
+//
+func (c *Client) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	calls := make([]Call, len(c.calls))
+	copy(calls, c.calls)
+	return calls
+}
+
+// CallsTo returns the calls made so far to the given method.
+//
+func (c *Client) CallsTo(method string) []Call {
+	var calls []Call
+	for _, call := range c.Calls() {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls.
+//
+func (c *Client) Reset() {
+	c.mu.Lock()
+	c.calls = nil
+	c.mu.Unlock()
+}
+
+func (c *Client) record(method string, header http.Header, args ...interface{}) {
+	c.mu.Lock()
+	c.calls = append(c.calls, Call{Method: method, Header: header, Args: args})
+	c.mu.Unlock()
+}
+
+// Register calls RegisterFunc.
+//
+func (c *Client) Register(header http.Header, body *wallet.RegisterWalletBody) (*wallet.WalletResponse, error) {
+	c.record("Register", header, body)
+	if c.RegisterFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.RegisterFunc(header, body)
+}
+
+// RegisterSubWallet calls RegisterSubWalletFunc.
+//
+func (c *Client) RegisterSubWallet(header http.Header, body *wallet.RegisterSubWalletBody) (*wallet.WalletResponse, error) {
+	c.record("RegisterSubWallet", header, body)
+	if c.RegisterSubWalletFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.RegisterSubWalletFunc(header, body)
+}
+
+// GetWalletBalance calls GetWalletBalanceFunc.
+//
+func (c *Client) GetWalletBalance(header http.Header, id did.Identifier) (*wallet.WalletBalance, error) {
+	c.record("GetWalletBalance", header, id)
+	if c.GetWalletBalanceFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.GetWalletBalanceFunc(header, id)
+}
+
+// GetWalletInfo calls GetWalletInfoFunc.
+//
+func (c *Client) GetWalletInfo(header http.Header, id did.Identifier) (*wallet.WalletInfo, error) {
+	c.record("GetWalletInfo", header, id)
+	if c.GetWalletInfoFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.GetWalletInfoFunc(header, id)
+}
+
+// QueryWalletInfo calls QueryWalletInfoFunc.
+//
+func (c *Client) QueryWalletInfo(header http.Header, id did.Identifier) (*wallet.WalletInfo, error) {
+	c.record("QueryWalletInfo", header, id)
+	if c.QueryWalletInfoFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryWalletInfoFunc(header, id)
+}
+
+// UploadPOEFileFromReader calls UploadPOEFileFromReaderFunc.
+//
+func (c *Client) UploadPOEFileFromReader(header http.Header, poeID string, fileName string, size int64, r io.Reader, readOnly bool) (*wallet.UploadResponse, error) {
+	c.record("UploadPOEFileFromReader", header, poeID, fileName, size, r, readOnly)
+	if c.UploadPOEFileFromReaderFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.UploadPOEFileFromReaderFunc(header, poeID, fileName, size, r, readOnly)
+}
+
+// QueryWalletBalance calls QueryWalletBalanceFunc.
+//
+func (c *Client) QueryWalletBalance(header http.Header, id did.Identifier) (*api.WalletBalances, error) {
+	c.record("QueryWalletBalance", header, id)
+	if c.QueryWalletBalanceFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryWalletBalanceFunc(header, id)
+}
+
+// CreatePOE calls CreatePOEFunc.
+//
+func (c *Client) CreatePOE(header http.Header, body *wallet.POEBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("CreatePOE", header, body, signParams)
+	if c.CreatePOEFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.CreatePOEFunc(header, body, signParams)
+}
+
+// UpdatePOE calls UpdatePOEFunc.
+//
+func (c *Client) UpdatePOE(header http.Header, body *wallet.POEBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("UpdatePOE", header, body, signParams)
+	if c.UpdatePOEFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.UpdatePOEFunc(header, body, signParams)
+}
+
+// QueryPOE calls QueryPOEFunc.
+//
+func (c *Client) QueryPOE(header http.Header, id did.Identifier) (*wallet.POEPayload, error) {
+	c.record("QueryPOE", header, id)
+	if c.QueryPOEFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryPOEFunc(header, id)
+}
+
+// UploadPOEFile calls UploadPOEFileFunc.
+//
+func (c *Client) UploadPOEFile(header http.Header, poeID string, poeFile string, readOnly bool) (*wallet.UploadResponse, error) {
+	c.record("UploadPOEFile", header, poeID, poeFile, readOnly)
+	if c.UploadPOEFileFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.UploadPOEFileFunc(header, poeID, poeFile, readOnly)
+}
+
+// IssueCToken calls IssueCTokenFunc.
+//
+func (c *Client) IssueCToken(header http.Header, body *wallet.IssueBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("IssueCToken", header, body, signParams)
+	if c.IssueCTokenFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.IssueCTokenFunc(header, body, signParams)
+}
+
+// IssueAsset calls IssueAssetFunc.
+//
+func (c *Client) IssueAsset(header http.Header, body *wallet.IssueAssetBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("IssueAsset", header, body, signParams)
+	if c.IssueAssetFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.IssueAssetFunc(header, body, signParams)
+}
+
+// TransferCToken calls TransferCTokenFunc.
+//
+func (c *Client) TransferCToken(header http.Header, body *wallet.TransferCTokenBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("TransferCToken", header, body, signParams)
+	if c.TransferCTokenFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.TransferCTokenFunc(header, body, signParams)
+}
+
+// TransferAsset calls TransferAssetFunc.
+//
+func (c *Client) TransferAsset(header http.Header, body *wallet.TransferAssetBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("TransferAsset", header, body, signParams)
+	if c.TransferAssetFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.TransferAssetFunc(header, body, signParams)
+}
+
+// QueryTransactionLogs calls QueryTransactionLogsFunc.
+//
+func (c *Client) QueryTransactionLogs(header http.Header, id did.Identifier, txType string, num, page int32) ([]*pw.UTXO, error) {
+	c.record("QueryTransactionLogs", header, id, txType, num, page)
+	if c.QueryTransactionLogsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryTransactionLogsFunc(header, id, txType, num, page)
+}
+
+// QueryTransactionUTXO calls QueryTransactionUTXOFunc.
+//
+func (c *Client) QueryTransactionUTXO(header http.Header, id did.Identifier, num, page int32) ([]*pw.UTXO, error) {
+	c.record("QueryTransactionUTXO", header, id, num, page)
+	if c.QueryTransactionUTXOFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryTransactionUTXOFunc(header, id, num, page)
+}
+
+// QueryTransactionSTXO calls QueryTransactionSTXOFunc.
+//
+func (c *Client) QueryTransactionSTXO(header http.Header, id did.Identifier, num, page int32) ([]*pw.UTXO, error) {
+	c.record("QueryTransactionSTXO", header, id, num, page)
+	if c.QueryTransactionSTXOFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryTransactionSTXOFunc(header, id, num, page)
+}
+
+// IndexSet calls IndexSetFunc.
+//
+func (c *Client) IndexSet(header http.Header, body *wallet.IndexSetPayload) ([]string, error) {
+	c.record("IndexSet", header, body)
+	if c.IndexSetFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.IndexSetFunc(header, body)
+}
+
+// IndexGet calls IndexGetFunc.
+//
+func (c *Client) IndexGet(header http.Header, body *wallet.IndexGetPayload) ([]string, error) {
+	c.record("IndexGet", header, body)
+	if c.IndexGetFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.IndexGetFunc(header, body)
+}
+
+// PrepareCreatePOE calls PrepareCreatePOEFunc.
+//
+func (c *Client) PrepareCreatePOE(header http.Header, body *wallet.POEBody, signParams *pki.SignatureParam) ([]byte, error) {
+	c.record("PrepareCreatePOE", header, body, signParams)
+	if c.PrepareCreatePOEFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.PrepareCreatePOEFunc(header, body, signParams)
+}
+
+// PrepareUpdatePOE calls PrepareUpdatePOEFunc.
+//
+func (c *Client) PrepareUpdatePOE(header http.Header, body *wallet.POEBody, signParams *pki.SignatureParam) ([]byte, error) {
+	c.record("PrepareUpdatePOE", header, body, signParams)
+	if c.PrepareUpdatePOEFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.PrepareUpdatePOEFunc(header, body, signParams)
+}
+
+// PrepareIssueCToken calls PrepareIssueCTokenFunc.
+//
+func (c *Client) PrepareIssueCToken(header http.Header, body *wallet.IssueBody, signParams *pki.SignatureParam) ([]byte, error) {
+	c.record("PrepareIssueCToken", header, body, signParams)
+	if c.PrepareIssueCTokenFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.PrepareIssueCTokenFunc(header, body, signParams)
+}
+
+// PrepareIssueAsset calls PrepareIssueAssetFunc.
+//
+func (c *Client) PrepareIssueAsset(header http.Header, body *wallet.IssueAssetBody, signParams *pki.SignatureParam) ([]byte, error) {
+	c.record("PrepareIssueAsset", header, body, signParams)
+	if c.PrepareIssueAssetFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.PrepareIssueAssetFunc(header, body, signParams)
+}
+
+// PrepareTransferCToken calls PrepareTransferCTokenFunc.
+//
+func (c *Client) PrepareTransferCToken(header http.Header, body *wallet.TransferCTokenBody, signParams *pki.SignatureParam) ([]byte, error) {
+	c.record("PrepareTransferCToken", header, body, signParams)
+	if c.PrepareTransferCTokenFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.PrepareTransferCTokenFunc(header, body, signParams)
+}
+
+// PrepareTransferAsset calls PrepareTransferAssetFunc.
+//
+func (c *Client) PrepareTransferAsset(header http.Header, body *wallet.TransferAssetBody, signParams *pki.SignatureParam) ([]byte, error) {
+	c.record("PrepareTransferAsset", header, body, signParams)
+	if c.PrepareTransferAssetFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.PrepareTransferAssetFunc(header, body, signParams)
+}
+
+// SubmitPrepared calls SubmitPreparedFunc.
+//
+func (c *Client) SubmitPrepared(header http.Header, data []byte) (*wallet.WalletResponse, error) {
+	c.record("SubmitPrepared", header, data)
+	if c.SubmitPreparedFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.SubmitPreparedFunc(header, data)
+}
+
+// SubmitSignedTransaction calls SubmitSignedTransactionFunc.
+//
+func (c *Client) SubmitSignedTransaction(header http.Header, tx *api.SignedTransaction) (*wallet.WalletResponse, error) {
+	c.record("SubmitSignedTransaction", header, tx)
+	if c.SubmitSignedTransactionFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.SubmitSignedTransactionFunc(header, tx)
+}
+
+// SubmitMultiSigTransaction calls SubmitMultiSigTransactionFunc.
+//
+func (c *Client) SubmitMultiSigTransaction(header http.Header, tx *api.PendingTransaction) (*wallet.WalletResponse, error) {
+	c.record("SubmitMultiSigTransaction", header, tx)
+	if c.SubmitMultiSigTransactionFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.SubmitMultiSigTransactionFunc(header, tx)
+}
+
+// TransferCTokenBatch calls TransferCTokenBatchFunc.
+//
+func (c *Client) TransferCTokenBatch(header http.Header, transfers []*wallet.TransferCTokenBody, signParams *pki.SignatureParam) (*api.BatchResult, error) {
+	c.record("TransferCTokenBatch", header, transfers, signParams)
+	if c.TransferCTokenBatchFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.TransferCTokenBatchFunc(header, transfers, signParams)
+}
+
+// IssueAssetBatch calls IssueAssetBatchFunc.
+//
+func (c *Client) IssueAssetBatch(header http.Header, assets []*wallet.IssueAssetBody, signParams *pki.SignatureParam) (*api.BatchResult, error) {
+	c.record("IssueAssetBatch", header, assets, signParams)
+	if c.IssueAssetBatchFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.IssueAssetBatchFunc(header, assets, signParams)
+}
+
+// CreatePOEBatch calls CreatePOEBatchFunc.
+//
+func (c *Client) CreatePOEBatch(header http.Header, poes []*wallet.POEBody, signParams *pki.SignatureParam) (*api.BatchResult, error) {
+	c.record("CreatePOEBatch", header, poes, signParams)
+	if c.CreatePOEBatchFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.CreatePOEBatchFunc(header, poes, signParams)
+}
+
+// DistributeCToken calls DistributeCTokenFunc.
+//
+func (c *Client) DistributeCToken(header http.Header, body *api.DistributeCTokenBody, opts *api.DistributionOptions, signParams *pki.SignatureParam) (*api.DistributionReport, error) {
+	c.record("DistributeCToken", header, body, opts, signParams)
+	if c.DistributeCTokenFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.DistributeCTokenFunc(header, body, opts, signParams)
+}
+
+// HoldCToken calls HoldCTokenFunc.
+//
+func (c *Client) HoldCToken(header http.Header, body *api.HoldCTokenBody, signParams *pki.SignatureParam) (*api.HoldResponse, error) {
+	c.record("HoldCToken", header, body, signParams)
+	if c.HoldCTokenFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.HoldCTokenFunc(header, body, signParams)
+}
+
+// CaptureHold calls CaptureHoldFunc.
+//
+func (c *Client) CaptureHold(header http.Header, body *api.CaptureHoldBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("CaptureHold", header, body, signParams)
+	if c.CaptureHoldFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.CaptureHoldFunc(header, body, signParams)
+}
+
+// VoidHold calls VoidHoldFunc.
+//
+func (c *Client) VoidHold(header http.Header, body *api.VoidHoldBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("VoidHold", header, body, signParams)
+	if c.VoidHoldFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.VoidHoldFunc(header, body, signParams)
+}
+
+// VoidExpiredHolds calls VoidExpiredHoldsFunc.
+//
+func (c *Client) VoidExpiredHolds(header http.Header, id did.Identifier, signParams *pki.SignatureParam) ([]string, error) {
+	c.record("VoidExpiredHolds", header, id, signParams)
+	if c.VoidExpiredHoldsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.VoidExpiredHoldsFunc(header, id, signParams)
+}
+
+// QueryHolds calls QueryHoldsFunc.
+//
+func (c *Client) QueryHolds(header http.Header, id did.Identifier, status api.HoldStatus) ([]*api.Hold, error) {
+	c.record("QueryHolds", header, id, status)
+	if c.QueryHoldsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryHoldsFunc(header, id, status)
+}
+
+// QueryActiveHolds calls QueryActiveHoldsFunc.
+//
+func (c *Client) QueryActiveHolds(header http.Header, id did.Identifier) ([]*api.Hold, error) {
+	c.record("QueryActiveHolds", header, id)
+	if c.QueryActiveHoldsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryActiveHoldsFunc(header, id)
+}
+
+// Refund calls RefundFunc.
+//
+func (c *Client) Refund(header http.Header, body *api.RefundBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("Refund", header, body, signParams)
+	if c.RefundFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.RefundFunc(header, body, signParams)
+}
+
+// QueryRefunds calls QueryRefundsFunc.
+//
+func (c *Client) QueryRefunds(header http.Header, originalTxID string) ([]*api.Refund, error) {
+	c.record("QueryRefunds", header, originalTxID)
+	if c.QueryRefundsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryRefundsFunc(header, originalTxID)
+}
+
+// OpenDispute calls OpenDisputeFunc.
+//
+func (c *Client) OpenDispute(header http.Header, body *api.OpenDisputeBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("OpenDispute", header, body, signParams)
+	if c.OpenDisputeFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.OpenDisputeFunc(header, body, signParams)
+}
+
+// AddDisputeEvidence calls AddDisputeEvidenceFunc.
+//
+func (c *Client) AddDisputeEvidence(header http.Header, body *api.DisputeEvidenceBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("AddDisputeEvidence", header, body, signParams)
+	if c.AddDisputeEvidenceFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.AddDisputeEvidenceFunc(header, body, signParams)
+}
+
+// ResolveDispute calls ResolveDisputeFunc.
+//
+func (c *Client) ResolveDispute(header http.Header, body *api.ResolveDisputeBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("ResolveDispute", header, body, signParams)
+	if c.ResolveDisputeFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.ResolveDisputeFunc(header, body, signParams)
+}
+
+// QueryDisputes calls QueryDisputesFunc.
+//
+func (c *Client) QueryDisputes(header http.Header, txID string) ([]*api.Dispute, error) {
+	c.record("QueryDisputes", header, txID)
+	if c.QueryDisputesFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryDisputesFunc(header, txID)
+}
+
+// SetWalletKYCStatus calls SetWalletKYCStatusFunc.
+//
+func (c *Client) SetWalletKYCStatus(header http.Header, body *api.KYCStatusBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("SetWalletKYCStatus", header, body, signParams)
+	if c.SetWalletKYCStatusFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.SetWalletKYCStatusFunc(header, body, signParams)
+}
+
+// QueryWalletKYCStatus calls QueryWalletKYCStatusFunc.
+//
+func (c *Client) QueryWalletKYCStatus(header http.Header, id did.Identifier) (*api.KYCStatus, error) {
+	c.record("QueryWalletKYCStatus", header, id)
+	if c.QueryWalletKYCStatusFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryWalletKYCStatusFunc(header, id)
+}
+
+// RecordConsent calls RecordConsentFunc.
+//
+func (c *Client) RecordConsent(header http.Header, receipt *api.ConsentReceipt, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("RecordConsent", header, receipt, signParams)
+	if c.RecordConsentFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.RecordConsentFunc(header, receipt, signParams)
+}
+
+// RevokeConsent calls RevokeConsentFunc.
+//
+func (c *Client) RevokeConsent(header http.Header, id did.Identifier, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("RevokeConsent", header, id, signParams)
+	if c.RevokeConsentFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.RevokeConsentFunc(header, id, signParams)
+}
+
+// QueryConsent calls QueryConsentFunc.
+//
+func (c *Client) QueryConsent(header http.Header, id did.Identifier) (*api.ConsentReceipt, error) {
+	c.record("QueryConsent", header, id)
+	if c.QueryConsentFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryConsentFunc(header, id)
+}
+
+// EraseOffchainData calls EraseOffchainDataFunc.
+//
+func (c *Client) EraseOffchainData(header http.Header, body *api.EraseOffchainBody, signParams *pki.SignatureParam) (*api.OffchainErasure, error) {
+	c.record("EraseOffchainData", header, body, signParams)
+	if c.EraseOffchainDataFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.EraseOffchainDataFunc(header, body, signParams)
+}
+
+// QueryOffchainErasure calls QueryOffchainErasureFunc.
+//
+func (c *Client) QueryOffchainErasure(header http.Header, poeID did.Identifier) (*api.OffchainErasure, error) {
+	c.record("QueryOffchainErasure", header, poeID)
+	if c.QueryOffchainErasureFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryOffchainErasureFunc(header, poeID)
+}
+
+// VerifySignatureRemote calls VerifySignatureRemoteFunc.
+//
+func (c *Client) VerifySignatureRemote(header http.Header, payload []byte, signParams *pki.SignatureParam) (*api.VerifySignatureResult, error) {
+	c.record("VerifySignatureRemote", header, payload, signParams)
+	if c.VerifySignatureRemoteFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.VerifySignatureRemoteFunc(header, payload, signParams)
+}
+
+// QueryTravelRuleEnvelope calls QueryTravelRuleEnvelopeFunc.
+//
+func (c *Client) QueryTravelRuleEnvelope(header http.Header, txID string) (*api.TravelRuleEnvelope, error) {
+	c.record("QueryTravelRuleEnvelope", header, txID)
+	if c.QueryTravelRuleEnvelopeFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryTravelRuleEnvelopeFunc(header, txID)
+}
+
+// SubscribeTransactionEvents calls SubscribeTransactionEventsFunc.
+//
+func (c *Client) SubscribeTransactionEvents(ctx context.Context, header http.Header, walletID did.Identifier, filter *api.EventFilter) (<-chan *api.Event, error) {
+	c.record("SubscribeTransactionEvents", header, walletID, filter)
+	if c.SubscribeTransactionEventsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.SubscribeTransactionEventsFunc(ctx, header, walletID, filter)
+}
+
+// UploadPOEFileChunked calls UploadPOEFileChunkedFunc.
+//
+func (c *Client) UploadPOEFileChunked(header http.Header, poeID string, poeFile string, chunkSize int64, readOnly bool) (*wallet.UploadResponse, *api.UploadSession, error) {
+	c.record("UploadPOEFileChunked", header, poeID, poeFile, chunkSize, readOnly)
+	if c.UploadPOEFileChunkedFunc == nil {
+		return nil, nil, ErrNotImplemented
+	}
+	return c.UploadPOEFileChunkedFunc(header, poeID, poeFile, chunkSize, readOnly)
+}
+
+// StartChunkedUpload calls StartChunkedUploadFunc.
+//
+func (c *Client) StartChunkedUpload(header http.Header, poeID string, poeFile string, chunkSize int64, readOnly bool) (*api.UploadSession, error) {
+	c.record("StartChunkedUpload", header, poeID, poeFile, chunkSize, readOnly)
+	if c.StartChunkedUploadFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.StartChunkedUploadFunc(header, poeID, poeFile, chunkSize, readOnly)
+}
+
+// ContinueChunkedUpload calls ContinueChunkedUploadFunc.
+//
+func (c *Client) ContinueChunkedUpload(header http.Header, session *api.UploadSession, poeFile string, progress func(uploaded, chunks int)) (*wallet.UploadResponse, error) {
+	c.record("ContinueChunkedUpload", header, session, poeFile, progress)
+	if c.ContinueChunkedUploadFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.ContinueChunkedUploadFunc(header, session, poeFile, progress)
+}
+
+// DownloadPOEFile calls DownloadPOEFileFunc.
+//
+func (c *Client) DownloadPOEFile(header http.Header, poeID did.Identifier, out io.Writer) (*wallet.POEPayload, error) {
+	c.record("DownloadPOEFile", header, poeID, out)
+	if c.DownloadPOEFileFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.DownloadPOEFileFunc(header, poeID, out)
+}
+
+// DownloadPOEFileDecrypted calls DownloadPOEFileDecryptedFunc.
+//
+func (c *Client) DownloadPOEFileDecrypted(header http.Header, poeID did.Identifier, out io.Writer, key *api.POEFileKey) (*wallet.POEPayload, *api.POEEncryption, error) {
+	c.record("DownloadPOEFileDecrypted", header, poeID, out, key)
+	if c.DownloadPOEFileDecryptedFunc == nil {
+		return nil, nil, ErrNotImplemented
+	}
+	return c.DownloadPOEFileDecryptedFunc(header, poeID, out, key)
+}
+
+// QueryTransactionStatus calls QueryTransactionStatusFunc.
+//
+func (c *Client) QueryTransactionStatus(header http.Header, txID string) (*api.TransactionReceipt, error) {
+	c.record("QueryTransactionStatus", header, txID)
+	if c.QueryTransactionStatusFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryTransactionStatusFunc(header, txID)
+}
+
+// WaitForConfirmation calls WaitForConfirmationFunc.
+//
+func (c *Client) WaitForConfirmation(ctx context.Context, header http.Header, txID string, opts *api.PollOptions) (*api.TransactionReceipt, error) {
+	c.record("WaitForConfirmation", header, txID, opts)
+	if c.WaitForConfirmationFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.WaitForConfirmationFunc(ctx, header, txID, opts)
+}
+
+// QueryTransactionLogsPage calls QueryTransactionLogsPageFunc.
+//
+func (c *Client) QueryTransactionLogsPage(header http.Header, query *api.TransactionLogsQuery) (*api.TransactionLogsPage, error) {
+	c.record("QueryTransactionLogsPage", header, query)
+	if c.QueryTransactionLogsPageFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryTransactionLogsPageFunc(header, query)
+}
+
+// TransactionLogs calls TransactionLogsFunc. When it is not set, the
+// returned iterator queries the pages with QueryTransactionLogsPage.
+//
+func (c *Client) TransactionLogs(header http.Header, query *api.TransactionLogsQuery) *api.TransactionLogIterator {
+	c.record("TransactionLogs", header, query)
+	if c.TransactionLogsFunc == nil {
+		return api.NewTransactionLogIterator(c, header, query)
+	}
+	return c.TransactionLogsFunc(header, query)
+}
+
+// RevokePOE calls RevokePOEFunc.
+//
+func (c *Client) RevokePOE(header http.Header, body *api.RevokePOEBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("RevokePOE", header, body, signParams)
+	if c.RevokePOEFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.RevokePOEFunc(header, body, signParams)
+}
+
+// QueryPOEHistory calls QueryPOEHistoryFunc.
+//
+func (c *Client) QueryPOEHistory(header http.Header, id did.Identifier) ([]*api.POEVersion, error) {
+	c.record("QueryPOEHistory", header, id)
+	if c.QueryPOEHistoryFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryPOEHistoryFunc(header, id)
+}
+
+// VerifyPOE calls VerifyPOEFunc.
+//
+func (c *Client) VerifyPOE(header http.Header, id did.Identifier, file io.Reader) (*api.POEVerification, error) {
+	c.record("VerifyPOE", header, id, file)
+	if c.VerifyPOEFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.VerifyPOEFunc(header, id, file)
+}
+
+// AttachPOEFile calls AttachPOEFileFunc.
+//
+func (c *Client) AttachPOEFile(header http.Header, poeID did.Identifier, name string, fileName string, size int64, r io.Reader, readOnly bool) (*api.POEAttachment, error) {
+	c.record("AttachPOEFile", header, poeID, name, fileName, size, r, readOnly)
+	if c.AttachPOEFileFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.AttachPOEFileFunc(header, poeID, name, fileName, size, r, readOnly)
+}
+
+// ListPOEAttachments calls ListPOEAttachmentsFunc.
+//
+func (c *Client) ListPOEAttachments(header http.Header, poeID did.Identifier) ([]*api.POEAttachment, error) {
+	c.record("ListPOEAttachments", header, poeID)
+	if c.ListPOEAttachmentsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.ListPOEAttachmentsFunc(header, poeID)
+}
+
+// DeletePOEAttachment calls DeletePOEAttachmentFunc.
+//
+func (c *Client) DeletePOEAttachment(header http.Header, poeID did.Identifier, name string) error {
+	c.record("DeletePOEAttachment", header, poeID, name)
+	if c.DeletePOEAttachmentFunc == nil {
+		return ErrNotImplemented
+	}
+	return c.DeletePOEAttachmentFunc(header, poeID, name)
+}
+
+// QueryPOEList calls QueryPOEListFunc.
+//
+func (c *Client) QueryPOEList(header http.Header, owner did.Identifier, filter *api.POEFilter, page *api.PageRequest) (*api.POEListPage, error) {
+	c.record("QueryPOEList", header, owner, filter, page)
+	if c.QueryPOEListFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryPOEListFunc(header, owner, filter, page)
+}
+
+// UploadPOEFileEncrypted calls UploadPOEFileEncryptedFunc.
+//
+func (c *Client) UploadPOEFileEncrypted(header http.Header, poeID string, poeFile string, readOnly bool, key *api.POEFileKey) (*api.EncryptedUploadResponse, error) {
+	c.record("UploadPOEFileEncrypted", header, poeID, poeFile, readOnly, key)
+	if c.UploadPOEFileEncryptedFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.UploadPOEFileEncryptedFunc(header, poeID, poeFile, readOnly, key)
+}
+
+// GeneratePOEDownloadURL calls GeneratePOEDownloadURLFunc.
+//
+func (c *Client) GeneratePOEDownloadURL(header http.Header, poeID did.Identifier, ttl time.Duration) (*api.POEDownloadURL, error) {
+	c.record("GeneratePOEDownloadURL", header, poeID, ttl)
+	if c.GeneratePOEDownloadURLFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.GeneratePOEDownloadURLFunc(header, poeID, ttl)
+}
+
+// RegisterCallback calls RegisterCallbackFunc.
+//
+func (c *Client) RegisterCallback(header http.Header, body *api.CallbackBody) (*api.CallbackRegistration, error) {
+	c.record("RegisterCallback", header, body)
+	if c.RegisterCallbackFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.RegisterCallbackFunc(header, body)
+}
+
+// UpdateCallback calls UpdateCallbackFunc.
+//
+func (c *Client) UpdateCallback(header http.Header, body *api.CallbackBody) (*api.CallbackRegistration, error) {
+	c.record("UpdateCallback", header, body)
+	if c.UpdateCallbackFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.UpdateCallbackFunc(header, body)
+}
+
+// QueryCallback calls QueryCallbackFunc.
+//
+func (c *Client) QueryCallback(header http.Header, walletID did.Identifier) (*api.CallbackRegistration, error) {
+	c.record("QueryCallback", header, walletID)
+	if c.QueryCallbackFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryCallbackFunc(header, walletID)
+}
+
+// DeleteCallback calls DeleteCallbackFunc.
+//
+func (c *Client) DeleteCallback(header http.Header, walletID did.Identifier) error {
+	c.record("DeleteCallback", header, walletID)
+	if c.DeleteCallbackFunc == nil {
+		return ErrNotImplemented
+	}
+	return c.DeleteCallbackFunc(header, walletID)
+}
+
+// QueryOperationStatus calls QueryOperationStatusFunc.
+//
+func (c *Client) QueryOperationStatus(header http.Header, id string) (*api.Operation, error) {
+	c.record("QueryOperationStatus", header, id)
+	if c.QueryOperationStatusFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryOperationStatusFunc(header, id)
+}
+
+// ListPendingOperations calls ListPendingOperationsFunc.
+//
+func (c *Client) ListPendingOperations(header http.Header, walletID did.Identifier) ([]*api.Operation, error) {
+	c.record("ListPendingOperations", header, walletID)
+	if c.ListPendingOperationsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.ListPendingOperationsFunc(header, walletID)
+}
+
+// QueryCTokenInfo calls QueryCTokenInfoFunc.
+//
+func (c *Client) QueryCTokenInfo(header http.Header, tokenID string) (*api.CTokenInfo, error) {
+	c.record("QueryCTokenInfo", header, tokenID)
+	if c.QueryCTokenInfoFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryCTokenInfoFunc(header, tokenID)
+}
+
+// ListCTokens calls ListCTokensFunc.
+//
+func (c *Client) ListCTokens(header http.Header, issuer did.Identifier) ([]*api.CTokenInfo, error) {
+	c.record("ListCTokens", header, issuer)
+	if c.ListCTokensFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.ListCTokensFunc(header, issuer)
+}
+
+// QueryAsset calls QueryAssetFunc.
+//
+func (c *Client) QueryAsset(header http.Header, assetID string) (*api.AssetInfo, error) {
+	c.record("QueryAsset", header, assetID)
+	if c.QueryAssetFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryAssetFunc(header, assetID)
+}
+
+// QueryAssetHistory calls QueryAssetHistoryFunc.
+//
+func (c *Client) QueryAssetHistory(header http.Header, assetID string) ([]*api.AssetEvent, error) {
+	c.record("QueryAssetHistory", header, assetID)
+	if c.QueryAssetHistoryFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryAssetHistoryFunc(header, assetID)
+}
+
+// ListWalletAssets calls ListWalletAssetsFunc.
+//
+func (c *Client) ListWalletAssets(header http.Header, id did.Identifier, page *api.PageRequest) (*api.AssetListPage, error) {
+	c.record("ListWalletAssets", header, id, page)
+	if c.ListWalletAssetsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.ListWalletAssetsFunc(header, id, page)
+}
+
+// ListWalletCTokens calls ListWalletCTokensFunc.
+//
+func (c *Client) ListWalletCTokens(header http.Header, id did.Identifier, page *api.PageRequest) (*api.CTokenListPage, error) {
+	c.record("ListWalletCTokens", header, id, page)
+	if c.ListWalletCTokensFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.ListWalletCTokensFunc(header, id, page)
+}
+
+// IssueNFT calls IssueNFTFunc.
+//
+func (c *Client) IssueNFT(header http.Header, body *api.IssueNFTBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("IssueNFT", header, body, signParams)
+	if c.IssueNFTFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.IssueNFTFunc(header, body, signParams)
+}
+
+// TransferNFT calls TransferNFTFunc.
+//
+func (c *Client) TransferNFT(header http.Header, body *api.TransferNFTBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("TransferNFT", header, body, signParams)
+	if c.TransferNFTFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.TransferNFTFunc(header, body, signParams)
+}
+
+// ReissueCToken calls ReissueCTokenFunc.
+//
+func (c *Client) ReissueCToken(header http.Header, body *api.ReissueCTokenBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("ReissueCToken", header, body, signParams)
+	if c.ReissueCTokenFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.ReissueCTokenFunc(header, body, signParams)
+}
+
+// SetSupplyCap calls SetSupplyCapFunc.
+//
+func (c *Client) SetSupplyCap(header http.Header, body *api.SupplyCapBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("SetSupplyCap", header, body, signParams)
+	if c.SetSupplyCapFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.SetSupplyCapFunc(header, body, signParams)
+}
+
+// SplitCToken calls SplitCTokenFunc.
+//
+func (c *Client) SplitCToken(header http.Header, body *api.SplitCTokenBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("SplitCToken", header, body, signParams)
+	if c.SplitCTokenFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.SplitCTokenFunc(header, body, signParams)
+}
+
+// MergeCToken calls MergeCTokenFunc.
+//
+func (c *Client) MergeCToken(header http.Header, body *api.MergeCTokenBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("MergeCToken", header, body, signParams)
+	if c.MergeCTokenFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.MergeCTokenFunc(header, body, signParams)
+}
+
+// TrustKeyPair calls TrustKeyPairFunc.
+//
+func (c *Client) TrustKeyPair(header http.Header, body *api.TrustKeyPairBody) (string, error) {
+	c.record("TrustKeyPair", header, body)
+	if c.TrustKeyPairFunc == nil {
+		return "", ErrNotImplemented
+	}
+	return c.TrustKeyPairFunc(header, body)
+}
+
+// UntrustKeyPair calls UntrustKeyPairFunc.
+//
+func (c *Client) UntrustKeyPair(header http.Header, id did.Identifier, securityCode string) (*api.KeyPair, error) {
+	c.record("UntrustKeyPair", header, id, securityCode)
+	if c.UntrustKeyPairFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.UntrustKeyPairFunc(header, id, securityCode)
+}
+
+// RotateSecurityCode calls RotateSecurityCodeFunc.
+//
+func (c *Client) RotateSecurityCode(header http.Header, id did.Identifier, securityCode string) (string, error) {
+	c.record("RotateSecurityCode", header, id, securityCode)
+	if c.RotateSecurityCodeFunc == nil {
+		return "", ErrNotImplemented
+	}
+	return c.RotateSecurityCodeFunc(header, id, securityCode)
+}
+
+// RotateWalletKey calls RotateWalletKeyFunc.
+//
+func (c *Client) RotateWalletKey(header http.Header, id did.Identifier, newPublicKey string, newSigner api.Signer, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("RotateWalletKey", header, id, newPublicKey, newSigner, signParams)
+	if c.RotateWalletKeyFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.RotateWalletKeyFunc(header, id, newPublicKey, newSigner, signParams)
+}
+
+// BurnCToken calls BurnCTokenFunc.
+//
+func (c *Client) BurnCToken(header http.Header, body *api.BurnCTokenBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("BurnCToken", header, body, signParams)
+	if c.BurnCTokenFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.BurnCTokenFunc(header, body, signParams)
+}
+
+// BurnCTokenSign calls BurnCTokenSignFunc.
+//
+func (c *Client) BurnCTokenSign(header http.Header, body *api.BurnCTokenBody, signParams *pki.SignatureParam) ([]*pw.TX, error) {
+	c.record("BurnCTokenSign", header, body, signParams)
+	if c.BurnCTokenSignFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.BurnCTokenSignFunc(header, body, signParams)
+}
+
+// Approve calls ApproveFunc.
+//
+func (c *Client) Approve(header http.Header, body *api.ApproveBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("Approve", header, body, signParams)
+	if c.ApproveFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.ApproveFunc(header, body, signParams)
+}
+
+// TransferFrom calls TransferFromFunc.
+//
+func (c *Client) TransferFrom(header http.Header, body *api.TransferFromBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("TransferFrom", header, body, signParams)
+	if c.TransferFromFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.TransferFromFunc(header, body, signParams)
+}
+
+// QueryAllowance calls QueryAllowanceFunc.
+//
+func (c *Client) QueryAllowance(header http.Header, owner, spender did.Identifier, tokenId string) (*api.Allowance, error) {
+	c.record("QueryAllowance", header, owner, spender, tokenId)
+	if c.QueryAllowanceFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryAllowanceFunc(header, owner, spender, tokenId)
+}
+
+// ExchangeAsset calls ExchangeAssetFunc.
+//
+func (c *Client) ExchangeAsset(header http.Header, body *api.SwapBody, buyerSignParams, sellerSignParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("ExchangeAsset", header, body, buyerSignParams, sellerSignParams)
+	if c.ExchangeAssetFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.ExchangeAssetFunc(header, body, buyerSignParams, sellerSignParams)
+}
+
+// TransferCTokenHTLC calls TransferCTokenHTLCFunc.
+//
+func (c *Client) TransferCTokenHTLC(header http.Header, body *api.HTLCTransferBody, signParams *pki.SignatureParam) (*api.HTLCResponse, error) {
+	c.record("TransferCTokenHTLC", header, body, signParams)
+	if c.TransferCTokenHTLCFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.TransferCTokenHTLCFunc(header, body, signParams)
+}
+
+// TransferCTokenMemo calls TransferCTokenMemoFunc.
+//
+func (c *Client) TransferCTokenMemo(header http.Header, body *api.MemoTransferBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("TransferCTokenMemo", header, body, signParams)
+	if c.TransferCTokenMemoFunc == nil {
+		return nil, nil
+	}
+	return c.TransferCTokenMemoFunc(header, body, signParams)
+}
+
+// ClaimHTLC calls ClaimHTLCFunc.
+//
+func (c *Client) ClaimHTLC(header http.Header, body *api.ClaimHTLCBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("ClaimHTLC", header, body, signParams)
+	if c.ClaimHTLCFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.ClaimHTLCFunc(header, body, signParams)
+}
+
+// RefundHTLC calls RefundHTLCFunc.
+//
+func (c *Client) RefundHTLC(header http.Header, body *api.RefundHTLCBody, signParams *pki.SignatureParam) (*wallet.WalletResponse, error) {
+	c.record("RefundHTLC", header, body, signParams)
+	if c.RefundHTLCFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.RefundHTLCFunc(header, body, signParams)
+}
+
+// QueryHTLC calls QueryHTLCFunc.
+//
+func (c *Client) QueryHTLC(header http.Header, htlcId string) (*api.HTLC, error) {
+	c.record("QueryHTLC", header, htlcId)
+	if c.QueryHTLCFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.QueryHTLCFunc(header, htlcId)
+}
+
+// EstimateFee calls EstimateFeeFunc.
+//
+func (c *Client) EstimateFee(header http.Header, txType string, body interface{}) (*wallet.Fee, error) {
+	c.record("EstimateFee", header, txType, body)
+	if c.EstimateFeeFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return c.EstimateFeeFunc(header, txType, body)
+}
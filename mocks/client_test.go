@@ -0,0 +1,125 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mocks
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+// poeName depends on the client interface only
+func poeName(client api.Client, header http.Header, id did.Identifier) (string, error) {
+	poe, err := client.QueryPOE(header, id)
+	if err != nil {
+		return "", err
+	}
+	return poe.Name, nil
+}
+
+func TestClient(t *testing.T) {
+	client := &Client{
+		QueryPOEFunc: func(header http.Header, id did.Identifier) (*wallet.POEPayload, error) {
+			return &wallet.POEPayload{Id: id, Name: "MyCar"}, nil
+		},
+	}
+
+	header := http.Header{}
+	header.Set("X-Auth-Token", "user-token-001")
+	name, err := poeName(client, header, "did:axn:001")
+	if err != nil {
+		t.Fatalf("query poe fail: %v", err)
+	}
+	if name != "MyCar" {
+		t.Fatalf("poe name should be MyCar, got %s", name)
+	}
+
+	if _, err := client.IssueCToken(header, &wallet.IssueBody{}, nil); err != ErrNotImplemented {
+		t.Fatalf("err should be ErrNotImplemented when the function is not set, got %v", err)
+	}
+
+	calls := client.CallsTo("QueryPOE")
+	if len(calls) != 1 {
+		t.Fatalf("QueryPOE should be called once, got %d", len(calls))
+	}
+	if calls[0].Header.Get("X-Auth-Token") != "user-token-001" || calls[0].Args[0] != did.Identifier("did:axn:001") {
+		t.Fatalf("QueryPOE call mismatch: %#v", calls[0])
+	}
+	if len(client.Calls()) != 2 {
+		t.Fatalf("2 calls should be recorded, got %d", len(client.Calls()))
+	}
+
+	client.Reset()
+	if len(client.Calls()) != 0 {
+		t.Fatalf("calls should be forgotten after reset")
+	}
+}
+
+func TestClientContextCall(t *testing.T) {
+	events := make(chan *api.Event)
+	client := &Client{
+		SubscribeTransactionEventsFunc: func(ctx context.Context, header http.Header, walletID did.Identifier, filter *api.EventFilter) (<-chan *api.Event, error) {
+			return events, nil
+		},
+	}
+
+	header := http.Header{}
+	header.Set("X-Auth-Token", "user-token-001")
+	ch, err := client.SubscribeTransactionEvents(context.Background(), header, "did:axn:001", nil)
+	if err != nil || ch != (<-chan *api.Event)(events) {
+		t.Fatalf("subscribe should return the programmed channel, got %v", err)
+	}
+	calls := client.CallsTo("SubscribeTransactionEvents")
+	if len(calls) != 1 || calls[0].Header.Get("X-Auth-Token") != "user-token-001" || calls[0].Args[0] != did.Identifier("did:axn:001") {
+		t.Fatalf("the call should be recorded with its header and arguments: %#v", calls)
+	}
+}
+
+func TestClientTransactionLogs(t *testing.T) {
+	client := &Client{
+		QueryTransactionLogsPageFunc: func(header http.Header, query *api.TransactionLogsQuery) (*api.TransactionLogsPage, error) {
+			if query.Cursor == "" {
+				return &api.TransactionLogsPage{Logs: []*pw.UTXO{{SourceTxDataHash: "tx-001"}}, NextCursor: "2"}, nil
+			}
+			return &api.TransactionLogsPage{Logs: []*pw.UTXO{{SourceTxDataHash: "tx-002"}}}, nil
+		},
+	}
+
+	logs := client.TransactionLogs(http.Header{}, &api.TransactionLogsQuery{Id: "did:axn:001"})
+	var hashes []string
+	for {
+		log, err := logs.Next(context.Background())
+		if err == api.ErrIteratorDone {
+			break
+		}
+		if err != nil {
+			t.Fatalf("next log fail: %v", err)
+		}
+		hashes = append(hashes, log.SourceTxDataHash)
+	}
+	if len(hashes) != 2 || hashes[1] != "tx-002" {
+		t.Fatalf("logs of both pages should be returned, got %v", hashes)
+	}
+	if len(client.CallsTo("QueryTransactionLogsPage")) != 2 {
+		t.Fatalf("the pages should be queried with QueryTransactionLogsPage")
+	}
+}
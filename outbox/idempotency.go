@@ -0,0 +1,289 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package outbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+// IdempotencyKeyHeader is the http header carrying the idempotency key of a
+// submission, the gateway uses it to drop duplicated submissions.
+const IdempotencyKeyHeader = api.IdempotencyKeyHeader
+
+// Submission records one confirmed submission of an idempotency key.
+//
+type Submission struct {
+	TransactionIds []string  `json:"transaction_ids"`
+	Submitted      time.Time `json:"submitted"`
+}
+
+// IdempotencyStore persists which idempotency keys have been submitted.
+//
+type IdempotencyStore interface {
+	Record(key string, s *Submission) error
+	Lookup(key string) ([]*Submission, error)
+	Keys() ([]string, error)
+}
+
+// MemoryIdempotencyStore is an IdempotencyStore keeping the keys in memory.
+//
+type MemoryIdempotencyStore struct {
+	mu   sync.Mutex
+	keys map[string][]*Submission
+}
+
+// NewMemoryIdempotencyStore returns a MemoryIdempotencyStore instance.
+//
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{keys: make(map[string][]*Submission)}
+}
+
+// Record implements IdempotencyStore.
+//
+func (m *MemoryIdempotencyStore) Record(key string, s *Submission) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.keys[key] = append(m.keys[key], s)
+	return nil
+}
+
+// Lookup implements IdempotencyStore.
+//
+func (m *MemoryIdempotencyStore) Lookup(key string) ([]*Submission, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.keys[key], nil
+}
+
+// Keys implements IdempotencyStore.
+//
+func (m *MemoryIdempotencyStore) Keys() ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0, len(m.keys))
+	for key := range m.keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// FileIdempotencyStore is an IdempotencyStore persisted in a file, one JSON
+// record per line. Submissions are appended to the file, so recording one
+// does not depend on the number of keys already recorded.
+//
+type FileIdempotencyStore struct {
+	mem  *MemoryIdempotencyStore
+	path string
+}
+
+// idempotencyRecord is a line of the FileIdempotencyStore file.
+type idempotencyRecord struct {
+	Key        string      `json:"key"`
+	Submission *Submission `json:"submission"`
+}
+
+// NewFileIdempotencyStore returns a FileIdempotencyStore instance, loading
+// the keys already recorded in path. A last record cut by a crash is
+// dropped.
+//
+func NewFileIdempotencyStore(path string) (*FileIdempotencyStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("idempotency store path must be set")
+	}
+	f := &FileIdempotencyStore{mem: NewMemoryIdempotencyStore(), path: path}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	valid := 0
+	for valid < len(data) {
+		n := bytes.IndexByte(data[valid:], '\n')
+		if n < 0 {
+			break
+		}
+		var r idempotencyRecord
+		if err = json.Unmarshal(data[valid:valid+n], &r); err != nil {
+			return nil, fmt.Errorf("idempotency store %s invalid: %v", path, err)
+		}
+		if r.Key == "" || r.Submission == nil {
+			return nil, fmt.Errorf("idempotency store %s invalid: incomplete record", path)
+		}
+		f.mem.keys[r.Key] = append(f.mem.keys[r.Key], r.Submission)
+		valid += n + 1
+	}
+	if valid < len(data) {
+		if err = os.Truncate(path, int64(valid)); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// Record implements IdempotencyStore, the key is recorded in memory once
+// persisted.
+//
+func (f *FileIdempotencyStore) Record(key string, s *Submission) error {
+	data, err := json.Marshal(&idempotencyRecord{Key: key, Submission: s})
+	if err != nil {
+		return err
+	}
+	f.mem.mu.Lock()
+	defer f.mem.mu.Unlock()
+	if err = f.append(append(data, '\n')); err != nil {
+		return err
+	}
+	f.mem.keys[key] = append(f.mem.keys[key], s)
+	return nil
+}
+
+// append appends the line to the file, a partially written line is
+// truncated.
+func (f *FileIdempotencyStore) append(line []byte) error {
+	_, err := os.Stat(f.path)
+	created := os.IsNotExist(err)
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err == nil {
+		if _, err = file.Write(line); err == nil {
+			err = file.Sync()
+		} else {
+			file.Truncate(info.Size())
+		}
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil || !created {
+		return err
+	}
+	return syncDir(filepath.Dir(f.path))
+}
+
+// Lookup implements IdempotencyStore.
+//
+func (f *FileIdempotencyStore) Lookup(key string) ([]*Submission, error) {
+	return f.mem.Lookup(key)
+}
+
+// Keys implements IdempotencyStore.
+//
+func (f *FileIdempotencyStore) Keys() ([]string, error) {
+	return f.mem.Keys()
+}
+
+// Confirmer reports whether a blockchain transaction is confirmed.
+//
+type Confirmer interface {
+	IsConfirmed(txID string) (bool, error)
+}
+
+// Discrepancy is an idempotency key which does not map to confirmed
+// transactions, or whose submissions map to different transactions.
+//
+type Discrepancy struct {
+	Key         string
+	Submissions []*Submission
+	Reason      string
+}
+
+// Reconcile verifies that every idempotency key recorded in store maps to
+// confirmed transactions, the same for each of its submissions, and returns
+// the keys which do not. A key may map to several transactions, e.g. the
+// ones of a process_txs operation.
+//
+func Reconcile(store IdempotencyStore, confirmer Confirmer) ([]*Discrepancy, error) {
+	if store == nil || confirmer == nil {
+		return nil, fmt.Errorf("idempotency store and confirmer must be set")
+	}
+	keys, err := store.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	var discrepancies []*Discrepancy
+	for _, key := range keys {
+		subs, err := store.Lookup(key)
+		if err != nil {
+			return nil, err
+		}
+		d := &Discrepancy{Key: key, Submissions: subs}
+		var txIDs []string
+		conflict := false
+		if len(subs) > 0 {
+			txIDs = sortedIDs(subs[0].TransactionIds)
+			for _, s := range subs[1:] {
+				conflict = conflict || !equalIDs(txIDs, sortedIDs(s.TransactionIds))
+			}
+		}
+		switch {
+		case conflict:
+			d.Reason = fmt.Sprintf("submitted %d times with different transactions", len(subs))
+		case len(txIDs) == 0:
+			d.Reason = "maps to no transaction"
+		default:
+			for _, txID := range txIDs {
+				ok, err := confirmer.IsConfirmed(txID)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					d.Reason = fmt.Sprintf("transaction %s not confirmed", txID)
+					break
+				}
+			}
+		}
+		if d.Reason != "" {
+			discrepancies = append(discrepancies, d)
+		}
+	}
+	return discrepancies, nil
+}
+
+func sortedIDs(ids []string) []string {
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func equalIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
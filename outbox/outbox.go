@@ -0,0 +1,406 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package outbox queues signed wallet operations while the gateway is
+// unreachable and submits them in order once it can be reached again.
+//
+// Operations are produced by the Prepare* APIs of the wallet client, so they
+// are signed at enqueue time and submitted later through SubmitPrepared.
+package outbox
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+// Submitter submits prepared requests, it is implemented by the wallet client.
+//
+type Submitter interface {
+	SubmitPrepared(header http.Header, data []byte) (*wallet.WalletResponse, error)
+}
+
+// ConflictFunc decides what to do with an entry rejected by the gateway.
+//
+// Returning true keeps the entry in the outbox to be retried on the next
+// flush, returning false drops it.
+//
+type ConflictFunc func(e *Entry, err error) bool
+
+// ClassifyFunc classifies the submission errors, see api.ClassifyError.
+//
+type ClassifyFunc func(err error) api.ErrorClass
+
+// Result is the outcome of submitting one outbox entry.
+//
+type Result struct {
+	Entry    *Entry
+	Response *wallet.WalletResponse
+	Err      error
+}
+
+// FlushResult reports what happened during one flush.
+//
+type FlushResult struct {
+	Submitted  []*Result
+	Conflicts  []*Result
+	Expired    []*Entry
+	Duplicates []*Entry
+	Pending    int
+}
+
+// Outbox queues signed operations and flushes them in order.
+//
+type Outbox struct {
+	// flushMu serializes the flushes, mu guards the settings and the
+	// sequence, it is not held while submitting
+	flushMu    sync.Mutex
+	mu         sync.Mutex
+	submitter  Submitter
+	store      Store
+	header     http.Header
+	ttl        time.Duration
+	onConflict ConflictFunc
+	classify   ClassifyFunc
+	keys       IdempotencyStore
+	seq        uint64
+}
+
+// New returns an Outbox instance.
+//
+// If store is nil, entries are kept in memory only.
+//
+func New(submitter Submitter, store Store, header http.Header) (*Outbox, error) {
+	if submitter == nil {
+		return nil, fmt.Errorf("outbox submitter must be set")
+	}
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	entries, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	o := &Outbox{
+		submitter: submitter,
+		store:     store,
+		header:    header,
+		classify:  api.ClassifyError,
+	}
+	for _, e := range entries {
+		if e.Seq > o.seq {
+			o.seq = e.Seq
+		}
+	}
+	return o, nil
+}
+
+// SetTTL sets how long an entry stays valid after being enqueued, zero means forever.
+//
+func (o *Outbox) SetTTL(ttl time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.ttl = ttl
+}
+
+// SetConflictHandler sets the handler called when the gateway rejects an entry.
+//
+// Without a handler, rejected entries are dropped.
+//
+func (o *Outbox) SetConflictHandler(f ConflictFunc) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.onConflict = f
+}
+
+// SetClassifier sets how submission errors are classified, e.g. the
+// ClassifyError method of the wallet client to apply its overrides.
+//
+// Retryable and fatal errors stop the flush and keep the entry for the next
+// one, non-retryable errors are conflicts. Without a classifier,
+// api.ClassifyError is used.
+//
+func (o *Outbox) SetClassifier(f ClassifyFunc) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if f == nil {
+		f = api.ClassifyError
+	}
+	o.classify = f
+}
+
+// SetIdempotencyStore enables exactly-once submission.
+//
+// Every entry is submitted with its idempotency key in the Idempotency-Key
+// header so the gateway drops duplicated submissions, and the confirmed keys
+// are recorded in store so an entry left behind by a crash is not submitted
+// again once it is known to be confirmed.
+//
+func (o *Outbox) SetIdempotencyStore(store IdempotencyStore) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.keys = store
+}
+
+// Enqueue appends a prepared request to the outbox.
+//
+func (o *Outbox) Enqueue(prepared []byte) (*Entry, error) {
+	return o.EnqueueWith(prepared, o.store.Append)
+}
+
+// EnqueueWith builds the outbox entry of a prepared request and hands it to
+// persist instead of appending it to the store directly.
+//
+// It is the hook for enqueueing as part of the application's own transaction:
+// persist writes the entry with the same transaction as the business data, to
+// the storage the outbox Store reads from. If persist fails, nothing is enqueued.
+//
+func (o *Outbox) EnqueueWith(prepared []byte, persist func(e *Entry) error) (*Entry, error) {
+	if len(prepared) == 0 {
+		return nil, fmt.Errorf("prepared request must be set")
+	}
+	if persist == nil {
+		return nil, fmt.Errorf("persist function must be set")
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	id, err := newEntryID()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	e := &Entry{
+		Id:       id,
+		Key:      id,
+		Seq:      o.seq + 1,
+		Prepared: prepared,
+		Enqueued: now,
+	}
+	if o.ttl > 0 {
+		e.Expires = now.Add(o.ttl)
+	}
+	if err = persist(e); err != nil {
+		return nil, err
+	}
+	o.seq = e.Seq
+	return e, nil
+}
+
+// Pending returns the entries waiting in the outbox in enqueue order.
+//
+func (o *Outbox) Pending() ([]*Entry, error) {
+	return o.store.List()
+}
+
+// Flush submits the queued entries in enqueue order.
+//
+// Expired entries are dropped without being submitted. When an entry fails
+// with a retryable or fatal error, e.g. because the gateway cannot be reached,
+// the flush stops so that the order of operations is preserved, and the
+// remaining entries are kept for the next flush. When the gateway rejects an
+// entry, the conflict handler decides whether it is kept, which stops the
+// flush like a retryable error, or dropped.
+//
+// Enqueue is not blocked by a flush, the entries enqueued meanwhile are
+// submitted by the next flush.
+//
+// Entries are removed from the store only after they have been submitted, and
+// the attempt is recorded before submitting, so with a durable store every
+// entry is submitted at least once even if the process crashes in between.
+// An entry found with Attempts > 0 may already have been submitted.
+//
+func (o *Outbox) Flush() (*FlushResult, error) {
+	o.flushMu.Lock()
+	defer o.flushMu.Unlock()
+
+	o.mu.Lock()
+	f := &flusher{
+		submitter:  o.submitter,
+		header:     o.header,
+		keys:       o.keys,
+		onConflict: o.onConflict,
+		classify:   o.classify,
+	}
+	o.mu.Unlock()
+
+	entries, err := o.store.List()
+	if err != nil {
+		return nil, err
+	}
+	return f.flush(o.store, entries)
+}
+
+// flusher submits the entries of a flush with the settings of the outbox
+// when the flush started, so that the outbox is not locked meanwhile.
+type flusher struct {
+	submitter  Submitter
+	header     http.Header
+	keys       IdempotencyStore
+	onConflict ConflictFunc
+	classify   ClassifyFunc
+}
+
+func (f *flusher) flush(store Store, entries []*Entry) (*FlushResult, error) {
+	result := &FlushResult{}
+	now := time.Now()
+	for i, e := range entries {
+		if e.Expired(now) {
+			if err := store.Remove(e.Id); err != nil {
+				return result, err
+			}
+			result.Expired = append(result.Expired, e)
+			continue
+		}
+
+		if f.keys != nil && e.Key != "" {
+			subs, err := f.keys.Lookup(e.Key)
+			if err != nil {
+				return result, err
+			}
+			if len(subs) > 0 {
+				if err = store.Remove(e.Id); err != nil {
+					return result, err
+				}
+				result.Duplicates = append(result.Duplicates, e)
+				continue
+			}
+		}
+
+		e.Attempts++
+		if err := store.Update(e); err != nil {
+			return result, err
+		}
+		resp, serr := f.submit(e)
+		if serr == nil {
+			if f.keys != nil && e.Key != "" {
+				sub := &Submission{Submitted: time.Now()}
+				if resp != nil {
+					sub.TransactionIds = resp.TransactionIds
+				}
+				if err := f.keys.Record(e.Key, sub); err != nil {
+					return result, err
+				}
+			}
+			if err := store.Remove(e.Id); err != nil {
+				return result, err
+			}
+			result.Submitted = append(result.Submitted, &Result{Entry: e, Response: resp})
+			continue
+		}
+
+		e.LastError = serr.Error()
+		if f.classifyError(serr) != api.ErrorNonRetryable {
+			// gateway unreachable or client misconfigured, keep the order
+			// and retry later
+			if err := store.Update(e); err != nil {
+				return result, err
+			}
+			result.Pending = len(entries) - i
+			return result, serr
+		}
+
+		result.Conflicts = append(result.Conflicts, &Result{Entry: e, Err: serr})
+		if f.keepConflict(e, serr) {
+			// the entry is retried first on the next flush, keep the order
+			if err := store.Update(e); err != nil {
+				return result, err
+			}
+			result.Pending = len(entries) - i
+			return result, nil
+		}
+		if err := store.Remove(e.Id); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// submit submits an entry, a panicking submitter is returned as an
+// *api.PanicError, so the entry is kept.
+func (f *flusher) submit(e *Entry) (resp *wallet.WalletResponse, err error) {
+	defer api.RecoverPanic(&err)
+	return f.submitter.SubmitPrepared(f.entryHeader(e), e.Prepared)
+}
+
+// classifyError classifies a submission error, a panicking classifier makes
+// the error retryable.
+func (f *flusher) classifyError(err error) (class api.ErrorClass) {
+	defer func() {
+		if recover() != nil {
+			class = api.ErrorRetryable
+		}
+	}()
+	return f.classify(err)
+}
+
+// keepConflict calls the conflict handler, a panicking handler keeps the entry.
+func (f *flusher) keepConflict(e *Entry, err error) (keep bool) {
+	if f.onConflict == nil {
+		return false
+	}
+	defer func() {
+		if recover() != nil {
+			keep = true
+		}
+	}()
+	return f.onConflict(e, err)
+}
+
+func (f *flusher) entryHeader(e *Entry) http.Header {
+	if f.keys == nil || e.Key == "" {
+		return f.header
+	}
+	header := http.Header{}
+	for k, v := range f.header {
+		header[k] = v
+	}
+	header.Set(IdempotencyKeyHeader, e.Key)
+	return header
+}
+
+// Run flushes the outbox every interval until stop is closed.
+//
+// Flush errors are reported to onError if it is not nil.
+//
+func (o *Outbox) Run(interval time.Duration, stop <-chan struct{}, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, err := o.Flush(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+func newEntryID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
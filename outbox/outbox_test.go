@@ -0,0 +1,543 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package outbox
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/rest"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+type fakeSubmitter struct {
+	submitted []string
+	errs      map[string]error
+}
+
+func (f *fakeSubmitter) SubmitPrepared(header http.Header, data []byte) (*wallet.WalletResponse, error) {
+	if err, ok := f.errs[string(data)]; ok {
+		return nil, err
+	}
+	f.submitted = append(f.submitted, string(data))
+	return &wallet.WalletResponse{}, nil
+}
+
+func TestFlushInOrder(t *testing.T) {
+	submitter := &fakeSubmitter{errs: map[string]error{}}
+	o, err := New(submitter, nil, http.Header{})
+	if err != nil {
+		t.Fatalf("new outbox fail: %v", err)
+	}
+
+	for _, op := range []string{"op-1", "op-2", "op-3"} {
+		if _, err = o.Enqueue([]byte(op)); err != nil {
+			t.Fatalf("enqueue fail: %v", err)
+		}
+	}
+
+	// gateway unreachable at op-2
+	submitter.errs["op-2"] = fmt.Errorf("dial tcp: connection refused")
+	result, err := o.Flush()
+	if err == nil {
+		t.Fatalf("err should not be nil when gateway is unreachable")
+	}
+	if len(result.Submitted) != 1 || result.Pending != 2 {
+		t.Fatalf("flush should submit 1 and keep 2 entries, not %d/%d", len(result.Submitted), result.Pending)
+	}
+
+	// reconnected
+	delete(submitter.errs, "op-2")
+	if _, err = o.Flush(); err != nil {
+		t.Fatalf("flush fail: %v", err)
+	}
+	if fmt.Sprint(submitter.submitted) != "[op-1 op-2 op-3]" {
+		t.Fatalf("entries should be submitted in order, not %v", submitter.submitted)
+	}
+	pending, _ := o.Pending()
+	if len(pending) != 0 {
+		t.Fatalf("outbox should be empty, not %d", len(pending))
+	}
+}
+
+func TestFlushConflictAndExpiry(t *testing.T) {
+	submitter := &fakeSubmitter{errs: map[string]error{
+		"op-conflict": rest.CodedError(5015, "BalancesNotSufficient"),
+	}}
+	o, err := New(submitter, nil, http.Header{})
+	if err != nil {
+		t.Fatalf("new outbox fail: %v", err)
+	}
+
+	o.SetTTL(time.Nanosecond)
+	if _, err = o.Enqueue([]byte("op-expired")); err != nil {
+		t.Fatalf("enqueue fail: %v", err)
+	}
+	o.SetTTL(0)
+	if _, err = o.Enqueue([]byte("op-conflict")); err != nil {
+		t.Fatalf("enqueue fail: %v", err)
+	}
+	if _, err = o.Enqueue([]byte("op-ok")); err != nil {
+		t.Fatalf("enqueue fail: %v", err)
+	}
+
+	time.Sleep(time.Millisecond)
+	result, err := o.Flush()
+	if err != nil {
+		t.Fatalf("flush fail: %v", err)
+	}
+	if len(result.Expired) != 1 || len(result.Conflicts) != 1 || len(result.Submitted) != 1 {
+		t.Fatalf("flush should report 1 expired, 1 conflict and 1 submitted entry")
+	}
+	pending, _ := o.Pending()
+	if len(pending) != 0 {
+		t.Fatalf("conflicting entry should be dropped by default")
+	}
+}
+
+func TestFlushKeptConflictStops(t *testing.T) {
+	submitter := &fakeSubmitter{errs: map[string]error{
+		"op-1": rest.CodedError(5015, "BalancesNotSufficient"),
+	}}
+	o, err := New(submitter, nil, http.Header{})
+	if err != nil {
+		t.Fatalf("new outbox fail: %v", err)
+	}
+	o.SetConflictHandler(func(e *Entry, err error) bool { return true })
+	for _, op := range []string{"op-1", "op-2"} {
+		if _, err = o.Enqueue([]byte(op)); err != nil {
+			t.Fatalf("enqueue fail: %v", err)
+		}
+	}
+
+	result, err := o.Flush()
+	if err != nil {
+		t.Fatalf("flush fail: %v", err)
+	}
+	if len(result.Conflicts) != 1 || len(result.Submitted) != 0 || result.Pending != 2 {
+		t.Fatalf("a kept conflict should stop the flush, got %d conflicts/%d submitted/%d pending", len(result.Conflicts), len(result.Submitted), result.Pending)
+	}
+	if len(submitter.submitted) != 0 {
+		t.Fatalf("the entries after a kept conflict should not be submitted, got %v", submitter.submitted)
+	}
+}
+
+// blockingSubmitter holds the submissions until released
+type blockingSubmitter struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingSubmitter) SubmitPrepared(header http.Header, data []byte) (*wallet.WalletResponse, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return &wallet.WalletResponse{}, nil
+}
+
+func TestEnqueueDuringFlush(t *testing.T) {
+	submitter := &blockingSubmitter{started: make(chan struct{}, 1), release: make(chan struct{})}
+	o, err := New(submitter, nil, http.Header{})
+	if err != nil {
+		t.Fatalf("new outbox fail: %v", err)
+	}
+	if _, err = o.Enqueue([]byte("op-1")); err != nil {
+		t.Fatalf("enqueue fail: %v", err)
+	}
+
+	flushed := make(chan *FlushResult)
+	go func() {
+		result, _ := o.Flush()
+		flushed <- result
+	}()
+	<-submitter.started
+
+	enqueued := make(chan error)
+	go func() {
+		_, err := o.Enqueue([]byte("op-2"))
+		enqueued <- err
+	}()
+	select {
+	case err = <-enqueued:
+		if err != nil {
+			t.Fatalf("enqueue fail: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("enqueue should not be blocked by a flush")
+	}
+
+	close(submitter.release)
+	if result := <-flushed; len(result.Submitted) != 1 {
+		t.Fatalf("the flush should submit the entries listed when it started, got %d", len(result.Submitted))
+	}
+	pending, _ := o.Pending()
+	if len(pending) != 1 || string(pending[0].Prepared) != "op-2" {
+		t.Fatalf("the entry enqueued during the flush should be pending")
+	}
+}
+
+func TestFlushClassifier(t *testing.T) {
+	const customCode = 7001
+	submitter := &fakeSubmitter{errs: map[string]error{
+		"op-1": rest.CodedError(customCode, "GatewayBusy"),
+	}}
+	o, err := New(submitter, nil, http.Header{})
+	if err != nil {
+		t.Fatalf("new outbox fail: %v", err)
+	}
+	client := &api.WalletClient{}
+	client.SetErrorClass(customCode, api.ErrorRetryable)
+	o.SetClassifier(client.ClassifyError)
+
+	for _, op := range []string{"op-1", "op-2"} {
+		if _, err = o.Enqueue([]byte(op)); err != nil {
+			t.Fatalf("enqueue fail: %v", err)
+		}
+	}
+
+	result, err := o.Flush()
+	if err == nil {
+		t.Fatalf("err should not be nil when entry fails with a retryable error")
+	}
+	if len(result.Conflicts) != 0 || result.Pending != 2 {
+		t.Fatalf("retryable error should keep all entries, not %d conflicts/%d pending", len(result.Conflicts), result.Pending)
+	}
+}
+
+func TestFileStorePersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "outbox")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("new file store fail: %v", err)
+	}
+	o, err := New(&fakeSubmitter{}, store, http.Header{})
+	if err != nil {
+		t.Fatalf("new outbox fail: %v", err)
+	}
+	for _, op := range []string{"op-1", "op-2"} {
+		if _, err = o.Enqueue([]byte(op)); err != nil {
+			t.Fatalf("enqueue fail: %v", err)
+		}
+	}
+
+	// reopen after restart
+	store, err = NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("new file store fail: %v", err)
+	}
+	submitter := &fakeSubmitter{}
+	o, err = New(submitter, store, http.Header{})
+	if err != nil {
+		t.Fatalf("new outbox fail: %v", err)
+	}
+	if _, err = o.Enqueue([]byte("op-3")); err != nil {
+		t.Fatalf("enqueue fail: %v", err)
+	}
+	if _, err = o.Flush(); err != nil {
+		t.Fatalf("flush fail: %v", err)
+	}
+	if fmt.Sprint(submitter.submitted) != "[op-1 op-2 op-3]" {
+		t.Fatalf("entries should survive restart in order, not %v", submitter.submitted)
+	}
+}
+
+type crashingStore struct {
+	Store
+	crash bool
+}
+
+func (c *crashingStore) Remove(id string) error {
+	if c.crash {
+		return fmt.Errorf("process crashed")
+	}
+	return c.Store.Remove(id)
+}
+
+func TestAtLeastOnceAcrossCrash(t *testing.T) {
+	dir, err := ioutil.TempDir("", "outbox")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	fs, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("new file store fail: %v", err)
+	}
+	store := &crashingStore{Store: fs, crash: true}
+	submitter := &fakeSubmitter{}
+	o, err := New(submitter, store, http.Header{})
+	if err != nil {
+		t.Fatalf("new outbox fail: %v", err)
+	}
+	if _, err = o.Enqueue([]byte("transfer-1")); err != nil {
+		t.Fatalf("enqueue fail: %v", err)
+	}
+
+	// crash right after the submission
+	if _, err = o.Flush(); err == nil {
+		t.Fatalf("err should not be nil when store fails")
+	}
+
+	// restart
+	o, err = New(submitter, fs, http.Header{})
+	if err != nil {
+		t.Fatalf("new outbox fail: %v", err)
+	}
+	pending, _ := o.Pending()
+	if len(pending) != 1 || pending[0].Attempts != 1 {
+		t.Fatalf("entry should be kept with its attempt recorded")
+	}
+	if _, err = o.Flush(); err != nil {
+		t.Fatalf("flush fail: %v", err)
+	}
+	if len(submitter.submitted) != 2 {
+		t.Fatalf("entry should be submitted again after crash, not %d times", len(submitter.submitted))
+	}
+}
+
+func TestEnqueueWith(t *testing.T) {
+	store := NewMemoryStore()
+	o, err := New(&fakeSubmitter{}, store, http.Header{})
+	if err != nil {
+		t.Fatalf("new outbox fail: %v", err)
+	}
+
+	// application transaction rolled back
+	_, err = o.EnqueueWith([]byte("transfer-1"), func(e *Entry) error {
+		return fmt.Errorf("rollback")
+	})
+	if err == nil {
+		t.Fatalf("err should not be nil when persist fails")
+	}
+
+	e, err := o.EnqueueWith([]byte("transfer-2"), store.Append)
+	if err != nil {
+		t.Fatalf("enqueue fail: %v", err)
+	}
+	if e.Seq != 1 {
+		t.Fatalf("failed enqueue should not consume a sequence number")
+	}
+}
+
+type fakeConfirmer map[string]bool
+
+func (f fakeConfirmer) IsConfirmed(txID string) (bool, error) {
+	return f[txID], nil
+}
+
+type txSubmitter struct {
+	headers []http.Header
+}
+
+func (s *txSubmitter) SubmitPrepared(header http.Header, data []byte) (*wallet.WalletResponse, error) {
+	s.headers = append(s.headers, header)
+	return &wallet.WalletResponse{TransactionIds: []string{"tx-" + string(data)}}, nil
+}
+
+func TestExactlyOnceAndReconcile(t *testing.T) {
+	keys := NewMemoryIdempotencyStore()
+	store := NewMemoryStore()
+	submitter := &txSubmitter{}
+	o, err := New(submitter, store, http.Header{})
+	if err != nil {
+		t.Fatalf("new outbox fail: %v", err)
+	}
+	o.SetIdempotencyStore(keys)
+
+	e, err := o.Enqueue([]byte("transfer-1"))
+	if err != nil {
+		t.Fatalf("enqueue fail: %v", err)
+	}
+	if _, err = o.Flush(); err != nil {
+		t.Fatalf("flush fail: %v", err)
+	}
+	if submitter.headers[0].Get(IdempotencyKeyHeader) != e.Key {
+		t.Fatalf("submission should carry idempotency key %v", e.Key)
+	}
+
+	// the entry is left behind by a crash after the key has been recorded
+	if err = store.Append(e); err != nil {
+		t.Fatalf("%v", err)
+	}
+	result, err := o.Flush()
+	if err != nil {
+		t.Fatalf("flush fail: %v", err)
+	}
+	if len(result.Duplicates) != 1 || len(submitter.headers) != 1 {
+		t.Fatalf("confirmed entry should not be submitted again")
+	}
+
+	discrepancies, err := Reconcile(keys, fakeConfirmer{"tx-transfer-1": true})
+	if err != nil {
+		t.Fatalf("reconcile fail: %v", err)
+	}
+	if len(discrepancies) != 0 {
+		t.Fatalf("reconcile should find no discrepancy, not %v", discrepancies[0].Reason)
+	}
+
+	discrepancies, err = Reconcile(keys, fakeConfirmer{})
+	if err != nil {
+		t.Fatalf("reconcile fail: %v", err)
+	}
+	if len(discrepancies) != 1 {
+		t.Fatalf("reconcile should report the unconfirmed transaction")
+	}
+}
+
+func BenchmarkFlush(b *testing.B) {
+	const batch = 100
+	submitter := &fakeSubmitter{errs: map[string]error{}}
+	o, err := New(submitter, nil, http.Header{})
+	if err != nil {
+		b.Fatalf("new outbox fail: %v", err)
+	}
+	o.SetIdempotencyStore(NewMemoryIdempotencyStore())
+	op := []byte(`{"kind":"process_txs","body":{}}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		submitter.submitted = submitter.submitted[:0]
+		for j := 0; j < batch; j++ {
+			if _, err = o.Enqueue(op); err != nil {
+				b.Fatalf("enqueue fail: %v", err)
+			}
+		}
+		b.StartTimer()
+		if _, err = o.Flush(); err != nil {
+			b.Fatalf("flush fail: %v", err)
+		}
+	}
+}
+
+func TestFileIdempotencyStoreWriteFail(t *testing.T) {
+	dir, err := ioutil.TempDir("", "idempotency")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keys", "keys.json")
+	if err = os.Mkdir(filepath.Dir(path), 0700); err != nil {
+		t.Fatalf("%v", err)
+	}
+	store, err := NewFileIdempotencyStore(path)
+	if err != nil {
+		t.Fatalf("new idempotency store fail: %v", err)
+	}
+
+	// the directory is gone, the key can not be persisted
+	if err = os.Remove(filepath.Dir(path)); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err = store.Record("key-1", &Submission{TransactionIds: []string{"tx-1"}}); err == nil {
+		t.Fatalf("record should fail when the file can not be written")
+	}
+	if subs, _ := store.Lookup("key-1"); len(subs) != 0 {
+		t.Fatalf("a key which was not persisted should not be recorded")
+	}
+
+	if err = os.Mkdir(filepath.Dir(path), 0700); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err = store.Record("key-1", &Submission{TransactionIds: []string{"tx-1"}}); err != nil {
+		t.Fatalf("record fail: %v", err)
+	}
+	reloaded, err := NewFileIdempotencyStore(path)
+	if err != nil {
+		t.Fatalf("reload idempotency store fail: %v", err)
+	}
+	if subs, _ := reloaded.Lookup("key-1"); len(subs) != 1 {
+		t.Fatalf("the key should be recorded once, got %d", len(subs))
+	}
+}
+
+func TestReconcileMultipleTransactions(t *testing.T) {
+	keys := NewMemoryIdempotencyStore()
+	keys.Record("key-1", &Submission{TransactionIds: []string{"tx-1", "tx-2"}})
+	keys.Record("key-2", &Submission{TransactionIds: []string{"tx-3"}})
+	keys.Record("key-2", &Submission{TransactionIds: []string{"tx-3"}})
+	keys.Record("key-3", &Submission{TransactionIds: []string{"tx-4"}})
+	keys.Record("key-3", &Submission{TransactionIds: []string{"tx-5"}})
+	keys.Record("key-4", &Submission{})
+
+	confirmed := fakeConfirmer{"tx-1": true, "tx-2": true, "tx-3": true, "tx-4": true, "tx-5": true}
+	discrepancies, err := Reconcile(keys, confirmed)
+	if err != nil {
+		t.Fatalf("reconcile fail: %v", err)
+	}
+	if len(discrepancies) != 2 || discrepancies[0].Key != "key-3" || discrepancies[1].Key != "key-4" {
+		t.Fatalf("reconcile should only report the conflicting and empty keys, got %d", len(discrepancies))
+	}
+}
+
+func TestFileIdempotencyStoreAppend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "idempotency")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keys.json")
+	store, err := NewFileIdempotencyStore(path)
+	if err != nil {
+		t.Fatalf("new idempotency store fail: %v", err)
+	}
+	for _, key := range []string{"key-1", "key-2", "key-1"} {
+		if err = store.Record(key, &Submission{TransactionIds: []string{"tx-" + key}}); err != nil {
+			t.Fatalf("record fail: %v", err)
+		}
+	}
+
+	// a record cut by a crash is dropped
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	file.Write([]byte(`{"key":"key-3","subm`))
+	file.Close()
+
+	reloaded, err := NewFileIdempotencyStore(path)
+	if err != nil {
+		t.Fatalf("reload idempotency store fail: %v", err)
+	}
+	if keys, _ := reloaded.Keys(); len(keys) != 2 {
+		t.Fatalf("2 keys should be recorded, got %v", keys)
+	}
+	if subs, _ := reloaded.Lookup("key-1"); len(subs) != 2 {
+		t.Fatalf("key-1 should be recorded twice, got %d", len(subs))
+	}
+	if err = reloaded.Record("key-3", &Submission{TransactionIds: []string{"tx-3"}}); err != nil {
+		t.Fatalf("record fail: %v", err)
+	}
+	if reloaded, err = NewFileIdempotencyStore(path); err != nil {
+		t.Fatalf("reload idempotency store fail: %v", err)
+	}
+	if subs, _ := reloaded.Lookup("key-3"); len(subs) != 1 {
+		t.Fatalf("key-3 should be recorded after the cut record")
+	}
+}
@@ -0,0 +1,268 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is one signed operation waiting in the outbox.
+//
+type Entry struct {
+	Id        string    `json:"id"`
+	Key       string    `json:"key,omitempty"`
+	Seq       uint64    `json:"seq"`
+	Prepared  []byte    `json:"prepared"`
+	Enqueued  time.Time `json:"enqueued"`
+	Expires   time.Time `json:"expires,omitempty"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// Expired reports whether the entry is expired at the specified time.
+//
+func (e *Entry) Expired(now time.Time) bool {
+	return !e.Expires.IsZero() && !now.Before(e.Expires)
+}
+
+// Store persists outbox entries.
+//
+// List must return the entries in enqueue order, i.e. sorted by Seq.
+//
+type Store interface {
+	Append(e *Entry) error
+	List() ([]*Entry, error)
+	Update(e *Entry) error
+	Remove(id string) error
+}
+
+// MemoryStore is a Store keeping the entries in memory.
+//
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries []*Entry
+}
+
+// NewMemoryStore returns a MemoryStore instance.
+//
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Append implements Store.
+//
+func (m *MemoryStore) Append(e *Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := *e
+	m.entries = append(m.entries, &c)
+	return nil
+}
+
+// List implements Store.
+//
+func (m *MemoryStore) List() ([]*Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entries := make([]*Entry, 0, len(m.entries))
+	for _, e := range m.entries {
+		c := *e
+		entries = append(entries, &c)
+	}
+	return entries, nil
+}
+
+// Update implements Store.
+//
+func (m *MemoryStore) Update(e *Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, old := range m.entries {
+		if old.Id == e.Id {
+			c := *e
+			m.entries[i] = &c
+			return nil
+		}
+	}
+	return fmt.Errorf("outbox entry %s not found", e.Id)
+}
+
+// Remove implements Store.
+//
+func (m *MemoryStore) Remove(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, e := range m.entries {
+		if e.Id == id {
+			m.entries = append(m.entries[:i], m.entries[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// FileStore is a Store keeping each entry in its own JSON file of a directory.
+//
+// Files are written to a temporary name and renamed into place, so an entry
+// survives a crash either completely or not at all.
+//
+type FileStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+const fileStoreExt = ".json"
+
+// NewFileStore returns a FileStore instance persisting entries under dir.
+//
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("outbox directory must be set")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (f *FileStore) path(e *Entry) string {
+	return filepath.Join(f.dir, fmt.Sprintf("%020d-%s%s", e.Seq, e.Id, fileStoreExt))
+}
+
+func (f *FileStore) write(e *Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(f.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err = os.Rename(tmp.Name(), f.path(e)); err != nil {
+		return err
+	}
+	return syncDir(f.dir)
+}
+
+// syncDir flushes the directory entry of renamed or removed files to disk.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// Append implements Store.
+//
+func (f *FileStore) Append(e *Entry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.write(e)
+}
+
+// Update implements Store.
+//
+func (f *FileStore) Update(e *Entry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := os.Stat(f.path(e)); err != nil {
+		return fmt.Errorf("outbox entry %s not found", e.Id)
+	}
+	return f.write(e)
+}
+
+// List implements Store.
+//
+func (f *FileStore) List() ([]*Entry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	names, err := f.names()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*Entry, 0, len(names))
+	for _, name := range names {
+		data, err := ioutil.ReadFile(filepath.Join(f.dir, name))
+		if err != nil {
+			return nil, err
+		}
+		var e Entry
+		if err = json.Unmarshal(data, &e); err != nil {
+			return nil, fmt.Errorf("outbox entry %s invalid: %v", name, err)
+		}
+		entries = append(entries, &e)
+	}
+	return entries, nil
+}
+
+// Remove implements Store.
+//
+func (f *FileStore) Remove(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	names, err := f.names()
+	if err != nil {
+		return err
+	}
+	suffix := "-" + id + fileStoreExt
+	for _, name := range names {
+		if strings.HasSuffix(name, suffix) {
+			if err = os.Remove(filepath.Join(f.dir, name)); err != nil {
+				return err
+			}
+			return syncDir(f.dir)
+		}
+	}
+	return nil
+}
+
+func (f *FileStore) names() ([]string, error) {
+	infos, err := ioutil.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, fileStoreExt) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,245 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package saga orchestrates multi-step wallet flows, such as issuing a token,
+// transferring it to a user and creating a POE receipt, and runs the registered
+// compensations of the completed steps when a later step fails.
+package saga
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+// StepFunc performs or compensates one step of a saga.
+//
+type StepFunc func() error
+
+// Step is one step of a saga.
+//
+type Step struct {
+	Name       string
+	Action     StepFunc
+	Compensate StepFunc
+}
+
+// StepState is the state of one step after a run.
+//
+type StepState int
+
+const (
+	// StepPending means the step has not been run
+	StepPending StepState = iota
+	// StepCompleted means the step action succeeded
+	StepCompleted
+	// StepFailed means the step action failed
+	StepFailed
+	// StepCompensated means the step has been compensated
+	StepCompensated
+	// StepCompensationFailed means the step compensation failed
+	StepCompensationFailed
+)
+
+func (s StepState) String() string {
+	switch s {
+	case StepPending:
+		return "pending"
+	case StepCompleted:
+		return "completed"
+	case StepFailed:
+		return "failed"
+	case StepCompensated:
+		return "compensated"
+	case StepCompensationFailed:
+		return "compensation-failed"
+	}
+	return fmt.Sprintf("StepState(%d)", int(s))
+}
+
+// Error is returned by Run when a step fails.
+//
+type Error struct {
+	Step             string
+	Err              error
+	CompensationErrs map[string]error
+
+	// steps are the step names in order
+	steps []string
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("saga step %s fail: %v", e.Step, e.Err)
+	if len(e.CompensationErrs) == 0 {
+		return msg
+	}
+	names := e.steps
+	if names == nil {
+		for name := range e.CompensationErrs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	var errs []string
+	for _, name := range names {
+		if err, ok := e.CompensationErrs[name]; ok {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	return fmt.Sprintf("%s; compensation fail: %s", msg, strings.Join(errs, ", "))
+}
+
+// Saga runs its steps in order and compensates completed steps on failure.
+//
+type Saga struct {
+	// run is held while the saga runs
+	run sync.Mutex
+
+	mu     sync.Mutex
+	steps  []*Step
+	states map[string]StepState
+	err    error
+}
+
+// New returns a Saga instance.
+//
+func New() *Saga {
+	return &Saga{states: make(map[string]StepState)}
+}
+
+// AddStep appends a step to the saga, compensate may be nil if the step needs
+// no compensation. The step names must be unique, Run fails when a name is
+// added twice.
+//
+func (s *Saga) AddStep(name string, action, compensate StepFunc) *Saga {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.states[name]; ok {
+		if s.err == nil {
+			s.err = fmt.Errorf("saga step %s duplicated", name)
+		}
+		return s
+	}
+	s.steps = append(s.steps, &Step{Name: name, Action: action, Compensate: compensate})
+	s.states[name] = StepPending
+	return s
+}
+
+// State returns the state of the named step, it may be called while the
+// saga runs.
+//
+func (s *Saga) State(name string) StepState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.states[name]
+}
+
+func (s *Saga) setState(name string, state StepState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[name] = state
+}
+
+// Run runs the steps in order, the steps completed by a previous run are
+// skipped.
+//
+// When a step fails, the compensations of the completed steps are run in
+// reverse order and an *Error describing the failure is returned. The
+// completed steps without compensation are marked compensated too, so that
+// they are all run again by the next Run.
+//
+// The compensations which failed in a previous run are run again first, in
+// reverse order, and no step is run while one of them still fails, as the
+// effects of their steps are still applied.
+//
+func (s *Saga) Run() error {
+	s.run.Lock()
+	defer s.run.Unlock()
+
+	s.mu.Lock()
+	if s.err != nil {
+		s.mu.Unlock()
+		return s.err
+	}
+	steps := append([]*Step(nil), s.steps...)
+	states := make(map[string]StepState, len(s.states))
+	for name, state := range s.states {
+		states[name] = state
+	}
+	s.mu.Unlock()
+
+	for _, step := range steps {
+		if step.Name == "" || step.Action == nil {
+			return fmt.Errorf("saga step name and action must be set")
+		}
+	}
+
+	for i := len(steps) - 1; i >= 0; i-- {
+		step := steps[i]
+		if states[step.Name] != StepCompensationFailed {
+			continue
+		}
+		if err := call(step.Compensate); err != nil {
+			return fmt.Errorf("saga step %s compensation fail: %v", step.Name, err)
+		}
+		states[step.Name] = StepCompensated
+		s.setState(step.Name, StepCompensated)
+	}
+
+	for i, step := range steps {
+		if states[step.Name] == StepCompleted {
+			continue
+		}
+		if err := call(step.Action); err != nil {
+			s.setState(step.Name, StepFailed)
+			return s.compensate(steps, i, &Error{Step: step.Name, Err: err})
+		}
+		s.setState(step.Name, StepCompleted)
+	}
+	return nil
+}
+
+func (s *Saga) compensate(steps []*Step, failed int, sagaErr *Error) error {
+	for _, step := range steps {
+		sagaErr.steps = append(sagaErr.steps, step.Name)
+	}
+	for i := failed - 1; i >= 0; i-- {
+		step := steps[i]
+		if step.Compensate == nil {
+			s.setState(step.Name, StepCompensated)
+			continue
+		}
+		if err := call(step.Compensate); err != nil {
+			s.setState(step.Name, StepCompensationFailed)
+			if sagaErr.CompensationErrs == nil {
+				sagaErr.CompensationErrs = make(map[string]error)
+			}
+			sagaErr.CompensationErrs[step.Name] = err
+			continue
+		}
+		s.setState(step.Name, StepCompensated)
+	}
+	return sagaErr
+}
+
+// call runs a step function, a panic is returned as an *api.PanicError.
+func call(f StepFunc) (err error) {
+	defer api.RecoverPanic(&err)
+	return f()
+}
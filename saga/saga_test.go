@@ -0,0 +1,202 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saga
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+func TestRunSucc(t *testing.T) {
+	var done []string
+	s := New().
+		AddStep("issue", func() error { done = append(done, "issue"); return nil }, nil).
+		AddStep("transfer", func() error { done = append(done, "transfer"); return nil }, nil)
+
+	if err := s.Run(); err != nil {
+		t.Fatalf("run saga fail: %v", err)
+	}
+	if fmt.Sprint(done) != "[issue transfer]" {
+		t.Fatalf("steps should run in order, not %v", done)
+	}
+	if s.State("transfer") != StepCompleted {
+		t.Fatalf("step state should be %v", StepCompleted)
+	}
+}
+
+func TestRunCompensate(t *testing.T) {
+	var compensated []string
+	s := New().
+		AddStep("issue", func() error { return nil }, func() error {
+			compensated = append(compensated, "burn")
+			return nil
+		}).
+		AddStep("transfer", func() error { return nil }, func() error {
+			compensated = append(compensated, "reverse-transfer")
+			return nil
+		}).
+		AddStep("create-poe", func() error { return fmt.Errorf("gateway error") }, nil)
+
+	err := s.Run()
+	if err == nil {
+		t.Fatalf("err should not be nil when a step fails")
+	}
+	sagaErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("error type should be *Error")
+	}
+	if sagaErr.Step != "create-poe" {
+		t.Fatalf("failed step should be create-poe, not %v", sagaErr.Step)
+	}
+	if fmt.Sprint(compensated) != "[reverse-transfer burn]" {
+		t.Fatalf("compensations should run in reverse order, not %v", compensated)
+	}
+	if s.State("issue") != StepCompensated || s.State("create-poe") != StepFailed {
+		t.Fatalf("step states should be updated after compensation")
+	}
+}
+
+func TestRunPanicCompensate(t *testing.T) {
+	compensated := false
+	s := New().
+		AddStep("issue", func() error { return nil }, func() error {
+			compensated = true
+			return nil
+		}).
+		AddStep("transfer", func() error { panic("nil response") }, nil)
+
+	err := s.Run()
+	if err == nil {
+		t.Fatalf("err should not be nil when a step panics")
+	}
+	sagaErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("error type should be *Error")
+	}
+	if _, ok = sagaErr.Err.(*api.PanicError); !ok {
+		t.Fatalf("step error type should be *api.PanicError, not %T", sagaErr.Err)
+	}
+	if !compensated {
+		t.Fatalf("completed steps should be compensated when a step panics")
+	}
+}
+
+func TestAddStepDuplicated(t *testing.T) {
+	s := New().
+		AddStep("issue", func() error { return nil }, nil).
+		AddStep("issue", func() error { return nil }, nil)
+	if err := s.Run(); err == nil {
+		t.Fatalf("err should not be nil when a step name is duplicated")
+	}
+}
+
+func TestStateWhileRunning(t *testing.T) {
+	s := New()
+	var state StepState
+	s.AddStep("issue", func() error { return nil }, nil).
+		AddStep("transfer", func() error {
+			state = s.State("issue")
+			return nil
+		}, nil)
+	if err := s.Run(); err != nil {
+		t.Fatalf("run saga fail: %v", err)
+	}
+	if state != StepCompleted {
+		t.Fatalf("state should be readable while running, got %v", state)
+	}
+}
+
+func TestRunAgainAfterCompensation(t *testing.T) {
+	var done []string
+	fail := true
+	step := func(name string) StepFunc {
+		return func() error { done = append(done, name); return nil }
+	}
+	failing := func(name string) StepFunc {
+		return func() error { return fmt.Errorf("%s fail", name) }
+	}
+	s := New().
+		AddStep("issue", step("issue"), nil).
+		AddStep("transfer", step("transfer"), failing("reverse-transfer")).
+		AddStep("lock", step("lock"), failing("unlock")).
+		AddStep("create-poe", func() error {
+			if fail {
+				return fmt.Errorf("gateway error")
+			}
+			done = append(done, "create-poe")
+			return nil
+		}, nil)
+
+	err := s.Run()
+	if err == nil {
+		t.Fatalf("err should not be nil when a step fails")
+	}
+	expected := "saga step create-poe fail: gateway error; compensation fail: transfer: reverse-transfer fail, lock: unlock fail"
+	if err.Error() != expected {
+		t.Fatalf("compensation errors should be in step order, got %q", err.Error())
+	}
+	if s.State("issue") != StepCompensated {
+		t.Fatalf("step without compensation should be compensated, got %v", s.State("issue"))
+	}
+
+	fail = false
+	done = nil
+	if err = s.Run(); err != nil {
+		t.Fatalf("run saga fail: %v", err)
+	}
+	if fmt.Sprint(done) != "[issue transfer lock create-poe]" {
+		t.Fatalf("all steps should run again, not %v", done)
+	}
+}
+
+func TestRunAfterCompensationFailed(t *testing.T) {
+	issued := 0
+	burnFail := true
+	s := New().
+		AddStep("issue", func() error { issued++; return nil }, func() error {
+			if burnFail {
+				return fmt.Errorf("burn fail")
+			}
+			return nil
+		}).
+		AddStep("transfer", func() error { return fmt.Errorf("gateway error") }, nil)
+
+	if err := s.Run(); err == nil {
+		t.Fatalf("err should not be nil when a step fails")
+	}
+	if s.State("issue") != StepCompensationFailed {
+		t.Fatalf("step state should be %v", StepCompensationFailed)
+	}
+
+	// the compensation still fails, the issuance must not run again
+	if err := s.Run(); err == nil {
+		t.Fatalf("err should not be nil while a compensation fails")
+	}
+	if issued != 1 {
+		t.Fatalf("step should not run again before it is compensated, ran %d times", issued)
+	}
+
+	burnFail = false
+	if err := s.Run(); err == nil {
+		t.Fatalf("err should not be nil when a step fails")
+	}
+	if issued != 2 {
+		t.Fatalf("step should run again once compensated, ran %d times", issued)
+	}
+}
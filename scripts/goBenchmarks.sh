@@ -0,0 +1,61 @@
+#!/bin/bash
+#
+# Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+#
+# SPDX-License-Identifier: Apache-2.0
+#
+# Runs the go benchmarks.
+#
+#   goBenchmarks.sh baseline  records the results as the baseline
+#   goBenchmarks.sh           compares the results with the baseline and fails
+#                             when a benchmark is slower by more than
+#                             BENCH_THRESHOLD percent
+#
+
+set -e
+
+BENCH_DIR=${BENCH_DIR:-build/bench}
+BENCH_COUNT=${BENCH_COUNT:-5}
+BENCH_THRESHOLD=${BENCH_THRESHOLD:-10}
+
+mkdir -p $BENCH_DIR
+
+echo -n "Obtaining list of packages to benchmark.."
+PKGS=`go list github.com/arxanchain/wallet-sdk-go/... | grep -v /vendor/`
+echo "DONE!"
+
+if [ "$1" == "baseline" ]; then
+	OUTPUT=$BENCH_DIR/baseline.txt
+else
+	OUTPUT=$BENCH_DIR/current.txt
+fi
+
+echo "Running benchmarks..."
+go test -run XXX_NO_TESTS -bench . -benchmem -count $BENCH_COUNT $PKGS | tee $OUTPUT
+
+if [ "$1" == "baseline" ]; then
+	echo "Baseline recorded in $OUTPUT"
+	exit 0
+fi
+
+if [ ! -f $BENCH_DIR/baseline.txt ]; then
+	echo "No baseline to compare with, run 'make bench-baseline' first"
+	exit 0
+fi
+
+echo "Comparing with baseline..."
+benchcmp -best $BENCH_DIR/baseline.txt $OUTPUT | tee $BENCH_DIR/compare.txt
+
+# benchcmp prints one section per unit, fail on time regressions
+awk -v threshold=$BENCH_THRESHOLD '
+	$1 == "benchmark" { unit = $3; next }
+	unit == "ns/op" && $1 ~ /^Benchmark/ {
+		delta = $NF
+		gsub(/[+%]/, "", delta)
+		if (delta + 0 > threshold) {
+			printf "%s regressed by %s%%\n", $1, delta
+			failed = 1
+		}
+	}
+	END { exit failed }
+' $BENCH_DIR/compare.txt
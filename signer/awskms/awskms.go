@@ -0,0 +1,151 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package awskms provides an api.Signer delegating the signatures to AWS Key
+// Management Service, the private keys never leave the service.
+//
+// It calls the KMS JSON API signed with AWS Signature Version 4, so it does
+// not depend on the AWS SDK. KMS only supports ECDSA on the NIST P-256 curve
+// among the wallet algorithms.
+package awskms
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/utils"
+	"github.com/arxanchain/wallet-sdk-go/signer"
+)
+
+const (
+	serviceName    = "kms"
+	signTarget     = "TrentService.Sign"
+	contentType    = "application/x-amz-json-1.1"
+	defaultTimeout = 10 * time.Second
+)
+
+// Credentials are the AWS credentials signing the requests.
+//
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is set for temporary credentials only
+	SessionToken string
+}
+
+// Config is the configuration of an AWS KMS signer.
+//
+type Config struct {
+	// Region is the AWS region of the key, e.g. us-east-1
+	Region string
+	// KeyId is the key id, ARN or alias of an ECC_NIST_P256 signing key
+	KeyId string
+	// Credentials sign the requests, allowed to use kms:Sign on the key
+	Credentials Credentials
+	// Endpoint is the KMS endpoint, https://kms.{Region}.amazonaws.com if empty
+	Endpoint string
+	// Creator is the DID the key belongs to
+	Creator did.Identifier
+	// HTTPClient sends the requests, a client with a 10 seconds timeout if nil
+	HTTPClient *http.Client
+}
+
+// Signer signs with an AWS KMS key.
+//
+type Signer struct {
+	cfg Config
+	now func() time.Time
+}
+
+type signRequest struct {
+	KeyId            string `json:"KeyId"`
+	Message          string `json:"Message"`
+	MessageType      string `json:"MessageType"`
+	SigningAlgorithm string `json:"SigningAlgorithm"`
+}
+
+type signResponse struct {
+	Signature string `json:"Signature"`
+}
+
+// New returns an AWS KMS signer.
+//
+func New(cfg *Config) (*Signer, error) {
+	if cfg == nil || cfg.Region == "" || cfg.KeyId == "" || cfg.Creator == "" {
+		return nil, fmt.Errorf("aws kms config invalid")
+	}
+	if cfg.Credentials.AccessKeyID == "" || cfg.Credentials.SecretAccessKey == "" {
+		return nil, fmt.Errorf("aws kms credentials invalid")
+	}
+	s := &Signer{cfg: *cfg, now: time.Now}
+	if s.cfg.Endpoint == "" {
+		s.cfg.Endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com", s.cfg.Region)
+	}
+	if _, err := url.Parse(s.cfg.Endpoint); err != nil {
+		return nil, fmt.Errorf("aws kms endpoint invalid: %v", err)
+	}
+	if s.cfg.HTTPClient == nil {
+		s.cfg.HTTPClient = &http.Client{Timeout: defaultTimeout}
+	}
+	return s, nil
+}
+
+// Sign signs the SHA-256 digest of the payload with the key, the signature
+// is encoded in ASN.1 DER.
+//
+func (s *Signer) Sign(payload []byte) (*pki.SignatureBody, error) {
+	body, err := signer.NewBody(s.cfg.Creator)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(payload)
+	data, err := json.Marshal(&signRequest{
+		KeyId:            s.cfg.KeyId,
+		Message:          base64.StdEncoding.EncodeToString(digest[:]),
+		MessageType:      "DIGEST",
+		SigningAlgorithm: "ECDSA_SHA_256",
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("POST", s.cfg.Endpoint+"/", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Amz-Target", signTarget)
+	signV4(req, data, s.cfg.Credentials, s.cfg.Region, serviceName, s.now())
+
+	var resp signResponse
+	if err = signer.DoJSON(s.cfg.HTTPClient, req, &resp); err != nil {
+		return nil, fmt.Errorf("aws kms sign fail: %v", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(resp.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("aws kms signature invalid: %v", err)
+	}
+	body.SignatureValue = utils.EncodeBase64(sig)
+	return body, nil
+}
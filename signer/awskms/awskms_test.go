@@ -0,0 +1,92 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awskms
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSignV4 checks the get-vanilla case of the AWS Signature Version 4 test suite.
+func TestSignV4(t *testing.T) {
+	req, err := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+	if err != nil {
+		t.Fatalf("new request fail: %v", err)
+	}
+	creds := Credentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	signV4(req, nil, creds, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, " +
+		"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if auth := req.Header.Get("Authorization"); auth != expected {
+		t.Fatalf("authorization should be %q, not %q", expected, auth)
+	}
+}
+
+func TestSign(t *testing.T) {
+	const payload = "request payload"
+	var received signRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != signTarget ||
+			r.Header.Get("X-Amz-Security-Token") != "session" ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		json.NewEncoder(w).Encode(&signResponse{Signature: base64.StdEncoding.EncodeToString([]byte("signature"))})
+	}))
+	defer server.Close()
+
+	s, err := New(&Config{
+		Region:      "us-east-1",
+		KeyId:       "alias/issuer",
+		Credentials: Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"},
+		Endpoint:    server.URL,
+		Creator:     "did:axn:issuer",
+	})
+	if err != nil {
+		t.Fatalf("new aws kms signer fail: %v", err)
+	}
+	body, err := s.Sign([]byte(payload))
+	if err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+	digest := sha256.Sum256([]byte(payload))
+	if received.KeyId != "alias/issuer" || received.MessageType != "DIGEST" ||
+		received.Message != base64.StdEncoding.EncodeToString(digest[:]) {
+		t.Fatalf("sign request mismatch: %#v", received)
+	}
+	if body.Creator != "did:axn:issuer" || body.SignatureValue == "" {
+		t.Fatalf("signature body mismatch: %#v", body)
+	}
+
+	s.cfg.Credentials.SessionToken = ""
+	if _, err = s.Sign([]byte(payload)); err == nil {
+		t.Fatalf("err should not be nil when the request is rejected")
+	}
+}
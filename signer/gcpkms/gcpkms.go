@@ -0,0 +1,158 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gcpkms provides an api.Signer delegating the signatures to Google
+// Cloud Key Management Service, the private keys never leave the service.
+//
+// It calls the Cloud KMS REST API, the OAuth2 access token is provided by a
+// TokenFunc, e.g. wrapping a golang.org/x/oauth2 token source:
+//
+//	ts, err := google.DefaultTokenSource(ctx, "https://www.googleapis.com/auth/cloudkms")
+//	token := func() (string, error) {
+//		t, err := ts.Token()
+//		if err != nil {
+//			return "", err
+//		}
+//		return t.AccessToken, nil
+//	}
+package gcpkms
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/utils"
+	"github.com/arxanchain/wallet-sdk-go/signer"
+)
+
+const (
+	defaultEndpoint = "https://cloudkms.googleapis.com"
+	defaultTimeout  = 10 * time.Second
+)
+
+// TokenFunc returns an OAuth2 access token allowed to sign with the key.
+//
+type TokenFunc func() (string, error)
+
+// Config is the configuration of a Cloud KMS signer.
+//
+type Config struct {
+	// KeyVersion is the resource name of the key version, i.e.
+	// projects/*/locations/*/keyRings/*/cryptoKeys/*/cryptoKeyVersions/*
+	KeyVersion string
+	// Token returns the access token of the requests
+	Token TokenFunc
+	// Algorithm is the signature algorithm of the key, EC_SIGN_ED25519 or
+	// EC_SIGN_P256_SHA256
+	Algorithm signer.Algorithm
+	// Creator is the DID the key belongs to
+	Creator did.Identifier
+	// Endpoint is the Cloud KMS endpoint, https://cloudkms.googleapis.com if empty
+	Endpoint string
+	// HTTPClient sends the requests, a client with a 10 seconds timeout if nil
+	HTTPClient *http.Client
+}
+
+// Signer signs with a Cloud KMS key version.
+//
+type Signer struct {
+	cfg Config
+	url string
+}
+
+type signDigest struct {
+	SHA256 string `json:"sha256"`
+}
+
+type signRequest struct {
+	Data   string      `json:"data,omitempty"`
+	Digest *signDigest `json:"digest,omitempty"`
+}
+
+type signResponse struct {
+	Signature string `json:"signature"`
+}
+
+// New returns a Cloud KMS signer.
+//
+func New(cfg *Config) (*Signer, error) {
+	if cfg == nil || cfg.KeyVersion == "" || cfg.Token == nil || cfg.Creator == "" {
+		return nil, fmt.Errorf("gcp kms config invalid")
+	}
+	if cfg.Algorithm != signer.Ed25519 && cfg.Algorithm != signer.ECDSAP256 {
+		return nil, fmt.Errorf("gcp kms algorithm invalid: %v", cfg.Algorithm)
+	}
+	s := &Signer{cfg: *cfg}
+	if s.cfg.Endpoint == "" {
+		s.cfg.Endpoint = defaultEndpoint
+	}
+	if s.cfg.HTTPClient == nil {
+		s.cfg.HTTPClient = &http.Client{Timeout: defaultTimeout}
+	}
+	s.url = fmt.Sprintf("%s/v1/%s:asymmetricSign", strings.TrimRight(s.cfg.Endpoint, "/"), s.cfg.KeyVersion)
+	return s, nil
+}
+
+// Sign signs the payload with the key version, ed25519 keys sign the
+// payload itself and ecdsa keys its SHA-256 digest.
+//
+func (s *Signer) Sign(payload []byte) (*pki.SignatureBody, error) {
+	body, err := signer.NewBody(s.cfg.Creator)
+	if err != nil {
+		return nil, err
+	}
+
+	reqBody := &signRequest{}
+	if s.cfg.Algorithm == signer.ECDSAP256 {
+		digest := sha256.Sum256(payload)
+		reqBody.Digest = &signDigest{SHA256: base64.StdEncoding.EncodeToString(digest[:])}
+	} else {
+		reqBody.Data = base64.StdEncoding.EncodeToString(payload)
+	}
+	data, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+	token, err := s.cfg.Token()
+	if err != nil {
+		return nil, fmt.Errorf("gcp kms token fail: %v", err)
+	}
+	req, err := http.NewRequest("POST", s.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp signResponse
+	if err = signer.DoJSON(s.cfg.HTTPClient, req, &resp); err != nil {
+		return nil, fmt.Errorf("gcp kms sign fail: %v", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(resp.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("gcp kms signature invalid: %v", err)
+	}
+	body.SignatureValue = utils.EncodeBase64(sig)
+	return body, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcpkms
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/arxanchain/wallet-sdk-go/signer"
+)
+
+const keyVersion = "projects/p/locations/global/keyRings/r/cryptoKeys/issuer/cryptoKeyVersions/1"
+
+func newTestServer(t *testing.T, received *signRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/"+keyVersion+":asymmetricSign" || r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(received)
+		json.NewEncoder(w).Encode(&signResponse{Signature: base64.StdEncoding.EncodeToString([]byte("signature"))})
+	}))
+}
+
+func TestSign(t *testing.T) {
+	const payload = "request payload"
+	var received signRequest
+	server := newTestServer(t, &received)
+	defer server.Close()
+
+	s, err := New(&Config{
+		KeyVersion: keyVersion,
+		Token:      func() (string, error) { return "access-token", nil },
+		Algorithm:  signer.ECDSAP256,
+		Creator:    "did:axn:issuer",
+		Endpoint:   server.URL,
+	})
+	if err != nil {
+		t.Fatalf("new gcp kms signer fail: %v", err)
+	}
+	body, err := s.Sign([]byte(payload))
+	if err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+	digest := sha256.Sum256([]byte(payload))
+	if received.Digest == nil || received.Digest.SHA256 != base64.StdEncoding.EncodeToString(digest[:]) || received.Data != "" {
+		t.Fatalf("ecdsa keys should sign the digest, got %#v", received)
+	}
+	if body.Creator != "did:axn:issuer" || body.SignatureValue == "" {
+		t.Fatalf("signature body mismatch: %#v", body)
+	}
+}
+
+func TestSignEd25519(t *testing.T) {
+	const payload = "request payload"
+	var received signRequest
+	server := newTestServer(t, &received)
+	defer server.Close()
+
+	s, err := New(&Config{
+		KeyVersion: keyVersion,
+		Token:      func() (string, error) { return "access-token", nil },
+		Algorithm:  signer.Ed25519,
+		Creator:    "did:axn:issuer",
+		Endpoint:   server.URL,
+	})
+	if err != nil {
+		t.Fatalf("new gcp kms signer fail: %v", err)
+	}
+	if _, err = s.Sign([]byte(payload)); err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+	if received.Data != base64.StdEncoding.EncodeToString([]byte(payload)) || received.Digest != nil {
+		t.Fatalf("ed25519 keys should sign the payload, got %#v", received)
+	}
+
+	s.cfg.Token = func() (string, error) { return "expired-token", nil }
+	if _, err = s.Sign([]byte(payload)); err == nil {
+		t.Fatalf("err should not be nil when the token is rejected")
+	}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pkcs11 provides an api.Signer performing the signatures inside a
+// hardware security module through its PKCS#11 library, the private keys
+// never leave the token.
+//
+// The package needs cgo and github.com/miekg/pkcs11, it is only built with
+// the pkcs11 build tag:
+//
+//	go get github.com/miekg/pkcs11
+//	go build -tags pkcs11
+//
+// The signer is registered on the wallet client for the DID of the key:
+//
+//	s, err := pkcs11.New(&pkcs11.Config{
+//		Module:     "/usr/lib/softhsm/libsofthsm2.so",
+//		TokenLabel: "issuer",
+//		PIN:        pin,
+//		KeyLabel:   "issuer-ed25519",
+//		Algorithm:  signer.Ed25519,
+//		Creator:    "did:axn:issuer",
+//	})
+//	defer s.Close()
+//	walletClient.SetSigner("did:axn:issuer", s)
+package pkcs11
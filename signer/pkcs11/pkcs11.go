@@ -0,0 +1,196 @@
+//go:build pkcs11
+// +build pkcs11
+
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkcs11
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/utils"
+	"github.com/arxanchain/wallet-sdk-go/signer"
+	p11 "github.com/miekg/pkcs11"
+)
+
+// ckmEDDSA is the PKCS#11 3.0 EdDSA mechanism, not defined by older
+// versions of the bindings
+const ckmEDDSA = 0x00001057
+
+// Config is the configuration of a PKCS#11 signer.
+//
+type Config struct {
+	// Module is the path of the PKCS#11 library of the HSM
+	Module string
+	// TokenLabel is the label of the token holding the key
+	TokenLabel string
+	// PIN is the user PIN of the token
+	PIN string
+	// KeyLabel is the label of the private key
+	KeyLabel string
+	// Algorithm is the signature algorithm of the key
+	Algorithm signer.Algorithm
+	// Creator is the DID the key belongs to
+	Creator did.Identifier
+}
+
+// Signer signs with a private key held by a PKCS#11 token. It is safe for
+// concurrent use, the signatures are serialized on one session.
+//
+type Signer struct {
+	mu        sync.Mutex
+	ctx       *p11.Ctx
+	session   p11.SessionHandle
+	key       p11.ObjectHandle
+	algorithm signer.Algorithm
+	creator   did.Identifier
+}
+
+// New loads the PKCS#11 library, logs in the token and finds the key.
+//
+func New(cfg *Config) (s *Signer, err error) {
+	if cfg == nil || cfg.Module == "" || cfg.KeyLabel == "" || cfg.Creator == "" {
+		return nil, fmt.Errorf("pkcs11 config invalid")
+	}
+	if cfg.Algorithm != signer.Ed25519 && cfg.Algorithm != signer.ECDSAP256 {
+		return nil, fmt.Errorf("pkcs11 algorithm invalid: %v", cfg.Algorithm)
+	}
+
+	ctx := p11.New(cfg.Module)
+	if ctx == nil {
+		return nil, fmt.Errorf("load pkcs11 module %s fail", cfg.Module)
+	}
+	if err = ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return nil, err
+	}
+	s = &Signer{ctx: ctx, algorithm: cfg.Algorithm, creator: cfg.Creator}
+	defer func() {
+		if err != nil {
+			s.Close()
+			s = nil
+		}
+	}()
+
+	slot, err := findSlot(ctx, cfg.TokenLabel)
+	if err != nil {
+		return
+	}
+	if s.session, err = ctx.OpenSession(slot, p11.CKF_SERIAL_SESSION); err != nil {
+		return
+	}
+	if err = ctx.Login(s.session, p11.CKU_USER, cfg.PIN); err != nil {
+		return
+	}
+	s.key, err = findKey(ctx, s.session, cfg.KeyLabel)
+	return
+}
+
+// Sign signs the payload inside the token.
+//
+func (s *Signer) Sign(payload []byte) (*pki.SignatureBody, error) {
+	body, err := signer.NewBody(s.creator)
+	if err != nil {
+		return nil, err
+	}
+
+	mechanism, data := uint(ckmEDDSA), payload
+	if s.algorithm == signer.ECDSAP256 {
+		digest := sha256.Sum256(payload)
+		mechanism, data = p11.CKM_ECDSA, digest[:]
+	}
+
+	s.mu.Lock()
+	err = s.ctx.SignInit(s.session, []*p11.Mechanism{p11.NewMechanism(mechanism, nil)}, s.key)
+	var sig []byte
+	if err == nil {
+		sig, err = s.ctx.Sign(s.session, data)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11 sign fail: %v", err)
+	}
+
+	if s.algorithm == signer.ECDSAP256 {
+		if sig, err = signer.ECDSASignature(sig); err != nil {
+			return nil, err
+		}
+	}
+	body.SignatureValue = utils.EncodeBase64(sig)
+	return body, nil
+}
+
+// Close logs out and releases the PKCS#11 library.
+//
+func (s *Signer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx == nil {
+		return nil
+	}
+	if s.session != 0 {
+		s.ctx.Logout(s.session)
+		s.ctx.CloseSession(s.session)
+	}
+	s.ctx.Finalize()
+	s.ctx.Destroy()
+	s.ctx = nil
+	return nil
+}
+
+// findSlot returns the slot of the token with the label, or the first slot
+// with a token if label is empty.
+func findSlot(ctx *p11.Ctx, label string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, err
+	}
+	for _, slot := range slots {
+		if label == "" {
+			return slot, nil
+		}
+		info, err := ctx.GetTokenInfo(slot)
+		if err == nil && info.Label == label {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("pkcs11 token %q not found", label)
+}
+
+// findKey returns the private key with the label.
+func findKey(ctx *p11.Ctx, session p11.SessionHandle, label string) (p11.ObjectHandle, error) {
+	template := []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_CLASS, p11.CKO_PRIVATE_KEY),
+		p11.NewAttribute(p11.CKA_LABEL, label),
+	}
+	if err := ctx.FindObjectsInit(session, template); err != nil {
+		return 0, err
+	}
+	objects, _, err := ctx.FindObjects(session, 1)
+	ctx.FindObjectsFinal(session)
+	if err != nil {
+		return 0, err
+	}
+	if len(objects) == 0 {
+		return 0, fmt.Errorf("pkcs11 key %q not found", label)
+	}
+	return objects[0], nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package signer holds the helpers shared by the api.Signer implementations
+// backed by external key stores, which live in the sub packages:
+//
+//	pkcs11    hardware security modules, built with the pkcs11 tag
+//	awskms    AWS Key Management Service
+//	gcpkms    Google Cloud Key Management Service
+//	vault     HashiCorp Vault transit secrets engine
+//
+// Keys are never loaded into the client process, the key stores sign the
+// payloads and return the raw signatures.
+package signer
+
+import (
+	"crypto/rand"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+// Algorithm is the signature algorithm of a key.
+//
+type Algorithm int
+
+const (
+	// Ed25519 signs the payload with an EdDSA key on curve 25519
+	Ed25519 Algorithm = iota
+	// ECDSAP256 signs the SHA-256 digest of the payload with an ECDSA key on
+	// the NIST P-256 curve, the signature is encoded in ASN.1 DER
+	ECDSAP256
+)
+
+// String returns the name of the algorithm.
+//
+func (a Algorithm) String() string {
+	switch a {
+	case Ed25519:
+		return "Ed25519"
+	case ECDSAP256:
+		return "ECDSA-P256-SHA256"
+	}
+	return fmt.Sprintf("Algorithm(%d)", int(a))
+}
+
+const nonceSize = 16
+
+// NewNonce returns a random signature nonce.
+//
+func NewNonce() (string, error) {
+	b := make([]byte, nonceSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// nonces generates the nonces of the signature bodies, the same way as the
+// client for the signature params without nonce.
+var nonces = api.NewNonceGenerator()
+
+// NewBody returns the signature body of creator, with a new nonce and the
+// current time. Like the client does with the signature params, it is made
+// before the payload is signed, the signers then set the base64 of the raw
+// signature as its SignatureValue.
+//
+// The nonces are made by an api.NonceGenerator, unique and ordered for the
+// creator.
+//
+func NewBody(creator did.Identifier) (*pki.SignatureBody, error) {
+	nonce, err := nonces.Nonce(creator)
+	if err != nil {
+		return nil, err
+	}
+	return &pki.SignatureBody{
+		Creator: creator,
+		Created: time.Now().Unix(),
+		Nonce:   nonce,
+	}, nil
+}
+
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// ECDSASignature encodes a raw ECDSA signature, r and s of the same size
+// concatenated as returned by HSMs, in ASN.1 DER.
+//
+func ECDSASignature(raw []byte) ([]byte, error) {
+	if len(raw) == 0 || len(raw)%2 != 0 {
+		return nil, fmt.Errorf("raw ecdsa signature invalid: %d bytes", len(raw))
+	}
+	half := len(raw) / 2
+	return asn1.Marshal(ecdsaSignature{
+		R: new(big.Int).SetBytes(raw[:half]),
+		S: new(big.Int).SetBytes(raw[half:]),
+	})
+}
+
+// DoJSON sends the request with client and decodes the json response into
+// out, a response status other than 200 is returned as an error with the
+// response body.
+//
+func DoJSON(client *http.Client, req *http.Request, out interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response code: %d (%s)", resp.StatusCode, body)
+	}
+	return json.Unmarshal(body, out)
+}
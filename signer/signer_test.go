@@ -0,0 +1,76 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+)
+
+func TestNewBody(t *testing.T) {
+	body, err := NewBody("did:axn:001")
+	if err != nil {
+		t.Fatalf("build signature body fail: %v", err)
+	}
+	if body.Creator != "did:axn:001" || body.Created == 0 || body.Nonce == "" || body.SignatureValue != "" {
+		t.Fatalf("signature body mismatch: %#v", body)
+	}
+	other, err := NewBody("did:axn:001")
+	if err != nil {
+		t.Fatalf("build signature body fail: %v", err)
+	}
+	if other.Nonce <= body.Nonce {
+		t.Fatalf("nonces of a creator should be unique and ordered: %s, %s", body.Nonce, other.Nonce)
+	}
+}
+
+func TestECDSASignature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	digest := sha256.Sum256([]byte("payload"))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	raw := make([]byte, 64)
+	rb, sb := r.Bytes(), s.Bytes()
+	copy(raw[32-len(rb):32], rb)
+	copy(raw[64-len(sb):], sb)
+
+	der, err := ECDSASignature(raw)
+	if err != nil {
+		t.Fatalf("encode ecdsa signature fail: %v", err)
+	}
+	var sig struct{ R, S *big.Int }
+	if _, err = asn1.Unmarshal(der, &sig); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if !ecdsa.Verify(&key.PublicKey, digest[:], sig.R, sig.S) {
+		t.Fatalf("encoded signature should verify")
+	}
+
+	if _, err = ECDSASignature(raw[:63]); err == nil {
+		t.Fatalf("err should not be nil for an odd raw signature")
+	}
+}
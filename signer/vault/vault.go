@@ -0,0 +1,144 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vault provides an api.Signer delegating the signatures to the
+// transit secrets engine of HashiCorp Vault, the private keys never leave
+// Vault.
+package vault
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/pki"
+	"github.com/arxanchain/sdk-go-common/utils"
+	"github.com/arxanchain/wallet-sdk-go/signer"
+)
+
+const (
+	defaultMount   = "transit"
+	defaultTimeout = 10 * time.Second
+)
+
+// Config is the configuration of a Vault transit signer.
+//
+type Config struct {
+	// Address is the Vault address, e.g. https://vault:8200
+	Address string
+	// Token is the Vault token, allowed to sign with the key
+	Token string
+	// Mount is the mount path of the transit engine, "transit" if empty
+	Mount string
+	// KeyName is the name of the transit key
+	KeyName string
+	// Algorithm is the signature algorithm of the key, an ed25519 or
+	// ecdsa-p256 transit key
+	Algorithm signer.Algorithm
+	// Creator is the DID the key belongs to
+	Creator did.Identifier
+	// HTTPClient sends the requests, a client with a 10 seconds timeout if nil
+	HTTPClient *http.Client
+}
+
+// Signer signs with a Vault transit key.
+//
+type Signer struct {
+	cfg Config
+	url string
+}
+
+type signRequest struct {
+	Input               string `json:"input"`
+	HashAlgorithm       string `json:"hash_algorithm,omitempty"`
+	MarshalingAlgorithm string `json:"marshaling_algorithm,omitempty"`
+}
+
+type signResponse struct {
+	Data struct {
+		Signature string `json:"signature"`
+	} `json:"data"`
+}
+
+// New returns a Vault transit signer.
+//
+func New(cfg *Config) (*Signer, error) {
+	if cfg == nil || cfg.Address == "" || cfg.Token == "" || cfg.KeyName == "" || cfg.Creator == "" {
+		return nil, fmt.Errorf("vault config invalid")
+	}
+	if cfg.Algorithm != signer.Ed25519 && cfg.Algorithm != signer.ECDSAP256 {
+		return nil, fmt.Errorf("vault algorithm invalid: %v", cfg.Algorithm)
+	}
+	s := &Signer{cfg: *cfg}
+	if s.cfg.Mount == "" {
+		s.cfg.Mount = defaultMount
+	}
+	if s.cfg.HTTPClient == nil {
+		s.cfg.HTTPClient = &http.Client{Timeout: defaultTimeout}
+	}
+	s.url = fmt.Sprintf("%s/v1/%s/sign/%s", strings.TrimRight(s.cfg.Address, "/"), s.cfg.Mount, s.cfg.KeyName)
+	return s, nil
+}
+
+// Sign signs the payload with the transit key.
+//
+func (s *Signer) Sign(payload []byte) (*pki.SignatureBody, error) {
+	body, err := signer.NewBody(s.cfg.Creator)
+	if err != nil {
+		return nil, err
+	}
+
+	reqBody := &signRequest{Input: base64.StdEncoding.EncodeToString(payload)}
+	if s.cfg.Algorithm == signer.ECDSAP256 {
+		reqBody.HashAlgorithm = "sha2-256"
+		reqBody.MarshalingAlgorithm = "asn1"
+	}
+	data, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("POST", s.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", s.cfg.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp signResponse
+	if err = signer.DoJSON(s.cfg.HTTPClient, req, &resp); err != nil {
+		return nil, fmt.Errorf("vault sign fail: %v", err)
+	}
+	sig, err := parseSignature(resp.Data.Signature)
+	if err != nil {
+		return nil, err
+	}
+	body.SignatureValue = utils.EncodeBase64(sig)
+	return body, nil
+}
+
+// parseSignature decodes a "vault:v<version>:<base64>" signature.
+func parseSignature(value string) ([]byte, error) {
+	parts := strings.SplitN(value, ":", 3)
+	if len(parts) != 3 || parts[0] != "vault" {
+		return nil, fmt.Errorf("vault signature invalid: %q", value)
+	}
+	return base64.StdEncoding.DecodeString(parts[2])
+}
@@ -0,0 +1,77 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/arxanchain/wallet-sdk-go/signer"
+)
+
+func TestSign(t *testing.T) {
+	const payload = "request payload"
+	var received signRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/transit/sign/issuer" || r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"data":{"signature":"vault:v1:` + base64.StdEncoding.EncodeToString([]byte("signature")) + `"}}`))
+	}))
+	defer server.Close()
+
+	s, err := New(&Config{
+		Address:   server.URL,
+		Token:     "vault-token",
+		KeyName:   "issuer",
+		Algorithm: signer.ECDSAP256,
+		Creator:   "did:axn:issuer",
+	})
+	if err != nil {
+		t.Fatalf("new vault signer fail: %v", err)
+	}
+	body, err := s.Sign([]byte(payload))
+	if err != nil {
+		t.Fatalf("sign fail: %v", err)
+	}
+	if received.Input != base64.StdEncoding.EncodeToString([]byte(payload)) || received.HashAlgorithm != "sha2-256" || received.MarshalingAlgorithm != "asn1" {
+		t.Fatalf("sign request mismatch: %#v", received)
+	}
+	if body.Creator != "did:axn:issuer" || body.SignatureValue == "" {
+		t.Fatalf("signature body mismatch: %#v", body)
+	}
+
+	s.cfg.Token = "other-token"
+	if _, err = s.Sign([]byte(payload)); err == nil {
+		t.Fatalf("err should not be nil when vault denies the signature")
+	}
+}
+
+func TestParseSignature(t *testing.T) {
+	sig, err := parseSignature("vault:v2:" + base64.StdEncoding.EncodeToString([]byte("sig")))
+	if err != nil || string(sig) != "sig" {
+		t.Fatalf("parse signature fail: %v", err)
+	}
+	if _, err = parseSignature("c2ln"); err == nil {
+		t.Fatalf("err should not be nil without vault prefix")
+	}
+}
@@ -0,0 +1,315 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package statements builds periodic account statements (opening balance,
+// transactions and closing balance per token) on top of the wallet logs
+// and balance APIs.
+package statements
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+const (
+	// DirectionIn marks an income transaction line
+	DirectionIn = "in"
+	// DirectionOut marks a spending transaction line
+	DirectionOut = "out"
+
+	defaultPageSize int32 = 100
+)
+
+// Client is the subset of the wallet client used to build statements.
+//
+type Client interface {
+	GetWalletBalance(header http.Header, id did.Identifier) (*wallet.WalletBalance, error)
+	QueryTransactionLogsPage(header http.Header, query *api.TransactionLogsQuery) (*api.TransactionLogsPage, error)
+}
+
+// Line is one transaction line of a statement.
+//
+type Line struct {
+	Direction    string `json:"direction"`
+	TokenId      string `json:"token_id"`
+	Amount       int64  `json:"amount"`
+	TxHash       string `json:"tx_hash"`
+	Counterparty string `json:"counterparty"`
+}
+
+// TokenStatement is the statement section of a single token, its balances
+// are the balances at the start and at the end of the statement period.
+//
+type TokenStatement struct {
+	TokenId      string  `json:"token_id"`
+	Opening      int64   `json:"opening_balance"`
+	TotalIn      int64   `json:"total_in"`
+	TotalOut     int64   `json:"total_out"`
+	Closing      int64   `json:"closing_balance"`
+	Transactions []*Line `json:"transactions"`
+}
+
+// Statement is a periodic account statement of one wallet.
+//
+type Statement struct {
+	WalletId  did.Identifier    `json:"wallet_id"`
+	From      time.Time         `json:"from"`
+	To        time.Time         `json:"to"`
+	Generated time.Time         `json:"generated"`
+	Tokens    []*TokenStatement `json:"tokens"`
+}
+
+// Generator produces account statements from the wallet logs and balance APIs.
+//
+type Generator struct {
+	client   Client
+	header   http.Header
+	pageSize int32
+}
+
+// NewGenerator returns a Generator instance.
+//
+// The header is attached to every request issued while building statements.
+//
+func NewGenerator(client Client, header http.Header) *Generator {
+	return &Generator{client: client, header: header, pageSize: defaultPageSize}
+}
+
+// SetPageSize sets the page size used when paging through transaction logs.
+//
+func (g *Generator) SetPageSize(num int32) {
+	if num > 0 {
+		g.pageSize = num
+	}
+}
+
+// Generate builds the statement of the specified wallet for the period [from, to).
+//
+// Only the logs of the period make the statement. The closing balance is
+// derived from the wallet balance reported by the gateway by reverting the
+// transactions after the period, and the opening balance from the closing
+// balance by reverting the transactions of the period. The logs are dated
+// by the gateway to the second. A period ending in the future stops at the
+// time the balance is read, one starting in the future is rejected.
+//
+func (g *Generator) Generate(id did.Identifier, from, to time.Time) (*Statement, error) {
+	if g.client == nil {
+		return nil, fmt.Errorf("statement client must be set")
+	}
+	if id == "" {
+		return nil, fmt.Errorf("wallet id must be set")
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("statement period invalid: %v - %v", from, to)
+	}
+	start, end := periodSeconds(from, to)
+	if start > end {
+		return nil, fmt.Errorf("statement period shorter than a second: %v - %v", from, to)
+	}
+
+	generated := time.Now()
+	if from.After(generated) {
+		return nil, fmt.Errorf("statement period starts in the future: %v", from)
+	}
+	balance, err := g.client.GetWalletBalance(g.header, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make(map[string]*TokenStatement)
+	token := func(tokenID string) *TokenStatement {
+		ts, ok := tokens[tokenID]
+		if !ok {
+			ts = &TokenStatement{TokenId: tokenID}
+			tokens[tokenID] = ts
+		}
+		return ts
+	}
+
+	if balance != nil {
+		for tokenID, b := range balance.ColoredTokens {
+			if b != nil {
+				token(tokenID).Closing = b.Amount
+			}
+		}
+	}
+
+	// the logs are bounded at the time the balance was read, the later
+	// transactions are not in the balance
+	read := generated.Unix()
+	if end > read {
+		end = read
+	}
+
+	for _, direction := range []string{DirectionIn, DirectionOut} {
+		// revert the transactions after the period from the current balance
+		if end < read {
+			logs, err := g.queryLogs(id, direction, end+1, read)
+			if err != nil {
+				return nil, err
+			}
+			for _, utxo := range logs {
+				ts := token(utxo.CTokenId)
+				if direction == DirectionIn {
+					ts.Closing -= utxo.Value
+				} else {
+					ts.Closing += utxo.Value
+				}
+			}
+		}
+
+		if start > end {
+			// the period starts within the second the balance was read
+			continue
+		}
+		logs, err := g.queryLogs(id, direction, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, utxo := range logs {
+			ts := token(utxo.CTokenId)
+			line := &Line{
+				Direction: direction,
+				TokenId:   utxo.CTokenId,
+				Amount:    utxo.Value,
+				TxHash:    utxo.SourceTxDataHash,
+			}
+			if direction == DirectionIn {
+				line.Counterparty = utxo.Founder
+				ts.TotalIn += utxo.Value
+			} else {
+				line.Counterparty = utxo.Addr
+				ts.TotalOut += utxo.Value
+			}
+			ts.Transactions = append(ts.Transactions, line)
+		}
+	}
+
+	s := &Statement{
+		WalletId:  id,
+		From:      from,
+		To:        to,
+		Generated: generated,
+	}
+	for _, ts := range tokens {
+		ts.Opening = ts.Closing - ts.TotalIn + ts.TotalOut
+		s.Tokens = append(s.Tokens, ts)
+	}
+	sort.Slice(s.Tokens, func(i, j int) bool {
+		return s.Tokens[i].TokenId < s.Tokens[j].TokenId
+	})
+
+	return s, nil
+}
+
+// GenerateMonth builds the statement of the specified wallet for a calendar
+// month in the location loc, UTC if nil.
+//
+func (g *Generator) GenerateMonth(id did.Identifier, year int, month time.Month, loc *time.Location) (*Statement, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	from := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	return g.Generate(id, from, from.AddDate(0, 1, 0))
+}
+
+// periodSeconds returns the first and last unix seconds of the period
+// [from, to), the inclusive bounds of the logs queries.
+func periodSeconds(from, to time.Time) (start, end int64) {
+	start = from.Unix()
+	if from.Nanosecond() > 0 {
+		start++
+	}
+	if start < 0 {
+		start = 0
+	}
+	end = to.Unix()
+	if to.Nanosecond() == 0 {
+		end--
+	}
+	return start, end
+}
+
+// queryLogs returns the logs of the direction between the unix seconds
+// start and end, both inclusive, zero end for no bound.
+func (g *Generator) queryLogs(id did.Identifier, txType string, start, end int64) ([]*pw.UTXO, error) {
+	var logs []*pw.UTXO
+	query := &api.TransactionLogsQuery{
+		Id:        id,
+		TxType:    txType,
+		PageSize:  g.pageSize,
+		StartTime: start,
+		EndTime:   end,
+	}
+	for {
+		page, err := g.client.QueryTransactionLogsPage(g.header, query)
+		if err != nil {
+			return nil, err
+		}
+		if page == nil {
+			return logs, nil
+		}
+		logs = append(logs, page.Logs...)
+		if page.NextCursor == "" {
+			return logs, nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+// WriteJSON exports the statement as JSON.
+//
+func (s *Statement) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// WriteCSV exports the statement as CSV, one record per balance or transaction line.
+//
+func (s *Statement) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	records := [][]string{
+		{"wallet_id", "from", "to", "token_id", "type", "amount", "tx_hash", "counterparty"},
+	}
+	from := s.From.Format(time.RFC3339)
+	to := s.To.Format(time.RFC3339)
+	record := func(tokenID, typ string, amount int64, txHash, counterparty string) []string {
+		return []string{string(s.WalletId), from, to, tokenID, typ, strconv.FormatInt(amount, 10), txHash, counterparty}
+	}
+	for _, ts := range s.Tokens {
+		records = append(records, record(ts.TokenId, "opening", ts.Opening, "", ""))
+		for _, line := range ts.Transactions {
+			records = append(records, record(ts.TokenId, line.Direction, line.Amount, line.TxHash, line.Counterparty))
+		}
+		records = append(records, record(ts.TokenId, "closing", ts.Closing, "", ""))
+	}
+	if err := cw.WriteAll(records); err != nil {
+		return err
+	}
+	return cw.Error()
+}
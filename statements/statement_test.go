@@ -0,0 +1,234 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statements
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	pw "github.com/arxanchain/sdk-go-common/protos/wallet"
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/sdk-go-common/structs/wallet"
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+// datedLog is a log of the fake client, dated in unix seconds.
+type datedLog struct {
+	at   int64
+	utxo *pw.UTXO
+}
+
+type fakeClient struct {
+	balance *wallet.WalletBalance
+	logs    map[string][]datedLog
+	queries []api.TransactionLogsQuery
+}
+
+func (f *fakeClient) GetWalletBalance(header http.Header, id did.Identifier) (*wallet.WalletBalance, error) {
+	return f.balance, nil
+}
+
+func (f *fakeClient) QueryTransactionLogsPage(header http.Header, query *api.TransactionLogsQuery) (*api.TransactionLogsPage, error) {
+	f.queries = append(f.queries, *query)
+	var logs []*pw.UTXO
+	for _, log := range f.logs[query.TxType] {
+		if log.at >= query.StartTime && (query.EndTime == 0 || log.at <= query.EndTime) {
+			logs = append(logs, log.utxo)
+		}
+	}
+	start, _ := strconv.Atoi(query.Cursor)
+	if start >= len(logs) {
+		return &api.TransactionLogsPage{}, nil
+	}
+	end := start + int(query.PageSize)
+	page := &api.TransactionLogsPage{}
+	if end < len(logs) {
+		page.NextCursor = strconv.Itoa(end)
+	} else {
+		end = len(logs)
+	}
+	page.Logs = logs[start:end]
+	return page, nil
+}
+
+func TestGenerateSucc(t *testing.T) {
+	const (
+		walletID = "did:axn:001"
+		tokenID  = "ctoken-001"
+	)
+	var (
+		february = time.Date(2018, time.February, 20, 0, 0, 0, 0, time.UTC).Unix()
+		march    = time.Date(2018, time.March, 1, 0, 0, 0, 0, time.UTC).Unix()
+		april    = time.Date(2018, time.April, 1, 0, 0, 0, 0, time.UTC).Unix()
+	)
+
+	client := &fakeClient{
+		balance: &wallet.WalletBalance{
+			ColoredTokens: map[string]*wallet.Balance{
+				tokenID: {Id: tokenID, Amount: 80},
+			},
+		},
+		logs: map[string][]datedLog{
+			DirectionIn: {
+				{february, &pw.UTXO{SourceTxDataHash: "tx-000", CTokenId: tokenID, Value: 10, Founder: "did:axn:002"}},
+				{march, &pw.UTXO{SourceTxDataHash: "tx-001", CTokenId: tokenID, Value: 50, Founder: "did:axn:002"}},
+				{april - 1, &pw.UTXO{SourceTxDataHash: "tx-002", CTokenId: tokenID, Value: 30, Founder: "did:axn:003"}},
+				{april, &pw.UTXO{SourceTxDataHash: "tx-004", CTokenId: tokenID, Value: 15, Founder: "did:axn:003"}},
+			},
+			DirectionOut: {
+				{march + 86400, &pw.UTXO{SourceTxDataHash: "tx-003", CTokenId: tokenID, Value: 20, Addr: "did:axn:004"}},
+				{april + 86400, &pw.UTXO{SourceTxDataHash: "tx-005", CTokenId: tokenID, Value: 5, Addr: "did:axn:004"}},
+			},
+		},
+	}
+
+	g := NewGenerator(client, http.Header{})
+	g.SetPageSize(1)
+	s, err := g.GenerateMonth(walletID, 2018, time.March, nil)
+	if err != nil {
+		t.Fatalf("generate statement fail: %v", err)
+	}
+	if !s.From.Equal(time.Unix(march, 0)) || !s.To.Equal(time.Unix(april, 0)) {
+		t.Fatalf("statement period should be March, got %v - %v", s.From, s.To)
+	}
+	if len(s.Tokens) != 1 {
+		t.Fatalf("statement should contain 1 token, not %d", len(s.Tokens))
+	}
+	ts := s.Tokens[0]
+	// 80 now, less the 15 received and plus the 5 spent in April
+	if ts.Closing != 70 {
+		t.Fatalf("closing balance should be 70, not %d", ts.Closing)
+	}
+	if ts.TotalIn != 80 || ts.TotalOut != 20 {
+		t.Fatalf("totals should be 80/20, not %d/%d", ts.TotalIn, ts.TotalOut)
+	}
+	// the 10 received in February
+	if ts.Opening != 10 {
+		t.Fatalf("opening balance should be 10, not %d", ts.Opening)
+	}
+	if len(ts.Transactions) != 3 {
+		t.Fatalf("statement should contain 3 transactions, not %d", len(ts.Transactions))
+	}
+	for _, q := range client.queries {
+		if q.StartTime == march && q.EndTime != april-1 {
+			t.Fatalf("the period query should end the second before April, got %d", q.EndTime)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err = s.WriteJSON(&buf); err != nil {
+		t.Fatalf("write json fail: %v", err)
+	}
+	var decoded Statement
+	if err = json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode json fail: %v", err)
+	}
+	if decoded.WalletId != walletID {
+		t.Fatalf("wallet id should be %v", walletID)
+	}
+
+	buf.Reset()
+	if err = s.WriteCSV(&buf); err != nil {
+		t.Fatalf("write csv fail: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("csv should contain 6 lines, not %d", len(lines))
+	}
+}
+
+func TestGenerateAfterBalance(t *testing.T) {
+	const tokenID = "ctoken-001"
+	march := time.Date(2018, time.March, 1, 0, 0, 0, 0, time.UTC).Unix()
+	// received once the balance was read
+	later := time.Now().Add(time.Hour).Unix()
+
+	client := &fakeClient{
+		balance: &wallet.WalletBalance{
+			ColoredTokens: map[string]*wallet.Balance{
+				tokenID: {Id: tokenID, Amount: 80},
+			},
+		},
+		logs: map[string][]datedLog{
+			DirectionIn: {
+				{march, &pw.UTXO{SourceTxDataHash: "tx-001", CTokenId: tokenID, Value: 50, Founder: "did:axn:002"}},
+				{later, &pw.UTXO{SourceTxDataHash: "tx-002", CTokenId: tokenID, Value: 15, Founder: "did:axn:002"}},
+			},
+		},
+	}
+
+	g := NewGenerator(client, http.Header{})
+	s, err := g.GenerateMonth("did:axn:001", 2018, time.March, nil)
+	if err != nil {
+		t.Fatalf("generate statement fail: %v", err)
+	}
+	if len(s.Tokens) != 1 || s.Tokens[0].Closing != 80 || s.Tokens[0].Opening != 30 {
+		t.Fatalf("transactions after the balance should not be reverted, got %+v", s.Tokens)
+	}
+	for _, q := range client.queries {
+		if q.EndTime == 0 || q.EndTime > s.Generated.Unix() {
+			t.Fatalf("queries should end when the balance was read, got %d", q.EndTime)
+		}
+	}
+}
+
+func TestPeriodSeconds(t *testing.T) {
+	from := time.Unix(100, 0)
+	if start, end := periodSeconds(from, time.Unix(200, 0)); start != 100 || end != 199 {
+		t.Fatalf("period should be 100-199, got %d-%d", start, end)
+	}
+	if start, end := periodSeconds(time.Unix(100, 5), time.Unix(200, 5)); start != 101 || end != 200 {
+		t.Fatalf("period should be 101-200, got %d-%d", start, end)
+	}
+}
+
+func TestGenerateFail(t *testing.T) {
+	g := NewGenerator(&fakeClient{}, http.Header{})
+	now := time.Now()
+	if _, err := g.Generate("", now.AddDate(0, -1, 0), now); err == nil {
+		t.Fatalf("err should not be nil when wallet id is empty")
+	}
+	if _, err := g.Generate("did:axn:001", now, now); err == nil {
+		t.Fatalf("err should not be nil when period is empty")
+	}
+	if _, err := g.Generate("did:axn:001", now.Add(time.Hour), now.AddDate(0, 1, 0)); err == nil {
+		t.Fatalf("err should not be nil when period starts in the future")
+	}
+}
+
+func TestGenerateCurrentPeriod(t *testing.T) {
+	client := &fakeClient{}
+	g := NewGenerator(client, http.Header{})
+	now := time.Now()
+	if _, err := g.Generate("did:axn:001", now.Add(-time.Hour), now.Add(time.Hour)); err != nil {
+		t.Fatalf("generate statement fail: %v", err)
+	}
+	// a period starting within the second the balance is read
+	if _, err := g.Generate("did:axn:001", time.Now(), now.Add(time.Hour)); err != nil {
+		t.Fatalf("generate statement fail: %v", err)
+	}
+	for _, q := range client.queries {
+		if q.StartTime > q.EndTime {
+			t.Fatalf("queries should not start after they end, got %d-%d", q.StartTime, q.EndTime)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/arxanchain/sdk-go-common/structs/did"
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+// Gateway is the Storage uploading the files to the wallet gateway, which
+// records their hash on chain. The POE must be created before Put.
+//
+type Gateway struct {
+	Client *api.WalletClient
+	// Header is sent with the requests, e.g. with the auth token
+	Header   http.Header
+	ReadOnly bool
+}
+
+// NewGateway returns a Gateway storage uploading with client.
+//
+func NewGateway(client *api.WalletClient, header http.Header) *Gateway {
+	return &Gateway{Client: client, Header: header}
+}
+
+// Put implements Storage.
+//
+func (g *Gateway) Put(poeID string, name string, size int64, r io.Reader) (*Object, error) {
+	if r == nil {
+		return nil, fmt.Errorf("reader must be set when storing poe file")
+	}
+	src := newHashingReader(r)
+	if _, err := g.Client.UploadPOEFileFromReader(g.Header, poeID, name, size, src, g.ReadOnly); err != nil {
+		return nil, err
+	}
+	return &Object{Backend: BackendGateway, Ref: poeID, Hash: src.sum(), Size: src.n}, nil
+}
+
+// Get implements Storage, the file is checked against the hash recorded on
+// chain.
+//
+func (g *Gateway) Get(obj *Object, w io.Writer) error {
+	if err := checkObject(obj, BackendGateway); err != nil {
+		return err
+	}
+	_, err := g.Client.DownloadPOEFile(g.Header, did.Identifier(obj.Ref), w)
+	return err
+}
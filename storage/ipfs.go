@@ -0,0 +1,135 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// IPFS is the Storage adding the files to an IPFS node through its HTTP
+// API, the objects are referenced by their CID.
+//
+type IPFS struct {
+	// APIURL is the url of the HTTP API of the node, e.g.
+	// "http://127.0.0.1:5001"
+	APIURL string
+	// Pin pins the added files on the node
+	Pin        bool
+	HTTPClient *http.Client
+}
+
+// NewIPFS returns an IPFS storage adding and pinning the files with the
+// node of the HTTP API at apiURL.
+//
+func NewIPFS(apiURL string) *IPFS {
+	return &IPFS{APIURL: apiURL, Pin: true}
+}
+
+type ipfsAdded struct {
+	Hash string `json:"Hash"`
+}
+
+// Put implements Storage.
+//
+func (s *IPFS) Put(poeID string, name string, size int64, r io.Reader) (*Object, error) {
+	if r == nil {
+		return nil, fmt.Errorf("reader must be set when storing poe file")
+	}
+	src := newHashingReader(r)
+
+	// Stream the multipart form through a pipe
+	bodyReader, bodyWriter := io.Pipe()
+	writer := multipart.NewWriter(bodyWriter)
+	go func() {
+		part, err := writer.CreateFormFile("file", name)
+		if err == nil {
+			_, err = io.Copy(part, src)
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		bodyWriter.CloseWithError(err)
+	}()
+	defer bodyReader.Close()
+
+	params := url.Values{"pin": {fmt.Sprint(s.Pin)}, "cid-version": {"1"}}
+	resp, err := s.call("/api/v0/add", params, writer.FormDataContentType(), bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var added ipfsAdded
+	if err = json.NewDecoder(resp.Body).Decode(&added); err != nil {
+		return nil, fmt.Errorf("ipfs add response invalid: %v", err)
+	}
+	if added.Hash == "" {
+		return nil, fmt.Errorf("ipfs add response has no cid")
+	}
+	if size >= 0 && src.n != size {
+		return nil, fmt.Errorf("file size mismatch: %d bytes read, %d expected", src.n, size)
+	}
+
+	return &Object{Backend: BackendIPFS, Ref: added.Hash, Hash: src.sum(), Size: src.n}, nil
+}
+
+// Get implements Storage.
+//
+func (s *IPFS) Get(obj *Object, w io.Writer) error {
+	if err := checkObject(obj, BackendIPFS); err != nil {
+		return err
+	}
+	resp, err := s.call("/api/v0/cat", url.Values{"arg": {obj.Ref}}, "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return copyVerified(w, resp.Body, obj.Hash)
+}
+
+// call sends a request to the HTTP API, which only accepts POST requests.
+func (s *IPFS) call(path string, params url.Values, contentType string, body io.Reader) (*http.Response, error) {
+	u := strings.TrimRight(s.APIURL, "/") + path + "?" + params.Encode()
+	req, err := http.NewRequest("POST", u, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("ipfs %s fail: %s: %s", path, resp.Status, bytes.TrimSpace(msg))
+	}
+	return resp, nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package storage stores the offchain files of the POE digital assets in
+// pluggable stores: the wallet gateway, S3 compatible object stores or IPFS.
+//
+// A file stored with the gateway is recorded on chain by the gateway. With
+// the other stores, the POE is created with the hash of the stored object
+// and a reference to it in its metadata:
+//
+//	obj, err := store.Put(poeID, "contract.pdf", size, f)
+//	if err != nil {
+//		return err
+//	}
+//	metadata, err := obj.Metadata()
+//	if err != nil {
+//		return err
+//	}
+//	body := &wallet.POEBody{Id: poeID, Name: "contract", Owner: owner, Hash: obj.Hash, Metadata: metadata}
+//
+// The file is then read back with ParseMetadata and Get, which checks the
+// hash of the object.
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+// Storage backends.
+//
+const (
+	BackendGateway = "gateway"
+	BackendS3      = "s3"
+	BackendIPFS    = "ipfs"
+)
+
+// Storage stores the offchain files of the POE digital assets.
+//
+type Storage interface {
+	// Put stores the contents read from r as the file name of the POE
+	// poeID. size is the number of bytes of r, or -1 if unknown.
+	Put(poeID string, name string, size int64, r io.Reader) (*Object, error)
+	// Get writes the contents of obj to w. It returns
+	// api.ErrPOEFileHashMismatch when they do not match obj.Hash, the data
+	// written to w must then be discarded.
+	Get(obj *Object, w io.Writer) error
+}
+
+// Object is a file stored with a Storage.
+//
+type Object struct {
+	// Backend is the storage backend, e.g. BackendIPFS
+	Backend string `json:"backend"`
+	// Ref is the reference of the object in the store: the POE id with the
+	// gateway, the object key with S3, the CID with IPFS
+	Ref string `json:"ref"`
+	// Hash is the sha256 hash of the object, hex encoded
+	Hash string `json:"hash"`
+	Size int64  `json:"size"`
+}
+
+// objectMetadata is the POE metadata referencing an object.
+type objectMetadata struct {
+	Storage *Object `json:"storage"`
+}
+
+// Metadata returns the POE metadata referencing the object.
+//
+func (o *Object) Metadata() ([]byte, error) {
+	return json.Marshal(&objectMetadata{Storage: o})
+}
+
+// ParseMetadata returns the object referenced by the metadata of a POE.
+//
+func ParseMetadata(metadata []byte) (*Object, error) {
+	var m objectMetadata
+	if err := json.Unmarshal(metadata, &m); err != nil {
+		return nil, fmt.Errorf("poe metadata invalid: %v", err)
+	}
+	if m.Storage == nil || m.Storage.Backend == "" || m.Storage.Ref == "" {
+		return nil, fmt.Errorf("poe metadata references no stored object")
+	}
+	return m.Storage, nil
+}
+
+// hashingReader hashes and counts the bytes read from r.
+type hashingReader struct {
+	r io.Reader
+	h hash.Hash
+	n int64
+}
+
+func newHashingReader(r io.Reader) *hashingReader {
+	return &hashingReader{r: r, h: sha256.New()}
+}
+
+func (h *hashingReader) Read(p []byte) (int, error) {
+	n, err := h.r.Read(p)
+	h.h.Write(p[:n])
+	h.n += int64(n)
+	return n, err
+}
+
+func (h *hashingReader) sum() string {
+	return hex.EncodeToString(h.h.Sum(nil))
+}
+
+// copyVerified copies src to w, checking its sha256 hash is hash.
+func copyVerified(w io.Writer, src io.Reader, hash string) error {
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(w, h), src); err != nil {
+		return err
+	}
+	if !strings.EqualFold(hex.EncodeToString(h.Sum(nil)), hash) {
+		return api.ErrPOEFileHashMismatch
+	}
+	return nil
+}
+
+// checkObject checks obj is an object of backend.
+func checkObject(obj *Object, backend string) error {
+	if obj == nil || obj.Ref == "" {
+		return fmt.Errorf("storage object invalid")
+	}
+	if obj.Backend != backend {
+		return fmt.Errorf("storage object of %s backend, not %s", obj.Backend, backend)
+	}
+	return nil
+}
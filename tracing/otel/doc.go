@@ -0,0 +1,29 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package otel traces the calls of the wallet client with OpenTelemetry.
+//
+// It is built with the otel build tag, so that programs not using it do not
+// need the OpenTelemetry modules:
+//
+//	go build -tags otel
+//
+// Every call to the wallet gateway gets a client span with its endpoint,
+// wallet DID, transaction type, invoking mode and error code, and the trace
+// context is propagated to the gateway in the request headers:
+//
+//	walletClient.SetTracer(otel.NewTracer(nil))
+package otel
@@ -0,0 +1,122 @@
+//go:build otel
+// +build otel
+
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otel
+
+import (
+	"context"
+	"errors"
+
+	gotel "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+// instrumentationName is the name of the tracer of the spans.
+const instrumentationName = "github.com/arxanchain/wallet-sdk-go"
+
+// Span attributes. AttrWalletID is the Redact hash of the wallet DID when
+// the client is in privacy mode.
+//
+const (
+	AttrEndpoint   = attribute.Key("wallet.endpoint")
+	AttrWalletID   = attribute.Key("wallet.did")
+	AttrTxType     = attribute.Key("wallet.tx_type")
+	AttrInvokeMode = attribute.Key("wallet.invoke_mode")
+	AttrErrorCode  = attribute.Key("wallet.error_code")
+	AttrMethod     = attribute.Key("http.request.method")
+	AttrStatusCode = attribute.Key("http.response.status_code")
+)
+
+// Config is the configuration of the tracer, the zero value uses the global
+// tracer provider and propagator.
+//
+type Config struct {
+	TracerProvider trace.TracerProvider
+	Propagator     propagation.TextMapPropagator
+}
+
+// tracer starts a span per call.
+type tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// NewTracer returns the api.Tracer starting the OpenTelemetry spans of the
+// calls, config can be nil.
+//
+func NewTracer(config *Config) api.Tracer {
+	if config == nil {
+		config = &Config{}
+	}
+	provider := config.TracerProvider
+	if provider == nil {
+		provider = gotel.GetTracerProvider()
+	}
+	propagator := config.Propagator
+	if propagator == nil {
+		propagator = gotel.GetTextMapPropagator()
+	}
+	return &tracer{
+		tracer:     provider.Tracer(instrumentationName),
+		propagator: propagator,
+	}
+}
+
+// StartCall starts the span of the call and injects its context in the
+// request headers.
+//
+func (t *tracer) StartCall(ctx context.Context, call *api.Call) func(err error) {
+	attrs := []attribute.KeyValue{
+		AttrMethod.String(call.Method),
+		AttrEndpoint.String(call.Endpoint),
+		AttrInvokeMode.String(call.InvokeMode()),
+	}
+	if id := call.WalletID(); id != "" {
+		attrs = append(attrs, AttrWalletID.String(id))
+	}
+	if typ := call.TxType(); typ != "" {
+		attrs = append(attrs, AttrTxType.String(typ))
+	}
+	ctx, span := t.tracer.Start(ctx, call.Method+" "+call.Endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(call.Header))
+
+	return func(err error) {
+		if err != nil {
+			var e *api.Error
+			if errors.As(err, &e) {
+				if e.StatusCode != 0 {
+					span.SetAttributes(AttrStatusCode.Int(e.StatusCode))
+				}
+				if e.ErrCode != 0 {
+					span.SetAttributes(AttrErrorCode.Int(e.ErrCode))
+				}
+			}
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
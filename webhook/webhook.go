@@ -0,0 +1,183 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook receives the events sent by the wallet gateway to the
+// callback URLs registered with RegisterCallback.
+//
+// The events are signed with the secret returned by RegisterCallback: the
+// Callback-Signature header is the hex encoded HMAC-SHA256 of the
+// Callback-Timestamp header, a dot and the request body. Handler verifies
+// them before passing them to the application:
+//
+//	http.Handle("/wallet/events", webhook.Handler(secret, func(e *api.Event) error {
+//		tx, err := e.Transaction()
+//		if err != nil {
+//			return err
+//		}
+//		return markConfirmed(tx.TransactionId, !tx.IsInvalid)
+//	}))
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+// Headers of the event requests.
+//
+const (
+	SignatureHeader = "Callback-Signature"
+	TimestampHeader = "Callback-Timestamp"
+)
+
+// DefaultTolerance is the maximum age of the events, older events are
+// rejected as replays.
+//
+const DefaultTolerance = 5 * time.Minute
+
+// maxEventSize bounds the event request bodies.
+const maxEventSize = 1 << 20
+
+var (
+	// ErrSignatureInvalid is returned when the event signature is missing
+	// or does not match
+	ErrSignatureInvalid = fmt.Errorf("callback signature invalid")
+	// ErrTimestampExpired is returned when the event timestamp is out of
+	// the tolerance
+	ErrTimestampExpired = fmt.Errorf("callback timestamp expired")
+)
+
+// Sign returns the signature of an event body sent at timestamp, in unix
+// seconds, e.g. to test a receiver.
+//
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verifier verifies the events signed with Secret.
+//
+type Verifier struct {
+	Secret string
+	// Tolerance is the maximum age of the events, DefaultTolerance if zero
+	Tolerance time.Duration
+
+	// now returns the current time, for the tests
+	now func() time.Time
+}
+
+// VerifyCallback reads the body of the event request r and returns the
+// event if it is signed with secret and not older than DefaultTolerance.
+//
+func VerifyCallback(r *http.Request, secret string) (*api.Event, error) {
+	v := &Verifier{Secret: secret}
+	return v.Verify(r)
+}
+
+// Verify reads the body of the event request r and returns the event if
+// its signature and timestamp are valid.
+//
+func (v *Verifier) Verify(r *http.Request) (*api.Event, error) {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxEventSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxEventSize {
+		return nil, fmt.Errorf("callback event too large")
+	}
+	return v.VerifyBody(r.Header.Get(TimestampHeader), r.Header.Get(SignatureHeader), body)
+}
+
+// VerifyBody returns the event of body if signature and timestamp, the
+// values of the event request headers, are valid.
+//
+func (v *Verifier) VerifyBody(timestamp, signature string, body []byte) (*api.Event, error) {
+	if v.Secret == "" {
+		return nil, fmt.Errorf("callback secret must be set")
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || signature == "" {
+		return nil, ErrSignatureInvalid
+	}
+	expected := Sign(v.Secret, ts, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, ErrSignatureInvalid
+	}
+
+	now := time.Now
+	if v.now != nil {
+		now = v.now
+	}
+	tolerance := v.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	age := now().Sub(time.Unix(ts, 0))
+	if age > tolerance || age < -tolerance {
+		return nil, ErrTimestampExpired
+	}
+
+	event := &api.Event{}
+	if err = json.Unmarshal(body, event); err != nil {
+		return nil, fmt.Errorf("callback event invalid: %v", err)
+	}
+	return event, nil
+}
+
+// Handler returns the http handler verifying the events signed with secret
+// and passing them to fn. It replies 401 to the invalid events, 500 when fn
+// fails so that the gateway sends the event again, and 200 otherwise.
+//
+func Handler(secret string, fn func(e *api.Event) error) http.Handler {
+	v := &Verifier{Secret: secret}
+	return v.Handler(fn)
+}
+
+// Handler returns the http handler verifying the events and passing them to
+// fn, see the Handler function.
+//
+func (v *Verifier) Handler(fn func(e *api.Event) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			w.Header().Set("Allow", "POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		event, err := v.Verify(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err = fn(event); err != nil {
+			http.Error(w, "callback event not processed", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,106 @@
+/*
+Copyright ArxanFintech Technology Ltd. 2018 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/arxanchain/wallet-sdk-go/api"
+)
+
+func eventRequest(secret string, timestamp int64, body string) *http.Request {
+	r := httptest.NewRequest("POST", "/wallet/events", bytes.NewBufferString(body))
+	r.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	r.Header.Set(SignatureHeader, Sign(secret, timestamp, []byte(body)))
+	return r
+}
+
+const confirmedEvent = `{"id":"event-001","type":"transaction.confirmed","wallet_id":"did:axn:wallet-001","timestamp":1500000000,` +
+	`"data":{"block_number":12,"transaction_id":"tx-001","timestamp":{"seconds":1500000000,"nanos":5},"is_invalid":false,"payload":{"id":"poe-001"}}}`
+
+func TestVerifyCallback(t *testing.T) {
+	now := time.Now().Unix()
+	event, err := VerifyCallback(eventRequest("secret-001", now, confirmedEvent), "secret-001")
+	if err != nil {
+		t.Fatalf("verify callback fail: %v", err)
+	}
+	if event.Id != "event-001" || event.WalletId != "did:axn:wallet-001" {
+		t.Fatalf("event should be decoded, got %+v", event)
+	}
+	tx, err := event.Transaction()
+	if err != nil {
+		t.Fatalf("event transaction fail: %v", err)
+	}
+	if tx.TransactionId != "tx-001" || tx.BlockNumber != 12 || tx.Timestamp.Time().Unix() != 1500000000 || string(tx.Payload) != `{"id":"poe-001"}` {
+		t.Fatalf("transaction should be decoded, got %+v", tx)
+	}
+	if _, err = (&api.Event{Id: "event-002", Type: "wallet.updated"}).Transaction(); err == nil {
+		t.Fatalf("err should not be nil for other events")
+	}
+
+	if _, err = VerifyCallback(eventRequest("other-secret", now, confirmedEvent), "secret-001"); err != ErrSignatureInvalid {
+		t.Fatalf("err should be ErrSignatureInvalid, got %v", err)
+	}
+	tampered := eventRequest("secret-001", now, confirmedEvent)
+	tampered.Header.Set(TimestampHeader, strconv.FormatInt(now+1, 10))
+	if _, err = VerifyCallback(tampered, "secret-001"); err != ErrSignatureInvalid {
+		t.Fatalf("err should be ErrSignatureInvalid, got %v", err)
+	}
+	if _, err = VerifyCallback(eventRequest("secret-001", now-3600, confirmedEvent), "secret-001"); err != ErrTimestampExpired {
+		t.Fatalf("err should be ErrTimestampExpired, got %v", err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var received []*api.Event
+	fail := false
+	handler := Handler("secret-001", func(e *api.Event) error {
+		if fail {
+			return fmt.Errorf("database unavailable")
+		}
+		received = append(received, e)
+		return nil
+	})
+
+	now := time.Now().Unix()
+	for _, c := range []struct {
+		r    *http.Request
+		fail bool
+		code int
+	}{
+		{eventRequest("secret-001", now, confirmedEvent), false, http.StatusOK},
+		{eventRequest("other-secret", now, confirmedEvent), false, http.StatusUnauthorized},
+		{eventRequest("secret-001", now, confirmedEvent), true, http.StatusInternalServerError},
+		{httptest.NewRequest("GET", "/wallet/events", nil), false, http.StatusMethodNotAllowed},
+	} {
+		fail = c.fail
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, c.r)
+		if w.Code != c.code {
+			t.Fatalf("status should be %d, got %d", c.code, w.Code)
+		}
+	}
+	if len(received) != 1 {
+		t.Fatalf("only the valid event should be processed, got %d", len(received))
+	}
+}